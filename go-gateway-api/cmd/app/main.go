@@ -15,6 +15,7 @@ import (
 
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/repository"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/server"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq" // PostgreSQL driver
@@ -107,28 +108,276 @@ func main() {
 	defer kafkaProducer.Close()
 
 	// Inicializa camadas da aplicação (repository -> service -> server)
+	// Trilha de auditoria das operações sensíveis, compartilhada pelos services
+	auditService := service.NewAuditService(repository.NewAuditRepository(db))
+
 	accountRepository := repository.NewAccountRepository(db)
-	accountService := service.NewAccountService(accountRepository)
+	accountService := service.NewAccountService(accountRepository, auditService)
+
+	// Valores aprovados ficam pendentes até a data de liquidação definida para a conta
+	receivableRepository := repository.NewReceivableRepository(db)
+	settlementService := service.NewSettlementService(accountRepository, receivableRepository)
+
+	// Apenas o valor líquido das taxas (MDR) é creditado; a receita vai para a conta da plataforma
+	feeRepository := repository.NewFeeRepository(db)
+	feeService := service.NewFeeService(feeRepository, accountRepository, auditService, service.NewFeeConfig())
+
+	// Faturas em outra moeda são convertidas para a moeda da conta pela cotação travada na aprovação
+	fxRepository := repository.NewFXRepository(db)
+	fxService := service.NewFXService(fxRepository, newFXRateProvider(getEnv("FX_PROVIDER", "database"), fxRepository), auditService, service.NewFXConfig())
 
 	invoiceRepository := repository.NewInvoiceRepository(db)
-	invoiceService := service.NewInvoiceService(invoiceRepository, *accountService, kafkaProducer)
+
+	// Faturas Pix aguardam o pagamento e são aprovadas pelo callback do PSP
+	pixRepository := repository.NewPixRepository(db)
+	pixService := service.NewPixService(
+		pixRepository,
+		invoiceRepository,
+		*accountService,
+		fxService,
+		feeService,
+		settlementService,
+		newPixProvider(getEnv("PIX_PROVIDER", "simulator")),
+		auditService,
+		service.NewPixConfig(),
+	)
+
+	// Boletos são registrados no banco emissor e aprovados pelo aviso de liquidação
+	boletoRepository := repository.NewBoletoRepository(db)
+	boletoConfig := service.NewBoletoConfig()
+	boletoService := service.NewBoletoService(
+		boletoRepository,
+		invoiceRepository,
+		*accountService,
+		fxService,
+		feeService,
+		settlementService,
+		newBoletoBank(getEnv("BOLETO_BANK", "simulator")),
+		auditService,
+		boletoConfig,
+	)
+
+	payerConfig := service.NewPayerConfig()
+	// Cupons de desconto resgatados pelo código na emissão das faturas com itens
+	couponRepository := repository.NewCouponRepository(db)
+	couponService := service.NewCouponService(couponRepository, accountService, auditService)
+
+	invoiceService := service.NewInvoiceService(invoiceRepository, *accountService, kafkaProducer, settlementService, fxService, feeService, auditService, service.NewInstallmentConfig(), pixService, boletoService, payerConfig, couponService)
+
+	// Recibos das faturas em HTML ou PDF, com o logo, as cores e o rodapé de cada conta
+	receiptSettingsRepository := repository.NewReceiptSettingsRepository(db)
+	receiptService := service.NewReceiptService(invoiceRepository, receiptSettingsRepository, accountService, auditService)
+
+	// Assinaturas cobram o plano no cartão salvo do cliente a cada período; as falhas de
+	// cobrança são publicadas para o lojista
+	subscriptionEventsTopic := getEnv("KAFKA_SUBSCRIPTION_EVENTS_TOPIC", "subscription_events")
+	subscriptionEventsProducer := service.NewKafkaProducer(baseKafkaConfig.WithTopic(subscriptionEventsTopic))
+	defer subscriptionEventsProducer.Close()
+
+	customerRepository := repository.NewCustomerRepository(db)
+	customerService := service.NewCustomerService(customerRepository, accountService, auditService, payerConfig)
+	subscriptionRepository := repository.NewSubscriptionRepository(db)
+	subscriptionService := service.NewSubscriptionService(
+		subscriptionRepository,
+		customerRepository,
+		invoiceRepository,
+		accountService,
+		invoiceService,
+		fxService,
+		auditService,
+		subscriptionEventsProducer,
+		service.NewDunningConfig(),
+	)
+
+	subscriptionBillingJob := service.NewSubscriptionBillingJob(
+		subscriptionService,
+		subscriptionRepository,
+		repository.NewLockRepository(db),
+		service.NewSubscriptionBillingJobConfig(),
+	)
+
+	go func() {
+		if err := subscriptionBillingJob.Run(context.Background()); err != nil {
+			log.Printf("Error running subscription billing job: %v", err)
+		}
+	}()
+
+	// Sessões de checkout e links de pagamento são pagos pelo comprador no navegador, com o
+	// cartão tokenizado pela chave pública da conta
+	cardTokenService := service.NewCardTokenService(repository.NewCardTokenRepository(db), service.NewCardTokenConfig())
+	checkoutRepository := repository.NewCheckoutRepository(db)
+	checkoutService := service.NewCheckoutService(
+		checkoutRepository,
+		accountService,
+		invoiceService,
+		cardTokenService,
+		auditService,
+		service.NewCheckoutConfig(),
+	)
 
 	// Configura e inicializa o consumidor Kafka
 	consumerTopic := getEnv("KAFKA_CONSUMER_TOPIC", "transaction_results")
 	consumerConfig := baseKafkaConfig.WithTopic(consumerTopic)
 	groupID := getEnv("KAFKA_CONSUMER_GROUP_ID", "gateway-group")
-	kafkaConsumer := service.NewKafkaConsumer(consumerConfig, groupID, invoiceService)
+
+	// Mensagens que falharem após as retentativas vão para a dead-letter queue
+	dlqTopic := getEnv("KAFKA_DLQ_TOPIC", consumerTopic+"_dlq")
+	dlqProducer := service.NewKafkaDLQProducer(baseKafkaConfig.WithTopic(dlqTopic))
+	defer dlqProducer.Close()
+
+	kafkaConsumer := service.NewKafkaConsumer(
+		consumerConfig,
+		groupID,
+		invoiceService,
+		dlqProducer,
+		service.NewRetryPolicy(),
+		service.NewWorkerPoolConfig(),
+	)
 	defer kafkaConsumer.Close()
 
-	// Inicia o consumidor Kafka em uma goroutine
+	// Faturas pendentes sem resposta são republicadas ou expiradas periodicamente
+	invoiceEventsTopic := getEnv("KAFKA_INVOICE_EVENTS_TOPIC", "invoice_events")
+	invoiceEventsProducer := service.NewKafkaProducer(baseKafkaConfig.WithTopic(invoiceEventsTopic))
+	defer invoiceEventsProducer.Close()
+
+	sweeper := service.NewPendingInvoiceSweeper(
+		invoiceRepository,
+		repository.NewLockRepository(db),
+		kafkaProducer,
+		invoiceEventsProducer,
+		auditService,
+		service.NewSweeperConfig(),
+	)
+
+	go func() {
+		if err := sweeper.Run(context.Background()); err != nil {
+			log.Printf("Error running pending invoice sweeper: %v", err)
+		}
+	}()
+
+	// Saques reservam o saldo na criação e são enviados ao provedor em segundo plano
+	payoutRepository := repository.NewPayoutRepository(db)
+	payoutConfig := service.NewPayoutConfig()
+	payoutService := service.NewPayoutService(payoutRepository, accountService, auditService, payoutConfig)
+
+	// Com PAYOUT_PROVIDER=cnab os saques são enviados ao banco por remessa, gerada pelos operadores,
+	// e apenas contas bancárias são aceitas como destino
+	if payoutConfig.Provider != "cnab" {
+		payoutProcessor := service.NewPayoutProcessor(
+			payoutRepository,
+			repository.NewLockRepository(db),
+			newPayoutProvider(payoutConfig.Provider),
+			auditService,
+			service.NewPayoutProcessorConfig(),
+		)
+
+		go func() {
+			if err := payoutProcessor.Run(context.Background()); err != nil {
+				log.Printf("Error running payout processor: %v", err)
+			}
+		}()
+	}
+
+	// Remessas e retornos CNAB para os bancos que trabalham por arquivo
+	cnabService := service.NewCNABService(
+		repository.NewCNABRepository(db),
+		payoutRepository,
+		boletoService,
+		auditService,
+		boletoConfig.Issuer,
+		service.NewCNABConfig(),
+	)
+
+	// Cobranças Pix não pagas dentro do prazo são expiradas junto com a fatura
+	pixExpirationJob := service.NewPixExpirationJob(
+		pixRepository,
+		invoiceRepository,
+		repository.NewLockRepository(db),
+		auditService,
+		service.NewPixExpirationJobConfig(),
+	)
+
+	go func() {
+		if err := pixExpirationJob.Run(context.Background()); err != nil {
+			log.Printf("Error running pix expiration job: %v", err)
+		}
+	}()
+
+	// Boletos não pagos até a data limite são expirados junto com a fatura
+	boletoExpirationJob := service.NewBoletoExpirationJob(
+		boletoRepository,
+		invoiceRepository,
+		repository.NewLockRepository(db),
+		auditService,
+		service.NewBoletoExpirationJobConfig(),
+	)
+
 	go func() {
-		if err := kafkaConsumer.Consume(context.Background()); err != nil {
-			log.Printf("Error consuming kafka messages: %v", err)
+		if err := boletoExpirationJob.Run(context.Background()); err != nil {
+			log.Printf("Error running boleto expiration job: %v", err)
 		}
 	}()
 
+	// Recebíveis futuros podem ser antecipados com desconto pro rata pelos dias até o vencimento
+	anticipationRepository := repository.NewAnticipationRepository(db)
+	anticipationService := service.NewAnticipationService(
+		anticipationRepository,
+		accountService,
+		feeService,
+		auditService,
+		service.NewAnticipationConfig(),
+	)
+
+	settlementJob := service.NewSettlementJob(
+		receivableRepository,
+		repository.NewLockRepository(db),
+		service.NewSettlementJobConfig(),
+	)
+
+	go func() {
+		if err := settlementJob.Run(context.Background()); err != nil {
+			log.Printf("Error running settlement job: %v", err)
+		}
+	}()
+
+	// Inicia o consumidor Kafka em uma goroutine; Run o reinicia após falhas
+	go kafkaConsumer.Run(context.Background())
+
 	port := getEnv("HTTP_PORT", "8080")
-	srv := server.NewServer(accountService, invoiceService, port)
+	reviewRepository := repository.NewReviewRepository(db)
+	reviewService := service.NewReviewService(reviewRepository, invoiceRepository, invoiceService, auditService)
+
+	// Operadores internos no formato "nome:papel:token,nome:papel:token"
+	operatorTokens, err := middleware.ParseOperatorTokens(getEnv("OPERATOR_TOKENS", ""))
+	if err != nil {
+		log.Fatalf("Invalid OPERATOR_TOKENS: %v", err)
+	}
+	if len(operatorTokens) == 0 {
+		log.Println("Aviso: OPERATOR_TOKENS vazio, nenhum operador consegue acessar as rotas /admin nem criar contas (POST /admin/accounts).")
+	}
+	allowedOrigins := middleware.ParseAllowedOrigins(getEnv("CHECKOUT_ALLOWED_ORIGINS", ""))
+	browserRateLimit := middleware.ParseRateLimit(getEnv("BROWSER_RATE_LIMIT", "60/1m"))
+	publishableKeyRateLimit := middleware.ParseRateLimit(getEnv("PUBLISHABLE_KEY_RATE_LIMIT", "300/1m"))
+	// Proxies à frente da API ("10.0.0.0/8,192.168.1.10"); vazio usa o IP da conexão
+	trustedProxies := middleware.ParseTrustedProxies(getEnv("TRUSTED_PROXIES", ""))
+
+	dataExportService := service.NewDataExportService(
+		accountRepository,
+		invoiceRepository,
+		payoutRepository,
+		receivableRepository,
+		pixRepository,
+		boletoRepository,
+		feeRepository,
+		anticipationRepository,
+		customerRepository,
+		subscriptionRepository,
+		checkoutRepository,
+		couponRepository,
+		receiptSettingsRepository,
+	)
+
+	srv := server.NewServer(accountService, invoiceService, reviewService, dataExportService, auditService, payoutService, settlementService, feeService, anticipationService, pixService, boletoService, cnabService, fxService, customerService, subscriptionService, checkoutService, cardTokenService, couponService, receiptService, operatorTokens, allowedOrigins, browserRateLimit, publishableKeyRateLimit, trustedProxies, port)
 	srv.ConfigureRoutes()
 
 	if err := srv.Start(); err != nil {
@@ -136,6 +385,56 @@ func main() {
 	}
 }
 
+// newPayoutProvider escolhe a integração de saques; por enquanto só existe o simulador local
+func newPayoutProvider(name string) service.PayoutProvider {
+	switch name {
+	case "simulator":
+		return service.NewSimulatedPayoutProvider(service.NewSimulatedPayoutProviderConfig())
+	default:
+		log.Fatalf("Unknown payout provider: %s", name)
+		return nil
+	}
+}
+
+// newPixProvider escolhe o PSP dos pagamentos Pix; por enquanto só existe o simulador local
+func newPixProvider(name string) service.PixProvider {
+	switch name {
+	case "simulator":
+		return service.NewSimulatedPixProvider(service.NewSimulatedPixProviderConfig())
+	default:
+		log.Fatalf("Unknown pix provider: %s", name)
+		return nil
+	}
+}
+
+// newFXRateProvider escolhe a fonte das cotações: a tabela fx_rates, alimentada pela importação
+// do provedor, ou um arquivo CSV (FX_RATES_FILE)
+func newFXRateProvider(name string, fxRepository *repository.FXRepository) service.FXRateProvider {
+	switch name {
+	case "database":
+		return service.NewDatabaseFXRateProvider(fxRepository)
+	case "file":
+		return service.NewFileFXRateProvider(getEnv("FX_RATES_FILE", "fx_rates.csv"))
+	default:
+		log.Fatalf("Unknown fx provider: %s", name)
+		return nil
+	}
+}
+
+// newBoletoBank escolhe o banco emissor dos boletos: o simulador local ou um banco que
+// trabalha com remessa e retorno CNAB
+func newBoletoBank(name string) service.BoletoBank {
+	switch name {
+	case "simulator":
+		return service.NewSimulatedBoletoBank(service.NewSimulatedBoletoBankConfig())
+	case "cnab":
+		return service.NewCNABBoletoBank()
+	default:
+		log.Fatalf("Unknown boleto bank: %s", name)
+		return nil
+	}
+}
+
 // isNetworkError tenta identificar erros de rede comuns
 func isNetworkError(err error) bool {
 	errMsg := err.Error()
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/joho/godotenv"
+)
+
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
+
+// Comando administrativo que reenvia as mensagens da DLQ para o tópico de origem
+func main() {
+	if getEnv("APP_ENV", "development") == "development" {
+		if err := godotenv.Load(); err != nil {
+			log.Printf("Arquivo .env não encontrado, usando variáveis de ambiente: %v", err)
+		}
+	}
+
+	consumerTopic := getEnv("KAFKA_CONSUMER_TOPIC", "transaction_results")
+
+	dlqTopic := flag.String("dlq-topic", getEnv("KAFKA_DLQ_TOPIC", consumerTopic+"_dlq"), "tópico da dead-letter queue")
+	targetTopic := flag.String("target-topic", consumerTopic, "tópico usado quando a mensagem não informa a origem")
+	groupID := flag.String("group-id", getEnv("KAFKA_DLQ_REPLAY_GROUP_ID", "gateway-dlq-replay"), "consumer group do replay")
+	limit := flag.Int("limit", 0, "quantidade máxima de mensagens reenviadas (0 = todas)")
+	idleTimeout := flag.Duration("idle-timeout", 10*time.Second, "tempo sem novas mensagens para encerrar")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	replayer := service.NewKafkaDLQReplayer(service.NewKafkaConfig().WithTopic(*dlqTopic), *groupID)
+	defer replayer.Close()
+
+	replayed, err := replayer.Replay(ctx, *targetTopic, *limit, *idleTimeout)
+	if err != nil {
+		log.Fatalf("Erro ao reenviar mensagens da DLQ após %d mensagens: %v", replayed, err)
+	}
+
+	log.Printf("%d mensagens reenviadas da DLQ %s", replayed, *dlqTopic)
+}
@@ -0,0 +1,119 @@
+// Package cnab gera os arquivos de remessa e lê os arquivos de retorno dos bancos nos
+// leiautes FEBRABAN CNAB 240 (cobrança e pagamentos) e CNAB 400 (cobrança).
+//
+// As posições dos campos seguem a documentação da FEBRABAN: começam em 1 e incluem o fim.
+package cnab
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// record é uma linha de largura fixa, inicializada com brancos
+type record []byte
+
+func newRecord(width int) record {
+	return record(strings.Repeat(" ", width))
+}
+
+// alpha grava um campo alfanumérico: maiúsculo, sem acentos, alinhado à esquerda e
+// completado com brancos
+func (r record) alpha(start, end int, value string) {
+	value = strings.ToUpper(domain.ASCIIText(value))
+	size := end - start + 1
+	if len(value) > size {
+		value = value[:size]
+	}
+	copy(r[start-1:end], value+strings.Repeat(" ", size-len(value)))
+}
+
+// numeric grava um campo numérico alinhado à direita e completado com zeros; dígitos
+// excedentes à esquerda são descartados
+func (r record) numeric(start, end int, value string) {
+	size := end - start + 1
+	value = strings.Map(func(c rune) rune {
+		if c >= '0' && c <= '9' {
+			return c
+		}
+		return -1
+	}, value)
+
+	if len(value) > size {
+		value = value[len(value)-size:]
+	}
+	copy(r[start-1:end], strings.Repeat("0", size-len(value))+value)
+}
+
+func (r record) number(start, end int, value int64) {
+	r.numeric(start, end, strconv.FormatInt(value, 10))
+}
+
+// amount grava um valor monetário em centavos, sem separador decimal
+func (r record) amount(start, end int, value float64) {
+	r.number(start, end, int64(math.Round(value*100)))
+}
+
+// date grava a data no formato DDMMAAAA (CNAB 240) ou DDMMAA (CNAB 400), conforme o tamanho do campo
+func (r record) date(start, end int, value time.Time) {
+	if value.IsZero() {
+		r.numeric(start, end, "")
+		return
+	}
+
+	if end-start+1 == 6 {
+		r.numeric(start, end, value.Format("020106"))
+		return
+	}
+	r.numeric(start, end, value.Format("02012006"))
+}
+
+func (r record) String() string {
+	return string(r)
+}
+
+// line é uma linha lida de um arquivo de retorno
+type line struct {
+	number int
+	text   string
+}
+
+func (l line) field(start, end int) string {
+	return strings.TrimSpace(l.text[start-1 : end])
+}
+
+func (l line) amount(start, end int, name string) (float64, error) {
+	value := l.field(start, end)
+	if value == "" {
+		return 0, nil
+	}
+
+	cents, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return float64(cents) / 100, nil
+}
+
+// date lê DDMMAAAA ou DDMMAA; datas zeradas ou em branco retornam o valor zero
+func (l line) date(start, end int, name string) (time.Time, error) {
+	value := l.field(start, end)
+	if value == "" || strings.Trim(value, "0") == "" {
+		return time.Time{}, nil
+	}
+
+	layout := "02012006"
+	if len(value) == 6 {
+		layout = "020106"
+	}
+
+	date, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return date, nil
+}
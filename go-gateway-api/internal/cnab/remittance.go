@@ -0,0 +1,370 @@
+package cnab
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// lineBreak separa os registros; os bancos esperam CRLF
+const lineBreak = "\r\n"
+
+// Códigos de forma de lançamento dos pagamentos (CNAB 240, header de lote)
+const (
+	paymentMethodAccountCredit = "01" // crédito em conta no próprio banco
+	paymentMethodTED           = "41" // TED para outra titularidade
+)
+
+// BoletoRemittance gera a remessa de registro dos boletos no leiaute informado
+func BoletoRemittance(layout domain.CNABLayout, issuer domain.BoletoIssuer, sequence int64, boletos []*domain.Boleto, now time.Time) (string, error) {
+	switch layout {
+	case domain.CNABLayout240:
+		return boletoRemittance240(issuer, sequence, boletos, now), nil
+	case domain.CNABLayout400:
+		return boletoRemittance400(issuer, sequence, boletos, now), nil
+	default:
+		return "", domain.ErrInvalidCNABLayout
+	}
+}
+
+// PayoutRemittance gera a remessa de pagamentos (CNAB 240), com um lote por forma de lançamento
+func PayoutRemittance(issuer domain.BoletoIssuer, sequence int64, entries []domain.PayoutRemittanceEntry, now time.Time) string {
+	batches := map[string][]domain.PayoutRemittanceEntry{}
+	for _, entry := range entries {
+		method := paymentMethodTED
+		if entry.Destination.BankAccount.BankCode == issuer.BankCode {
+			method = paymentMethodAccountCredit
+		}
+		batches[method] = append(batches[method], entry)
+	}
+
+	records := []record{fileHeader240(issuer, sequence, now)}
+	batchNumber := 0
+	for _, method := range []string{paymentMethodAccountCredit, paymentMethodTED} {
+		if len(batches[method]) == 0 {
+			continue
+		}
+		batchNumber++
+		records = append(records, paymentBatch240(issuer, batchNumber, method, batches[method], now)...)
+	}
+	records = append(records, fileTrailer240(issuer, batchNumber, len(records)+1))
+
+	return join(records)
+}
+
+func boletoRemittance240(issuer domain.BoletoIssuer, sequence int64, boletos []*domain.Boleto, now time.Time) string {
+	records := []record{fileHeader240(issuer, sequence, now)}
+
+	header := batchHeader240(issuer, 1)
+	header.alpha(9, 9, "R")
+	header.numeric(10, 11, "01") // cobrança
+	header.numeric(14, 16, "060")
+	header.number(184, 191, sequence) // número da remessa
+	header.date(192, 199, now)
+	batch := []record{header}
+
+	for _, boleto := range boletos {
+		batch = append(batch, segmentP(issuer, boleto, len(batch)), segmentQ(issuer, boleto, len(batch)+1))
+		if boleto.FinePercentage > 0 {
+			batch = append(batch, segmentR(issuer, boleto, len(batch)))
+		}
+	}
+
+	batch = append(batch, batchTrailer240(issuer, 1, len(batch)+1))
+	records = append(records, batch...)
+	records = append(records, fileTrailer240(issuer, 1, len(records)+1))
+
+	return join(records)
+}
+
+func paymentBatch240(issuer domain.BoletoIssuer, batchNumber int, method string, entries []domain.PayoutRemittanceEntry, now time.Time) []record {
+	header := batchHeader240(issuer, batchNumber)
+	header.alpha(9, 9, "C")
+	header.numeric(10, 11, "20") // pagamento a fornecedores
+	header.numeric(12, 13, method)
+	header.numeric(14, 16, "045")
+	batch := []record{header}
+
+	var total float64
+	for _, entry := range entries {
+		batch = append(batch, segmentA(issuer, batchNumber, len(batch), method, entry, now), segmentB(issuer, batchNumber, len(batch)+1, entry))
+		total += entry.Payout.Amount
+	}
+
+	trailer := batchTrailer240(issuer, batchNumber, len(batch)+1)
+	trailer.amount(24, 41, total)
+	return append(batch, trailer)
+}
+
+func fileHeader240(issuer domain.BoletoIssuer, sequence int64, now time.Time) record {
+	r := newRecord(240)
+	r.numeric(1, 3, issuer.BankCode)
+	r.numeric(4, 7, "0000")
+	r.numeric(8, 8, "0")
+	r.numeric(18, 18, inscriptionType(issuer.Document))
+	r.numeric(19, 32, issuer.Document)
+	r.numeric(53, 57, issuer.Agency)
+	r.numeric(59, 70, issuer.Account)
+	r.alpha(73, 102, issuer.Name)
+	r.numeric(143, 143, "1") // remessa
+	r.date(144, 151, now)
+	r.numeric(152, 157, now.Format("150405"))
+	r.number(158, 163, sequence)
+	r.numeric(164, 166, "103")
+	r.numeric(167, 171, "0")
+	return r
+}
+
+func batchHeader240(issuer domain.BoletoIssuer, batchNumber int) record {
+	r := newRecord(240)
+	r.numeric(1, 3, issuer.BankCode)
+	r.number(4, 7, int64(batchNumber))
+	r.numeric(8, 8, "1")
+	r.numeric(18, 18, inscriptionType(issuer.Document))
+	r.numeric(19, 33, issuer.Document)
+	r.numeric(54, 58, issuer.Agency)
+	r.numeric(60, 71, issuer.Account)
+	r.alpha(74, 103, issuer.Name)
+	return r
+}
+
+func detail240(issuer domain.BoletoIssuer, batchNumber, sequence int, segment string) record {
+	r := newRecord(240)
+	r.numeric(1, 3, issuer.BankCode)
+	r.number(4, 7, int64(batchNumber))
+	r.numeric(8, 8, "3")
+	r.number(9, 13, int64(sequence))
+	r.alpha(14, 14, segment)
+	return r
+}
+
+// segmentP traz os dados do título: nosso número, vencimento, valor e juros de mora
+func segmentP(issuer domain.BoletoIssuer, boleto *domain.Boleto, sequence int) record {
+	r := detail240(issuer, 1, sequence, "P")
+	r.numeric(16, 17, "01") // entrada de título
+	r.numeric(18, 22, issuer.Agency)
+	r.numeric(24, 35, issuer.Account)
+	r.alpha(38, 57, boleto.OurNumber)
+	r.numeric(58, 58, "1")                 // cobrança simples
+	r.numeric(59, 59, "1")                 // título registrado
+	r.numeric(60, 60, "2")                 // escritural
+	r.numeric(61, 61, "2")                 // boleto emitido pelo beneficiário
+	r.alpha(62, 62, "2")                   // distribuição pelo beneficiário
+	r.alpha(63, 77, boleto.InvoiceID[:15]) // número do documento
+	r.date(78, 85, boleto.DueDate)
+	r.amount(86, 100, boleto.Amount)
+	r.numeric(107, 108, "02") // duplicata mercantil
+	r.alpha(109, 109, "N")
+	r.date(110, 117, boleto.CreatedAt)
+	if boleto.InterestPercentage > 0 {
+		r.numeric(118, 118, "2") // taxa mensal
+		r.date(119, 126, boleto.DueDate.AddDate(0, 0, 1))
+		r.amount(127, 141, boleto.InterestPercentage)
+	} else {
+		r.numeric(118, 118, "3") // isento
+	}
+	r.numeric(142, 142, "0")
+	r.alpha(196, 220, boleto.ID)
+	r.numeric(221, 221, "3") // não protestar
+	r.numeric(224, 224, "1") // baixar após a data limite
+	r.number(225, 227, int64(boleto.PaymentLimitDate.Sub(boleto.DueDate).Hours()/24))
+	r.numeric(228, 229, "09") // real
+	return r
+}
+
+// segmentQ traz o pagador
+func segmentQ(issuer domain.BoletoIssuer, boleto *domain.Boleto, sequence int) record {
+	r := detail240(issuer, 1, sequence, "Q")
+	r.numeric(16, 17, "01")
+	r.numeric(18, 18, inscriptionType(boleto.Payer.Document))
+	r.numeric(19, 33, boleto.Payer.Document)
+	r.alpha(34, 73, boleto.Payer.Name)
+	return r
+}
+
+// segmentR traz a multa
+func segmentR(issuer domain.BoletoIssuer, boleto *domain.Boleto, sequence int) record {
+	r := detail240(issuer, 1, sequence, "R")
+	r.numeric(16, 17, "01")
+	r.numeric(66, 66, "2") // percentual
+	r.date(67, 74, boleto.DueDate.AddDate(0, 0, 1))
+	r.amount(75, 89, boleto.FinePercentage)
+	return r
+}
+
+// segmentA traz o favorecido, o "seu número" que identifica o saque no retorno e o valor
+func segmentA(issuer domain.BoletoIssuer, batchNumber, sequence int, method string, entry domain.PayoutRemittanceEntry, now time.Time) record {
+	account := entry.Destination.BankAccount
+
+	r := detail240(issuer, batchNumber, sequence, "A")
+	r.numeric(15, 15, "0")  // inclusão
+	r.numeric(16, 17, "00") // inclusão de registro detalhe liberado
+	if method == paymentMethodTED {
+		r.numeric(18, 20, "018")
+	} else {
+		r.numeric(18, 20, "000")
+	}
+	r.numeric(21, 23, account.BankCode)
+	r.numeric(24, 28, account.Branch)
+	number, checkDigit := splitCheckDigit(account.Number)
+	r.numeric(30, 41, number)
+	r.alpha(42, 42, checkDigit)
+	r.alpha(44, 73, account.HolderName)
+	r.alpha(74, 93, entry.Payout.ProviderReference)
+	r.date(94, 101, now)
+	r.alpha(102, 104, "BRL")
+	r.amount(120, 134, entry.Payout.Amount)
+	r.numeric(155, 162, "")
+	r.amount(163, 177, 0)
+	r.numeric(230, 230, "0")
+	return r
+}
+
+// segmentB traz o documento do favorecido
+func segmentB(issuer domain.BoletoIssuer, batchNumber, sequence int, entry domain.PayoutRemittanceEntry) record {
+	r := detail240(issuer, batchNumber, sequence, "B")
+	r.numeric(18, 18, inscriptionType(entry.Destination.BankAccount.HolderDocument))
+	r.numeric(19, 32, entry.Destination.BankAccount.HolderDocument)
+	return r
+}
+
+func batchTrailer240(issuer domain.BoletoIssuer, batchNumber, records int) record {
+	r := newRecord(240)
+	r.numeric(1, 3, issuer.BankCode)
+	r.number(4, 7, int64(batchNumber))
+	r.numeric(8, 8, "5")
+	r.number(18, 23, int64(records))
+	return r
+}
+
+func fileTrailer240(issuer domain.BoletoIssuer, batches, records int) record {
+	r := newRecord(240)
+	r.numeric(1, 3, issuer.BankCode)
+	r.numeric(4, 7, "9999")
+	r.numeric(8, 8, "9")
+	r.number(18, 23, int64(batches))
+	r.number(24, 29, int64(records))
+	return r
+}
+
+func boletoRemittance400(issuer domain.BoletoIssuer, sequence int64, boletos []*domain.Boleto, now time.Time) string {
+	header := newRecord(400)
+	header.numeric(1, 1, "0")
+	header.numeric(2, 2, "1")
+	header.alpha(3, 9, "REMESSA")
+	header.numeric(10, 11, "01")
+	header.alpha(12, 26, "COBRANCA")
+	header.numeric(27, 46, issuer.Agency+issuer.Account)
+	header.alpha(47, 76, issuer.Name)
+	header.numeric(77, 79, issuer.BankCode)
+	header.date(95, 100, now)
+	header.alpha(109, 110, "MX")
+	header.number(111, 117, sequence)
+	header.number(395, 400, 1)
+
+	records := []record{header}
+	for _, boleto := range boletos {
+		records = append(records, detail400(issuer, boleto, len(records)+1))
+	}
+
+	trailer := newRecord(400)
+	trailer.numeric(1, 1, "9")
+	trailer.number(395, 400, int64(len(records)+1))
+	records = append(records, trailer)
+
+	return join(records)
+}
+
+func detail400(issuer domain.BoletoIssuer, boleto *domain.Boleto, sequence int) record {
+	r := newRecord(400)
+	r.numeric(1, 1, "1")
+	r.numeric(2, 20, "")
+	r.numeric(21, 37, "0"+leftPad(issuer.Wallet, 3)+leftPad(issuer.Agency, 5)+leftPad(issuer.Account, 7)+"0")
+	r.alpha(38, 62, boleto.ID)
+	r.numeric(63, 65, "")
+	if boleto.FinePercentage > 0 {
+		r.numeric(66, 66, "2")
+		r.amount(67, 70, boleto.FinePercentage)
+	} else {
+		r.numeric(66, 70, "0")
+	}
+	r.numeric(71, 81, boleto.OurNumber)
+	r.alpha(82, 82, OurNumberCheckDigit(issuer.Wallet, boleto.OurNumber))
+	r.numeric(83, 92, "")
+	r.numeric(93, 93, "2") // boleto emitido pelo beneficiário
+	r.alpha(94, 94, "N")
+	r.numeric(109, 110, "01") // remessa
+	r.alpha(111, 120, boleto.InvoiceID[:10])
+	r.date(121, 126, boleto.DueDate)
+	r.amount(127, 139, boleto.Amount)
+	r.numeric(140, 147, "")
+	r.numeric(148, 149, "01") // duplicata
+	r.alpha(150, 150, "N")
+	r.date(151, 156, boleto.CreatedAt)
+	r.numeric(157, 160, "")
+	r.amount(161, 173, boleto.Amount*boleto.InterestPercentage/100/30) // juros por dia de atraso
+	r.numeric(174, 218, "")
+	r.numeric(219, 220, "0"+inscriptionType(boleto.Payer.Document))
+	r.numeric(221, 234, boleto.Payer.Document)
+	r.alpha(235, 274, boleto.Payer.Name)
+	r.number(395, 400, int64(sequence))
+	return r
+}
+
+// OurNumberCheckDigit é o dígito do nosso número no CNAB 400: módulo 11 da carteira mais o
+// nosso número com pesos de 2 a 7; resto 1 vira "P" e resto 0 vira "0"
+func OurNumberCheckDigit(wallet, ourNumber string) string {
+	digits := leftPad(wallet, 2) + ourNumber
+
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 7 {
+			weight = 2
+		}
+	}
+
+	switch remainder := sum % 11; remainder {
+	case 0:
+		return "0"
+	case 1:
+		return "P"
+	default:
+		return strconv.Itoa(11 - remainder)
+	}
+}
+
+// inscriptionType é 1 para CPF e 2 para CNPJ
+func inscriptionType(document string) string {
+	if len(document) == 11 {
+		return "1"
+	}
+	return "2"
+}
+
+// splitCheckDigit separa o dígito verificador do número da conta
+func splitCheckDigit(number string) (string, string) {
+	if len(number) < 2 {
+		return number, ""
+	}
+	return number[:len(number)-1], number[len(number)-1:]
+}
+
+func leftPad(value string, size int) string {
+	if len(value) >= size {
+		return value[len(value)-size:]
+	}
+	return strings.Repeat("0", size-len(value)) + value
+}
+
+func join(records []record) string {
+	var content strings.Builder
+	for _, r := range records {
+		content.WriteString(r.String())
+		content.WriteString(lineBreak)
+	}
+	return content.String()
+}
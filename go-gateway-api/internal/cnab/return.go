@@ -0,0 +1,231 @@
+package cnab
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// Ocorrências de cobrança (retorno CNAB 240 e 400)
+const (
+	BoletoOccurrenceRegistered        = "02" // entrada confirmada
+	BoletoOccurrenceRejected          = "03" // entrada rejeitada
+	BoletoOccurrencePaid              = "06" // liquidação
+	BoletoOccurrencePaidAfterWriteOff = "17" // liquidação após baixa
+)
+
+// Ocorrências de pagamento (retorno CNAB 240, segmento A)
+const (
+	PayoutOccurrencePaid      = "00" // crédito efetuado
+	PayoutOccurrenceScheduled = "BD" // inclusão efetuada com sucesso
+)
+
+// BoletoReturn é a ocorrência de um título no retorno de cobrança
+type BoletoReturn struct {
+	Line       int
+	OurNumber  string
+	Occurrence string
+	Reasons    string // motivos da ocorrência, quando informados pelo banco
+	PaidAmount float64
+	Date       time.Time // data da ocorrência
+}
+
+// PayoutReturn é a ocorrência de um pagamento no retorno de pagamentos
+type PayoutReturn struct {
+	Line        int
+	Reference   string // "seu número" enviado na remessa
+	Occurrences string // até cinco códigos de dois caracteres
+	Amount      float64
+	Date        time.Time
+}
+
+// Return é o conteúdo aproveitável de um arquivo de retorno
+type Return struct {
+	Layout   domain.CNABLayout
+	Kind     domain.CNABFileKind
+	BankCode string
+	Boletos  []BoletoReturn
+	Payouts  []PayoutReturn
+}
+
+// ParseReturn lê o arquivo de retorno. Linhas inválidas são reportadas individualmente e
+// não impedem a leitura das demais; ErrInvalidCNABFile indica que o arquivo não pôde ser
+// reconhecido como retorno.
+func ParseReturn(content string) (*Return, []domain.CNABLineError, error) {
+	var lines []line
+	for i, text := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		lines = append(lines, line{number: i + 1, text: text})
+	}
+
+	if len(lines) == 0 {
+		return nil, nil, domain.ErrInvalidCNABFile
+	}
+
+	switch len(lines[0].text) {
+	case 240:
+		return parseReturn240(lines)
+	case 400:
+		return parseReturn400(lines)
+	default:
+		return nil, nil, domain.ErrInvalidCNABFile
+	}
+}
+
+type lineErrors []domain.CNABLineError
+
+func (e *lineErrors) add(l line, format string, args ...any) {
+	*e = append(*e, domain.CNABLineError{Line: l.number, Message: fmt.Sprintf(format, args...)})
+}
+
+func parseReturn240(lines []line) (*Return, []domain.CNABLineError, error) {
+	header := lines[0]
+	if header.field(8, 8) != "0" || header.field(143, 143) != "2" {
+		return nil, nil, domain.ErrInvalidCNABFile
+	}
+
+	result := &Return{Layout: domain.CNABLayout240, BankCode: header.field(1, 3)}
+	var errors lineErrors
+	var pending *BoletoReturn // segmento T aguardando o segmento U
+
+	for _, l := range lines[1:] {
+		if len(l.text) != 240 {
+			errors.add(l, "line has %d characters, expected 240", len(l.text))
+			continue
+		}
+
+		switch l.field(8, 8) {
+		case "1":
+			if l.field(10, 11) == "01" {
+				result.Kind = domain.CNABFileKindBoleto
+			} else {
+				result.Kind = domain.CNABFileKindPayout
+			}
+		case "3":
+			switch l.field(14, 14) {
+			case "T":
+				if pending != nil {
+					errors.add(l, "segment T without the segment U of line %d", pending.Line)
+				}
+				pending = &BoletoReturn{
+					Line:       l.number,
+					OurNumber:  ourNumber(l.field(38, 57)),
+					Occurrence: l.field(16, 17),
+					Reasons:    l.field(214, 223),
+				}
+			case "U":
+				if pending == nil {
+					errors.add(l, "segment U without segment T")
+					continue
+				}
+
+				boleto := *pending
+				pending = nil
+
+				var err error
+				if boleto.PaidAmount, err = l.amount(78, 92, "paid amount"); err != nil {
+					errors.add(l, "%v", err)
+					continue
+				}
+				if boleto.Date, err = l.date(138, 145, "occurrence date"); err != nil {
+					errors.add(l, "%v", err)
+					continue
+				}
+				result.Boletos = append(result.Boletos, boleto)
+			case "A":
+				payout := PayoutReturn{
+					Line:        l.number,
+					Reference:   l.field(74, 93),
+					Occurrences: l.field(231, 240),
+				}
+
+				var err error
+				if payout.Amount, err = l.amount(120, 134, "payment amount"); err != nil {
+					errors.add(l, "%v", err)
+					continue
+				}
+				if payout.Date, err = l.date(155, 162, "payment date"); err != nil {
+					errors.add(l, "%v", err)
+					continue
+				}
+				if payout.Reference == "" {
+					errors.add(l, "missing payment reference")
+					continue
+				}
+				result.Payouts = append(result.Payouts, payout)
+			case "B", "P", "Q", "R", "Z":
+				// sem informação usada pelo gateway
+			default:
+				errors.add(l, "unknown segment %q", l.field(14, 14))
+			}
+		case "5", "9":
+		default:
+			errors.add(l, "unknown record type %q", l.field(8, 8))
+		}
+	}
+
+	if pending != nil {
+		errors = append(errors, domain.CNABLineError{Line: pending.Line, Message: "segment T without segment U"})
+	}
+
+	if result.Kind == "" {
+		return nil, nil, domain.ErrInvalidCNABFile
+	}
+
+	return result, errors, nil
+}
+
+func parseReturn400(lines []line) (*Return, []domain.CNABLineError, error) {
+	header := lines[0]
+	if header.field(1, 1) != "0" || header.field(2, 2) != "2" {
+		return nil, nil, domain.ErrInvalidCNABFile
+	}
+
+	result := &Return{Layout: domain.CNABLayout400, Kind: domain.CNABFileKindBoleto, BankCode: header.field(77, 79)}
+	var errors lineErrors
+
+	for _, l := range lines[1:] {
+		if len(l.text) != 400 {
+			errors.add(l, "line has %d characters, expected 400", len(l.text))
+			continue
+		}
+
+		switch l.field(1, 1) {
+		case "1":
+			boleto := BoletoReturn{
+				Line:       l.number,
+				OurNumber:  ourNumber(l.field(71, 81)),
+				Occurrence: l.field(109, 110),
+				Reasons:    l.field(319, 328),
+			}
+
+			var err error
+			if boleto.Date, err = l.date(111, 116, "occurrence date"); err != nil {
+				errors.add(l, "%v", err)
+				continue
+			}
+			if boleto.PaidAmount, err = l.amount(254, 266, "paid amount"); err != nil {
+				errors.add(l, "%v", err)
+				continue
+			}
+			result.Boletos = append(result.Boletos, boleto)
+		case "9":
+		default:
+			errors.add(l, "unknown record type %q", l.field(1, 1))
+		}
+	}
+
+	return result, errors, nil
+}
+
+// ourNumber normaliza o nosso número para os 11 dígitos gravados no boleto
+func ourNumber(value string) string {
+	if len(value) > 11 {
+		value = value[:11]
+	}
+	return leftPad(value, 11)
+}
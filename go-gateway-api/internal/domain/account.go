@@ -3,22 +3,34 @@ package domain
 import (
 	"crypto/rand"
 	"encoding/hex"
-	"sync"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type AccountStatus string
+
+const (
+	AccountStatusActive    AccountStatus = "active"
+	AccountStatusSuspended AccountStatus = "suspended" // bloqueada por um operador, pode ser reativada
+	AccountStatusClosed    AccountStatus = "closed"    // encerrada definitivamente
+)
 
 type Account struct {
-	ID        string
-	Name      string
-	Email     string
-	APIKey    string
-	Balance   float64
-	mu  	sync.RWMutex // Bloqueia a escrita concorrente de valor
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                 string
+	Name               string
+	Email              string
+	APIKey             string  // chave secreta, apenas para o servidor do lojista
+	PublishableKey     string  // chave pública do navegador, restrita à tokenização e ao checkout
+	Balance            float64 // saldo disponível para saques
+	PendingBalance     float64 // valores aprovados aguardando a data de liquidação
+	SettlementSchedule SettlementSchedule
+	Currency           Currency // moeda do saldo; faturas em outra moeda são convertidas para ela
+	Status             AccountStatus
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
 }
 
 func generateAPIKey() string {
@@ -27,24 +39,149 @@ func generateAPIKey() string {
 	return hex.EncodeToString(b)
 }
 
+// PublishableKeyPrefix distingue as chaves públicas das secretas, que são apenas hexadecimais
+const PublishableKeyPrefix = "pk_"
+
+func generatePublishableKey() string {
+	return PublishableKeyPrefix + generateAPIKey()
+}
+
+// IsPublishableKey indica se a chave enviada é a chave pública de uma conta
+func IsPublishableKey(key string) bool {
+	return strings.HasPrefix(key, PublishableKeyPrefix)
+}
+
 func NewAccount(name, email string) *Account {
 	account := &Account{
-		ID: uuid.New().String(),
-		Name:      name,
-		Email:     email,
-		Balance:  0.0,
-		APIKey:  generateAPIKey(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:                 uuid.New().String(),
+		Name:               name,
+		Email:              email,
+		Balance:            0.0,
+		APIKey:             generateAPIKey(),
+		PublishableKey:     generatePublishableKey(),
+		SettlementSchedule: DefaultSettlementSchedule,
+		Currency:           DefaultCurrency,
+		Status:             AccountStatusActive,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
 	}
 
 	return account
 }
 
-// criação de uma função que vai atuar como um "método" da classe Account
-func (a *Account) AddBalance(amount float64) {
-	a.mu.Lock()
-	defer a.mu.Unlock() // O defer vai rodar sempre por ultimo
-	a.Balance += amount
+// RotateAPIKey substitui a API key por uma nova
+func (a *Account) RotateAPIKey() {
+	a.APIKey = generateAPIKey()
+	a.UpdatedAt = time.Now()
+}
+
+// RotatePublishableKey substitui a chave pública; a anterior deixa de funcionar nos navegadores
+func (a *Account) RotatePublishableKey() {
+	a.PublishableKey = generatePublishableKey()
+	a.UpdatedAt = time.Now()
+}
+
+func (a *Account) IsActive() bool {
+	return a.Status == AccountStatusActive
+}
+
+func (a *Account) Suspend() error {
+	if a.Status != AccountStatusActive {
+		return ErrInvalidAccountStatus
+	}
+
+	a.Status = AccountStatusSuspended
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// Close encerra a conta definitivamente; só é permitido sem saldo disponível nem a liquidar
+func (a *Account) Close() error {
+	if a.Status == AccountStatusClosed {
+		return ErrInvalidAccountStatus
+	}
+
+	if a.Balance != 0 || a.PendingBalance != 0 {
+		return ErrNonZeroBalance
+	}
+
+	a.Status = AccountStatusClosed
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateSettlementSchedule altera o prazo de liquidação das próximas aprovações; recebíveis
+// já agendados mantêm a data original
+func (a *Account) UpdateSettlementSchedule(schedule SettlementSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	a.SettlementSchedule = schedule
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateProfile altera apenas os campos informados (nil mantém o valor atual)
+func (a *Account) UpdateProfile(name, email *string) error {
+	if name != nil {
+		trimmed := strings.TrimSpace(*name)
+		if trimmed == "" {
+			return ErrInvalidName
+		}
+		a.Name = trimmed
+	}
+
+	if email != nil {
+		address, err := mail.ParseAddress(strings.TrimSpace(*email))
+		if err != nil || address.Name != "" {
+			return ErrInvalidEmail
+		}
+		a.Email = address.Address
+	}
+
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+func (a *Account) Reactivate() error {
+	if a.Status != AccountStatusSuspended {
+		return ErrInvalidAccountStatus
+	}
+
+	a.Status = AccountStatusActive
 	a.UpdatedAt = time.Now()
-}
\ No newline at end of file
+	return nil
+}
+
+// BalanceAdjustment é um ajuste manual de saldo feito por um operador
+type BalanceAdjustment struct {
+	ID            string
+	AccountID     string
+	Amount        float64 // positivo credita, negativo debita
+	Reason        string
+	Operator      string
+	BalanceBefore float64
+	BalanceAfter  float64
+	CreatedAt     time.Time
+}
+
+func NewBalanceAdjustment(accountID string, amount float64, reason, operator string) (*BalanceAdjustment, error) {
+	if amount == 0 {
+		return nil, ErrInvalidAdjustmentAmount
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	return &BalanceAdjustment{
+		ID:        uuid.New().String(),
+		AccountID: accountID,
+		Amount:    amount,
+		Reason:    reason,
+		Operator:  operator,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Anticipation é a liquidação antecipada de recebíveis agendados: o valor bruto sai do saldo
+// pendente e o líquido (bruto menos o desconto) entra no saldo disponível
+type Anticipation struct {
+	ID             string
+	AccountID      string
+	MonthlyRate    float64 // taxa de desconto a.m.; 2.5 significa 2,5%
+	GrossAmount    float64
+	DiscountAmount float64
+	NetAmount      float64
+	Items          []*AnticipationItem
+	CreatedAt      time.Time
+}
+
+type AnticipationItem struct {
+	ReceivableID      string
+	InvoiceID         string
+	InstallmentNumber int
+	DueDate           time.Time
+	Amount            float64
+	Days              int // dias corridos entre a antecipação e o vencimento
+	Discount          float64
+	NetAmount         float64
+}
+
+// NewAnticipation calcula o desconto de cada recebível pro rata pelos dias até o vencimento
+// (taxa mensal / 30 por dia). Apenas recebíveis agendados da conta com vencimento futuro
+// podem ser antecipados.
+func NewAnticipation(accountID string, receivables []*Receivable, monthlyRate float64, now time.Time) (*Anticipation, error) {
+	if len(receivables) == 0 {
+		return nil, ErrNoReceivablesToAnticipate
+	}
+
+	if monthlyRate < 0 || monthlyRate > 100 {
+		return nil, ErrInvalidAnticipationRate
+	}
+
+	today := truncateToDate(now)
+	anticipation := &Anticipation{
+		ID:          uuid.New().String(),
+		AccountID:   accountID,
+		MonthlyRate: monthlyRate,
+		Items:       make([]*AnticipationItem, len(receivables)),
+		CreatedAt:   now,
+	}
+
+	for i, receivable := range receivables {
+		if receivable.AccountID != accountID {
+			return nil, ErrReceivableNotAnticipatable
+		}
+
+		days := int(math.Round(receivable.DueDate.Sub(today).Hours() / 24))
+		if receivable.Status != ReceivableStatusScheduled || days < 1 {
+			return nil, ErrReceivableNotAnticipatable
+		}
+
+		discount := roundCents(receivable.Amount * monthlyRate / 100 * float64(days) / 30)
+		discount = math.Min(discount, receivable.Amount)
+
+		anticipation.Items[i] = &AnticipationItem{
+			ReceivableID:      receivable.ID,
+			InvoiceID:         receivable.InvoiceID,
+			InstallmentNumber: receivable.InstallmentNumber,
+			DueDate:           receivable.DueDate,
+			Amount:            receivable.Amount,
+			Days:              days,
+			Discount:          discount,
+			NetAmount:         roundCents(receivable.Amount - discount),
+		}
+
+		anticipation.GrossAmount += receivable.Amount
+		anticipation.DiscountAmount += discount
+	}
+
+	anticipation.GrossAmount = roundCents(anticipation.GrossAmount)
+	anticipation.DiscountAmount = roundCents(anticipation.DiscountAmount)
+	anticipation.NetAmount = roundCents(anticipation.GrossAmount - anticipation.DiscountAmount)
+
+	return anticipation, nil
+}
+
+func (a *Anticipation) ReceivableIDs() []string {
+	ids := make([]string, len(a.Items))
+	for i, item := range a.Items {
+		ids[i] = item.ReceivableID
+	}
+	return ids
+}
+
+// LedgerEntries registra a movimentação da antecipação: o crédito bruto e o desconto na conta
+// e, quando configurada, a receita do desconto na conta da plataforma
+func (a *Anticipation) LedgerEntries(platformAccountID string) []*LedgerEntry {
+	entries := []*LedgerEntry{
+		NewLedgerEntry(a.AccountID, LedgerEntryAnticipationCredit, a.GrossAmount, a.ID, "Antecipação de recebíveis"),
+	}
+
+	if a.DiscountAmount > 0 {
+		entries = append(entries, NewLedgerEntry(a.AccountID, LedgerEntryAnticipationDiscount, -a.DiscountAmount, a.ID, "Desconto da antecipação"))
+
+		if platformAccountID != "" {
+			entries = append(entries, NewLedgerEntry(platformAccountID, LedgerEntryAnticipationRevenue, a.DiscountAmount, a.ID, "Receita de antecipação"))
+		}
+	}
+
+	return entries
+}
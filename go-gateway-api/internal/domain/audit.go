@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ActorType string
+
+const (
+	ActorTypeMerchant ActorType = "merchant" // lojista autenticado por API key
+	ActorTypeOperator ActorType = "operator" // operador interno autenticado por token
+	ActorTypeSystem   ActorType = "system"   // processos internos (consumer, jobs)
+	ActorTypeCustomer ActorType = "customer" // comprador no checkout, identificado pela sessão
+)
+
+// Actor identifica quem executou uma operação
+type Actor struct {
+	Type ActorType
+	ID   string
+	IP   string
+}
+
+func NewSystemActor(name string) Actor {
+	return Actor{Type: ActorTypeSystem, ID: name}
+}
+
+type AuditAction string
+
+const (
+	AuditActionAccountCreated            AuditAction = "account.created"
+	AuditActionAccountProfileUpdated     AuditAction = "account.profile_updated"
+	AuditActionAccountSuspended          AuditAction = "account.suspended"
+	AuditActionAccountReactivated        AuditAction = "account.reactivated"
+	AuditActionAccountClosed             AuditAction = "account.closed"
+	AuditActionAPIKeyCreated             AuditAction = "account.api_key_created"
+	AuditActionAPIKeyRotated             AuditAction = "account.api_key_rotated"
+	AuditActionPublishableKeyRotated     AuditAction = "account.publishable_key_rotated"
+	AuditActionSettlementScheduleUpdated AuditAction = "account.settlement_schedule_updated"
+	AuditActionFeeRulesUpdated           AuditAction = "account.fee_rules_updated"
+	AuditActionBalanceAdjusted           AuditAction = "account.balance_adjusted"
+	AuditActionInvoiceCreated            AuditAction = "invoice.created"
+	AuditActionInvoiceStatusChanged      AuditAction = "invoice.status_changed"
+	AuditActionReviewClaimed             AuditAction = "review.claimed"
+	AuditActionReviewReleased            AuditAction = "review.released"
+	AuditActionReviewDecided             AuditAction = "review.decided"
+	AuditActionPayoutDestinationAdded    AuditAction = "payout_destination.created"
+	AuditActionPayoutRequested           AuditAction = "payout.requested"
+	AuditActionPayoutStatusChanged       AuditAction = "payout.status_changed"
+	AuditActionAnticipationRequested     AuditAction = "anticipation.requested"
+	AuditActionCNABRemittanceGenerated   AuditAction = "cnab.remittance_generated"
+	AuditActionCNABReturnProcessed       AuditAction = "cnab.return_processed"
+	AuditActionFXRateOverridden          AuditAction = "fx.rate_overridden"
+	AuditActionFXRateOverrideRemoved     AuditAction = "fx.rate_override_removed"
+	AuditActionCustomerCreated           AuditAction = "customer.created"
+	AuditActionPaymentMethodAdded        AuditAction = "payment_method.created"
+	AuditActionPlanCreated               AuditAction = "plan.created"
+	AuditActionSubscriptionCreated       AuditAction = "subscription.created"
+	AuditActionSubscriptionUpdated       AuditAction = "subscription.updated"
+	AuditActionSubscriptionCanceled      AuditAction = "subscription.canceled"
+	AuditActionSubscriptionStatusChanged AuditAction = "subscription.status_changed"
+	AuditActionCheckoutSessionCreated    AuditAction = "checkout_session.created"
+	AuditActionPaymentLinkCreated        AuditAction = "payment_link.created"
+	AuditActionPaymentLinkDeactivated    AuditAction = "payment_link.deactivated"
+	AuditActionCouponCreated             AuditAction = "coupon.created"
+	AuditActionCouponDeactivated         AuditAction = "coupon.deactivated"
+	AuditActionReceiptSettingsUpdated    AuditAction = "receipt_settings.updated"
+)
+
+// AuditEvent é um registro imutável encadeado ao anterior por hash: alterar ou remover
+// qualquer evento invalida o hash de todos os seguintes
+type AuditEvent struct {
+	ID         string
+	Sequence   int64
+	Actor      Actor
+	Action     AuditAction
+	EntityType string
+	EntityID   string
+	Before     json.RawMessage // nil quando não há estado anterior
+	After      json.RawMessage
+	OccurredAt time.Time
+	PrevHash   string
+	Hash       string
+}
+
+func NewAuditEvent(actor Actor, action AuditAction, entityType, entityID string, before, after any) (*AuditEvent, error) {
+	beforeJSON, err := marshalAuditState(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterJSON, err := marshalAuditState(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditEvent{
+		ID:         uuid.New().String(),
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		// O Postgres guarda microssegundos; truncar mantém o hash reproduzível
+		OccurredAt: time.Now().UTC().Truncate(time.Microsecond),
+	}, nil
+}
+
+func marshalAuditState(state any) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
+
+// Chain posiciona o evento logo após o último evento gravado
+func (e *AuditEvent) Chain(prevSequence int64, prevHash string) {
+	e.Sequence = prevSequence + 1
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash()
+}
+
+func (e *AuditEvent) ComputeHash() string {
+	h := sha256.New()
+	for _, field := range []string{
+		e.PrevHash,
+		strconv.FormatInt(e.Sequence, 10),
+		e.ID,
+		string(e.Actor.Type),
+		e.Actor.ID,
+		e.Actor.IP,
+		string(e.Action),
+		e.EntityType,
+		e.EntityID,
+		string(e.Before),
+		string(e.After),
+		e.OccurredAt.UTC().Format(time.RFC3339Nano),
+	} {
+		// O tamanho antes de cada campo evita ambiguidade na concatenação
+		h.Write([]byte(strconv.Itoa(len(field))))
+		h.Write([]byte{':'})
+		h.Write([]byte(field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	ActorID    string
+	Action     string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
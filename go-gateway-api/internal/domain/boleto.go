@@ -0,0 +1,281 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentTypeBoleto identifica faturas pagas por boleto bancário, sem dados de cartão
+const PaymentTypeBoleto = "boleto"
+
+// Limites aceitos para multa e juros de mora, em percentual
+const (
+	maxBoletoFinePercentage     = 10
+	maxBoletoInterestPercentage = 10
+)
+
+// maxBoletoAmount é o maior valor representável nos 10 dígitos do código de barras
+const maxBoletoAmount = 99999999.99
+
+// boletoDueDateBase é a data-base do fator de vencimento da FEBRABAN; o fator volta a 1000
+// depois de chegar a 9999 (o que aconteceu em 22/02/2025)
+var boletoDueDateBase = time.Date(1997, time.October, 7, 0, 0, 0, 0, time.UTC)
+
+type BoletoStatus string
+
+const (
+	BoletoStatusRegistered BoletoStatus = "registered"
+	BoletoStatusPaid       BoletoStatus = "paid"
+	BoletoStatusExpired    BoletoStatus = "expired"
+)
+
+// BoletoIssuer é o beneficiário do boleto: a conta da plataforma no banco emissor
+type BoletoIssuer struct {
+	BankCode string // código do banco na compensação, 3 dígitos
+	Agency   string // 4 dígitos, sem o dígito verificador
+	Account  string // até 7 dígitos, sem o dígito verificador
+	Wallet   string // carteira de cobrança, 2 dígitos
+	Name     string
+	Document string // CNPJ do beneficiário
+}
+
+// BoletoPayer é o pagador informado na emissão
+type BoletoPayer struct {
+	Name     string
+	Document string // CPF ou CNPJ, apenas dígitos
+}
+
+// BoletoPayment é a liquidação informada pelo banco
+type BoletoPayment struct {
+	BankCode  string
+	OurNumber string
+	Amount    float64
+	PaidAt    time.Time
+}
+
+// Boleto é o boleto de uma fatura, identificado no banco pelo nosso número
+type Boleto struct {
+	ID                 string
+	InvoiceID          string
+	AccountID          string
+	BankCode           string
+	OurNumber          string // nosso número, 11 dígitos
+	Payer              BoletoPayer
+	Amount             float64
+	DueDate            time.Time
+	PaymentLimitDate   time.Time // último dia em que o banco aceita o pagamento
+	FinePercentage     float64   // multa cobrada uma vez após o vencimento
+	InterestPercentage float64   // juros de mora ao mês, cobrados pro rata por dia de atraso
+	Barcode            string    // 44 dígitos
+	DigitableLine      string    // 47 dígitos, sem formatação
+	Status             BoletoStatus
+	PaidAmount         float64   // zero até o pagamento
+	PaidAt             time.Time // zero até o pagamento
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// NewBoletoInvoice cria uma fatura de boleto aguardando o pagamento; não passa pela análise de fraude
+func NewBoletoInvoice(accountID string, amount float64, description string) (*Invoice, error) {
+	if amount > maxBoletoAmount {
+		return nil, ErrInvalidAmount
+	}
+
+	return newAwaitingPaymentInvoice(accountID, amount, description, PaymentTypeBoleto)
+}
+
+// NewBoleto monta o boleto da fatura e gera o código de barras e a linha digitável
+func NewBoleto(
+	invoice *Invoice,
+	issuer BoletoIssuer,
+	ourNumber int64,
+	payer BoletoPayer,
+	dueDate time.Time,
+	paymentLimitDays int,
+	finePercentage float64,
+	interestPercentage float64,
+	now time.Time,
+) (*Boleto, error) {
+	payer.Name = strings.TrimSpace(payer.Name)
+	if payer.Name == "" {
+		return nil, ErrInvalidPayerName
+	}
+
+	payer.Document = onlyDigits(payer.Document)
+	if !ValidTaxID(payer.Document) {
+		return nil, ErrInvalidTaxID
+	}
+
+	dueDate = truncateToDate(dueDate)
+	if dueDate.Before(truncateToDate(now)) {
+		return nil, ErrInvalidBoletoDueDate
+	}
+
+	if finePercentage < 0 || finePercentage > maxBoletoFinePercentage ||
+		interestPercentage < 0 || interestPercentage > maxBoletoInterestPercentage {
+		return nil, ErrInvalidBoletoCharges
+	}
+
+	boleto := &Boleto{
+		ID:                 uuid.New().String(),
+		InvoiceID:          invoice.ID,
+		AccountID:          invoice.AccountID,
+		BankCode:           issuer.BankCode,
+		OurNumber:          fmt.Sprintf("%011d", ourNumber),
+		Payer:              payer,
+		Amount:             invoice.TotalAmount,
+		DueDate:            dueDate,
+		PaymentLimitDate:   dueDate.AddDate(0, 0, paymentLimitDays),
+		FinePercentage:     finePercentage,
+		InterestPercentage: interestPercentage,
+		Status:             BoletoStatusRegistered,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	freeField := fmt.Sprintf("%04s%02s%s%07s0", issuer.Agency, issuer.Wallet, boleto.OurNumber, issuer.Account)
+	boleto.Barcode = BoletoBarcode(issuer.BankCode, dueDate, boleto.Amount, freeField)
+	boleto.DigitableLine = BoletoDigitableLine(boleto.Barcode)
+
+	return boleto, nil
+}
+
+// AmountDue é o valor a pagar na data informada, com multa e juros de mora após o vencimento
+func (b *Boleto) AmountDue(date time.Time) float64 {
+	daysLate := int(truncateToDate(date).Sub(b.DueDate).Hours() / 24)
+	if daysLate <= 0 {
+		return b.Amount
+	}
+
+	fine := b.Amount * b.FinePercentage / 100
+	interest := b.Amount * b.InterestPercentage / 100 / 30 * float64(daysLate)
+	return roundCents(b.Amount + fine + interest)
+}
+
+// Pay registra a liquidação informada pelo banco. O banco calcula os encargos de atraso,
+// então o pagamento é aceito quando cobre ao menos o valor do documento.
+func (b *Boleto) Pay(amount float64, paidAt time.Time) error {
+	if b.Status == BoletoStatusPaid {
+		return ErrBoletoAlreadyPaid
+	}
+
+	if b.Status == BoletoStatusExpired || truncateToDate(paidAt).After(b.PaymentLimitDate) {
+		return ErrBoletoExpired
+	}
+
+	if roundCents(amount) < roundCents(b.Amount) {
+		return ErrBoletoAmountMismatch
+	}
+
+	b.Status = BoletoStatusPaid
+	b.PaidAmount = roundCents(amount)
+	b.PaidAt = paidAt
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+// LateCharges é a multa mais os juros pagos, zero antes do pagamento ou se pago em dia
+func (b *Boleto) LateCharges() float64 {
+	if b.Status != BoletoStatusPaid {
+		return 0
+	}
+	return roundCents(b.PaidAmount - b.Amount)
+}
+
+// Expire encerra o boleto que não foi pago até a data limite
+func (b *Boleto) Expire() error {
+	if b.Status != BoletoStatusRegistered {
+		return ErrInvalidStatus
+	}
+
+	b.Status = BoletoStatusExpired
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+// FormattedDigitableLine separa os campos da linha digitável como impresso no boleto
+func (b *Boleto) FormattedDigitableLine() string {
+	line := b.DigitableLine
+	if len(line) != 47 {
+		return line
+	}
+
+	return line[0:5] + "." + line[5:10] + " " +
+		line[10:15] + "." + line[15:21] + " " +
+		line[21:26] + "." + line[26:32] + " " +
+		line[32:33] + " " +
+		line[33:47]
+}
+
+// BoletoBarcode monta o código de barras de 44 dígitos do padrão FEBRABAN: banco, moeda (9),
+// dígito verificador geral, fator de vencimento, valor e os 25 dígitos do campo livre do banco
+func BoletoBarcode(bankCode string, dueDate time.Time, amount float64, freeField string) string {
+	withoutCheckDigit := fmt.Sprintf("%03s9%04d%010d%s",
+		bankCode,
+		BoletoDueDateFactor(dueDate),
+		int64(math.Round(amount*100)),
+		freeField,
+	)
+
+	return withoutCheckDigit[:4] + strconv.Itoa(boletoBarcodeCheckDigit(withoutCheckDigit)) + withoutCheckDigit[4:]
+}
+
+// BoletoDigitableLine converte o código de barras na linha digitável de 47 dígitos; os três
+// primeiros campos têm dígito verificador próprio (módulo 10)
+func BoletoDigitableLine(barcode string) string {
+	field1 := barcode[0:4] + barcode[19:24]
+	field2 := barcode[24:34]
+	field3 := barcode[34:44]
+
+	return field1 + strconv.Itoa(mod10(field1)) +
+		field2 + strconv.Itoa(mod10(field2)) +
+		field3 + strconv.Itoa(mod10(field3)) +
+		barcode[4:5] +
+		barcode[5:19]
+}
+
+// BoletoDueDateFactor é o número de dias entre a data-base e o vencimento, de 1000 a 9999
+func BoletoDueDateFactor(dueDate time.Time) int {
+	days := int(truncateToDate(dueDate).Sub(boletoDueDateBase).Hours() / 24)
+	if days < 1000 {
+		return days
+	}
+	return (days-1000)%9000 + 1000
+}
+
+// boletoBarcodeCheckDigit é o módulo 11 com pesos de 2 a 9 da direita para a esquerda;
+// resultados 0, 10 e 11 viram 1
+func boletoBarcodeCheckDigit(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	digit := 11 - sum%11
+	if digit == 0 || digit == 10 || digit == 11 {
+		return 1
+	}
+	return digit
+}
+
+// mod10 multiplica os dígitos alternadamente por 2 e 1 da direita para a esquerda, somando os
+// algarismos dos produtos
+func mod10(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		product := int(digits[i]-'0') * weight
+		sum += product/10 + product%10
+		weight = 3 - weight
+	}
+
+	return (10 - sum%10) % 10
+}
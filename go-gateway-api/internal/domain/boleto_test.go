@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoletoBarcodeAndDigitableLine(t *testing.T) {
+	// Exemplo das especificações de boleto do Banco do Brasil (padrão FEBRABAN)
+	tests := []struct {
+		name          string
+		bankCode      string
+		dueDate       time.Time
+		amount        float64
+		freeField     string
+		barcode       string
+		digitableLine string
+		formatted     string
+	}{
+		{
+			name:          "Banco do Brasil",
+			bankCode:      "001",
+			dueDate:       time.Date(2007, time.December, 31, 0, 0, 0, 0, time.UTC),
+			amount:        1,
+			freeField:     "0500940144816060680935031",
+			barcode:       "00193373700000001000500940144816060680935031",
+			digitableLine: "00190500954014481606906809350314337370000000100",
+			formatted:     "00190.50095 40144.816069 06809.350314 3 37370000000100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barcode := BoletoBarcode(tt.bankCode, tt.dueDate, tt.amount, tt.freeField)
+			if barcode != tt.barcode {
+				t.Fatalf("BoletoBarcode() = %s, want %s", barcode, tt.barcode)
+			}
+
+			boleto := &Boleto{DigitableLine: BoletoDigitableLine(barcode)}
+			if boleto.DigitableLine != tt.digitableLine {
+				t.Errorf("BoletoDigitableLine() = %s, want %s", boleto.DigitableLine, tt.digitableLine)
+			}
+			if got := boleto.FormattedDigitableLine(); got != tt.formatted {
+				t.Errorf("FormattedDigitableLine() = %s, want %s", got, tt.formatted)
+			}
+		})
+	}
+}
+
+func TestBoletoDueDateFactor(t *testing.T) {
+	tests := []struct {
+		dueDate string
+		want    int
+	}{
+		{dueDate: "2000-07-03", want: 1000},
+		{dueDate: "2007-12-31", want: 3737},
+		{dueDate: "2025-02-21", want: 9999},
+		// Fator reiniciado pela FEBRABAN em 22/02/2025
+		{dueDate: "2025-02-22", want: 1000},
+		{dueDate: "2025-02-23", want: 1001},
+		{dueDate: "2049-10-13", want: 9999},
+		{dueDate: "2049-10-14", want: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dueDate, func(t *testing.T) {
+			dueDate, err := time.Parse(time.DateOnly, tt.dueDate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := BoletoDueDateFactor(dueDate); got != tt.want {
+				t.Errorf("BoletoDueDateFactor(%s) = %d, want %d", tt.dueDate, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "strings"
+
+type CardBrand string
+
+const (
+	CardBrandVisa       CardBrand = "visa"
+	CardBrandMastercard CardBrand = "mastercard"
+	CardBrandAmex       CardBrand = "amex"
+	CardBrandElo        CardBrand = "elo"
+	CardBrandHipercard  CardBrand = "hipercard"
+	CardBrandOther      CardBrand = "other"
+)
+
+// Prefixos de BIN da Elo; precisam ser verificados antes de Visa e Mastercard, que
+// compartilham os mesmos dígitos iniciais
+var eloPrefixes = []string{
+	"401178", "401179", "431274", "438935", "451416", "457393", "457631", "457632",
+	"504175", "506699", "5067", "509", "627780", "636297", "636368", "650", "6516", "6550",
+}
+
+// DetectCardBrand identifica a bandeira pelo BIN (primeiros dígitos do cartão)
+func DetectCardBrand(number string) CardBrand {
+	number = onlyDigits(number)
+
+	for _, prefix := range eloPrefixes {
+		if strings.HasPrefix(number, prefix) {
+			return CardBrandElo
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(number, "606282"), strings.HasPrefix(number, "3841"):
+		return CardBrandHipercard
+	case strings.HasPrefix(number, "34"), strings.HasPrefix(number, "37"):
+		return CardBrandAmex
+	case strings.HasPrefix(number, "4"):
+		return CardBrandVisa
+	case len(number) >= 2 && number[:2] >= "51" && number[:2] <= "55":
+		return CardBrandMastercard
+	case len(number) >= 4 && number[:4] >= "2221" && number[:4] <= "2720":
+		return CardBrandMastercard
+	default:
+		return CardBrandOther
+	}
+}
+
+// Valid indica se a bandeira é uma das conhecidas
+func (b CardBrand) Valid() bool {
+	switch b {
+	case CardBrandVisa, CardBrandMastercard, CardBrandAmex, CardBrandElo, CardBrandHipercard, CardBrandOther:
+		return true
+	default:
+		return false
+	}
+}
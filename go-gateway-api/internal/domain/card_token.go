@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+)
+
+// CardToken guarda por poucos minutos o cartão digitado no navegador, para que o número e o
+// CVV cheguem ao pagamento sem passar pelo servidor do lojista. O token é de uso único e o
+// cartão fica cifrado pelo service em SealedCard, apagado no uso.
+type CardToken struct {
+	ID              string
+	AccountID       string
+	CardBrand       CardBrand
+	CardLastDigits  string
+	ExpirationMonth int
+	ExpirationYear  int
+	CardholderName  string
+	SealedCard      []byte // número e CVV cifrados, nil depois do uso
+	ExpiresAt       time.Time
+	UsedAt          time.Time // zero enquanto o token não for usado
+	CreatedAt       time.Time
+}
+
+// NewCardToken valida o cartão; o número e o CVV são cifrados pelo service
+func NewCardToken(accountID string, card CreditCard, ttl time.Duration, now time.Time) (*CardToken, error) {
+	number, holder, err := normalizeCard(card)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(card.CVV) < 3 || len(card.CVV) > 4 || onlyDigits(card.CVV) != card.CVV {
+		return nil, ErrInvalidCard
+	}
+
+	if cardExpired(card.ExpirationMonth, card.ExpirationYear, now) {
+		return nil, ErrCardExpired
+	}
+
+	return &CardToken{
+		ID:              generatePublicID("tok_"),
+		AccountID:       accountID,
+		CardBrand:       DetectCardBrand(number),
+		CardLastDigits:  number[len(number)-4:],
+		ExpirationMonth: card.ExpirationMonth,
+		ExpirationYear:  card.ExpirationYear,
+		CardholderName:  holder,
+		ExpiresAt:       now.Add(ttl),
+		CreatedAt:       now,
+	}, nil
+}
+
+// Use consome o token da conta; tokens de outras contas não são encontrados
+func (t *CardToken) Use(accountID string, now time.Time) error {
+	if t.AccountID != accountID {
+		return ErrCardTokenNotFound
+	}
+
+	if !t.UsedAt.IsZero() {
+		return ErrCardTokenUsed
+	}
+
+	if !now.Before(t.ExpiresAt) {
+		return ErrCardTokenExpired
+	}
+
+	t.UsedAt = now
+	return nil
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type CheckoutSessionStatus string
+
+const (
+	CheckoutSessionStatusOpen       CheckoutSessionStatus = "open"       // aguardando o pagamento do comprador
+	CheckoutSessionStatusProcessing CheckoutSessionStatus = "processing" // pagamento em andamento, impede cobranças duplicadas
+	CheckoutSessionStatusCompleted  CheckoutSessionStatus = "completed"  // a fatura foi criada e não foi recusada
+	CheckoutSessionStatusFailed     CheckoutSessionStatus = "failed"     // encerrada após pagamentos recusados demais
+	CheckoutSessionStatusExpired    CheckoutSessionStatus = "expired"    // apenas na saída: aberta ou em pagamento além do prazo
+)
+
+// CheckoutSession é um pagamento preparado pelo lojista e concluído pelo comprador no
+// navegador. O ID é aleatório e público: quem o conhece pode pagar a sessão, mas não consulta
+// nada além dela.
+type CheckoutSession struct {
+	ID             string
+	AccountID      string
+	PaymentLinkID  string // link de pagamento que abriu a sessão, vazio nas criadas pela API
+	Amount         float64
+	Currency       Currency
+	Description    string
+	SuccessURL     string // para onde o comprador volta depois de pagar
+	CancelURL      string // para onde o comprador volta se desistir, opcional
+	Status         CheckoutSessionStatus
+	InvoiceID      string // fatura do pagamento, vazio até a sessão ser concluída
+	FailedAttempts int    // pagamentos recusados ou que não geraram fatura
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func generatePublicID(prefix string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+func NewCheckoutSession(accountID string, amount float64, currency Currency, description, successURL, cancelURL string, ttl time.Duration, now time.Time) (*CheckoutSession, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	if !validRedirectURL(successURL) || (cancelURL != "" && !validRedirectURL(cancelURL)) {
+		return nil, ErrInvalidRedirectURL
+	}
+
+	return &CheckoutSession{
+		ID:          generatePublicID("cs_"),
+		AccountID:   accountID,
+		Amount:      roundCents(amount),
+		Currency:    currency,
+		Description: strings.TrimSpace(description),
+		SuccessURL:  successURL,
+		CancelURL:   cancelURL,
+		Status:      CheckoutSessionStatusOpen,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// validRedirectURL aceita apenas URLs absolutas http ou https, evitando redirecionar o
+// comprador para esquemas como javascript:
+func validRedirectURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	return parsed.Scheme == "https" || parsed.Scheme == "http"
+}
+
+// CurrentStatus é o status exibido: sessões abertas além do prazo aparecem como expiradas, assim
+// como as que ficaram em processing porque o pagamento não foi concluído (ex.: queda do processo)
+func (s *CheckoutSession) CurrentStatus(now time.Time) CheckoutSessionStatus {
+	if (s.Status == CheckoutSessionStatusOpen || s.Status == CheckoutSessionStatusProcessing) && !now.Before(s.ExpiresAt) {
+		return CheckoutSessionStatusExpired
+	}
+	return s.Status
+}
+
+// StartPayment reserva a sessão para o pagamento do comprador; apenas um pagamento por vez
+func (s *CheckoutSession) StartPayment(now time.Time) error {
+	switch s.CurrentStatus(now) {
+	case CheckoutSessionStatusOpen:
+	case CheckoutSessionStatusExpired:
+		return ErrCheckoutSessionExpired
+	case CheckoutSessionStatusFailed:
+		return ErrCheckoutSessionFailed
+	default:
+		return ErrCheckoutSessionNotOpen
+	}
+
+	s.Status = CheckoutSessionStatusProcessing
+	s.UpdatedAt = now
+	return nil
+}
+
+// FinishPayment conclui a sessão com a fatura criada. Uma fatura recusada, ou nenhuma quando
+// a criação falhou (invoiceID vazio), reabre a sessão para o comprador tentar outro cartão, até
+// maxAttempts tentativas; depois disso a sessão fica failed e não aceita novos pagamentos,
+// impedindo que o ID público seja usado para testar cartões.
+func (s *CheckoutSession) FinishPayment(invoiceID string, status Status, maxAttempts int, now time.Time) {
+	s.UpdatedAt = now
+	if invoiceID == "" || status == StatusRejected {
+		s.FailedAttempts++
+		if s.FailedAttempts >= maxAttempts {
+			s.Status = CheckoutSessionStatusFailed
+			return
+		}
+		s.Status = CheckoutSessionStatusOpen
+		return
+	}
+
+	s.Status = CheckoutSessionStatusCompleted
+	s.InvoiceID = invoiceID
+}
+
+// PaymentLink é um link reutilizável do lojista: cada acesso do comprador abre uma nova
+// sessão de checkout com os mesmos dados
+type PaymentLink struct {
+	ID          string
+	AccountID   string
+	Amount      float64
+	Currency    Currency
+	Description string
+	SuccessURL  string
+	CancelURL   string
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewPaymentLink(accountID string, amount float64, currency Currency, description, successURL, cancelURL string, now time.Time) (*PaymentLink, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	if !validRedirectURL(successURL) || (cancelURL != "" && !validRedirectURL(cancelURL)) {
+		return nil, ErrInvalidRedirectURL
+	}
+
+	return &PaymentLink{
+		ID:          generatePublicID("plink_"),
+		AccountID:   accountID,
+		Amount:      roundCents(amount),
+		Currency:    currency,
+		Description: strings.TrimSpace(description),
+		SuccessURL:  successURL,
+		CancelURL:   cancelURL,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// NewSession abre uma sessão de checkout com os dados do link
+func (l *PaymentLink) NewSession(ttl time.Duration, now time.Time) (*CheckoutSession, error) {
+	if !l.Active {
+		return nil, ErrPaymentLinkInactive
+	}
+
+	session, err := NewCheckoutSession(l.AccountID, l.Amount, l.Currency, l.Description, l.SuccessURL, l.CancelURL, ttl, now)
+	if err != nil {
+		return nil, err
+	}
+
+	session.PaymentLinkID = l.ID
+	return session, nil
+}
+
+// Deactivate impede novas sessões; as sessões já abertas continuam válidas até expirar
+func (l *PaymentLink) Deactivate(now time.Time) error {
+	if !l.Active {
+		return ErrPaymentLinkInactive
+	}
+
+	l.Active = false
+	l.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CNABLayout é o leiaute FEBRABAN do arquivo: 240 posições (cobrança e pagamentos) ou
+// 400 posições (apenas cobrança)
+type CNABLayout string
+
+const (
+	CNABLayout240 CNABLayout = "240"
+	CNABLayout400 CNABLayout = "400"
+)
+
+type CNABFileType string
+
+const (
+	CNABFileTypeRemittance CNABFileType = "remittance" // remessa gerada pelo gateway para o banco
+	CNABFileTypeReturn     CNABFileType = "return"     // retorno enviado pelo banco
+)
+
+// CNABFileKind é o serviço bancário do arquivo
+type CNABFileKind string
+
+const (
+	CNABFileKindBoleto CNABFileKind = "boleto" // cobrança
+	CNABFileKindPayout CNABFileKind = "payout" // pagamentos a fornecedores
+)
+
+type CNABFileStatus string
+
+const (
+	CNABFileStatusGenerated           CNABFileStatus = "generated"
+	CNABFileStatusProcessed           CNABFileStatus = "processed"
+	CNABFileStatusProcessedWithErrors CNABFileStatus = "processed_with_errors"
+)
+
+// CNABLineError é um problema em uma linha do arquivo de retorno, de leitura ou de aplicação.
+// Os erros são gravados junto com o arquivo, em JSON.
+type CNABLineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// CNABFile é um arquivo de remessa ou de retorno. Retornos são identificados pelo hash do
+// conteúdo, o que torna o reenvio do mesmo arquivo idempotente.
+type CNABFile struct {
+	ID          string
+	Type        CNABFileType
+	Kind        CNABFileKind
+	Layout      CNABLayout
+	Sequence    int64 // número sequencial da remessa (NSA), zero nos retornos
+	FileName    string
+	Checksum    string // SHA-256 do conteúdo
+	Content     string
+	Status      CNABFileStatus
+	RecordCount int // títulos ou pagamentos na remessa; registros aplicados no retorno
+	Errors      []CNABLineError
+	CreatedAt   time.Time
+}
+
+func (l CNABLayout) Valid() bool {
+	return l == CNABLayout240 || l == CNABLayout400
+}
+
+// NewCNABRemittance registra o arquivo de remessa gerado; o nome segue o padrão usual dos
+// bancos: prefixo do serviço, dia e mês e o sequencial
+func NewCNABRemittance(kind CNABFileKind, layout CNABLayout, sequence int64, content string, recordCount int, now time.Time) *CNABFile {
+	prefix := "CB"
+	if kind == CNABFileKindPayout {
+		prefix = "PG"
+	}
+
+	return &CNABFile{
+		ID:          uuid.New().String(),
+		Type:        CNABFileTypeRemittance,
+		Kind:        kind,
+		Layout:      layout,
+		Sequence:    sequence,
+		FileName:    fmt.Sprintf("%s%s%05d.REM", prefix, now.Format("0201"), sequence%100000),
+		Checksum:    cnabChecksum(content),
+		Content:     content,
+		Status:      CNABFileStatusGenerated,
+		RecordCount: recordCount,
+		Errors:      []CNABLineError{},
+		CreatedAt:   now,
+	}
+}
+
+// NewCNABReturn registra o arquivo de retorno recebido; o serviço, o leiaute e o resultado
+// são preenchidos pelo processamento
+func NewCNABReturn(fileName, content string, now time.Time) *CNABFile {
+	fileName = strings.TrimSpace(fileName)
+	if fileName == "" {
+		fileName = "retorno.ret"
+	}
+
+	return &CNABFile{
+		ID:        uuid.New().String(),
+		Type:      CNABFileTypeReturn,
+		FileName:  fileName,
+		Checksum:  cnabChecksum(content),
+		Content:   content,
+		Errors:    []CNABLineError{},
+		CreatedAt: now,
+	}
+}
+
+// Finish registra o resultado do processamento do retorno
+func (f *CNABFile) Finish(kind CNABFileKind, layout CNABLayout, recordCount int, errors []CNABLineError) {
+	f.Kind = kind
+	f.Layout = layout
+	f.RecordCount = recordCount
+	f.Errors = append(f.Errors[:0], errors...)
+
+	f.Status = CNABFileStatusProcessed
+	if len(f.Errors) > 0 {
+		f.Status = CNABFileStatusProcessedWithErrors
+	}
+}
+
+// PayoutRemittanceEntry é um saque incluído na remessa de pagamentos, com a conta de destino
+type PayoutRemittanceEntry struct {
+	Payout      *Payout
+	Destination *PayoutDestination
+}
+
+// CNABPayoutReference é o "seu número" do saque na remessa (20 posições), devolvido pelo
+// banco no retorno
+func CNABPayoutReference(payout *Payout) string {
+	return strings.ReplaceAll(payout.ID, "-", "")[:20]
+}
+
+func cnabChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
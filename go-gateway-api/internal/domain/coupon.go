@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Coupon é um desconto do lojista resgatado pelo código na emissão das faturas. Cada fatura
+// emitida com o cupom conta um resgate, mesmo que o pagamento seja recusado depois.
+type Coupon struct {
+	ID             string
+	AccountID      string
+	Code           string // único por conta, em maiúsculas
+	DiscountType   DiscountType
+	Value          float64
+	Currency       Currency // moeda do desconto fixo; vazio nos cupons percentuais
+	MaxRedemptions int      // zero é ilimitado
+	Redemptions    int
+	ExpiresAt      time.Time // zero quando o cupom não vence
+	Active         bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewCoupon(accountID, code string, discountType DiscountType, value float64, currency Currency, maxRedemptions int, expiresAt time.Time, now time.Time) (*Coupon, error) {
+	code = NormalizeCouponCode(code)
+	if !validCouponCode(code) || !validDiscount(discountType, value) || maxRedemptions < 0 {
+		return nil, ErrInvalidCoupon
+	}
+
+	if !expiresAt.IsZero() && !expiresAt.After(now) {
+		return nil, ErrInvalidCoupon
+	}
+
+	// Só o desconto fixo depende da moeda da fatura
+	if discountType == DiscountTypePercent {
+		currency = ""
+	} else if !currency.Valid() {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	return &Coupon{
+		ID:             uuid.New().String(),
+		AccountID:      accountID,
+		Code:           code,
+		DiscountType:   discountType,
+		Value:          roundCents(value),
+		Currency:       currency,
+		MaxRedemptions: maxRedemptions,
+		ExpiresAt:      expiresAt,
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// NormalizeCouponCode deixa o código como é gravado, para que o comprador possa digitá-lo em
+// minúsculas
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validCouponCode aceita de 3 a 40 letras, dígitos, - ou _
+func validCouponCode(code string) bool {
+	if len(code) < 3 || len(code) > 40 {
+		return false
+	}
+
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply gera o desconto de uma fatura na moeda informada. O limite de resgates é verificado
+// de novo ao gravar a fatura, o que evita ultrapassá-lo com faturas simultâneas.
+func (c *Coupon) Apply(currency Currency, now time.Time) (*Discount, error) {
+	if !c.Active {
+		return nil, ErrCouponInactive
+	}
+
+	if !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt) {
+		return nil, ErrCouponExpired
+	}
+
+	if c.MaxRedemptions > 0 && c.Redemptions >= c.MaxRedemptions {
+		return nil, ErrCouponRedemptionLimitReached
+	}
+
+	if c.DiscountType == DiscountTypeFixed && c.Currency != currency {
+		return nil, ErrCouponCurrencyMismatch
+	}
+
+	return &Discount{
+		Type:       c.DiscountType,
+		Value:      c.Value,
+		CouponID:   c.ID,
+		CouponCode: c.Code,
+	}, nil
+}
+
+// Deactivate impede novos resgates; as faturas já emitidas mantêm o desconto
+func (c *Coupon) Deactivate(now time.Time) error {
+	if !c.Active {
+		return ErrCouponInactive
+	}
+
+	c.Active = false
+	c.UpdatedAt = now
+	return nil
+}
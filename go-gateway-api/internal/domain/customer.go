@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customer é um cliente final do lojista, cobrado de forma recorrente pelas assinaturas
+type Customer struct {
+	ID           string
+	AccountID    string
+	Name         string
+	Email        string
+	Document     string // CPF ou CNPJ, apenas dígitos; opcional para clientes do exterior
+	DocumentHash string // HMAC do documento, preenchido pelo service
+	CreatedAt    time.Time
+}
+
+func NewCustomer(accountID, name, email, document string) (*Customer, error) {
+	customer := &Customer{
+		ID:        uuid.New().String(),
+		AccountID: accountID,
+		Name:      strings.TrimSpace(name),
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		Document:  onlyDigits(document),
+		CreatedAt: time.Now(),
+	}
+
+	if customer.Name == "" {
+		return nil, ErrInvalidPayerName
+	}
+
+	parsed, err := mail.ParseAddress(customer.Email)
+	if err != nil || parsed.Address != customer.Email {
+		return nil, ErrInvalidPayerEmail
+	}
+
+	if customer.Document != "" && !ValidTaxID(customer.Document) {
+		return nil, ErrInvalidTaxID
+	}
+
+	return customer, nil
+}
+
+// Payer identifica o cliente nas faturas das assinaturas; nil quando não há documento
+func (c *Customer) Payer() *Payer {
+	if c.Document == "" {
+		return nil
+	}
+
+	return &Payer{
+		Name:         c.Name,
+		Document:     c.Document,
+		DocumentHash: c.DocumentHash,
+		Email:        c.Email,
+	}
+}
+
+// PaymentMethod é um cartão salvo do cliente para as cobranças recorrentes. O número completo
+// e o CVV não são gravados: guardamos apenas o necessário para identificar o cartão e cobrar
+// a fatura (numa integração com o adquirente, o token do cartão ficaria aqui).
+type PaymentMethod struct {
+	ID              string
+	AccountID       string
+	CustomerID      string
+	CardBrand       CardBrand
+	CardLastDigits  string
+	ExpirationMonth int
+	ExpirationYear  int
+	CardholderName  string
+	CreatedAt       time.Time
+}
+
+func NewCardPaymentMethod(customer *Customer, card CreditCard, now time.Time) (*PaymentMethod, error) {
+	number, holder, err := normalizeCard(card)
+	if err != nil {
+		return nil, err
+	}
+
+	method := &PaymentMethod{
+		ID:              uuid.New().String(),
+		AccountID:       customer.AccountID,
+		CustomerID:      customer.ID,
+		CardBrand:       DetectCardBrand(number),
+		CardLastDigits:  number[len(number)-4:],
+		ExpirationMonth: card.ExpirationMonth,
+		ExpirationYear:  card.ExpirationYear,
+		CardholderName:  holder,
+		CreatedAt:       now,
+	}
+
+	if method.Expired(now) {
+		return nil, ErrCardExpired
+	}
+
+	return method, nil
+}
+
+// Expired indica se o cartão venceu
+func (m *PaymentMethod) Expired(now time.Time) bool {
+	return cardExpired(m.ExpirationMonth, m.ExpirationYear, now)
+}
+
+// normalizeCard confere o número (Luhn), o titular e a validade informados, retornando o
+// número apenas com dígitos e o titular sem espaços extras
+func normalizeCard(card CreditCard) (string, string, error) {
+	number := onlyDigits(card.Number)
+	if len(number) < 13 || len(number) > 19 || !validLuhn(number) {
+		return "", "", ErrInvalidCard
+	}
+
+	holder := strings.TrimSpace(card.CardHolderName)
+	if holder == "" || card.ExpirationMonth < 1 || card.ExpirationMonth > 12 || card.ExpirationYear < 2000 {
+		return "", "", ErrInvalidCard
+	}
+
+	return number, holder, nil
+}
+
+// cardExpired indica se o cartão venceu; ele vale até o último dia do mês de validade
+func cardExpired(month, year int, now time.Time) bool {
+	expiresAt := time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, now.Location())
+	return !now.Before(expiresAt)
+}
+
+// validLuhn confere o dígito verificador do número do cartão
+func validLuhn(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// DeclineReason explica por que uma cobrança de cartão não foi aprovada
+type DeclineReason string
+
+const (
+	DeclineReasonCardDeclined    DeclineReason = "card_declined"    // recusada pelo emissor
+	DeclineReasonCardExpired     DeclineReason = "card_expired"     // o cartão salvo venceu
+	DeclineReasonFraudSuspected  DeclineReason = "fraud_suspected"  // recusada pela análise de fraude
+	DeclineReasonProcessingError DeclineReason = "processing_error" // a análise de fraude não respondeu a tempo
+)
+
+func (r DeclineReason) Valid() bool {
+	return r == DeclineReasonCardDeclined || r == DeclineReasonCardExpired ||
+		r == DeclineReasonFraudSuspected || r == DeclineReasonProcessingError
+}
+
+// DunningPolicy define as novas tentativas de cobrança de uma assinatura em atraso. As
+// tentativas são contadas a partir da data de cobrança original, e recusas cujo motivo não
+// muda com o tempo (ex.: cartão vencido) encerram a cobrança sem novas tentativas.
+type DunningPolicy struct {
+	RetryDays      []int // dias após a data de cobrança de cada nova tentativa, em ordem crescente
+	NoRetryReasons map[DeclineReason]bool
+	// FinalStatus é o status da assinatura quando as tentativas acabam: canceled encerra a
+	// assinatura e unpaid a mantém sem cobranças até o cartão ser trocado
+	FinalStatus SubscriptionStatus
+}
+
+func DefaultDunningPolicy() DunningPolicy {
+	return DunningPolicy{
+		RetryDays: []int{1, 3, 7},
+		NoRetryReasons: map[DeclineReason]bool{
+			DeclineReasonCardExpired:    true,
+			DeclineReasonFraudSuspected: true,
+		},
+		FinalStatus: SubscriptionStatusCanceled,
+	}
+}
+
+// NextRetry retorna quando tentar de novo depois de failedAttempts cobranças recusadas (a
+// primeira é a cobrança original); false quando não há mais tentativas
+func (p DunningPolicy) NextRetry(failedAttempts int, reason DeclineReason, dueDate time.Time) (time.Time, bool) {
+	if p.NoRetryReasons[reason] || failedAttempts < 1 || failedAttempts > len(p.RetryDays) {
+		return time.Time{}, false
+	}
+
+	return dueDate.AddDate(0, 0, p.RetryDays[failedAttempts-1]), true
+}
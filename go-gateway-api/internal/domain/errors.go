@@ -9,4 +9,103 @@ var (
 	ErrUnauthorizedAccess = errors.New("unauthorized access") // retornado quando o acesso não é autorizado
 	ErrInvalidAmount = errors.New("amount must be greater than 0") // retornado quando o valor da fatura é inválido
 	ErrInvalidStatus = errors.New("invalid status") // retornado quando o status da fatura é inválido
-)
\ No newline at end of file
+	ErrEventAlreadyProcessed = errors.New("event already processed") // retornado quando um evento já foi aplicado anteriormente
+	ErrInvalidEvent = errors.New("invalid event") // retornado quando um evento recebido não respeita o schema
+	ErrInvoiceAlreadyClaimed = errors.New("invoice already claimed by another reviewer") // retornado quando outro analista possui o claim da fatura
+	ErrReviewClaimRequired = errors.New("invoice must be claimed before review") // retornado quando o analista decide sem possuir o claim
+	ErrReasonRequired = errors.New("reason is required") // retornado quando uma ação exige justificativa
+	ErrAccountSuspended = errors.New("account is suspended") // retornado quando uma conta suspensa tenta usar a API
+	ErrInvalidAccountStatus = errors.New("invalid account status") // retornado quando a transição de status da conta não é permitida
+	ErrInvalidAdjustmentAmount = errors.New("adjustment amount must not be zero") // retornado quando o ajuste de saldo é nulo
+	ErrInsufficientBalance = errors.New("insufficient balance") // retornado quando o saldo ficaria negativo
+	ErrAccountClosed = errors.New("account is closed") // retornado quando uma conta encerrada tenta usar a API
+	ErrNonZeroBalance = errors.New("account balance must be zero to close") // retornado ao encerrar uma conta com saldo
+	ErrDuplicatedEmail = errors.New("email already in use") // retornado quando o email já pertence a outra conta
+	ErrInvalidEmail = errors.New("invalid email") // retornado quando o email informado é inválido
+	ErrInvalidName = errors.New("name must not be empty") // retornado quando o nome informado é vazio
+	ErrInvalidPayoutDestination = errors.New("invalid payout destination") // retornado quando os dados da conta bancária ou chave Pix são inválidos
+	ErrPayoutDestinationNotFound = errors.New("payout destination not found") // retornado quando o destino não existe ou pertence a outra conta
+	ErrPayoutNotFound = errors.New("payout not found") // retornado quando um saque não é encontrado
+	ErrInvalidPayoutStatus = errors.New("invalid payout status") // retornado quando a transição de status do saque não é permitida
+	ErrInvalidFeeRule = errors.New("invalid fee rule") // retornado quando percentual, valor fixo ou faixa de parcelas da taxa são inválidos
+	ErrInvalidInstallments = errors.New("installments must be between 1 and 12 and only credit card payments can be split") // retornado quando o parcelamento é inválido
+	ErrInvalidInterestMode = errors.New("interest mode must be merchant or buyer") // retornado quando o modo de juros do parcelamento é desconhecido
+	ErrNoReceivablesToAnticipate = errors.New("no receivables available for anticipation") // retornado quando não há recebíveis futuros para antecipar
+	ErrReceivableNotAnticipatable = errors.New("receivable cannot be anticipated") // retornado quando o recebível não é da conta, já foi liquidado ou vence hoje
+	ErrInvalidAnticipationRate = errors.New("anticipation rate must be between 0 and 100") // retornado quando a taxa mensal de antecipação é inválida
+	ErrAnticipationNotFound = errors.New("anticipation not found") // retornado quando uma antecipação não é encontrada
+	ErrInvalidPixChargeType = errors.New("pix charge type must be static or dynamic") // retornado quando o tipo da cobrança Pix é desconhecido
+	ErrInvalidPixExpiration = errors.New("pix expiration must be between 1 minute and 30 days") // retornado quando o prazo da cobrança Pix é inválido
+	ErrPixKeyNotConfigured = errors.New("pix key not configured for static charges") // retornado quando uma cobrança estática é criada sem chave Pix configurada
+	ErrPixChargeNotFound = errors.New("pix charge not found") // retornado quando a cobrança Pix não é encontrada
+	ErrPixChargeExpired = errors.New("pix charge expired") // retornado quando o pagamento chega depois da expiração
+	ErrPixChargeAlreadyPaid = errors.New("pix charge already paid") // retornado quando a cobrança Pix já foi paga
+	ErrPixAmountMismatch = errors.New("pix amount does not match the charge") // retornado quando o valor pago difere do cobrado
+	ErrInvalidPixSignature = errors.New("invalid pix callback signature") // retornado quando a assinatura do callback do PSP não confere
+	ErrInvalidPayerName = errors.New("payer name is required") // retornado quando o nome do pagador não é informado
+	ErrInvalidTaxID = errors.New("invalid CPF/CNPJ") // retornado quando o documento do pagador é inválido
+	ErrInvalidPayerEmail = errors.New("invalid payer email") // retornado quando o e-mail do pagador é inválido
+	ErrInvalidBillingAddress = errors.New("billing address requires street, city, a two-letter state and an 8-digit postal code") // retornado quando o endereço de cobrança é incompleto
+	ErrInvalidBoletoDueDate = errors.New("boleto due date must be today or later") // retornado quando o vencimento do boleto já passou ou tem formato inválido
+	ErrInvalidBoletoCharges = errors.New("boleto fine and interest must be between 0 and 10 percent") // retornado quando a multa ou os juros do boleto são inválidos
+	ErrBoletoNotFound = errors.New("boleto not found") // retornado quando o boleto não é encontrado
+	ErrBoletoExpired = errors.New("boleto expired") // retornado quando o pagamento chega depois da data limite
+	ErrBoletoAlreadyPaid = errors.New("boleto already paid") // retornado quando o boleto já foi pago
+	ErrBoletoAmountMismatch = errors.New("boleto paid amount is lower than its amount") // retornado quando o valor pago não cobre o documento
+	ErrInvalidBoletoSignature = errors.New("invalid boleto callback signature") // retornado quando a assinatura do callback do banco não confere
+	ErrInvalidCNABLayout = errors.New("cnab layout must be 240 or 400, and payouts only support 240") // retornado quando o leiaute pedido não existe para o serviço
+	ErrInvalidCNABKind = errors.New("cnab kind must be boleto or payout") // retornado quando o serviço da remessa não existe
+	ErrInvalidCNABFile = errors.New("file is not a recognized cnab 240 or 400 return file") // retornado quando o cabeçalho do retorno não é reconhecido
+	ErrCNABFileNotFound = errors.New("cnab file not found") // retornado quando o arquivo CNAB não é encontrado
+	ErrDuplicateCNABFile = errors.New("cnab file already processed") // retornado quando o mesmo retorno é enviado de novo
+	ErrNothingToRemit = errors.New("no pending boletos or payouts to remit") // retornado quando não há o que incluir na remessa
+	ErrInvalidSettlementSchedule = errors.New("settlement delay must be between 0 and 365 days") // retornado quando o prazo de liquidação é inválido
+	ErrUnsupportedCurrency = errors.New("currency must be BRL, USD or EUR") // retornado quando a moeda da conta ou da fatura não é aceita
+	ErrCurrencyNotSupportedByPaymentType = errors.New("pix and boleto only accept BRL") // retornado quando uma fatura Pix ou boleto é criada em outra moeda
+	ErrExchangeRateNotFound = errors.New("exchange rate not available") // retornado quando não há cotação recente para o par de moedas
+	ErrInvalidExchangeRate = errors.New("exchange rate must be greater than zero") // retornado quando a cotação informada é inválida
+	ErrInvalidExchangeRateExpiration = errors.New("exchange rate override must expire within 30 days") // retornado quando a validade da cotação manual é inválida
+	ErrExchangeRateOverrideNotFound = errors.New("exchange rate override not found") // retornado quando não há cotação manual para o par de moedas
+	ErrInvalidCard = errors.New("invalid card number, expiration or holder name") // retornado quando o cartão salvo é inválido
+	ErrCardExpired = errors.New("card expired") // retornado quando o cartão salvo já venceu
+	ErrCustomerNotFound = errors.New("customer not found") // retornado quando o cliente não é encontrado
+	ErrPaymentMethodNotFound = errors.New("payment method not found") // retornado quando o cartão salvo não é encontrado ou é de outro cliente
+	ErrInvalidPlan = errors.New("plan requires a name, an interval of day, week, month or year (up to 12) and up to 365 trial days") // retornado quando os dados do plano são inválidos
+	ErrPlanNotFound = errors.New("plan not found") // retornado quando o plano não é encontrado
+	ErrSubscriptionNotFound = errors.New("subscription not found") // retornado quando a assinatura não é encontrada
+	ErrInvalidSubscriptionStatus = errors.New("operation not allowed in the current subscription status") // retornado quando a assinatura não está num status que permite a operação
+	ErrIncompatiblePlan = errors.New("new plan must be a different plan with the same currency and interval") // retornado quando a troca de plano não permite o cálculo proporcional
+	ErrSubscriptionConflict = errors.New("subscription was modified concurrently, try again") // retornado quando a assinatura foi alterada por outra operação
+	ErrInvalidRedirectURL = errors.New("success_url and cancel_url must be absolute http or https URLs") // retornado quando as URLs de retorno do checkout são inválidas
+	ErrCheckoutSessionNotFound = errors.New("checkout session not found") // retornado quando a sessão de checkout não é encontrada
+	ErrCheckoutSessionExpired = errors.New("checkout session expired") // retornado quando o comprador tenta pagar uma sessão vencida
+	ErrCheckoutSessionNotOpen = errors.New("checkout session already completed or being paid") // retornado quando a sessão já foi paga ou tem um pagamento em andamento
+	ErrPaymentLinkNotFound = errors.New("payment link not found") // retornado quando o link de pagamento não é encontrado
+	ErrPaymentLinkInactive = errors.New("payment link is inactive") // retornado quando o link de pagamento foi desativado
+	ErrPublishableKeyNotAllowed = errors.New("publishable keys can only be used for tokenization and checkout") // retornado quando a chave pública é usada numa rota do servidor
+	ErrInvalidPublishableKey = errors.New("a valid publishable key is required") // retornado quando a rota do navegador recebe uma chave secreta ou desconhecida
+	ErrDuplicatedPublishableKey = errors.New("publishable key already exists") // retornado quando a chave pública gerada colide com outra
+	ErrCardTokenNotFound = errors.New("card token not found") // retornado quando o token do cartão não existe ou é de outra conta
+	ErrCardTokenUsed = errors.New("card token already used") // retornado quando o token do cartão já pagou uma sessão
+	ErrCardTokenExpired = errors.New("card token expired") // retornado quando o token do cartão venceu antes do pagamento
+	ErrInvalidLineItem = errors.New("line items require a description, a quantity and a unit price greater than zero, up to 100 items") // retornado quando um item da fatura é inválido
+	ErrInvalidDiscount = errors.New("discount must be percent (up to 100) or fixed, greater than zero, and cannot be combined with a coupon") // retornado quando o desconto da fatura é inválido
+	ErrInvalidTaxLine = errors.New("tax lines require a name and either a rate or an amount greater than zero") // retornado quando um imposto da fatura é inválido
+	ErrLineItemsRequired = errors.New("discounts and taxes require line items") // retornado quando a fatura tem desconto ou impostos sem itens
+	ErrInvoiceAmountMismatch = errors.New("amount does not match the total of line items, discount and taxes") // retornado quando o valor informado difere do total calculado
+	ErrInvalidCoupon = errors.New("coupon requires a code of 3 to 40 letters, digits, - or _, a valid discount, non-negative max_redemptions and a future expiration") // retornado quando os dados do cupom são inválidos
+	ErrCouponNotFound = errors.New("coupon not found") // retornado quando o cupom não existe ou é de outra conta
+	ErrDuplicatedCouponCode = errors.New("coupon code already exists") // retornado quando a conta já tem um cupom com o código
+	ErrCouponInactive = errors.New("coupon is inactive") // retornado quando o cupom foi desativado
+	ErrCouponExpired = errors.New("coupon expired") // retornado quando o cupom venceu
+	ErrCouponRedemptionLimitReached = errors.New("coupon redemption limit reached") // retornado quando o cupom atingiu o limite de resgates
+	ErrCouponCurrencyMismatch = errors.New("fixed coupon currency does not match the invoice currency") // retornado quando o cupom de valor fixo é de outra moeda
+	ErrUnsupportedReceiptFormat = errors.New("receipt format must be html or pdf") // retornado quando o formato do recibo não é aceito
+	ErrInvalidReceiptSettings = errors.New("receipt colors must be #RRGGBB and the footer up to 500 characters") // retornado quando a personalização do recibo é inválida
+	ErrReceiptSettingsNotFound = errors.New("receipt settings not found") // retornado quando a conta usa a personalização padrão dos recibos
+	ErrInvalidReceiptLogo = errors.New("receipt logo must be a base64 PNG or JPEG up to 256 KB and 1000x1000 pixels") // retornado quando o logo do recibo é inválido
+	ErrPayoutDestinationNotSupported = errors.New("payout destination type not supported by the payout provider") // retornado quando o provedor de saques não paga esse tipo de destino (ex.: chave Pix com CNAB)
+	ErrCNABPayoutsDisabled = errors.New("payout remittances require PAYOUT_PROVIDER=cnab") // retornado quando os saques são enviados pelo PayoutProcessor e não por remessa
+	ErrCheckoutSessionFailed = errors.New("checkout session closed after too many failed payments") // retornado quando a sessão foi encerrada por pagamentos recusados demais
+	ErrPaymentLinkSessionLimit = errors.New("too many open checkout sessions for this payment link") // retornado quando o link de pagamento já tem o máximo de sessões abertas
+)
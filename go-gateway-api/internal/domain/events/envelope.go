@@ -0,0 +1,156 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/google/uuid"
+)
+
+// O envelope segue o formato CloudEvents 1.0: id é o event_id, source identifica o
+// produtor, time é o occurred_at e data carrega o payload. A versão do payload vai na
+// extensão dataversion.
+const (
+	SpecVersion     = "1.0"
+	Producer        = "go-gateway-api"
+	JSONContentType = "application/json"
+
+	// Content type usado quando o envelope inteiro vai no corpo da mensagem (structured mode)
+	CloudEventsContentType = "application/cloudevents+json"
+)
+
+// Headers do binding Kafka do CloudEvents (binary mode): os atributos vão nos headers
+// e o payload fica sozinho no corpo da mensagem
+const (
+	HeaderSpecVersion = "ce_specversion"
+	HeaderID          = "ce_id"
+	HeaderType        = "ce_type"
+	HeaderSource      = "ce_source"
+	HeaderTime        = "ce_time"
+	HeaderDataVersion = "ce_dataversion"
+	HeaderContentType = "content-type"
+)
+
+type Envelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Source          string          `json:"source"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataVersion     string          `json:"dataversion"`
+	Data            json.RawMessage `json:"data"`
+}
+
+func NewEnvelope(eventType, version string, payload any) (*Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		SpecVersion:     SpecVersion,
+		ID:              uuid.New().String(),
+		Type:            eventType,
+		Source:          Producer,
+		Time:            time.Now().UTC(),
+		DataContentType: JSONContentType,
+		DataVersion:     version,
+		Data:            data,
+	}, nil
+}
+
+func (e *Envelope) Validate() error {
+	if e.SpecVersion != SpecVersion {
+		return fmt.Errorf("%w: unsupported specversion %q", domain.ErrInvalidEvent, e.SpecVersion)
+	}
+	if e.ID == "" {
+		return fmt.Errorf("%w: missing id", domain.ErrInvalidEvent)
+	}
+	if e.Type == "" {
+		return fmt.Errorf("%w: missing type", domain.ErrInvalidEvent)
+	}
+	if e.Source == "" {
+		return fmt.Errorf("%w: missing source", domain.ErrInvalidEvent)
+	}
+	if e.DataVersion == "" {
+		return fmt.Errorf("%w: missing dataversion", domain.ErrInvalidEvent)
+	}
+	if len(e.Data) == 0 {
+		return fmt.Errorf("%w: missing data", domain.ErrInvalidEvent)
+	}
+	return nil
+}
+
+// BinaryHeaders retorna os atributos do envelope como headers do binary mode
+func (e *Envelope) BinaryHeaders() map[string]string {
+	return map[string]string{
+		HeaderSpecVersion: e.SpecVersion,
+		HeaderID:          e.ID,
+		HeaderType:        e.Type,
+		HeaderSource:      e.Source,
+		HeaderTime:        e.Time.Format(time.RFC3339Nano),
+		HeaderDataVersion: e.DataVersion,
+		HeaderContentType: e.DataContentType,
+	}
+}
+
+// ParseEnvelope identifica o formato da mensagem. Retorna nil (sem erro) quando a
+// mensagem está no formato antigo, sem envelope.
+func ParseEnvelope(headers map[string]string, value []byte) (*Envelope, error) {
+	if specVersion, ok := headers[HeaderSpecVersion]; ok {
+		envelope := &Envelope{
+			SpecVersion:     specVersion,
+			ID:              headers[HeaderID],
+			Type:            headers[HeaderType],
+			Source:          headers[HeaderSource],
+			DataContentType: headers[HeaderContentType],
+			DataVersion:     headers[HeaderDataVersion],
+			Data:            value,
+		}
+
+		if rawTime := headers[HeaderTime]; rawTime != "" {
+			occurredAt, err := time.Parse(time.RFC3339Nano, rawTime)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid time %q", domain.ErrInvalidEvent, rawTime)
+			}
+			envelope.Time = occurredAt
+		}
+
+		return envelope, envelope.Validate()
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(value, &probe); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidEvent, err)
+	}
+
+	if _, ok := probe["specversion"]; !ok {
+		return nil, nil
+	}
+
+	// Atributos de extensão do CloudEvents são permitidos no envelope e ignorados; a validação
+	// estrita vale apenas para o payload em data
+	var envelope Envelope
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidEvent, err)
+	}
+
+	return &envelope, envelope.Validate()
+}
+
+// decodeStrict rejeita campos desconhecidos e conteúdo extra após o JSON
+func decodeStrict(data []byte, target any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidEvent, err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("%w: unexpected data after payload", domain.ErrInvalidEvent)
+	}
+	return nil
+}
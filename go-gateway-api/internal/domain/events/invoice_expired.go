@@ -0,0 +1,28 @@
+package events
+
+import "time"
+
+const (
+	InvoiceExpiredEventType = "invoice_expired"
+	InvoiceExpiredVersion   = "1"
+)
+
+type InvoiceExpired struct {
+	AccountID       string    `json:"account_id"`
+	InvoiceID       string    `json:"invoice_id"`
+	Amount          float64   `json:"amount"`
+	PublishAttempts int       `json:"publish_attempts"`
+	PendingSince    time.Time `json:"pending_since"`
+	ExpiredAt       time.Time `json:"expired_at"`
+}
+
+func NewInvoiceExpired(accountID, invoiceID string, amount float64, publishAttempts int, pendingSince, expiredAt time.Time) *InvoiceExpired {
+	return &InvoiceExpired{
+		AccountID:       accountID,
+		InvoiceID:       invoiceID,
+		Amount:          amount,
+		PublishAttempts: publishAttempts,
+		PendingSince:    pendingSince,
+		ExpiredAt:       expiredAt,
+	}
+}
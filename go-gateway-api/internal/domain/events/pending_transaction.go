@@ -1,15 +1,49 @@
 package events
 
+import "github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+
+const (
+	PendingTransactionEventType = "pending_transaction"
+	PendingTransactionVersion   = "1"
+)
+
 type PendingTransaction struct {
-	AccountID string  `json:"account_id"`
-	InvoiceID string  `json:"invoice_id"`
-	Amount    float64 `json:"amount"`
+	AccountID string                   `json:"account_id"`
+	InvoiceID string                   `json:"invoice_id"`
+	Amount    float64                  `json:"amount"`
+	Currency  string                   `json:"currency"`
+	Payer     *PendingTransactionPayer `json:"payer,omitempty"` // ausente quando o pagador não foi identificado
+}
+
+// PendingTransactionPayer identifica o pagador para a análise de fraude sem expor o
+// documento: o hash permite cruzar transações do mesmo CPF/CNPJ
+type PendingTransactionPayer struct {
+	Name           string                 `json:"name"`
+	DocumentType   string                 `json:"document_type"` // cpf ou cnpj
+	DocumentHash   string                 `json:"document_hash"`
+	MaskedDocument string                 `json:"masked_document"`
+	Email          string                 `json:"email,omitempty"`
+	BillingAddress *domain.BillingAddress `json:"billing_address,omitempty"`
 }
 
-func NewPendingTransaction(accountID, invoiceID string, amount float64) *PendingTransaction {
-	return &PendingTransaction{
-		AccountID: accountID,
-		InvoiceID: invoiceID,
-		Amount:    amount,
+func NewPendingTransaction(invoice *domain.Invoice) *PendingTransaction {
+	event := &PendingTransaction{
+		AccountID: invoice.AccountID,
+		InvoiceID: invoice.ID,
+		Amount:    invoice.Amount,
+		Currency:  string(invoice.Currency),
 	}
-}
\ No newline at end of file
+
+	if payer := invoice.Payer; payer != nil {
+		event.Payer = &PendingTransactionPayer{
+			Name:           payer.Name,
+			DocumentType:   string(payer.DocumentType()),
+			DocumentHash:   payer.DocumentHash,
+			MaskedDocument: payer.MaskedDocument(),
+			Email:          payer.Email,
+			BillingAddress: payer.BillingAddress,
+		}
+	}
+
+	return event
+}
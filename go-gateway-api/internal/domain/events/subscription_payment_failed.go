@@ -0,0 +1,45 @@
+package events
+
+import "time"
+
+const (
+	SubscriptionPaymentFailedEventType = "subscription.payment_failed"
+	SubscriptionPaymentFailedVersion   = "1"
+)
+
+// SubscriptionPaymentFailed é publicado a cada cobrança de assinatura recusada ou expirada,
+// incluindo as novas tentativas
+type SubscriptionPaymentFailed struct {
+	AccountID      string     `json:"account_id"`
+	SubscriptionID string     `json:"subscription_id"`
+	CustomerID     string     `json:"customer_id"`
+	InvoiceID      string     `json:"invoice_id"`
+	Amount         float64    `json:"amount"`
+	Currency       string     `json:"currency"`
+	Attempt        int        `json:"attempt"`
+	DeclineReason  string     `json:"decline_reason"`
+	Status         string     `json:"status"`                  // status da assinatura depois da recusa
+	NextRetryAt    *time.Time `json:"next_retry_at,omitempty"` // nil quando não há nova tentativa
+	FailedAt       time.Time  `json:"failed_at"`
+}
+
+func NewSubscriptionPaymentFailed(accountID, subscriptionID, customerID, invoiceID string, amount float64, currency string, attempt int, declineReason, status string, nextRetryAt, failedAt time.Time) *SubscriptionPaymentFailed {
+	event := &SubscriptionPaymentFailed{
+		AccountID:      accountID,
+		SubscriptionID: subscriptionID,
+		CustomerID:     customerID,
+		InvoiceID:      invoiceID,
+		Amount:         amount,
+		Currency:       currency,
+		Attempt:        attempt,
+		DeclineReason:  declineReason,
+		Status:         status,
+		FailedAt:       failedAt,
+	}
+
+	if !nextRetryAt.IsZero() {
+		event.NextRetryAt = &nextRetryAt
+	}
+
+	return event
+}
@@ -1,8 +1,28 @@
 package events
 
-import "github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+import (
+	"fmt"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	TransactionResultEventType = "transaction_result"
+
+	// TransactionResultVersion é a versão atual do payload. Mensagens sem envelope
+	// (formato antigo) continuam aceitas durante a migração dos produtores.
+	TransactionResultVersion = "1"
+)
 
 type TransactionResult struct {
+	EventID   string `json:"event_id,omitempty"`
+	InvoiceID string `json:"invoice_id"`
+	Status    string `json:"status"`
+}
+
+// transactionResultV1 é o payload carregado no envelope, que já possui o id do evento
+type transactionResultV1 struct {
 	InvoiceID string `json:"invoice_id"`
 	Status    string `json:"status"`
 }
@@ -14,6 +34,74 @@ func NewTransactionResult(invoiceID string, status string) *TransactionResult {
 	}
 }
 
-func (t *TransactionResult) ToDomainStatus() domain.Status {
-	return domain.Status(t.Status)
-}
\ No newline at end of file
+// DecodeTransactionResult aceita tanto o envelope (binary ou structured mode) quanto o
+// payload antigo, validando estritamente o conteúdo
+func DecodeTransactionResult(headers map[string]string, value []byte) (*TransactionResult, error) {
+	envelope, err := ParseEnvelope(headers, value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result TransactionResult
+	if envelope == nil {
+		if err := decodeStrict(value, &result); err != nil {
+			return nil, err
+		}
+	} else {
+		if envelope.Type != TransactionResultEventType {
+			return nil, fmt.Errorf("%w: unexpected type %q", domain.ErrInvalidEvent, envelope.Type)
+		}
+
+		switch envelope.DataVersion {
+		case TransactionResultVersion:
+			var payload transactionResultV1
+			if err := decodeStrict(envelope.Data, &payload); err != nil {
+				return nil, err
+			}
+			result = TransactionResult{
+				EventID:   envelope.ID,
+				InvoiceID: payload.InvoiceID,
+				Status:    payload.Status,
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported version %q", domain.ErrInvalidEvent, envelope.DataVersion)
+		}
+	}
+
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (t *TransactionResult) Validate() error {
+	if _, err := uuid.Parse(t.InvoiceID); err != nil {
+		return fmt.Errorf("%w: invalid invoice_id %q", domain.ErrInvalidEvent, t.InvoiceID)
+	}
+
+	if _, err := t.ToDomainStatus(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ToDomainStatus só aceita os status finais que a análise de fraude pode produzir
+func (t *TransactionResult) ToDomainStatus() (domain.Status, error) {
+	switch status := domain.Status(t.Status); status {
+	case domain.StatusApproved, domain.StatusRejected:
+		return status, nil
+	default:
+		return "", fmt.Errorf("%w: unknown status %q", domain.ErrInvalidStatus, t.Status)
+	}
+}
+
+// IdempotencyKey identifica o evento para deduplicação. Mensagens sem event_id usam a
+// fatura como chave, já que cada fatura recebe um único resultado da análise de fraude.
+func (t *TransactionResult) IdempotencyKey() string {
+	if t.EventID != "" {
+		return t.EventID
+	}
+	return TransactionResultEventType + ":" + t.InvoiceID
+}
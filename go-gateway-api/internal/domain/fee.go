@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxInstallments é o maior número de parcelas aceito em uma venda
+const MaxInstallments = 12
+
+// FeeRule é uma taxa (MDR) de uma conta: percentual sobre o valor mais uma parte fixa.
+// CardBrand e PaymentType vazios valem para qualquer bandeira/forma de pagamento.
+type FeeRule struct {
+	ID              string
+	AccountID       string
+	CardBrand       CardBrand
+	PaymentType     string
+	MinInstallments int
+	MaxInstallments int
+	Percentage      float64 // 2.99 significa 2,99%
+	FixedAmount     float64
+	CreatedAt       time.Time
+}
+
+func NewFeeRule(accountID string, cardBrand CardBrand, paymentType string, minInstallments, maxInstallments int, percentage, fixedAmount float64) (*FeeRule, error) {
+	// Sem faixa informada, a regra vale para qualquer número de parcelas
+	if minInstallments == 0 && maxInstallments == 0 {
+		minInstallments, maxInstallments = 1, MaxInstallments
+	}
+
+	if minInstallments < 1 || maxInstallments < minInstallments || maxInstallments > MaxInstallments {
+		return nil, ErrInvalidFeeRule
+	}
+
+	if percentage < 0 || percentage > 100 || fixedAmount < 0 {
+		return nil, ErrInvalidFeeRule
+	}
+
+	if cardBrand != "" && !cardBrand.Valid() {
+		return nil, ErrInvalidFeeRule
+	}
+
+	return &FeeRule{
+		ID:              uuid.New().String(),
+		AccountID:       accountID,
+		CardBrand:       cardBrand,
+		PaymentType:     strings.TrimSpace(paymentType),
+		MinInstallments: minInstallments,
+		MaxInstallments: maxInstallments,
+		Percentage:      percentage,
+		FixedAmount:     fixedAmount,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+func (r *FeeRule) matches(cardBrand CardBrand, paymentType string, installments int) bool {
+	return (r.CardBrand == "" || r.CardBrand == cardBrand) &&
+		(r.PaymentType == "" || r.PaymentType == paymentType) &&
+		installments >= r.MinInstallments && installments <= r.MaxInstallments
+}
+
+// specificity prefere regras de bandeira, depois de forma de pagamento e, por último,
+// as de faixa de parcelas mais estreita
+func (r *FeeRule) specificity() int {
+	score := MaxInstallments - (r.MaxInstallments - r.MinInstallments)
+	if r.PaymentType != "" {
+		score += 100
+	}
+	if r.CardBrand != "" {
+		score += 200
+	}
+	return score
+}
+
+// FeeSchedule reúne as regras de uma conta; Default é usada quando nenhuma regra se aplica
+type FeeSchedule struct {
+	Rules   []*FeeRule
+	Default FeeRule
+}
+
+// Match retorna a regra mais específica para a transação
+func (s *FeeSchedule) Match(cardBrand CardBrand, paymentType string, installments int) *FeeRule {
+	var best *FeeRule
+	for _, rule := range s.Rules {
+		if rule.matches(cardBrand, paymentType, installments) && (best == nil || rule.specificity() > best.specificity()) {
+			best = rule
+		}
+	}
+
+	if best == nil {
+		return &s.Default
+	}
+	return best
+}
+
+// MDR calcula a taxa de desconto sobre o valor cobrado, já convertido para a moeda da conta;
+// o limite da soma das taxas é aplicado em Invoice.ApplyFees
+func (s *FeeSchedule) MDR(invoice *Invoice) *InvoiceFee {
+	rule := s.Match(invoice.CardBrand, invoice.PaymentType, invoice.Installments)
+
+	amount := roundCents(invoice.SettlementAmount*rule.Percentage/100 + rule.FixedAmount)
+
+	return &InvoiceFee{
+		ID:          uuid.New().String(),
+		InvoiceID:   invoice.ID,
+		AccountID:   invoice.AccountID,
+		Type:        FeeTypeMDR,
+		Description: "MDR " + strconv.FormatFloat(rule.Percentage, 'f', -1, 64) + "% + " + strconv.FormatFloat(rule.FixedAmount, 'f', 2, 64),
+		Percentage:  rule.Percentage,
+		FixedAmount: rule.FixedAmount,
+		Amount:      amount,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// InterestFee é a linha dos juros do parcelamento, que ficam com a plataforma que financia as
+// parcelas; nil quando não há juros
+func InterestFee(invoice *Invoice) *InvoiceFee {
+	if invoice.InterestAmount <= 0 {
+		return nil
+	}
+
+	description := "Juros de parcelamento em " + strconv.Itoa(invoice.Installments) + "x"
+	if invoice.InterestMode == InterestModeBuyer {
+		description += " (pagos pelo comprador)"
+	}
+
+	return &InvoiceFee{
+		ID:          uuid.New().String(),
+		InvoiceID:   invoice.ID,
+		AccountID:   invoice.AccountID,
+		Type:        FeeTypeInstallmentInterest,
+		Description: description,
+		Percentage:  invoice.InterestRate,
+		Amount:      invoice.ToSettlement(invoice.InterestAmount),
+		CreatedAt:   time.Now(),
+	}
+}
+
+// FXMarkupFee é o spread cobrado sobre o valor convertido quando a fatura é cobrada em outra
+// moeda; nil quando não há conversão ou spread
+func FXMarkupFee(invoice *Invoice, percentage float64) *InvoiceFee {
+	if invoice.Currency == invoice.SettlementCurrency || percentage <= 0 {
+		return nil
+	}
+
+	return &InvoiceFee{
+		ID:          uuid.New().String(),
+		InvoiceID:   invoice.ID,
+		AccountID:   invoice.AccountID,
+		Type:        FeeTypeFXMarkup,
+		Description: "Spread de câmbio " + string(invoice.Currency) + "/" + string(invoice.SettlementCurrency) + " " + strconv.FormatFloat(percentage, 'f', -1, 64) + "%",
+		Percentage:  percentage,
+		Amount:      roundCents(invoice.SettlementAmount * percentage / 100),
+		CreatedAt:   time.Now(),
+	}
+}
+
+type FeeType string
+
+const (
+	FeeTypeMDR                 FeeType = "mdr"                  // taxa de desconto do adquirente
+	FeeTypeInstallmentInterest FeeType = "installment_interest" // juros do parcelamento
+	FeeTypeFXMarkup            FeeType = "fx_markup"            // spread sobre a conversão de moeda
+)
+
+// InvoiceFee é uma linha de taxa cobrada sobre uma fatura aprovada; a receita vai para a
+// conta da plataforma
+type InvoiceFee struct {
+	ID          string
+	InvoiceID   string
+	AccountID   string
+	Type        FeeType
+	Description string
+	Percentage  float64
+	FixedAmount float64
+	Amount      float64
+	CreatedAt   time.Time
+}
+
+// InvoiceApproval reúne o que deve ser gravado junto com a aprovação de uma fatura
+type InvoiceApproval struct {
+	Fees              []*InvoiceFee
+	PlatformAccountID string // conta que recebe a receita das taxas; vazio não credita
+	Receivables       []*Receivable
+}
@@ -0,0 +1,113 @@
+package domain
+
+import "testing"
+
+func TestInvoiceApplyFeesCapsTotalAtSettlementAmount(t *testing.T) {
+	schedule := FeeSchedule{Default: FeeRule{Percentage: 2.99, FixedAmount: 0.49}}
+
+	tests := []struct {
+		name          string
+		invoice       *Invoice
+		markup        float64
+		wantFees      []float64
+		wantFeeAmount float64
+		wantNetAmount float64
+	}{
+		{
+			name: "fatura comum não é limitada",
+			invoice: &Invoice{
+				TotalAmount:        100,
+				Currency:           CurrencyBRL,
+				SettlementCurrency: CurrencyBRL,
+			},
+			wantFees:      []float64{3.48},
+			wantFeeAmount: 3.48,
+			wantNetAmount: 96.52,
+		},
+		{
+			name: "juros descontados da conta somados à parte fixa passam do valor",
+			invoice: &Invoice{
+				Installments:       12,
+				InterestMode:       InterestModeMerchant,
+				InterestAmount:     0.80,
+				TotalAmount:        1,
+				Currency:           CurrencyBRL,
+				SettlementCurrency: CurrencyBRL,
+			},
+			wantFees:      []float64{0.52, 0.48},
+			wantFeeAmount: 1,
+			wantNetAmount: 0,
+		},
+		{
+			name: "spread de câmbio não cabe depois da parte fixa",
+			invoice: &Invoice{
+				TotalAmount:        0.10,
+				Currency:           CurrencyUSD,
+				SettlementCurrency: CurrencyBRL,
+			},
+			markup:        1.5,
+			wantFees:      []float64{0.50, 0},
+			wantFeeAmount: 0.50,
+			wantNetAmount: 0,
+		},
+		{
+			name: "parte fixa, juros e spread em uma fatura pequena",
+			invoice: &Invoice{
+				Installments:       3,
+				InterestMode:       InterestModeBuyer,
+				InterestAmount:     0.10,
+				TotalAmount:        0.20,
+				Currency:           CurrencyUSD,
+				SettlementCurrency: CurrencyBRL,
+			},
+			markup:        1.5,
+			wantFees:      []float64{0.52, 0.48, 0},
+			wantFeeAmount: 1,
+			wantNetAmount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := &ExchangeRate{Base: tt.invoice.Currency, Quote: tt.invoice.SettlementCurrency, Rate: 1}
+			if tt.invoice.Currency != tt.invoice.SettlementCurrency {
+				rate.Rate = 5
+			}
+			if err := tt.invoice.LockExchangeRate(rate); err != nil {
+				t.Fatalf("LockExchangeRate() error = %v", err)
+			}
+
+			fees := []*InvoiceFee{schedule.MDR(tt.invoice)}
+			if interest := InterestFee(tt.invoice); interest != nil {
+				fees = append(fees, interest)
+			}
+			if markup := FXMarkupFee(tt.invoice, tt.markup); markup != nil {
+				fees = append(fees, markup)
+			}
+
+			tt.invoice.ApplyFees(fees)
+
+			if len(fees) != len(tt.wantFees) {
+				t.Fatalf("got %d fee lines, want %d", len(fees), len(tt.wantFees))
+			}
+
+			var total float64
+			for i, fee := range fees {
+				if fee.Amount != tt.wantFees[i] {
+					t.Errorf("fee %s amount = %.2f, want %.2f", fee.Type, fee.Amount, tt.wantFees[i])
+				}
+				total = roundCents(total + fee.Amount)
+			}
+
+			if tt.invoice.FeeAmount != tt.wantFeeAmount {
+				t.Errorf("FeeAmount = %.2f, want %.2f", tt.invoice.FeeAmount, tt.wantFeeAmount)
+			}
+			if total != tt.invoice.FeeAmount {
+				t.Errorf("fee lines sum to %.2f, FeeAmount = %.2f", total, tt.invoice.FeeAmount)
+			}
+			if tt.invoice.NetAmount != tt.wantNetAmount {
+				t.Errorf("NetAmount = %.2f, want %.2f", tt.invoice.NetAmount, tt.wantNetAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Currency é o código ISO 4217 da moeda
+type Currency string
+
+const (
+	CurrencyBRL Currency = "BRL"
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+)
+
+// DefaultCurrency é a moeda das contas e faturas criadas sem moeda informada
+const DefaultCurrency = CurrencyBRL
+
+func (c Currency) Valid() bool {
+	return c == CurrencyBRL || c == CurrencyUSD || c == CurrencyEUR
+}
+
+// ParseCurrency normaliza o código informado; vazio retorna a moeda padrão
+func ParseCurrency(value string) (Currency, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return DefaultCurrency, nil
+	}
+
+	currency := Currency(value)
+	if !currency.Valid() {
+		return "", ErrUnsupportedCurrency
+	}
+	return currency, nil
+}
+
+// Origens da cotação usada em uma conversão
+const (
+	ExchangeRateSourceIdentity = "identity" // mesma moeda, sem conversão
+	ExchangeRateSourceOverride = "override" // cotação definida manualmente por um operador
+)
+
+// ExchangeRate é a cotação de Base em Quote: 1 Base = Rate Quote
+type ExchangeRate struct {
+	Base      Currency
+	Quote     Currency
+	Rate      float64
+	Source    string // provedor que forneceu a cotação, ou override/identity
+	UpdatedAt time.Time
+}
+
+// IdentityRate é a "cotação" de uma moeda para ela mesma
+func IdentityRate(currency Currency, now time.Time) *ExchangeRate {
+	return &ExchangeRate{Base: currency, Quote: currency, Rate: 1, Source: ExchangeRateSourceIdentity, UpdatedAt: now}
+}
+
+// Inverse converte a cotação do par oposto; o arredondamento fica para a conversão dos valores
+func (r *ExchangeRate) Inverse() *ExchangeRate {
+	return &ExchangeRate{Base: r.Quote, Quote: r.Base, Rate: 1 / r.Rate, Source: r.Source, UpdatedAt: r.UpdatedAt}
+}
+
+// ExchangeRateOverride é uma cotação fixada por um operador, que prevalece sobre o provedor
+// até expirar (ex.: durante uma falha do provedor ou para honrar um acordo comercial)
+type ExchangeRateOverride struct {
+	Base      Currency
+	Quote     Currency
+	Rate      float64
+	Reason    string
+	CreatedBy string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// maxExchangeRateOverride limita por quanto tempo uma cotação manual pode valer
+const maxExchangeRateOverride = 30 * 24 * time.Hour
+
+func NewExchangeRateOverride(base, quote Currency, rate float64, reason, createdBy string, expiresAt, now time.Time) (*ExchangeRateOverride, error) {
+	if !base.Valid() || !quote.Valid() || base == quote {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return nil, ErrInvalidExchangeRate
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	if !expiresAt.After(now) || expiresAt.Sub(now) > maxExchangeRateOverride {
+		return nil, ErrInvalidExchangeRateExpiration
+	}
+
+	return &ExchangeRateOverride{
+		Base:      base,
+		Quote:     quote,
+		Rate:      rate,
+		Reason:    reason,
+		CreatedBy: createdBy,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}, nil
+}
+
+func (o *ExchangeRateOverride) Active(now time.Time) bool {
+	return now.Before(o.ExpiresAt)
+}
+
+func (o *ExchangeRateOverride) ExchangeRate() *ExchangeRate {
+	return &ExchangeRate{Base: o.Base, Quote: o.Quote, Rate: o.Rate, Source: ExchangeRateSourceOverride, UpdatedAt: o.CreatedAt}
+}
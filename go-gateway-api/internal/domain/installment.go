@@ -0,0 +1,85 @@
+package domain
+
+import "math"
+
+// InterestMode define quem paga os juros do parcelamento
+type InterestMode string
+
+const (
+	InterestModeMerchant InterestMode = "merchant" // parcelado sem juros: a conta absorve o custo
+	InterestModeBuyer    InterestMode = "buyer"    // parcelado com juros: o comprador paga o acréscimo
+)
+
+func (m InterestMode) Valid() bool {
+	return m == InterestModeMerchant || m == InterestModeBuyer
+}
+
+// InterestTable guarda o percentual total de juros sobre o valor para cada número de parcelas.
+// Parcelas ausentes da tabela não têm juros.
+type InterestTable map[int]float64
+
+// NewInterestTable monta a tabela Price para a taxa mensal informada (1.99 significa 1,99% a.m.):
+// o percentual é o quanto a soma das parcelas excede o valor à vista
+func NewInterestTable(monthlyRate float64) InterestTable {
+	table := InterestTable{}
+	if monthlyRate <= 0 {
+		return table
+	}
+
+	rate := monthlyRate / 100
+	for installments := 2; installments <= MaxInstallments; installments++ {
+		n := float64(installments)
+		payment := rate / (1 - math.Pow(1+rate, -n))
+		table[installments] = math.Round((payment*n-1)*100*10000) / 10000
+	}
+
+	return table
+}
+
+func (t InterestTable) Rate(installments int) float64 {
+	return t[installments]
+}
+
+// ApplyInstallments define o parcelamento da fatura e calcula os juros pela tabela. Com juros
+// pagos pelo comprador, o valor cobrado (TotalAmount) inclui o acréscimo.
+func (i *Invoice) ApplyInstallments(installments int, mode InterestMode, table InterestTable) error {
+	if installments == 0 {
+		installments = 1
+	}
+
+	if mode == "" {
+		mode = InterestModeMerchant
+	}
+
+	if installments < 1 || installments > MaxInstallments {
+		return ErrInvalidInstallments
+	}
+
+	if !mode.Valid() {
+		return ErrInvalidInterestMode
+	}
+
+	// Apenas cartão de crédito pode ser parcelado
+	if installments > 1 && i.PaymentType != PaymentTypeCreditCard {
+		return ErrInvalidInstallments
+	}
+
+	i.Installments = installments
+	i.InterestMode = mode
+	i.InterestRate = table.Rate(installments)
+	i.InterestAmount = roundCents(i.Amount * i.InterestRate / 100)
+	i.TotalAmount = i.Amount
+	if mode == InterestModeBuyer {
+		i.TotalAmount = roundCents(i.Amount + i.InterestAmount)
+	}
+
+	return nil
+}
+
+// InstallmentAmount é o valor de cada parcela cobrada do comprador
+func (i *Invoice) InstallmentAmount() float64 {
+	if i.Installments < 1 {
+		return i.TotalAmount
+	}
+	return roundCents(i.TotalAmount / float64(i.Installments))
+}
@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"math"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,29 +12,56 @@ import (
 type Status string
 
 const (
-	StatusPending   Status = "pending"
-	StatusApproved   Status = "approved"
-	StatusRejected   Status = "rejected"
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	StatusExpired  Status = "expired" // a análise de fraude não respondeu ou o pagamento não foi feito dentro do prazo
+	// StatusAwaitingPayment é o status das faturas pagas pelo comprador fora do gateway (Pix, boleto),
+	// que só são aprovadas quando o pagamento é confirmado
+	StatusAwaitingPayment Status = "awaiting_payment"
 )
 
+// PaymentTypeCreditCard é a única forma de pagamento que aceita parcelamento
+const PaymentTypeCreditCard = "credit_card"
+
 type Invoice struct {
-	ID             string
-	AccountID      string
-	Amount         float64
-	Status         Status
-	Description    string
-	PaymentType    string
-	CardLastDigits string
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	ID                 string
+	AccountID          string
+	Amount             float64
+	Status             Status
+	Description        string
+	PaymentType        string
+	CardLastDigits     string
+	CardBrand          CardBrand
+	Installments       int
+	InterestMode       InterestMode
+	InterestRate       float64           // percentual total de juros do parcelamento
+	InterestAmount     float64           // juros do parcelamento, pagos pelo comprador ou descontados da conta
+	TotalAmount        float64           // valor cobrado do comprador (Amount mais os juros quando pagos por ele)
+	Currency           Currency          // moeda de Amount, InterestAmount e TotalAmount
+	SettlementCurrency Currency          // moeda do saldo da conta, na qual estão FeeAmount e NetAmount
+	ExchangeRate       float64           // cotação de Currency em SettlementCurrency travada na aprovação, zero até lá
+	SettlementAmount   float64           // TotalAmount convertido pela cotação travada, zero até a aprovação
+	FeeAmount          float64           // soma das taxas, calculada na aprovação
+	NetAmount          float64           // valor creditado à conta (SettlementAmount - FeeAmount), zero até a aprovação
+	PublishAttempts    int               // quantas vezes a transação pendente foi enviada para a análise de fraude
+	LastPublishedAt    time.Time         // zero quando a fatura nunca foi enviada
+	Payer              *Payer            // nil quando o pagador não foi identificado
+	DeclineReason      DeclineReason     // vazio enquanto a fatura não for recusada ou expirada
+	SubscriptionID     string            // assinatura cobrada pela fatura, vazio nas faturas avulsas
+	Breakdown          *InvoiceBreakdown // itens, desconto e impostos; nil quando a fatura não tem itens
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+
+	cardholderName string // usado apenas na análise de risco da criação; não é gravado
 }
 
 type CreditCard struct {
-	Number 			string
-	CVV 			string
+	Number          string
+	CVV             string
 	ExpirationMonth int
 	ExpirationYear  int
-	CardHolderName 	string
+	CardHolderName  string
 }
 
 func NewInvoice(accountID string, amount float64, description string, paymentType string, card CreditCard) (*Invoice, error) {
@@ -43,20 +72,54 @@ func NewInvoice(accountID string, amount float64, description string, paymentTyp
 	lastDigits := card.Number[len(card.Number)-4:]
 
 	return &Invoice{
-		ID: uuid.New().String(),
-		AccountID:      accountID,
-		Amount:         amount,
-		Status:         StatusPending,
-		Description:    description,
-		PaymentType:    paymentType,
-		CardLastDigits: lastDigits,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
+		ID:                 uuid.New().String(),
+		AccountID:          accountID,
+		Amount:             amount,
+		Status:             StatusPending,
+		Description:        description,
+		PaymentType:        paymentType,
+		CardLastDigits:     lastDigits,
+		CardBrand:          DetectCardBrand(card.Number),
+		Installments:       1,
+		InterestMode:       InterestModeMerchant,
+		TotalAmount:        amount,
+		Currency:           DefaultCurrency,
+		SettlementCurrency: DefaultCurrency,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+		cardholderName:     strings.TrimSpace(card.CardHolderName),
+	}, nil
+}
+
+// newAwaitingPaymentInvoice cria uma fatura sem cartão que aguarda a confirmação do pagamento
+func newAwaitingPaymentInvoice(accountID string, amount float64, description string, paymentType string) (*Invoice, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	return &Invoice{
+		ID:                 uuid.New().String(),
+		AccountID:          accountID,
+		Amount:             amount,
+		Status:             StatusAwaitingPayment,
+		Description:        description,
+		PaymentType:        paymentType,
+		Installments:       1,
+		InterestMode:       InterestModeMerchant,
+		TotalAmount:        amount,
+		Currency:           DefaultCurrency,
+		SettlementCurrency: DefaultCurrency,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
 	}, nil
 }
 
-func (i *Invoice) Process() error {
-	if i.Amount > 10000 {
+// Process decide as faturas de cartão que não precisam da análise de fraude. Transações de
+// alto valor e as de pessoa física paga com cartão de outro titular ficam pendentes.
+// O limite de valor vale na moeda da conta: settlementRate é a cotação atual de Currency em
+// SettlementCurrency (1 quando são iguais).
+func (i *Invoice) Process(settlementRate float64) error {
+	if i.Amount*settlementRate > 10000 || i.payerIsNotCardholder() {
 		return nil
 	}
 
@@ -67,6 +130,7 @@ func (i *Invoice) Process() error {
 		newStatus = StatusApproved
 	} else {
 		newStatus = StatusRejected
+		i.DeclineReason = DeclineReasonCardDeclined
 	}
 
 	i.Status = newStatus
@@ -78,7 +142,132 @@ func (i *Invoice) UpdateStatus(newStatus Status) error {
 		return ErrInvalidStatus
 	}
 
+	// Uma fatura pendente só pode ser aprovada ou rejeitada
+	if newStatus != StatusApproved && newStatus != StatusRejected {
+		return ErrInvalidStatus
+	}
+
+	// Faturas pendentes só são recusadas pela análise de fraude (automática ou manual)
+	if newStatus == StatusRejected {
+		i.DeclineReason = DeclineReasonFraudSuspected
+	}
+
 	i.Status = newStatus
 	i.UpdatedAt = time.Now()
 	return nil
-}
\ No newline at end of file
+}
+
+// SetCurrency define a moeda da cobrança e a do saldo da conta que recebe o valor.
+// Pix e boleto só existem em reais.
+func (i *Invoice) SetCurrency(currency, settlementCurrency Currency) error {
+	if !currency.Valid() || !settlementCurrency.Valid() {
+		return ErrUnsupportedCurrency
+	}
+
+	if (i.PaymentType == PaymentTypePix || i.PaymentType == PaymentTypeBoleto) && currency != CurrencyBRL {
+		return ErrCurrencyNotSupportedByPaymentType
+	}
+
+	i.Currency = currency
+	i.SettlementCurrency = settlementCurrency
+	return nil
+}
+
+// LockExchangeRate trava na aprovação a cotação usada para converter o valor cobrado para a
+// moeda da conta; as taxas e o valor líquido são calculados sobre o valor convertido
+func (i *Invoice) LockExchangeRate(rate *ExchangeRate) error {
+	if rate.Base != i.Currency || rate.Quote != i.SettlementCurrency {
+		return ErrExchangeRateNotFound
+	}
+
+	if rate.Rate <= 0 {
+		return ErrInvalidExchangeRate
+	}
+
+	i.ExchangeRate = rate.Rate
+	i.SettlementAmount = i.ToSettlement(i.TotalAmount)
+	return nil
+}
+
+// ToSettlement converte um valor da moeda da fatura pela cotação travada
+func (i *Invoice) ToSettlement(amount float64) float64 {
+	return roundCents(amount * i.ExchangeRate)
+}
+
+// ApplyFees registra as taxas cobradas na aprovação e o valor líquido da conta. A soma das
+// taxas nunca ultrapassa o valor convertido: as linhas são limitadas na ordem em que vêm, e as
+// que não cabem ficam zeradas, para que a receita da plataforma não crie saldo negativo na conta.
+func (i *Invoice) ApplyFees(fees []*InvoiceFee) {
+	var total float64
+	for _, fee := range fees {
+		fee.Amount = roundCents(math.Max(0, math.Min(fee.Amount, i.SettlementAmount-total)))
+		total = roundCents(total + fee.Amount)
+	}
+
+	i.FeeAmount = total
+	i.NetAmount = roundCents(i.SettlementAmount - i.FeeAmount)
+}
+
+// ConfirmPayment aprova a fatura quando o pagamento é confirmado pelo provedor
+func (i *Invoice) ConfirmPayment() error {
+	if i.Status != StatusAwaitingPayment {
+		return ErrInvalidStatus
+	}
+
+	i.Status = StatusApproved
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// AddLateCharges inclui no valor cobrado a multa e os juros pagos após o vencimento
+func (i *Invoice) AddLateCharges(amount float64) {
+	if amount <= 0 {
+		return
+	}
+
+	i.TotalAmount = roundCents(i.TotalAmount + amount)
+	i.UpdatedAt = time.Now()
+}
+
+// ExpirePayment encerra uma fatura cujo pagamento não foi feito dentro do prazo
+func (i *Invoice) ExpirePayment() error {
+	if i.Status != StatusAwaitingPayment {
+		return ErrInvalidStatus
+	}
+
+	i.Status = StatusExpired
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// payerIsNotCardholder compara os nomes sem acentos, caixa ou espaços extras; o titular de
+// um cartão usado por uma empresa (CNPJ) é naturalmente outra pessoa
+func (i *Invoice) payerIsNotCardholder() bool {
+	if i.Payer == nil || i.Payer.DocumentType() != TaxIDTypeCPF || i.cardholderName == "" {
+		return false
+	}
+
+	normalize := func(name string) string {
+		return strings.ToUpper(ASCIIText(strings.Join(strings.Fields(name), " ")))
+	}
+	return normalize(i.Payer.Name) != normalize(i.cardholderName)
+}
+
+// MarkPublished registra que a transação pendente foi enviada para a análise de fraude
+func (i *Invoice) MarkPublished() {
+	i.PublishAttempts++
+	i.LastPublishedAt = time.Now()
+	i.UpdatedAt = i.LastPublishedAt
+}
+
+// Expire encerra uma fatura que ficou pendente além do prazo
+func (i *Invoice) Expire() error {
+	if i.Status != StatusPending {
+		return ErrInvalidStatus
+	}
+
+	i.Status = StatusExpired
+	i.DeclineReason = DeclineReasonProcessingError
+	i.UpdatedAt = time.Now()
+	return nil
+}
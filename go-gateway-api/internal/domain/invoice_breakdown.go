@@ -0,0 +1,162 @@
+package domain
+
+import "strings"
+
+// maxLineItems limita os itens de uma fatura, que são gravados junto com ela
+const maxLineItems = 100
+
+type DiscountType string
+
+const (
+	DiscountTypePercent DiscountType = "percent" // percentual sobre o subtotal dos itens
+	DiscountTypeFixed   DiscountType = "fixed"   // valor fixo na moeda da fatura
+)
+
+// LineItem é um item vendido na fatura; Amount é Quantity vezes UnitPrice
+type LineItem struct {
+	SKU         string  `json:"sku,omitempty"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+func NewLineItem(sku, description string, quantity int, unitPrice float64) (LineItem, error) {
+	description = strings.TrimSpace(description)
+	if description == "" || quantity <= 0 || unitPrice <= 0 {
+		return LineItem{}, ErrInvalidLineItem
+	}
+
+	return LineItem{
+		SKU:         strings.TrimSpace(sku),
+		Description: description,
+		Quantity:    quantity,
+		UnitPrice:   roundCents(unitPrice),
+		Amount:      roundCents(float64(quantity) * roundCents(unitPrice)),
+	}, nil
+}
+
+// Discount é o desconto aplicado sobre o subtotal, avulso ou vindo de um cupom
+type Discount struct {
+	Type       DiscountType `json:"type"`
+	Value      float64      `json:"value"` // percentual ou valor fixo, conforme Type
+	CouponID   string       `json:"coupon_id,omitempty"`
+	CouponCode string       `json:"coupon_code,omitempty"`
+	Amount     float64      `json:"amount"` // valor descontado, calculado no detalhamento
+}
+
+func NewDiscount(discountType DiscountType, value float64) (*Discount, error) {
+	if !validDiscount(discountType, value) {
+		return nil, ErrInvalidDiscount
+	}
+
+	return &Discount{Type: discountType, Value: roundCents(value)}, nil
+}
+
+func validDiscount(discountType DiscountType, value float64) bool {
+	switch discountType {
+	case DiscountTypePercent:
+		return value > 0 && value <= 100
+	case DiscountTypeFixed:
+		return value > 0
+	}
+	return false
+}
+
+// TaxLine é um imposto da fatura: um percentual (Rate) sobre o subtotal com desconto ou um
+// valor fixo informado em Amount
+type TaxLine struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate,omitempty"`
+	Amount float64 `json:"amount"`
+}
+
+func NewTaxLine(name string, rate, amount float64) (TaxLine, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || rate < 0 || amount < 0 || (rate > 0) == (amount > 0) {
+		return TaxLine{}, ErrInvalidTaxLine
+	}
+
+	return TaxLine{Name: name, Rate: rate, Amount: roundCents(amount)}, nil
+}
+
+// InvoiceBreakdown detalha o valor da fatura: subtotal dos itens, menos o desconto, mais os
+// impostos. É gravado com a fatura, que guarda os valores calculados na emissão.
+type InvoiceBreakdown struct {
+	LineItems []LineItem `json:"line_items"`
+	Subtotal  float64    `json:"subtotal"`
+	Discount  *Discount  `json:"discount,omitempty"`
+	TaxLines  []TaxLine  `json:"tax_lines,omitempty"`
+	TaxAmount float64    `json:"tax_amount"`
+	Total     float64    `json:"total"`
+}
+
+// NewInvoiceBreakdown calcula o detalhamento; sem itens não há detalhamento (nil), e desconto
+// ou impostos sem itens são recusados. O desconto nunca passa do subtotal.
+func NewInvoiceBreakdown(items []LineItem, discount *Discount, taxes []TaxLine) (*InvoiceBreakdown, error) {
+	if len(items) == 0 {
+		if discount != nil || len(taxes) > 0 {
+			return nil, ErrLineItemsRequired
+		}
+		return nil, nil
+	}
+
+	if len(items) > maxLineItems {
+		return nil, ErrInvalidLineItem
+	}
+
+	breakdown := &InvoiceBreakdown{LineItems: items, Discount: discount, TaxLines: taxes}
+	for _, item := range items {
+		breakdown.Subtotal += item.Amount
+	}
+	breakdown.Subtotal = roundCents(breakdown.Subtotal)
+
+	taxable := breakdown.Subtotal
+	if discount != nil {
+		discount.Amount = discount.Value
+		if discount.Type == DiscountTypePercent {
+			discount.Amount = roundCents(breakdown.Subtotal * discount.Value / 100)
+		}
+		if discount.Amount > breakdown.Subtotal {
+			discount.Amount = breakdown.Subtotal
+		}
+		taxable = roundCents(taxable - discount.Amount)
+	}
+
+	for i := range breakdown.TaxLines {
+		tax := &breakdown.TaxLines[i]
+		if tax.Rate > 0 {
+			tax.Amount = roundCents(taxable * tax.Rate / 100)
+		}
+		breakdown.TaxAmount += tax.Amount
+	}
+	breakdown.TaxAmount = roundCents(breakdown.TaxAmount)
+
+	breakdown.Total = roundCents(taxable + breakdown.TaxAmount)
+	if breakdown.Total <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	return breakdown, nil
+}
+
+// DiscountAmount é o valor descontado, zero quando não há desconto
+func (b *InvoiceBreakdown) DiscountAmount() float64 {
+	if b.Discount == nil {
+		return 0
+	}
+	return b.Discount.Amount
+}
+
+// ResolveAmount valida o valor informado na fatura contra o total calculado; quando o valor
+// não é informado (zero), a fatura usa o total
+func (b *InvoiceBreakdown) ResolveAmount(amount float64) (float64, error) {
+	if amount == 0 {
+		return b.Total, nil
+	}
+
+	if roundCents(amount) != b.Total {
+		return 0, ErrInvoiceAmountMismatch
+	}
+	return b.Total, nil
+}
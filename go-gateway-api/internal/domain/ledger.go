@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LedgerEntryType string
+
+const (
+	LedgerEntryAnticipationCredit   LedgerEntryType = "anticipation.credit"   // valor bruto antecipado para o saldo disponível
+	LedgerEntryAnticipationDiscount LedgerEntryType = "anticipation.discount" // desconto cobrado da conta
+	LedgerEntryAnticipationRevenue  LedgerEntryType = "anticipation.revenue"  // desconto recebido pela plataforma
+)
+
+// LedgerEntry é um lançamento no saldo disponível de uma conta; Amount é negativo nos débitos
+type LedgerEntry struct {
+	ID          string
+	AccountID   string
+	Type        LedgerEntryType
+	Amount      float64
+	ReferenceID string // operação que originou o lançamento
+	Description string
+	CreatedAt   time.Time
+}
+
+func NewLedgerEntry(accountID string, entryType LedgerEntryType, amount float64, referenceID, description string) *LedgerEntry {
+	return &LedgerEntry{
+		ID:          uuid.New().String(),
+		AccountID:   accountID,
+		Type:        entryType,
+		Amount:      amount,
+		ReferenceID: referenceID,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+type TaxIDType string
+
+const (
+	TaxIDTypeCPF  TaxIDType = "cpf"
+	TaxIDTypeCNPJ TaxIDType = "cnpj"
+)
+
+var (
+	statePattern      = regexp.MustCompile(`^[A-Z]{2}$`)
+	postalCodePattern = regexp.MustCompile(`^[0-9]{8}$`)
+)
+
+// BillingAddress é o endereço de cobrança do pagador. É gravado junto com a fatura, em JSON.
+type BillingAddress struct {
+	Street     string `json:"street"`
+	Number     string `json:"number"`
+	Complement string `json:"complement,omitempty"`
+	District   string `json:"district"`
+	City       string `json:"city"`
+	State      string `json:"state"`       // UF
+	PostalCode string `json:"postal_code"` // CEP, apenas dígitos
+}
+
+// Payer identifica quem paga a fatura. O documento fica completo apenas no banco de dados:
+// respostas e logs usam a versão mascarada e as buscas usam DocumentHash.
+type Payer struct {
+	Name           string
+	Document       string // CPF ou CNPJ, apenas dígitos
+	DocumentHash   string // HMAC do documento, preenchido pelo service com a chave da plataforma
+	Email          string
+	BillingAddress *BillingAddress // opcional
+}
+
+// NewPayer valida o documento pelos dígitos verificadores e normaliza os demais campos
+func NewPayer(name, document, email string, address *BillingAddress) (*Payer, error) {
+	payer := &Payer{
+		Name:     strings.TrimSpace(name),
+		Document: onlyDigits(document),
+		Email:    strings.ToLower(strings.TrimSpace(email)),
+	}
+
+	if payer.Name == "" {
+		return nil, ErrInvalidPayerName
+	}
+
+	if !ValidTaxID(payer.Document) {
+		return nil, ErrInvalidTaxID
+	}
+
+	if payer.Email != "" {
+		parsed, err := mail.ParseAddress(payer.Email)
+		if err != nil || parsed.Address != payer.Email {
+			return nil, ErrInvalidPayerEmail
+		}
+	}
+
+	if address != nil {
+		normalized := *address
+		normalized.Street = strings.TrimSpace(normalized.Street)
+		normalized.Number = strings.TrimSpace(normalized.Number)
+		normalized.Complement = strings.TrimSpace(normalized.Complement)
+		normalized.District = strings.TrimSpace(normalized.District)
+		normalized.City = strings.TrimSpace(normalized.City)
+		normalized.State = strings.ToUpper(strings.TrimSpace(normalized.State))
+		normalized.PostalCode = onlyDigits(normalized.PostalCode)
+
+		if normalized.Street == "" || normalized.City == "" ||
+			!statePattern.MatchString(normalized.State) || !postalCodePattern.MatchString(normalized.PostalCode) {
+			return nil, ErrInvalidBillingAddress
+		}
+		payer.BillingAddress = &normalized
+	}
+
+	return payer, nil
+}
+
+func (p *Payer) DocumentType() TaxIDType {
+	if len(p.Document) == 14 {
+		return TaxIDTypeCNPJ
+	}
+	return TaxIDTypeCPF
+}
+
+func (p *Payer) MaskedDocument() string {
+	return MaskTaxID(p.Document)
+}
+
+// MaskedEmail mantém a primeira letra do usuário e o domínio
+func (p *Payer) MaskedEmail() string {
+	user, domain, found := strings.Cut(p.Email, "@")
+	if !found || user == "" {
+		return ""
+	}
+	return user[:1] + "***@" + domain
+}
+
+// LogValue garante que o pagador só apareça mascarado nos logs
+func (p *Payer) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("document", p.MaskedDocument()),
+		slog.String("document_type", string(p.DocumentType())),
+		slog.String("email", p.MaskedEmail()),
+	)
+}
+
+// ValidTaxID aceita CPF (11 dígitos) ou CNPJ (14 dígitos) com os dígitos verificadores corretos
+func ValidTaxID(document string) bool {
+	switch len(document) {
+	case 11:
+		return ValidCPF(document)
+	case 14:
+		return ValidCNPJ(document)
+	default:
+		return false
+	}
+}
+
+// ValidCPF confere os dois dígitos verificadores (módulo 11, pesos de 10 e 11 até 2).
+// Sequências de um mesmo dígito passam no cálculo mas não são CPFs válidos.
+func ValidCPF(document string) bool {
+	if len(document) != 11 || !digitsPattern.MatchString(document) || repeatedDigits(document) {
+		return false
+	}
+
+	return taxIDCheckDigit(document[:9], []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) == document[9] &&
+		taxIDCheckDigit(document[:10], []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}) == document[10]
+}
+
+// ValidCNPJ confere os dois dígitos verificadores (módulo 11, pesos de 2 a 9 da direita
+// para a esquerda)
+func ValidCNPJ(document string) bool {
+	if len(document) != 14 || !digitsPattern.MatchString(document) || repeatedDigits(document) {
+		return false
+	}
+
+	return taxIDCheckDigit(document[:12], []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == document[12] &&
+		taxIDCheckDigit(document[:13], []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == document[13]
+}
+
+func taxIDCheckDigit(digits string, weights []int) byte {
+	sum := 0
+	for i, weight := range weights {
+		sum += int(digits[i]-'0') * weight
+	}
+
+	remainder := sum % 11
+	if remainder < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - remainder)
+}
+
+func repeatedDigits(document string) bool {
+	return strings.Count(document, document[:1]) == len(document)
+}
+
+// MaskTaxID esconde o início e os dígitos verificadores: ***.456.789-** e **.345.678/0001-**
+func MaskTaxID(document string) string {
+	switch len(document) {
+	case 11:
+		return "***." + document[3:6] + "." + document[6:9] + "-**"
+	case 14:
+		return "**." + document[2:5] + "." + document[5:8] + "/" + document[8:12] + "-**"
+	default:
+		return ""
+	}
+}
+
+// HashTaxID permite buscar pelo documento sem expô-lo. Usa HMAC porque há poucos CPFs
+// possíveis e um hash simples seria revertido por força bruta.
+func HashTaxID(key []byte, document string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(onlyDigits(document)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
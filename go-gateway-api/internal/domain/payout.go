@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PayoutDestinationType string
+
+const (
+	PayoutDestinationBankAccount PayoutDestinationType = "bank_account"
+	PayoutDestinationPixKey      PayoutDestinationType = "pix_key"
+)
+
+type PixKeyType string
+
+const (
+	PixKeyTypeCPF    PixKeyType = "cpf"
+	PixKeyTypeCNPJ   PixKeyType = "cnpj"
+	PixKeyTypeEmail  PixKeyType = "email"
+	PixKeyTypePhone  PixKeyType = "phone"
+	PixKeyTypeRandom PixKeyType = "random" // chave aleatória (EVP)
+)
+
+type BankAccountType string
+
+const (
+	BankAccountTypeChecking BankAccountType = "checking"
+	BankAccountTypeSavings  BankAccountType = "savings"
+)
+
+var (
+	digitsPattern = regexp.MustCompile(`^[0-9]+$`)
+	phonePattern  = regexp.MustCompile(`^\+55[0-9]{10,11}$`)
+)
+
+// BankAccount é uma conta bancária de titularidade do lojista
+type BankAccount struct {
+	BankCode       string // código COMPE de 3 dígitos
+	Branch         string
+	Number         string // número da conta com o dígito verificador
+	Type           BankAccountType
+	HolderName     string
+	HolderDocument string // CPF ou CNPJ, apenas dígitos
+}
+
+// PixKey é uma chave Pix de titularidade do lojista
+type PixKey struct {
+	Type PixKeyType
+	Key  string
+}
+
+// PayoutDestination é um destino cadastrado para saques: uma conta bancária ou uma chave Pix
+type PayoutDestination struct {
+	ID          string
+	AccountID   string
+	Type        PayoutDestinationType
+	BankAccount *BankAccount // preenchido quando Type é bank_account
+	PixKey      *PixKey      // preenchido quando Type é pix_key
+	CreatedAt   time.Time
+}
+
+func NewBankAccountDestination(accountID string, bankAccount BankAccount) (*PayoutDestination, error) {
+	bankAccount.HolderName = strings.TrimSpace(bankAccount.HolderName)
+	bankAccount.HolderDocument = onlyDigits(bankAccount.HolderDocument)
+
+	if len(bankAccount.BankCode) != 3 || !digitsPattern.MatchString(bankAccount.BankCode) ||
+		bankAccount.Branch == "" || len(bankAccount.Branch) > 5 || !digitsPattern.MatchString(bankAccount.Branch) ||
+		bankAccount.Number == "" || len(bankAccount.Number) > 20 || !digitsPattern.MatchString(bankAccount.Number) ||
+		bankAccount.HolderName == "" ||
+		!ValidTaxID(bankAccount.HolderDocument) {
+		return nil, ErrInvalidPayoutDestination
+	}
+
+	if bankAccount.Type != BankAccountTypeChecking && bankAccount.Type != BankAccountTypeSavings {
+		return nil, ErrInvalidPayoutDestination
+	}
+
+	return &PayoutDestination{
+		ID:          uuid.New().String(),
+		AccountID:   accountID,
+		Type:        PayoutDestinationBankAccount,
+		BankAccount: &bankAccount,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+func NewPixKeyDestination(accountID string, pixKey PixKey) (*PayoutDestination, error) {
+	pixKey.Key = strings.TrimSpace(pixKey.Key)
+
+	switch pixKey.Type {
+	case PixKeyTypeCPF, PixKeyTypeCNPJ:
+		pixKey.Key = onlyDigits(pixKey.Key)
+		if (pixKey.Type == PixKeyTypeCPF && !ValidCPF(pixKey.Key)) || (pixKey.Type == PixKeyTypeCNPJ && !ValidCNPJ(pixKey.Key)) {
+			return nil, ErrInvalidPayoutDestination
+		}
+	case PixKeyTypeEmail:
+		address, err := mail.ParseAddress(pixKey.Key)
+		if err != nil || address.Address != pixKey.Key {
+			return nil, ErrInvalidPayoutDestination
+		}
+		pixKey.Key = strings.ToLower(pixKey.Key)
+	case PixKeyTypePhone:
+		if !phonePattern.MatchString(pixKey.Key) {
+			return nil, ErrInvalidPayoutDestination
+		}
+	case PixKeyTypeRandom:
+		if _, err := uuid.Parse(pixKey.Key); err != nil {
+			return nil, ErrInvalidPayoutDestination
+		}
+		pixKey.Key = strings.ToLower(pixKey.Key)
+	default:
+		return nil, ErrInvalidPayoutDestination
+	}
+
+	return &PayoutDestination{
+		ID:        uuid.New().String(),
+		AccountID: accountID,
+		Type:      PayoutDestinationPixKey,
+		PixKey:    &pixKey,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func onlyDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+type PayoutStatus string
+
+const (
+	PayoutStatusPending    PayoutStatus = "pending"    // saldo reservado, aguardando envio ao provedor
+	PayoutStatusSubmitting PayoutStatus = "submitting" // reservado pelo processador, sendo enviado ao provedor
+	PayoutStatusInTransit  PayoutStatus = "in_transit" // aceito pelo provedor, aguardando liquidação
+	PayoutStatusPaid       PayoutStatus = "paid"
+	PayoutStatusFailed     PayoutStatus = "failed" // o valor reservado volta para o saldo
+)
+
+// Payout é um saque do saldo da conta para um destino cadastrado
+type Payout struct {
+	ID                string
+	AccountID         string
+	DestinationID     string
+	Amount            float64
+	Status            PayoutStatus
+	ProviderReference string // identificador do saque no provedor, vazio enquanto pendente
+	FailureReason     string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func NewPayout(accountID string, destination *PayoutDestination, amount float64) (*Payout, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	if destination.AccountID != accountID {
+		return nil, ErrPayoutDestinationNotFound
+	}
+
+	return &Payout{
+		ID:            uuid.New().String(),
+		AccountID:     accountID,
+		DestinationID: destination.ID,
+		Amount:        amount,
+		Status:        PayoutStatusPending,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}, nil
+}
+
+// MarkSubmitting reserva o saque pendente para o envio ao provedor, para que a remessa CNAB
+// não o inclua
+func (p *Payout) MarkSubmitting() error {
+	if p.Status != PayoutStatusPending {
+		return ErrInvalidPayoutStatus
+	}
+
+	p.Status = PayoutStatusSubmitting
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkInTransit registra que o provedor (ou o banco, pela remessa) aceitou o saque
+func (p *Payout) MarkInTransit(providerReference string) error {
+	if p.Status != PayoutStatusPending && p.Status != PayoutStatusSubmitting {
+		return ErrInvalidPayoutStatus
+	}
+
+	p.Status = PayoutStatusInTransit
+	p.ProviderReference = providerReference
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+func (p *Payout) MarkPaid() error {
+	if p.Status != PayoutStatusInTransit {
+		return ErrInvalidPayoutStatus
+	}
+
+	p.Status = PayoutStatusPaid
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkFailed encerra o saque com falha; pode ocorrer no envio ou depois de aceito pelo provedor
+func (p *Payout) MarkFailed(reason string) error {
+	if p.Status != PayoutStatusPending && p.Status != PayoutStatusSubmitting && p.Status != PayoutStatusInTransit {
+		return ErrInvalidPayoutStatus
+	}
+
+	p.Status = PayoutStatusFailed
+	p.FailureReason = reason
+	p.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,264 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentTypePix identifica faturas pagas por Pix, sem dados de cartão
+const PaymentTypePix = "pix"
+
+// DefaultPixExpiration é o prazo de pagamento de uma cobrança Pix sem expiração informada
+const DefaultPixExpiration = 30 * time.Minute
+
+const maxPixExpiration = 30 * 24 * time.Hour
+
+type PixChargeType string
+
+const (
+	PixChargeTypeDynamic PixChargeType = "dynamic" // o payload aponta para a URL da cobrança no PSP
+	PixChargeTypeStatic  PixChargeType = "static"  // o payload carrega a chave Pix e o txid
+)
+
+type PixChargeStatus string
+
+const (
+	PixChargeStatusActive  PixChargeStatus = "active"
+	PixChargeStatusPaid    PixChargeStatus = "paid"
+	PixChargeStatusExpired PixChargeStatus = "expired"
+)
+
+// PixCharge é a cobrança Pix de uma fatura. O pagamento é confirmado pelo PSP via callback,
+// identificado pelo TxID.
+type PixCharge struct {
+	ID         string
+	InvoiceID  string
+	AccountID  string
+	TxID       string
+	Type       PixChargeType
+	Payload    string // BR Code (copia e cola)
+	Location   string // URL da cobrança dinâmica no PSP, sem o esquema
+	Amount     float64
+	Status     PixChargeStatus
+	ExpiresAt  time.Time
+	EndToEndID string    // identificador do pagamento no SPI, vazio até o pagamento
+	PaidAt     time.Time // zero até o pagamento
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// PixReceiver identifica quem recebe o Pix no BR Code
+type PixReceiver struct {
+	Key  string // chave Pix, obrigatória nas cobranças estáticas
+	Name string
+	City string
+}
+
+// NewPixInvoice cria uma fatura Pix aguardando o pagamento; não passa pela análise de fraude
+func NewPixInvoice(accountID string, amount float64, description string) (*Invoice, error) {
+	return newAwaitingPaymentInvoice(accountID, amount, description, PaymentTypePix)
+}
+
+func NewPixCharge(invoice *Invoice, chargeType PixChargeType, expiresIn time.Duration) (*PixCharge, error) {
+	if chargeType == "" {
+		chargeType = PixChargeTypeDynamic
+	}
+
+	if chargeType != PixChargeTypeDynamic && chargeType != PixChargeTypeStatic {
+		return nil, ErrInvalidPixChargeType
+	}
+
+	if expiresIn == 0 {
+		expiresIn = DefaultPixExpiration
+	}
+
+	if expiresIn < time.Minute || expiresIn > maxPixExpiration {
+		return nil, ErrInvalidPixExpiration
+	}
+
+	// O txid de cobranças dinâmicas tem de 26 a 35 caracteres; o de estáticas, até 25
+	txID := strings.ReplaceAll(uuid.New().String(), "-", "")
+	if chargeType == PixChargeTypeStatic {
+		txID = txID[:25]
+	}
+
+	now := time.Now()
+	return &PixCharge{
+		ID:        uuid.New().String(),
+		InvoiceID: invoice.ID,
+		AccountID: invoice.AccountID,
+		TxID:      txID,
+		Type:      chargeType,
+		Amount:    invoice.TotalAmount,
+		Status:    PixChargeStatusActive,
+		ExpiresAt: now.Add(expiresIn),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// BuildPayload gera o BR Code da cobrança; cobranças dinâmicas precisam da Location do PSP
+func (c *PixCharge) BuildPayload(receiver PixReceiver) error {
+	code := BRCode{
+		MerchantName: receiver.Name,
+		MerchantCity: receiver.City,
+		Amount:       c.Amount,
+	}
+
+	switch c.Type {
+	case PixChargeTypeDynamic:
+		if c.Location == "" {
+			return ErrInvalidPixChargeType
+		}
+		code.Location = c.Location
+	case PixChargeTypeStatic:
+		if receiver.Key == "" {
+			return ErrPixKeyNotConfigured
+		}
+		code.PixKey = receiver.Key
+		code.TxID = c.TxID
+	}
+
+	c.Payload = code.Payload()
+	return nil
+}
+
+// Pay registra o pagamento informado pelo PSP. Pagamentos repetidos com o mesmo EndToEndID
+// retornam ErrPixChargeAlreadyPaid para que o chamador os trate como reentrega.
+func (c *PixCharge) Pay(endToEndID string, amount float64, paidAt time.Time) error {
+	if c.Status == PixChargeStatusPaid {
+		return ErrPixChargeAlreadyPaid
+	}
+
+	if c.Status == PixChargeStatusExpired || paidAt.After(c.ExpiresAt) {
+		return ErrPixChargeExpired
+	}
+
+	if roundCents(amount) != roundCents(c.Amount) {
+		return ErrPixAmountMismatch
+	}
+
+	c.Status = PixChargeStatusPaid
+	c.EndToEndID = endToEndID
+	c.PaidAt = paidAt
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// BRCode monta o payload EMV-MPM do Pix conforme o Manual de Padrões para Iniciação do Pix
+type BRCode struct {
+	PixKey       string // cobrança estática
+	Location     string // cobrança dinâmica
+	MerchantName string
+	MerchantCity string
+	Amount       float64
+	TxID         string // vazio usa "***"
+}
+
+func (b BRCode) Payload() string {
+	merchantAccount := emvField("00", "br.gov.bcb.pix")
+	initiationMethod := "11" // reutilizável
+	if b.Location != "" {
+		merchantAccount += emvField("25", b.Location)
+		initiationMethod = "12" // uso único
+	} else {
+		merchantAccount += emvField("01", b.PixKey)
+	}
+
+	txID := b.TxID
+	if txID == "" {
+		txID = "***"
+	}
+
+	var payload strings.Builder
+	payload.WriteString(emvField("00", "01"))
+	payload.WriteString(emvField("01", initiationMethod))
+	payload.WriteString(emvField("26", merchantAccount))
+	payload.WriteString(emvField("52", "0000"))
+	payload.WriteString(emvField("53", "986")) // BRL
+	if b.Amount > 0 {
+		payload.WriteString(emvField("54", strconv.FormatFloat(b.Amount, 'f', 2, 64)))
+	}
+	payload.WriteString(emvField("58", "BR"))
+	payload.WriteString(emvField("59", emvText(b.MerchantName, 25)))
+	payload.WriteString(emvField("60", emvText(b.MerchantCity, 15)))
+	payload.WriteString(emvField("62", emvField("05", txID)))
+
+	// O CRC cobre todo o payload, incluindo o identificador e o tamanho do próprio campo 63
+	payload.WriteString("6304")
+	crc := strings.ToUpper(strconv.FormatUint(uint64(crc16CCITT(payload.String())), 16))
+	payload.WriteString(strings.Repeat("0", 4-len(crc)) + crc)
+
+	return payload.String()
+}
+
+func emvField(id, value string) string {
+	length := strconv.Itoa(len(value))
+	if len(length) == 1 {
+		length = "0" + length
+	}
+	return id + length + value
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"é", "e", "ê", "e", "è", "e", "É", "E", "Ê", "E", "È", "E",
+	"í", "i", "î", "i", "Í", "I", "Î", "I",
+	"ó", "o", "ô", "o", "õ", "o", "ö", "o", "Ó", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"ú", "u", "ü", "u", "Ú", "U", "Ü", "U",
+	"ç", "c", "Ç", "C",
+)
+
+// ASCIIText remove acentos e caracteres fora do ASCII imprimível, como exigem o BR Code e os
+// arquivos CNAB
+func ASCIIText(value string) string {
+	value = accentReplacer.Replace(strings.TrimSpace(value))
+
+	var text strings.Builder
+	for _, r := range value {
+		if r >= 0x20 && r <= 0x7E {
+			text.WriteRune(r)
+		}
+	}
+
+	return text.String()
+}
+
+// emvText remove acentos e caracteres fora do ASCII imprimível e limita o tamanho
+func emvText(value string, maxLength int) string {
+	result := ASCIIText(value)
+	if len(result) > maxLength {
+		result = strings.TrimSpace(result[:maxLength])
+	}
+	return result
+}
+
+// crc16CCITT é o CRC16-CCITT-FALSE (polinômio 0x1021, valor inicial 0xFFFF) exigido pelo BR Code
+func crc16CCITT(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Expire encerra a cobrança que não foi paga dentro do prazo
+func (c *PixCharge) Expire() error {
+	if c.Status != PixChargeStatusActive {
+		return ErrInvalidStatus
+	}
+
+	c.Status = PixChargeStatusExpired
+	c.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "testing"
+
+// Exemplo de BR Code estático do Manual de Padrões para Iniciação do Pix (BACEN)
+const bacenStaticBRCode = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestCRC16CCITT(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want uint16
+	}{
+		{name: "valor de verificação do CRC-16/CCITT-FALSE", data: "123456789", want: 0x29B1},
+		{name: "entrada vazia", data: "", want: 0xFFFF},
+		{name: "exemplo do manual do BACEN", data: bacenStaticBRCode[:len(bacenStaticBRCode)-4], want: 0x1D3D},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := crc16CCITT(tt.data); got != tt.want {
+				t.Errorf("crc16CCITT(%q) = %04X, want %04X", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBRCodePayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		brCode BRCode
+		want   string
+	}{
+		{
+			// O exemplo do manual com o campo 01 (010211) que o gateway sempre envia
+			name: "estático do manual do BACEN",
+			brCode: BRCode{
+				PixKey:       "123e4567-e12b-12d1-a456-426655440000",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "BRASILIA",
+			},
+			want: "00020101021126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***630448CD",
+		},
+		{
+			name: "dinâmico com valor e txid",
+			brCode: BRCode{
+				Location:     "pix.example.com/qr/v2/9d36b84fc70b478fb95c12729b90ca25",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "BRASILIA",
+				Amount:       123.45,
+				TxID:         "TX123",
+			},
+			want: "00020101021226760014br.gov.bcb.pix2554pix.example.com/qr/v2/9d36b84fc70b478fb95c12729b90ca255204000053039865406123.455802BR5913Fulano de Tal6008BRASILIA62090505TX123630407EF",
+		},
+		{
+			name: "nome e cidade sem acentos e truncados",
+			brCode: BRCode{
+				PixKey:       "fulano@example.com",
+				MerchantName: "José da Conceição Ltda Comércio",
+				MerchantCity: "São Paulo",
+				Amount:       10,
+			},
+			want: "00020101021126400014br.gov.bcb.pix0118fulano@example.com520400005303986540510.005802BR5925Jose da Conceicao Ltda Co6009Sao Paulo62070503***63043F32",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.brCode.Payload(); got != tt.want {
+				t.Errorf("Payload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg" // registra os decodificadores aceitos para o logo
+	_ "image/png"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type ReceiptFormat string
+
+const (
+	ReceiptFormatHTML ReceiptFormat = "html"
+	ReceiptFormatPDF  ReceiptFormat = "pdf"
+)
+
+// ParseReceiptFormat aceita html (padrão) ou pdf
+func ParseReceiptFormat(value string) (ReceiptFormat, error) {
+	switch ReceiptFormat(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ReceiptFormatHTML:
+		return ReceiptFormatHTML, nil
+	case ReceiptFormatPDF:
+		return ReceiptFormatPDF, nil
+	}
+	return "", ErrUnsupportedReceiptFormat
+}
+
+// Limites do logo: ele é gravado no banco e incorporado em cada recibo
+const (
+	maxReceiptLogoSize      = 256 * 1024
+	maxReceiptLogoDimension = 1000
+	maxReceiptFooterLength  = 500
+)
+
+const (
+	defaultReceiptPrimaryColor   = "#1F2937"
+	defaultReceiptSecondaryColor = "#F3F4F6"
+)
+
+// ReceiptSettings personaliza os recibos da conta. O logo é guardado e não buscado numa URL:
+// os recibos são montados sem acessar serviços externos.
+type ReceiptSettings struct {
+	AccountID       string
+	Logo            []byte // PNG ou JPEG; vazio quando a conta não tem logo
+	LogoContentType string
+	PrimaryColor    string // #RRGGBB do cabeçalho
+	SecondaryColor  string // #RRGGBB do fundo do cabeçalho da tabela de itens
+	Footer          string // texto livre no fim do recibo, ex.: dados de contato ou política de trocas
+	UpdatedAt       time.Time
+}
+
+// DefaultReceiptSettings é usado pelas contas que nunca personalizaram os recibos
+func DefaultReceiptSettings(accountID string) *ReceiptSettings {
+	return &ReceiptSettings{
+		AccountID:      accountID,
+		PrimaryColor:   defaultReceiptPrimaryColor,
+		SecondaryColor: defaultReceiptSecondaryColor,
+	}
+}
+
+// NewReceiptSettings valida a personalização; cores vazias usam as padrão
+func NewReceiptSettings(accountID string, logo []byte, primaryColor, secondaryColor, footer string, now time.Time) (*ReceiptSettings, error) {
+	settings := DefaultReceiptSettings(accountID)
+	settings.UpdatedAt = now
+
+	if primaryColor = strings.TrimSpace(primaryColor); primaryColor != "" {
+		settings.PrimaryColor = strings.ToUpper(primaryColor)
+	}
+	if secondaryColor = strings.TrimSpace(secondaryColor); secondaryColor != "" {
+		settings.SecondaryColor = strings.ToUpper(secondaryColor)
+	}
+
+	if !validHexColor(settings.PrimaryColor) || !validHexColor(settings.SecondaryColor) {
+		return nil, ErrInvalidReceiptSettings
+	}
+
+	settings.Footer = strings.TrimSpace(footer)
+	if utf8.RuneCountInString(settings.Footer) > maxReceiptFooterLength {
+		return nil, ErrInvalidReceiptSettings
+	}
+
+	if len(logo) > 0 {
+		contentType, err := receiptLogoContentType(logo)
+		if err != nil {
+			return nil, err
+		}
+		settings.Logo = logo
+		settings.LogoContentType = contentType
+	}
+
+	return settings, nil
+}
+
+// receiptLogoContentType confere o formato e as dimensões do logo sem decodificar os pixels
+func receiptLogoContentType(logo []byte) (string, error) {
+	if len(logo) > maxReceiptLogoSize {
+		return "", ErrInvalidReceiptLogo
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(logo))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return "", ErrInvalidReceiptLogo
+	}
+
+	if config.Width > maxReceiptLogoDimension || config.Height > maxReceiptLogoDimension {
+		return "", ErrInvalidReceiptLogo
+	}
+
+	return "image/" + format, nil
+}
+
+// validHexColor aceita apenas #RRGGBB, o que também impede injetar CSS nos recibos
+func validHexColor(color string) bool {
+	if len(color) != 7 || color[0] != '#' {
+		return false
+	}
+
+	for _, r := range color[1:] {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'F') {
+			return false
+		}
+	}
+	return true
+}
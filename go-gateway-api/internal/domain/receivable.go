@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxSettlementDelayDays = 365
+
+// SettlementSchedule define em quantos dias corridos após a aprovação o valor fica disponível.
+// Com PerInstallment, cada parcela é liquidada DelayDays após a anterior (ex.: D+30 por parcela);
+// sem ele, todas as parcelas são liquidadas juntas em D+DelayDays.
+type SettlementSchedule struct {
+	DelayDays      int
+	PerInstallment bool
+}
+
+// DefaultSettlementSchedule é o prazo usual de cartão de crédito (D+30)
+var DefaultSettlementSchedule = SettlementSchedule{DelayDays: 30}
+
+func (s SettlementSchedule) Validate() error {
+	if s.DelayDays < 0 || s.DelayDays > maxSettlementDelayDays {
+		return ErrInvalidSettlementSchedule
+	}
+	return nil
+}
+
+// Receivables divide o valor líquido da fatura em recebíveis com a data de liquidação de cada parcela.
+// Os centavos que sobram da divisão ficam na primeira parcela.
+func (s SettlementSchedule) Receivables(invoice *Invoice, installments int, approvedAt time.Time) []*Receivable {
+	if installments < 1 {
+		installments = 1
+	}
+
+	approvalDate := truncateToDate(approvedAt)
+
+	// A divisão é feita em centavos inteiros para não acumular erro de ponto flutuante
+	totalCents := int64(math.Round(invoice.NetAmount * 100))
+	installmentCents := totalCents / int64(installments)
+	remainderCents := totalCents - installmentCents*int64(installments)
+
+	receivables := make([]*Receivable, installments)
+	for i := range receivables {
+		number := i + 1
+
+		cents := installmentCents
+		if number == 1 {
+			cents += remainderCents
+		}
+
+		delay := s.DelayDays
+		if s.PerInstallment {
+			delay = s.DelayDays * number
+		}
+
+		receivables[i] = &Receivable{
+			ID:                uuid.New().String(),
+			AccountID:         invoice.AccountID,
+			InvoiceID:         invoice.ID,
+			InstallmentNumber: number,
+			Amount:            float64(cents) / 100,
+			DueDate:           approvalDate.AddDate(0, 0, delay),
+			Status:            ReceivableStatusScheduled,
+			CreatedAt:         approvedAt,
+		}
+	}
+
+	return receivables
+}
+
+type ReceivableStatus string
+
+const (
+	ReceivableStatusScheduled   ReceivableStatus = "scheduled"   // compõe o saldo pendente
+	ReceivableStatusSettled     ReceivableStatus = "settled"     // já movido para o saldo disponível
+	ReceivableStatusAnticipated ReceivableStatus = "anticipated" // liquidado antes do vencimento, com desconto
+)
+
+// Receivable é o valor de uma parcela aprovada a ser liquidado para a conta em DueDate
+type Receivable struct {
+	ID                string
+	AccountID         string
+	InvoiceID         string
+	InstallmentNumber int
+	Amount            float64
+	DueDate           time.Time // data (UTC) sem horário
+	Status            ReceivableStatus
+	SettledAt         time.Time // zero enquanto agendado
+	AnticipationID    string    // vazio quando não foi antecipado
+	CreatedAt         time.Time
+}
+
+// UpcomingSettlement agrupa os recebíveis agendados de uma conta por data de liquidação
+type UpcomingSettlement struct {
+	Date        time.Time
+	Amount      float64
+	Receivables int
+}
+
+// SumReceivables soma os valores arredondando para centavos
+func SumReceivables(receivables []*Receivable) float64 {
+	var total float64
+	for _, receivable := range receivables {
+		total += receivable.Amount
+	}
+	return roundCents(total)
+}
+
+// As datas de liquidação são calculadas em UTC para não depender do fuso da réplica
+func truncateToDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
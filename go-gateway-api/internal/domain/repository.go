@@ -1,16 +1,248 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 // essa interface define como o acesso ao banco de dados deve ser feito
+// Os métodos de escrita recebem os eventos de auditoria da operação e os gravam na mesma
+// transação da alteração
 type AccountRepository interface {
-	Save(account *Account) error
+	Save(account *Account, audit ...*AuditEvent) error
 	FindByAPIKey(apiKey string) (*Account, error)
+	FindByPublishableKey(publishableKey string) (*Account, error)
 	FindByID(id string) (*Account, error)
-	UpdateBalance(account *Account) error
+	// UpdateStatus só altera a conta se o status no banco ainda for from; caso contrário
+	// retorna ErrInvalidAccountStatus
+	UpdateStatus(account *Account, from AccountStatus, audit ...*AuditEvent) error
+	// AdjustBalance aplica o ajuste, preenche BalanceBefore e BalanceAfter e então grava o
+	// evento montado por audit, que depende dos saldos
+	AdjustBalance(adjustment *BalanceAdjustment, audit func() (*AuditEvent, error)) error
+	// UpdateProfile retorna ErrDuplicatedEmail quando o email já pertence a outra conta
+	UpdateProfile(account *Account, audit ...*AuditEvent) error
+	FindAdjustmentsByAccountID(accountID string) ([]*BalanceAdjustment, error)
+	FindLedgerEntriesByAccountID(accountID string) ([]*LedgerEntry, error)
+	// Close só encerra a conta se o saldo no banco ainda for zero
+	Close(account *Account, audit ...*AuditEvent) error
+	UpdateAPIKey(account *Account, audit ...*AuditEvent) error
+	UpdatePublishableKey(account *Account, audit ...*AuditEvent) error
+	UpdateSettlementSchedule(account *Account, audit ...*AuditEvent) error
 }
 
 type InvoiceRepository interface {
-	Save(invoice *Invoice) error
+	// Save grava a fatura; approval (apenas para faturas aprovadas) é gravada na mesma transação
+	Save(invoice *Invoice, approval *InvoiceApproval, audit ...*AuditEvent) error
 	FindByID(id string) (*Invoice, error)
 	FindByAccountID(accountID string) ([]*Invoice, error)
+	// FindByPayerDocumentHash busca pelo hash do documento do pagador; accountID vazio busca
+	// em todas as contas
+	FindByPayerDocumentHash(hash, accountID string) ([]*Invoice, error)
 	UpdateStatus(invoice *Invoice) error
-}
\ No newline at end of file
+	// ApplyTransactionResult registra o evento, atualiza o status da fatura pendente e
+	// grava as taxas, os recebíveis (se aprovada) e a ação do revisor (nas decisões manuais,
+	// nil nas demais) em uma única transação
+	ApplyTransactionResult(eventID string, invoice *Invoice, approval *InvoiceApproval, review *ReviewActionEntry, audit ...*AuditEvent) error
+	FindPendingPublishedBefore(before time.Time, limit int) ([]*Invoice, error)
+	MarkPublished(invoice *Invoice) error
+	// Expire marca o evento invoice.expired como pendente na mesma transação da expiração;
+	// FindExpiredEventPending e MarkExpiredEventPublished controlam a publicação
+	Expire(invoice *Invoice, audit ...*AuditEvent) error
+	FindExpiredEventPending(limit int) ([]*Invoice, error)
+	MarkExpiredEventPublished(invoice *Invoice) error
+}
+
+type ReviewRepository interface {
+	FindPendingQueue(limit int) ([]*ReviewQueueItem, error)
+	FindClaim(invoiceID string) (*ReviewClaim, error)
+	// Claim cria ou renova o claim se não houver outro analista com claim ativo
+	Claim(claim *ReviewClaim, entry *ReviewActionEntry, audit ...*AuditEvent) error
+	Release(invoiceID string, entry *ReviewActionEntry, audit ...*AuditEvent) error
+	FindActionsByInvoiceID(invoiceID string) ([]*ReviewActionEntry, error)
+}
+
+// AuditRepository consulta a trilha de auditoria. Os eventos são gravados pelos próprios
+// repositórios, na transação da operação auditada, encadeados ao último evento (preenchendo
+// Sequence, PrevHash e Hash) de forma serializada entre réplicas.
+type AuditRepository interface {
+	Find(filter AuditFilter) ([]*AuditEvent, error)
+	// FindAfterSequence retorna até limit eventos com sequence maior que after, em ordem
+	FindAfterSequence(after int64, limit int) ([]*AuditEvent, error)
+}
+
+type PayoutRepository interface {
+	SaveDestination(destination *PayoutDestination, audit ...*AuditEvent) error
+	FindDestinationByID(id string) (*PayoutDestination, error)
+	FindDestinationsByAccountID(accountID string) ([]*PayoutDestination, error)
+	// Create debita o valor do saldo e grava o saque na mesma transação,
+	// retornando ErrInsufficientBalance quando o saldo não cobre o saque
+	Create(payout *Payout, audit ...*AuditEvent) error
+	FindByID(id string) (*Payout, error)
+	FindByAccountID(accountID string) ([]*Payout, error)
+	FindByStatus(status PayoutStatus, limit int) ([]*Payout, error)
+	// UpdateStatus só altera o saque se ele ainda estiver em from
+	UpdateStatus(payout *Payout, from PayoutStatus, audit ...*AuditEvent) error
+	// Fail marca o saque como falho e devolve o valor ao saldo na mesma transação
+	Fail(payout *Payout, from PayoutStatus, audit ...*AuditEvent) error
+}
+
+type ReceivableRepository interface {
+	FindByInvoiceID(invoiceID string) ([]*Receivable, error)
+	FindByAccountID(accountID string) ([]*Receivable, error)
+	// FindUpcomingByAccountID agrupa os recebíveis agendados por data de liquidação
+	FindUpcomingByAccountID(accountID string) ([]*UpcomingSettlement, error)
+	// SettleDue liquida até limit recebíveis vencidos até date, movendo os valores do saldo
+	// pendente para o disponível, e retorna quantos foram liquidados
+	SettleDue(date time.Time, limit int) (int, error)
+}
+
+type AnticipationRepository interface {
+	// FindAnticipatable retorna os recebíveis agendados da conta que vencem depois de date;
+	// com ids vazio retorna todos
+	FindAnticipatable(accountID string, ids []string, date time.Time) ([]*Receivable, error)
+	// Create marca os recebíveis como antecipados, move os valores do saldo pendente para o
+	// disponível e grava os lançamentos na mesma transação; retorna ErrReceivableNotAnticipatable
+	// se algum recebível foi liquidado ou antecipado nesse meio tempo
+	Create(anticipation *Anticipation, entries []*LedgerEntry, audit ...*AuditEvent) error
+	FindByID(id string) (*Anticipation, error)
+	FindByAccountID(accountID string) ([]*Anticipation, error)
+}
+
+type PixRepository interface {
+	// Create grava a fatura e a cobrança Pix na mesma transação
+	Create(invoice *Invoice, charge *PixCharge, audit ...*AuditEvent) error
+	FindByInvoiceID(invoiceID string) (*PixCharge, error)
+	FindByTxID(txID string) (*PixCharge, error)
+	FindByAccountID(accountID string) ([]*PixCharge, error)
+	// ConfirmPayment marca a cobrança como paga e grava a aprovação da fatura na mesma transação
+	ConfirmPayment(charge *PixCharge, invoice *Invoice, approval *InvoiceApproval, audit ...*AuditEvent) error
+	FindExpired(now time.Time, limit int) ([]*PixCharge, error)
+	Expire(charge *PixCharge, invoice *Invoice, audit ...*AuditEvent) error
+}
+
+type BoletoRepository interface {
+	// NextOurNumber reserva o próximo nosso número dos boletos emitidos
+	NextOurNumber() (int64, error)
+	// Create grava a fatura e o boleto na mesma transação
+	Create(invoice *Invoice, boleto *Boleto, audit ...*AuditEvent) error
+	FindByInvoiceID(invoiceID string) (*Boleto, error)
+	FindByOurNumber(bankCode, ourNumber string) (*Boleto, error)
+	FindByAccountID(accountID string) ([]*Boleto, error)
+	// ConfirmPayment marca o boleto como pago e grava a aprovação da fatura na mesma transação
+	ConfirmPayment(boleto *Boleto, invoice *Invoice, approval *InvoiceApproval, audit ...*AuditEvent) error
+	FindExpired(date time.Time, limit int) ([]*Boleto, error)
+	Expire(boleto *Boleto, invoice *Invoice, audit ...*AuditEvent) error
+}
+
+type CNABRepository interface {
+	NextRemittanceSequence() (int64, error)
+	FindBoletosToRemit(limit int) ([]*Boleto, error)
+	FindPayoutsToRemit(limit int) ([]PayoutRemittanceEntry, error)
+	// CreateRemittance grava a remessa e marca os boletos como enviados e os saques como em
+	// trânsito na mesma transação
+	CreateRemittance(file *CNABFile, boletos []*Boleto, payouts []*Payout, audit ...*AuditEvent) error
+	// CreateReturn retorna ErrDuplicateCNABFile se o mesmo conteúdo já foi processado
+	CreateReturn(file *CNABFile, audit ...*AuditEvent) error
+	FindByID(id string) (*CNABFile, error)
+	FindByChecksum(fileType CNABFileType, checksum string) (*CNABFile, error)
+	List(limit int) ([]*CNABFile, error)
+	FindPayoutByReference(reference string) (*Payout, error)
+}
+
+type FeeRepository interface {
+	FindRulesByAccountID(accountID string) ([]*FeeRule, error)
+	// ReplaceRules substitui todas as regras da conta de uma vez
+	ReplaceRules(accountID string, rules []*FeeRule, audit ...*AuditEvent) error
+	FindInvoiceFeesByAccountID(accountID string) ([]*InvoiceFee, error)
+}
+
+type FXRepository interface {
+	// FindRate retorna a última cotação importada do par; ErrExchangeRateNotFound quando não há
+	FindRate(base, quote Currency) (*ExchangeRate, error)
+	FindOverride(base, quote Currency) (*ExchangeRateOverride, error)
+	ListOverrides() ([]*ExchangeRateOverride, error)
+	// SaveOverride substitui a cotação manual vigente do par
+	SaveOverride(override *ExchangeRateOverride, audit ...*AuditEvent) error
+	DeleteOverride(base, quote Currency, audit ...*AuditEvent) error
+}
+
+type CustomerRepository interface {
+	Save(customer *Customer, audit ...*AuditEvent) error
+	FindByID(id string) (*Customer, error)
+	FindByAccountID(accountID string) ([]*Customer, error)
+	SavePaymentMethod(method *PaymentMethod, audit ...*AuditEvent) error
+	FindPaymentMethodByID(id string) (*PaymentMethod, error)
+	FindPaymentMethodsByCustomerID(customerID string) ([]*PaymentMethod, error)
+	FindPaymentMethodsByAccountID(accountID string) ([]*PaymentMethod, error)
+}
+
+type SubscriptionRepository interface {
+	SavePlan(plan *Plan, audit ...*AuditEvent) error
+	FindPlanByID(id string) (*Plan, error)
+	FindPlansByAccountID(accountID string) ([]*Plan, error)
+	Create(subscription *Subscription, audit ...*AuditEvent) error
+	FindByID(id string) (*Subscription, error)
+	FindByAccountID(accountID string) ([]*Subscription, error)
+	// Update só grava se a assinatura não foi alterada desde a leitura (mesma Version),
+	// retornando ErrSubscriptionConflict caso contrário, e incrementa Version
+	Update(subscription *Subscription, audit ...*AuditEvent) error
+	// SaveCharge grava a fatura da cobrança, a aprovação (se aprovada) e a assinatura na
+	// mesma transação, com a mesma verificação de versão de Update
+	SaveCharge(subscription *Subscription, invoice *Invoice, approval *InvoiceApproval, audit ...*AuditEvent) error
+	// FindDue retorna as assinaturas em teste ou ativas, de contas ativas, cuja cobrança venceu
+	// até now e as em atraso cuja nova tentativa venceu até now
+	FindDue(now time.Time, limit int) ([]*Subscription, error)
+	// FindDecidedCharges retorna as assinaturas que aguardam uma fatura de cobrança já decidida
+	FindDecidedCharges(limit int) ([]*Subscription, error)
+}
+
+type CheckoutRepository interface {
+	CreateSession(session *CheckoutSession, audit ...*AuditEvent) error
+	FindSessionByID(id string) (*CheckoutSession, error)
+	FindSessionsByAccountID(accountID string) ([]*CheckoutSession, error)
+	// CountOpenSessionsByPaymentLinkID conta as sessões do link abertas ou em pagamento dentro do prazo
+	CountOpenSessionsByPaymentLinkID(paymentLinkID string, now time.Time) (int, error)
+	// UpdateSessionStatus só grava se a sessão ainda estiver no status from, retornando
+	// ErrCheckoutSessionNotOpen caso contrário
+	UpdateSessionStatus(session *CheckoutSession, from CheckoutSessionStatus) error
+	// SavePayment grava a fatura, a aprovação e a sessão na mesma transação, com a mesma trava
+	// de UpdateSessionStatus
+	SavePayment(session *CheckoutSession, from CheckoutSessionStatus, invoice *Invoice, approval *InvoiceApproval, audit ...*AuditEvent) error
+	SavePaymentLink(link *PaymentLink, audit ...*AuditEvent) error
+	FindPaymentLinkByID(id string) (*PaymentLink, error)
+	FindPaymentLinksByAccountID(accountID string) ([]*PaymentLink, error)
+	UpdatePaymentLink(link *PaymentLink, audit ...*AuditEvent) error
+}
+
+type CardTokenRepository interface {
+	// Create também apaga os tokens vencidos, que não podem mais ser usados
+	Create(token *CardToken) error
+	FindByID(id string) (*CardToken, error)
+	// MarkUsed apaga o cartão cifrado e só grava se o token ainda não foi usado,
+	// retornando ErrCardTokenUsed caso contrário
+	MarkUsed(token *CardToken) error
+}
+
+// CouponRepository guarda os cupons; os resgates são contados ao gravar a fatura com o
+// desconto, na mesma transação
+type CouponRepository interface {
+	Save(coupon *Coupon, audit ...*AuditEvent) error
+	FindByID(id string) (*Coupon, error)
+	FindByCode(accountID, code string) (*Coupon, error)
+	FindByAccountID(accountID string) ([]*Coupon, error)
+	Update(coupon *Coupon, audit ...*AuditEvent) error
+}
+
+type ReceiptSettingsRepository interface {
+	// FindByAccountID retorna ErrReceiptSettingsNotFound quando a conta nunca personalizou
+	// os recibos
+	FindByAccountID(accountID string) (*ReceiptSettings, error)
+	// Save cria ou substitui a personalização da conta
+	Save(settings *ReceiptSettings, audit ...*AuditEvent) error
+}
+
+// Locker coordena tarefas que devem rodar em apenas uma réplica por vez
+type Locker interface {
+	// TryLock não bloqueia: acquired é false quando outra réplica já possui o lock
+	TryLock(ctx context.Context, name string) (release func() error, acquired bool, err error)
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReviewAction string
+
+const (
+	ReviewActionClaim   ReviewAction = "claim"
+	ReviewActionRelease ReviewAction = "release"
+	ReviewActionApprove ReviewAction = "approve"
+	ReviewActionReject  ReviewAction = "reject"
+)
+
+// ReviewClaim reserva uma fatura pendente para um analista até ExpiresAt
+type ReviewClaim struct {
+	InvoiceID string
+	Reviewer  string
+	ClaimedAt time.Time
+	ExpiresAt time.Time
+}
+
+func NewReviewClaim(invoiceID, reviewer string, ttl time.Duration) *ReviewClaim {
+	now := time.Now()
+	return &ReviewClaim{
+		InvoiceID: invoiceID,
+		Reviewer:  reviewer,
+		ClaimedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+func (c *ReviewClaim) IsActive() bool {
+	return time.Now().Before(c.ExpiresAt)
+}
+
+// HeldBy indica se o analista possui um claim ainda válido
+func (c *ReviewClaim) HeldBy(reviewer string) bool {
+	return c.Reviewer == reviewer && c.IsActive()
+}
+
+// ReviewActionEntry é um registro imutável da trilha de auditoria da revisão manual
+type ReviewActionEntry struct {
+	ID        string
+	InvoiceID string
+	Reviewer  string
+	Action    ReviewAction
+	Reason    string
+	CreatedAt time.Time
+}
+
+func NewReviewActionEntry(invoiceID, reviewer string, action ReviewAction, reason string) (*ReviewActionEntry, error) {
+	reason = strings.TrimSpace(reason)
+	if (action == ReviewActionApprove || action == ReviewActionReject) && reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	return &ReviewActionEntry{
+		ID:        uuid.New().String(),
+		InvoiceID: invoiceID,
+		Reviewer:  reviewer,
+		Action:    action,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// DecisionStatus converte a ação de decisão no status final da fatura
+func (e *ReviewActionEntry) DecisionStatus() (Status, error) {
+	switch e.Action {
+	case ReviewActionApprove:
+		return StatusApproved, nil
+	case ReviewActionReject:
+		return StatusRejected, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
+
+// ReviewQueueItem reúne a fatura pendente e o contexto de risco da conta
+type ReviewQueueItem struct {
+	Invoice          *Invoice
+	AccountName      string
+	AccountEmail     string
+	AccountBalance   float64
+	AccountCreatedAt time.Time
+	ApprovedCount    int
+	ApprovedTotal    float64 // soma dos valores aprovados, na moeda da conta
+	RejectedCount    int
+	PendingCount     int
+	Claim            *ReviewClaim // nil quando ninguém possui um claim válido
+}
@@ -0,0 +1,381 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BillingInterval string
+
+const (
+	BillingIntervalDay   BillingInterval = "day"
+	BillingIntervalWeek  BillingInterval = "week"
+	BillingIntervalMonth BillingInterval = "month"
+	BillingIntervalYear  BillingInterval = "year"
+)
+
+const (
+	maxPlanIntervalCount = 12
+	maxPlanTrialDays     = 365
+)
+
+func (i BillingInterval) Valid() bool {
+	return i == BillingIntervalDay || i == BillingIntervalWeek || i == BillingIntervalMonth || i == BillingIntervalYear
+}
+
+// Plan define o valor e a periodicidade de uma assinatura: IntervalCount intervalos entre as
+// cobranças (ex.: 3 meses) e TrialDays dias de teste gratuito antes da primeira
+type Plan struct {
+	ID            string
+	AccountID     string
+	Name          string
+	Amount        float64
+	Currency      Currency
+	Interval      BillingInterval
+	IntervalCount int
+	TrialDays     int
+	CreatedAt     time.Time
+}
+
+func NewPlan(accountID, name string, amount float64, currency Currency, interval BillingInterval, intervalCount, trialDays int) (*Plan, error) {
+	if intervalCount == 0 {
+		intervalCount = 1
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || !interval.Valid() || intervalCount < 1 || intervalCount > maxPlanIntervalCount ||
+		trialDays < 0 || trialDays > maxPlanTrialDays {
+		return nil, ErrInvalidPlan
+	}
+
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	if !currency.Valid() {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	return &Plan{
+		ID:            uuid.New().String(),
+		AccountID:     accountID,
+		Name:          name,
+		Amount:        roundCents(amount),
+		Currency:      currency,
+		Interval:      interval,
+		IntervalCount: intervalCount,
+		TrialDays:     trialDays,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// NextBillingDate soma um período à data de cobrança mantendo o dia do ciclo: uma assinatura
+// mensal iniciada no dia 31 é cobrada no último dia dos meses mais curtos
+func (p *Plan) NextBillingDate(from time.Time, anchorDay int) time.Time {
+	months := 0
+	switch p.Interval {
+	case BillingIntervalDay:
+		return from.AddDate(0, 0, p.IntervalCount)
+	case BillingIntervalWeek:
+		return from.AddDate(0, 0, 7*p.IntervalCount)
+	case BillingIntervalMonth:
+		months = p.IntervalCount
+	case BillingIntervalYear:
+		months = 12 * p.IntervalCount
+	}
+
+	firstDay := time.Date(from.Year(), from.Month()+time.Month(months), 1, from.Hour(), from.Minute(), from.Second(), 0, from.Location())
+	lastDay := firstDay.AddDate(0, 1, -1).Day()
+	return firstDay.AddDate(0, 0, min(anchorDay, lastDay)-1)
+}
+
+// compatible indica se a assinatura pode trocar de um plano para o outro: a proporção é
+// calculada sobre o mesmo ciclo e na mesma moeda
+func (p *Plan) compatible(other *Plan) bool {
+	return p.AccountID == other.AccountID && p.Currency == other.Currency &&
+		p.Interval == other.Interval && p.IntervalCount == other.IntervalCount
+}
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusTrialing SubscriptionStatus = "trialing" // em teste, sem cobrança até TrialEnd
+	SubscriptionStatusActive   SubscriptionStatus = "active"
+	SubscriptionStatusPastDue  SubscriptionStatus = "past_due" // a cobrança do período foi recusada e será tentada de novo
+	SubscriptionStatusUnpaid   SubscriptionStatus = "unpaid"   // as tentativas acabaram; sem cobranças até o cartão ser trocado
+	SubscriptionStatusCanceled SubscriptionStatus = "canceled"
+)
+
+// Subscription cobra o plano do cliente no cartão salvo a cada período. CurrentPeriodEnd é a
+// data da próxima cobrança, que paga o período seguinte.
+type Subscription struct {
+	ID                 string
+	AccountID          string
+	CustomerID         string
+	PlanID             string
+	PaymentMethodID    string
+	Status             SubscriptionStatus
+	CurrentPeriodStart time.Time
+	CurrentPeriodEnd   time.Time
+	BillingCycleAnchor time.Time // define o dia do mês das cobranças
+	TrialEnd           time.Time // zero quando o plano não tem teste
+	CancelAtPeriodEnd  bool
+	CanceledAt         time.Time
+	// ProrationAmount é o ajuste das trocas de plano no meio do período, na moeda do plano:
+	// positivo é cobrado e negativo é abatido da próxima cobrança
+	ProrationAmount float64
+	LatestInvoiceID string    // última fatura de cobrança, vazio até a primeira
+	ChargePending   bool      // a última fatura aguarda a análise de fraude
+	FailedAttempts  int       // cobranças recusadas seguidas do período atual
+	NextRetryAt     time.Time // próxima tentativa da cobrança em atraso, zero fora de past_due
+	Version         int       // controle de concorrência entre a cobrança e as alterações do lojista
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewSubscription inicia a assinatura em teste quando o plano tem trial; caso contrário ela é
+// ativada com a primeira cobrança já vencida, feita em seguida
+func NewSubscription(customer *Customer, plan *Plan, method *PaymentMethod, now time.Time) (*Subscription, error) {
+	if plan.AccountID != customer.AccountID {
+		return nil, ErrPlanNotFound
+	}
+
+	if method.CustomerID != customer.ID {
+		return nil, ErrPaymentMethodNotFound
+	}
+
+	subscription := &Subscription{
+		ID:                 uuid.New().String(),
+		AccountID:          customer.AccountID,
+		CustomerID:         customer.ID,
+		PlanID:             plan.ID,
+		PaymentMethodID:    method.ID,
+		Status:             SubscriptionStatusActive,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now,
+		BillingCycleAnchor: now,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	if plan.TrialDays > 0 {
+		subscription.Status = SubscriptionStatusTrialing
+		subscription.TrialEnd = now.AddDate(0, 0, plan.TrialDays)
+		subscription.CurrentPeriodEnd = subscription.TrialEnd
+		subscription.BillingCycleAnchor = subscription.TrialEnd
+	}
+
+	return subscription, nil
+}
+
+// Due indica se a cobrança do próximo período já venceu ou se chegou a hora de tentar de
+// novo a cobrança em atraso
+func (s *Subscription) Due(now time.Time) bool {
+	if s.ChargePending {
+		return false
+	}
+
+	switch s.Status {
+	case SubscriptionStatusTrialing, SubscriptionStatusActive:
+		return !s.CurrentPeriodEnd.After(now)
+	case SubscriptionStatusPastDue:
+		return !s.NextRetryAt.IsZero() && !s.NextRetryAt.After(now)
+	}
+	return false
+}
+
+// NextPeriod é o período pago pela próxima cobrança
+func (s *Subscription) NextPeriod(plan *Plan) (time.Time, time.Time) {
+	return s.CurrentPeriodEnd, plan.NextBillingDate(s.CurrentPeriodEnd, s.BillingCycleAnchor.Day())
+}
+
+// ChargeAmount é o valor da próxima cobrança: o plano mais o ajuste das trocas de plano.
+// Zero ou negativo significa que o crédito cobre o período.
+func (s *Subscription) ChargeAmount(plan *Plan) float64 {
+	return roundCents(plan.Amount + s.ProrationAmount)
+}
+
+// StartCharge associa a fatura da cobrança; o resultado é aplicado por ApplyCharge
+func (s *Subscription) StartCharge(invoice *Invoice, now time.Time) {
+	s.LatestInvoiceID = invoice.ID
+	s.ChargePending = true
+	s.UpdatedAt = now
+}
+
+// ApplyCharge aplica o resultado da fatura da cobrança: aprovada renova o período, recusada ou
+// expirada deixa a assinatura em atraso conforme a política de novas tentativas e pendente
+// aguarda a análise de fraude
+func (s *Subscription) ApplyCharge(invoice *Invoice, plan *Plan, policy DunningPolicy, now time.Time) error {
+	if invoice.ID != s.LatestInvoiceID {
+		return ErrInvalidStatus
+	}
+
+	if invoice.Status == StatusPending {
+		return nil
+	}
+
+	s.ChargePending = false
+	s.UpdatedAt = now
+
+	// Cancelada durante a análise: o resultado não reativa a assinatura
+	if s.Status == SubscriptionStatusCanceled {
+		return nil
+	}
+
+	if invoice.Status == StatusApproved {
+		s.FailedAttempts = 0
+		s.NextRetryAt = time.Time{}
+		s.renew(plan, 0, now)
+		return nil
+	}
+
+	s.FailedAttempts++
+	if retryAt, retry := policy.NextRetry(s.FailedAttempts, invoice.DeclineReason, s.CurrentPeriodEnd); retry {
+		s.Status = SubscriptionStatusPastDue
+		s.NextRetryAt = retryAt
+		return nil
+	}
+
+	s.NextRetryAt = time.Time{}
+	s.Status = policy.FinalStatus
+	if s.Status == SubscriptionStatusCanceled {
+		s.CancelAtPeriodEnd = false
+		s.CanceledAt = now
+	}
+	return nil
+}
+
+// CoverWithCredit renova o período sem cobrança quando o crédito das trocas de plano cobre o
+// valor; o que sobra do crédito fica para a próxima cobrança
+func (s *Subscription) CoverWithCredit(plan *Plan, now time.Time) {
+	s.renew(plan, s.ChargeAmount(plan), now)
+}
+
+func (s *Subscription) renew(plan *Plan, remainingProration float64, now time.Time) {
+	s.CurrentPeriodStart, s.CurrentPeriodEnd = s.NextPeriod(plan)
+	s.Status = SubscriptionStatusActive
+	s.ProrationAmount = remainingProration
+	s.UpdatedAt = now
+}
+
+// ChangePlan troca o plano. Fora do teste, a diferença entre os planos proporcional aos dias
+// que faltam no período entra na próxima cobrança (crédito no downgrade, débito no upgrade).
+// Retorna o ajuste calculado.
+func (s *Subscription) ChangePlan(current, next *Plan, now time.Time) (float64, error) {
+	// Durante a análise de uma cobrança o ajuste se perderia na renovação
+	if s.Status != SubscriptionStatusTrialing && s.Status != SubscriptionStatusActive || s.ChargePending {
+		return 0, ErrInvalidSubscriptionStatus
+	}
+
+	if current.ID == next.ID || !current.compatible(next) {
+		return 0, ErrIncompatiblePlan
+	}
+
+	var proration float64
+	if s.Status == SubscriptionStatusActive {
+		proration = roundCents((next.Amount - current.Amount) * s.remainingFraction(now))
+	}
+
+	s.PlanID = next.ID
+	s.ProrationAmount = roundCents(s.ProrationAmount + proration)
+	s.UpdatedAt = now
+	return proration, nil
+}
+
+// remainingFraction é a parte do período atual que ainda não foi usada
+func (s *Subscription) remainingFraction(now time.Time) float64 {
+	length := s.CurrentPeriodEnd.Sub(s.CurrentPeriodStart)
+	if length <= 0 || !now.Before(s.CurrentPeriodEnd) {
+		return 0
+	}
+
+	if now.Before(s.CurrentPeriodStart) {
+		return 1
+	}
+	return float64(s.CurrentPeriodEnd.Sub(now)) / float64(length)
+}
+
+// ChangePaymentMethod troca o cartão usado nas próximas cobranças. Uma assinatura em atraso ou
+// sem pagamento tem a cobrança tentada de novo no novo cartão logo em seguida.
+func (s *Subscription) ChangePaymentMethod(method *PaymentMethod, now time.Time) error {
+	if s.Status == SubscriptionStatusCanceled {
+		return ErrInvalidSubscriptionStatus
+	}
+
+	if method.CustomerID != s.CustomerID {
+		return ErrPaymentMethodNotFound
+	}
+
+	s.PaymentMethodID = method.ID
+	if (s.Status == SubscriptionStatusPastDue || s.Status == SubscriptionStatusUnpaid) && !s.ChargePending {
+		s.Status = SubscriptionStatusPastDue
+		s.NextRetryAt = now
+	}
+
+	s.UpdatedAt = now
+	return nil
+}
+
+// Cancel encerra a assinatura na hora ou, com atPeriodEnd, no fim do período já pago (ou do
+// teste), sem novas cobranças. Assinaturas em atraso ou sem pagamento são sempre encerradas
+// na hora.
+func (s *Subscription) Cancel(atPeriodEnd bool, now time.Time) error {
+	if s.Status == SubscriptionStatusCanceled {
+		return ErrInvalidSubscriptionStatus
+	}
+
+	if atPeriodEnd && s.Status != SubscriptionStatusPastDue && s.Status != SubscriptionStatusUnpaid {
+		s.CancelAtPeriodEnd = true
+		s.UpdatedAt = now
+		return nil
+	}
+
+	s.Status = SubscriptionStatusCanceled
+	s.CancelAtPeriodEnd = false
+	s.CanceledAt = now
+	s.NextRetryAt = time.Time{}
+	s.UpdatedAt = now
+	return nil
+}
+
+// NewSubscriptionInvoice cria a fatura de cartão do próximo período, cobrada no cartão salvo na
+// moeda do plano; a moeda do saldo é definida pelo service com SetCurrency
+func NewSubscriptionInvoice(subscription *Subscription, plan *Plan, customer *Customer, method *PaymentMethod, now time.Time) (*Invoice, error) {
+	amount := subscription.ChargeAmount(plan)
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	start, end := subscription.NextPeriod(plan)
+
+	invoice := &Invoice{
+		ID:                 uuid.New().String(),
+		AccountID:          subscription.AccountID,
+		Amount:             amount,
+		Status:             StatusPending,
+		Description:        "Assinatura " + plan.Name + " (" + start.Format(time.DateOnly) + " a " + end.Format(time.DateOnly) + ")",
+		PaymentType:        PaymentTypeCreditCard,
+		CardLastDigits:     method.CardLastDigits,
+		CardBrand:          method.CardBrand,
+		Installments:       1,
+		InterestMode:       InterestModeMerchant,
+		TotalAmount:        amount,
+		Currency:           plan.Currency,
+		SettlementCurrency: DefaultCurrency,
+		SubscriptionID:     subscription.ID,
+		Payer:              customer.Payer(),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		cardholderName:     method.CardholderName,
+	}
+
+	// Um cartão vencido não tem como ser cobrado: a fatura já nasce recusada
+	if method.Expired(now) {
+		invoice.Status = StatusRejected
+		invoice.DeclineReason = DeclineReasonCardExpired
+	}
+
+	return invoice, nil
+}
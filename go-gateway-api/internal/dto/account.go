@@ -7,34 +7,147 @@ import (
 )
 
 type CreateAccountInput struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Currency string `json:"currency"` // moeda do saldo: BRL (padrão), USD ou EUR
 }
 
 type AccountOutput struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Balance   float64   `json:"balance"`
-	APIKey    string    `json:"api_key,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                 string                   `json:"id"`
+	Name               string                   `json:"name"`
+	Email              string                   `json:"email"`
+	Balance            float64                  `json:"balance"` // saldo disponível
+	PendingBalance     float64                  `json:"pending_balance"`
+	SettlementSchedule SettlementScheduleOutput `json:"settlement_schedule"`
+	Currency           string                   `json:"currency"`
+	Status             string                   `json:"status"`
+	APIKey             string                   `json:"api_key,omitempty"`
+	PublishableKey     string                   `json:"publishable_key"` // pode ser exposta no navegador
+	CreatedAt          time.Time                `json:"created_at"`
+	UpdatedAt          time.Time                `json:"updated_at"`
+}
+
+type SettlementScheduleOutput struct {
+	DelayDays      int  `json:"delay_days"`
+	PerInstallment bool `json:"per_installment"`
+}
+
+type SettlementScheduleInput struct {
+	DelayDays      int  `json:"delay_days"`
+	PerInstallment bool `json:"per_installment"`
 }
 
 // Quando eu tenho um DTO e quero transformar ele em um objeto de domínio
-func ToAccount(input CreateAccountInput) *domain.Account {
-	return domain.NewAccount(input.Name, input.Email)
+func ToAccount(input CreateAccountInput) (*domain.Account, error) {
+	currency, err := domain.ParseCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	account := domain.NewAccount(input.Name, input.Email)
+	account.Currency = currency
+	return account, nil
 }
 
 // Quando eu tenho um objeto de domínio e quero transformar ele em um DTO
 func FromAccount(account *domain.Account) AccountOutput {
 	return AccountOutput{
-		ID:        account.ID,
-		Name:      account.Name,
-		Email:     account.Email,
-		Balance:   account.Balance,
-		APIKey:    account.APIKey,
-		CreatedAt: account.CreatedAt,
-		UpdatedAt: account.UpdatedAt,
+		ID:             account.ID,
+		Name:           account.Name,
+		Email:          account.Email,
+		Balance:        account.Balance,
+		PendingBalance: account.PendingBalance,
+		SettlementSchedule: SettlementScheduleOutput{
+			DelayDays:      account.SettlementSchedule.DelayDays,
+			PerInstallment: account.SettlementSchedule.PerInstallment,
+		},
+		Currency:       string(account.Currency),
+		Status:         string(account.Status),
+		APIKey:         account.APIKey,
+		PublishableKey: account.PublishableKey,
+		CreatedAt:      account.CreatedAt,
+		UpdatedAt:      account.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
+
+// Campos nil não são alterados
+type UpdateAccountInput struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+type AccountStatusInput struct {
+	Reason string `json:"reason"`
+}
+
+type BalanceAdjustmentInput struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason"`
+}
+
+type BalanceAdjustmentOutput struct {
+	ID            string    `json:"id"`
+	AccountID     string    `json:"account_id"`
+	Amount        float64   `json:"amount"`
+	Reason        string    `json:"reason"`
+	Operator      string    `json:"operator"`
+	BalanceBefore float64   `json:"balance_before"`
+	BalanceAfter  float64   `json:"balance_after"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func FromBalanceAdjustment(adjustment *domain.BalanceAdjustment) BalanceAdjustmentOutput {
+	return BalanceAdjustmentOutput{
+		ID:            adjustment.ID,
+		AccountID:     adjustment.AccountID,
+		Amount:        adjustment.Amount,
+		Reason:        adjustment.Reason,
+		Operator:      adjustment.Operator,
+		BalanceBefore: adjustment.BalanceBefore,
+		BalanceAfter:  adjustment.BalanceAfter,
+		CreatedAt:     adjustment.CreatedAt,
+	}
+}
+
+type LedgerEntryOutput struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Amount      float64   `json:"amount"` // negativo nos débitos
+	ReferenceID string    `json:"reference_id,omitempty"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func FromLedgerEntry(entry *domain.LedgerEntry) *LedgerEntryOutput {
+	return &LedgerEntryOutput{
+		ID:          entry.ID,
+		Type:        string(entry.Type),
+		Amount:      entry.Amount,
+		ReferenceID: entry.ReferenceID,
+		Description: entry.Description,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
+
+// AccountExportOutput reúne todos os dados de uma conta (portabilidade/LGPD); as cobranças
+// Pix, os boletos e os recebíveis vêm dentro de cada fatura
+type AccountExportOutput struct {
+	ExportedAt         time.Time                  `json:"exported_at"`
+	Account            AccountOutput              `json:"account"`
+	Invoices           []*InvoiceOutput           `json:"invoices"`
+	InvoiceFees        []*InvoiceFeeOutput        `json:"invoice_fees"`
+	BalanceAdjustments []BalanceAdjustmentOutput  `json:"balance_adjustments"`
+	LedgerEntries      []*LedgerEntryOutput       `json:"ledger_entries"`
+	PayoutDestinations []*PayoutDestinationOutput `json:"payout_destinations"`
+	Payouts            []*PayoutOutput            `json:"payouts"`
+	FeeRules           []*FeeRuleOutput           `json:"fee_rules"`
+	Anticipations      []*AnticipationOutput      `json:"anticipations"`
+	Customers          []*CustomerOutput          `json:"customers"`
+	PaymentMethods     []*PaymentMethodOutput     `json:"payment_methods"`
+	Plans              []*PlanOutput              `json:"plans"`
+	Subscriptions      []*SubscriptionOutput      `json:"subscriptions"`
+	PaymentLinks       []*PaymentLinkOutput       `json:"payment_links"`
+	CheckoutSessions   []*CheckoutSessionOutput   `json:"checkout_sessions"`
+	Coupons            []*CouponOutput            `json:"coupons"`
+	ReceiptSettings    *ReceiptSettingsOutput     `json:"receipt_settings"`
+}
@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// AnticipationInput seleciona os recebíveis a antecipar; vazio antecipa todos os futuros
+type AnticipationInput struct {
+	ReceivableIDs []string `json:"receivable_ids"`
+}
+
+type AnticipationItemOutput struct {
+	ReceivableID      string  `json:"receivable_id"`
+	InvoiceID         string  `json:"invoice_id"`
+	InstallmentNumber int     `json:"installment_number"`
+	DueDate           string  `json:"due_date"` // AAAA-MM-DD
+	Days              int     `json:"days"`
+	Amount            float64 `json:"amount"`
+	Discount          float64 `json:"discount"`
+	NetAmount         float64 `json:"net_amount"`
+}
+
+type AnticipationOutput struct {
+	ID             string                    `json:"id,omitempty"` // vazio na simulação
+	MonthlyRate    float64                   `json:"monthly_rate"`
+	GrossAmount    float64                   `json:"gross_amount"`
+	DiscountAmount float64                   `json:"discount_amount"`
+	NetAmount      float64                   `json:"net_amount"`
+	Items          []*AnticipationItemOutput `json:"items,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+}
+
+type AnticipatableReceivableOutput struct {
+	ID                string  `json:"id"`
+	InvoiceID         string  `json:"invoice_id"`
+	InstallmentNumber int     `json:"installment_number"`
+	Amount            float64 `json:"amount"`
+	DueDate           string  `json:"due_date"` // AAAA-MM-DD
+}
+
+func FromAnticipation(anticipation *domain.Anticipation) *AnticipationOutput {
+	output := &AnticipationOutput{
+		ID:             anticipation.ID,
+		MonthlyRate:    anticipation.MonthlyRate,
+		GrossAmount:    anticipation.GrossAmount,
+		DiscountAmount: anticipation.DiscountAmount,
+		NetAmount:      anticipation.NetAmount,
+		Items:          make([]*AnticipationItemOutput, len(anticipation.Items)),
+		CreatedAt:      anticipation.CreatedAt,
+	}
+
+	for i, item := range anticipation.Items {
+		output.Items[i] = &AnticipationItemOutput{
+			ReceivableID:      item.ReceivableID,
+			InvoiceID:         item.InvoiceID,
+			InstallmentNumber: item.InstallmentNumber,
+			DueDate:           item.DueDate.Format(time.DateOnly),
+			Days:              item.Days,
+			Amount:            item.Amount,
+			Discount:          item.Discount,
+			NetAmount:         item.NetAmount,
+		}
+	}
+
+	return output
+}
+
+func FromAnticipatableReceivables(receivables []*domain.Receivable) []*AnticipatableReceivableOutput {
+	output := make([]*AnticipatableReceivableOutput, len(receivables))
+	for i, receivable := range receivables {
+		output[i] = &AnticipatableReceivableOutput{
+			ID:                receivable.ID,
+			InvoiceID:         receivable.InvoiceID,
+			InstallmentNumber: receivable.InstallmentNumber,
+			Amount:            receivable.Amount,
+			DueDate:           receivable.DueDate.Format(time.DateOnly),
+		}
+	}
+	return output
+}
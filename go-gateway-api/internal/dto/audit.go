@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type AuditEventOutput struct {
+	ID         string          `json:"id"`
+	Sequence   int64           `json:"sequence"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    string          `json:"actor_id"`
+	ActorIP    string          `json:"actor_ip,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+type AuditVerificationOutput struct {
+	Valid            bool   `json:"valid"`
+	CheckedEvents    int    `json:"checked_events"`
+	BrokenAtSequence *int64 `json:"broken_at_sequence,omitempty"`
+	LastHash         string `json:"last_hash,omitempty"`
+}
+
+func FromAuditEvent(event *domain.AuditEvent) *AuditEventOutput {
+	return &AuditEventOutput{
+		ID:         event.ID,
+		Sequence:   event.Sequence,
+		ActorType:  string(event.Actor.Type),
+		ActorID:    event.Actor.ID,
+		ActorIP:    event.Actor.IP,
+		Action:     string(event.Action),
+		EntityType: event.EntityType,
+		EntityID:   event.EntityID,
+		Before:     event.Before,
+		After:      event.After,
+		OccurredAt: event.OccurredAt,
+		PrevHash:   event.PrevHash,
+		Hash:       event.Hash,
+	}
+}
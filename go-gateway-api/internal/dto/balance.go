@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type UpcomingSettlementOutput struct {
+	Date        string  `json:"date"` // AAAA-MM-DD
+	Amount      float64 `json:"amount"`
+	Receivables int     `json:"receivables"`
+}
+
+type BalanceOutput struct {
+	Available float64                     `json:"available"`
+	Pending   float64                     `json:"pending"`
+	Upcoming  []*UpcomingSettlementOutput `json:"upcoming_settlements"`
+}
+
+func FromBalance(account *domain.Account, upcoming []*domain.UpcomingSettlement) *BalanceOutput {
+	output := &BalanceOutput{
+		Available: account.Balance,
+		Pending:   account.PendingBalance,
+		Upcoming:  make([]*UpcomingSettlementOutput, len(upcoming)),
+	}
+
+	for i, settlement := range upcoming {
+		output.Upcoming[i] = &UpcomingSettlementOutput{
+			Date:        settlement.Date.Format(time.DateOnly),
+			Amount:      settlement.Amount,
+			Receivables: settlement.Receivables,
+		}
+	}
+
+	return output
+}
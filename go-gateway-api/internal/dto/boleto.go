@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// BoletoCallbackInput é o aviso de liquidação enviado pelo simulador do banco
+type BoletoCallbackInput struct {
+	BankCode  string    `json:"bank_code"`
+	OurNumber string    `json:"our_number"`
+	Amount    float64   `json:"amount"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+type BoletoOutput struct {
+	BankCode           string     `json:"bank_code"`
+	OurNumber          string     `json:"our_number"`
+	PayerName          string     `json:"payer_name"`
+	DueDate            string     `json:"due_date"`           // AAAA-MM-DD
+	PaymentLimitDate   string     `json:"payment_limit_date"` // AAAA-MM-DD
+	FinePercentage     float64    `json:"fine_percentage"`
+	InterestPercentage float64    `json:"interest_percentage"` // ao mês
+	Barcode            string     `json:"barcode"`
+	DigitableLine      string     `json:"digitable_line"`
+	DocumentURL        string     `json:"document_url"`
+	Status             string     `json:"status"`
+	PaidAmount         float64    `json:"paid_amount,omitempty"`
+	PaidAt             *time.Time `json:"paid_at,omitempty"`
+}
+
+func FromBoleto(boleto *domain.Boleto) *BoletoOutput {
+	output := &BoletoOutput{
+		BankCode:           boleto.BankCode,
+		OurNumber:          boleto.OurNumber,
+		PayerName:          boleto.Payer.Name,
+		DueDate:            boleto.DueDate.Format(time.DateOnly),
+		PaymentLimitDate:   boleto.PaymentLimitDate.Format(time.DateOnly),
+		FinePercentage:     boleto.FinePercentage,
+		InterestPercentage: boleto.InterestPercentage,
+		Barcode:            boleto.Barcode,
+		DigitableLine:      boleto.FormattedDigitableLine(),
+		DocumentURL:        "/invoice/" + boleto.InvoiceID + "/boleto",
+		Status:             string(boleto.Status),
+		PaidAmount:         boleto.PaidAmount,
+	}
+
+	if !boleto.PaidAt.IsZero() {
+		output.PaidAt = &boleto.PaidAt
+	}
+
+	return output
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CreateCardTokenInput struct {
+	CardNumber      string `json:"card_number"`
+	CVV             string `json:"cvv"`
+	ExpirationMonth int    `json:"expiry_month"`
+	ExpirationYear  int    `json:"expiry_year"`
+	CardholderName  string `json:"cardholder_name"`
+}
+
+type CardTokenOutput struct {
+	ID              string    `json:"id"` // enviado em card_token no pagamento da sessão
+	CardBrand       string    `json:"card_brand"`
+	CardLastDigits  string    `json:"card_last_digits"`
+	ExpirationMonth int       `json:"expiry_month"`
+	ExpirationYear  int       `json:"expiry_year"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+func ToTokenCard(input CreateCardTokenInput) domain.CreditCard {
+	return domain.CreditCard{
+		Number:          input.CardNumber,
+		CVV:             input.CVV,
+		ExpirationMonth: input.ExpirationMonth,
+		ExpirationYear:  input.ExpirationYear,
+		CardHolderName:  input.CardholderName,
+	}
+}
+
+func FromCardToken(token *domain.CardToken) *CardTokenOutput {
+	return &CardTokenOutput{
+		ID:              token.ID,
+		CardBrand:       string(token.CardBrand),
+		CardLastDigits:  token.CardLastDigits,
+		ExpirationMonth: token.ExpirationMonth,
+		ExpirationYear:  token.ExpirationYear,
+		ExpiresAt:       token.ExpiresAt,
+	}
+}
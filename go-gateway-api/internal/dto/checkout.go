@@ -0,0 +1,161 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CreateCheckoutSessionInput struct {
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"` // BRL quando não informada
+	Description string  `json:"description"`
+	SuccessURL  string  `json:"success_url"`
+	CancelURL   string  `json:"cancel_url"` // opcional
+}
+
+type CreatePaymentLinkInput = CreateCheckoutSessionInput
+
+// CheckoutSessionOutput é exibido também ao comprador, por isso não inclui dados da conta
+// além do ID
+type CheckoutSessionOutput struct {
+	ID            string    `json:"id"`
+	AccountID     string    `json:"account_id"`
+	PaymentLinkID string    `json:"payment_link_id,omitempty"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Description   string    `json:"description"`
+	SuccessURL    string    `json:"success_url"`
+	CancelURL     string    `json:"cancel_url,omitempty"`
+	Status        string    `json:"status"`
+	InvoiceID     string    `json:"invoice_id,omitempty"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ConfirmCheckoutSessionInput traz o token do cartão criado no navegador ou o cartão digitado
+// pelo comprador; o valor e a descrição vêm da sessão
+type ConfirmCheckoutSessionInput struct {
+	CardToken       string `json:"card_token"` // quando informado, os campos do cartão são ignorados
+	CardNumber      string `json:"card_number"`
+	CVV             string `json:"cvv"`
+	ExpirationMonth int    `json:"expiry_month"`
+	ExpirationYear  int    `json:"expiry_year"`
+	CardholderName  string `json:"cardholder_name"`
+	Installments    int    `json:"installments"` // 1 a 12; vazio é à vista
+	// Pagador opcional, como nas faturas de cartão
+	PayerName      string               `json:"payer_name"`
+	PayerDocument  string               `json:"payer_document"`
+	PayerEmail     string               `json:"payer_email"`
+	BillingAddress *BillingAddressInput `json:"billing_address"`
+}
+
+type CheckoutPaymentOutput struct {
+	SessionID      string `json:"session_id"`
+	SessionStatus  string `json:"session_status"` // open quando o cartão foi recusado e o comprador pode tentar outro
+	InvoiceID      string `json:"invoice_id"`
+	PaymentStatus  string `json:"payment_status"` // approved, pending (em análise) ou rejected
+	DeclineReason  string `json:"decline_reason,omitempty"`
+	CardLastDigits string `json:"card_last_digits"`
+	RedirectURL    string `json:"redirect_url,omitempty"` // success_url quando a sessão foi concluída
+}
+
+type PaymentLinkOutput struct {
+	ID          string    `json:"id"`
+	AccountID   string    `json:"account_id"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	Description string    `json:"description"`
+	SuccessURL  string    `json:"success_url"`
+	CancelURL   string    `json:"cancel_url,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func ToCheckoutSession(input CreateCheckoutSessionInput, accountID string, ttl time.Duration, now time.Time) (*domain.CheckoutSession, error) {
+	currency, err := domain.ParseCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewCheckoutSession(accountID, input.Amount, currency, input.Description, input.SuccessURL, input.CancelURL, ttl, now)
+}
+
+func FromCheckoutSession(session *domain.CheckoutSession, now time.Time) *CheckoutSessionOutput {
+	return &CheckoutSessionOutput{
+		ID:            session.ID,
+		AccountID:     session.AccountID,
+		PaymentLinkID: session.PaymentLinkID,
+		Amount:        session.Amount,
+		Currency:      string(session.Currency),
+		Description:   session.Description,
+		SuccessURL:    session.SuccessURL,
+		CancelURL:     session.CancelURL,
+		Status:        string(session.CurrentStatus(now)),
+		InvoiceID:     session.InvoiceID,
+		ExpiresAt:     session.ExpiresAt,
+		CreatedAt:     session.CreatedAt,
+	}
+}
+
+// ToCheckoutInvoiceInput monta a fatura de cartão do pagamento da sessão
+func ToCheckoutInvoiceInput(session *domain.CheckoutSession, input ConfirmCheckoutSessionInput) CreateInvoiceInput {
+	return CreateInvoiceInput{
+		Amount:          session.Amount,
+		Description:     session.Description,
+		Currency:        string(session.Currency),
+		PaymentType:     domain.PaymentTypeCreditCard,
+		CardNumber:      input.CardNumber,
+		CVV:             input.CVV,
+		ExpirationMonth: input.ExpirationMonth,
+		ExpirationYear:  input.ExpirationYear,
+		CardholderName:  input.CardholderName,
+		Installments:    input.Installments,
+		PayerName:       input.PayerName,
+		PayerDocument:   input.PayerDocument,
+		PayerEmail:      input.PayerEmail,
+		BillingAddress:  input.BillingAddress,
+	}
+}
+
+func FromCheckoutPayment(session *domain.CheckoutSession, invoice *InvoiceOutput) *CheckoutPaymentOutput {
+	output := &CheckoutPaymentOutput{
+		SessionID:      session.ID,
+		SessionStatus:  string(session.Status),
+		InvoiceID:      invoice.ID,
+		PaymentStatus:  invoice.Status,
+		DeclineReason:  invoice.DeclineReason,
+		CardLastDigits: invoice.CardLastDigits,
+	}
+
+	if session.Status == domain.CheckoutSessionStatusCompleted {
+		output.RedirectURL = session.SuccessURL
+	}
+
+	return output
+}
+
+func ToPaymentLink(input CreatePaymentLinkInput, accountID string, now time.Time) (*domain.PaymentLink, error) {
+	currency, err := domain.ParseCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewPaymentLink(accountID, input.Amount, currency, input.Description, input.SuccessURL, input.CancelURL, now)
+}
+
+func FromPaymentLink(link *domain.PaymentLink) *PaymentLinkOutput {
+	return &PaymentLinkOutput{
+		ID:          link.ID,
+		AccountID:   link.AccountID,
+		Amount:      link.Amount,
+		Currency:    string(link.Currency),
+		Description: link.Description,
+		SuccessURL:  link.SuccessURL,
+		CancelURL:   link.CancelURL,
+		Active:      link.Active,
+		CreatedAt:   link.CreatedAt,
+		UpdatedAt:   link.UpdatedAt,
+	}
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CNABRemittanceInput struct {
+	Kind   string `json:"kind"`   // boleto ou payout
+	Layout string `json:"layout"` // 240 ou 400; pagamentos só existem em 240
+}
+
+type CNABLineErrorOutput struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type CNABFileOutput struct {
+	ID          string                `json:"id"`
+	Type        string                `json:"type"`
+	Kind        string                `json:"kind"`
+	Layout      string                `json:"layout"`
+	Sequence    int64                 `json:"sequence,omitempty"`
+	FileName    string                `json:"file_name"`
+	Checksum    string                `json:"checksum"`
+	Status      string                `json:"status"`
+	RecordCount int                   `json:"record_count"`
+	Errors      []CNABLineErrorOutput `json:"errors"`
+	ContentURL  string                `json:"content_url"`
+	Duplicate   bool                  `json:"duplicate,omitempty"` // retorno já processado anteriormente
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+func FromCNABFile(file *domain.CNABFile) *CNABFileOutput {
+	output := &CNABFileOutput{
+		ID:          file.ID,
+		Type:        string(file.Type),
+		Kind:        string(file.Kind),
+		Layout:      string(file.Layout),
+		Sequence:    file.Sequence,
+		FileName:    file.FileName,
+		Checksum:    file.Checksum,
+		Status:      string(file.Status),
+		RecordCount: file.RecordCount,
+		Errors:      make([]CNABLineErrorOutput, len(file.Errors)),
+		ContentURL:  "/admin/cnab/files/" + file.ID + "/content",
+		CreatedAt:   file.CreatedAt,
+	}
+
+	for i, lineError := range file.Errors {
+		output.Errors[i] = CNABLineErrorOutput{Line: lineError.Line, Message: lineError.Message}
+	}
+
+	return output
+}
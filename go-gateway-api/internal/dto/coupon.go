@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CreateCouponInput struct {
+	Code           string     `json:"code"`
+	DiscountType   string     `json:"discount_type"`   // percent ou fixed
+	Value          float64    `json:"value"`           // percentual (até 100) ou valor fixo
+	Currency       string     `json:"currency"`        // apenas para fixed; BRL quando vazio
+	MaxRedemptions int        `json:"max_redemptions"` // zero é ilimitado
+	ExpiresAt      *time.Time `json:"expires_at"`      // vazio não vence
+}
+
+type CouponOutput struct {
+	ID             string     `json:"id"`
+	AccountID      string     `json:"account_id"`
+	Code           string     `json:"code"`
+	DiscountType   string     `json:"discount_type"`
+	Value          float64    `json:"value"`
+	Currency       string     `json:"currency,omitempty"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	Redemptions    int        `json:"redemptions"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func ToCoupon(input CreateCouponInput, accountID string, now time.Time) (*domain.Coupon, error) {
+	currency, err := domain.ParseCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt time.Time
+	if input.ExpiresAt != nil {
+		expiresAt = *input.ExpiresAt
+	}
+
+	return domain.NewCoupon(accountID, input.Code, domain.DiscountType(input.DiscountType), input.Value, currency, input.MaxRedemptions, expiresAt, now)
+}
+
+func FromCoupon(coupon *domain.Coupon) *CouponOutput {
+	output := &CouponOutput{
+		ID:             coupon.ID,
+		AccountID:      coupon.AccountID,
+		Code:           coupon.Code,
+		DiscountType:   string(coupon.DiscountType),
+		Value:          coupon.Value,
+		Currency:       string(coupon.Currency),
+		MaxRedemptions: coupon.MaxRedemptions,
+		Redemptions:    coupon.Redemptions,
+		Active:         coupon.Active,
+		CreatedAt:      coupon.CreatedAt,
+		UpdatedAt:      coupon.UpdatedAt,
+	}
+
+	if !coupon.ExpiresAt.IsZero() {
+		output.ExpiresAt = &coupon.ExpiresAt
+	}
+
+	return output
+}
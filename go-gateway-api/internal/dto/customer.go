@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CreateCustomerInput struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Document string `json:"document"` // CPF ou CNPJ, opcional
+}
+
+type CustomerOutput struct {
+	ID           string    `json:"id"`
+	AccountID    string    `json:"account_id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	DocumentType string    `json:"document_type,omitempty"`
+	Document     string    `json:"document,omitempty"` // mascarado
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// O número completo e o CVV são usados apenas na validação e não são gravados
+type CreatePaymentMethodInput struct {
+	CardNumber      string `json:"card_number"`
+	CVV             string `json:"cvv"`
+	ExpirationMonth int    `json:"expiry_month"`
+	ExpirationYear  int    `json:"expiry_year"`
+	CardholderName  string `json:"cardholder_name"`
+}
+
+type PaymentMethodOutput struct {
+	ID              string    `json:"id"`
+	CustomerID      string    `json:"customer_id"`
+	CardBrand       string    `json:"card_brand"`
+	CardLastDigits  string    `json:"card_last_digits"`
+	ExpirationMonth int       `json:"expiry_month"`
+	ExpirationYear  int       `json:"expiry_year"`
+	CardholderName  string    `json:"cardholder_name"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func ToCustomer(input CreateCustomerInput, accountID string) (*domain.Customer, error) {
+	return domain.NewCustomer(accountID, input.Name, input.Email, input.Document)
+}
+
+func FromCustomer(customer *domain.Customer) *CustomerOutput {
+	output := &CustomerOutput{
+		ID:        customer.ID,
+		AccountID: customer.AccountID,
+		Name:      customer.Name,
+		Email:     customer.Email,
+		CreatedAt: customer.CreatedAt,
+	}
+
+	if payer := customer.Payer(); payer != nil {
+		output.DocumentType = string(payer.DocumentType())
+		output.Document = payer.MaskedDocument()
+	}
+
+	return output
+}
+
+func ToCreditCard(input CreatePaymentMethodInput) domain.CreditCard {
+	return domain.CreditCard{
+		Number:          input.CardNumber,
+		CVV:             input.CVV,
+		ExpirationMonth: input.ExpirationMonth,
+		ExpirationYear:  input.ExpirationYear,
+		CardHolderName:  input.CardholderName,
+	}
+}
+
+func FromPaymentMethod(method *domain.PaymentMethod) *PaymentMethodOutput {
+	return &PaymentMethodOutput{
+		ID:              method.ID,
+		CustomerID:      method.CustomerID,
+		CardBrand:       string(method.CardBrand),
+		CardLastDigits:  method.CardLastDigits,
+		ExpirationMonth: method.ExpirationMonth,
+		ExpirationYear:  method.ExpirationYear,
+		CardholderName:  method.CardholderName,
+		CreatedAt:       method.CreatedAt,
+	}
+}
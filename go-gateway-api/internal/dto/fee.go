@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type FeeRuleInput struct {
+	CardBrand       string  `json:"card_brand"`   // vazio vale para qualquer bandeira
+	PaymentType     string  `json:"payment_type"` // vazio vale para qualquer forma de pagamento
+	MinInstallments int     `json:"min_installments"`
+	MaxInstallments int     `json:"max_installments"`
+	Percentage      float64 `json:"percentage"`
+	FixedAmount     float64 `json:"fixed_amount"`
+}
+
+// FeeRulesInput substitui todas as regras da conta; uma lista vazia volta para a taxa padrão
+type FeeRulesInput struct {
+	Rules []FeeRuleInput `json:"rules"`
+}
+
+type FeeRuleOutput struct {
+	ID              string    `json:"id"`
+	CardBrand       string    `json:"card_brand"`
+	PaymentType     string    `json:"payment_type"`
+	MinInstallments int       `json:"min_installments"`
+	MaxInstallments int       `json:"max_installments"`
+	Percentage      float64   `json:"percentage"`
+	FixedAmount     float64   `json:"fixed_amount"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func ToFeeRules(input FeeRulesInput, accountID string) ([]*domain.FeeRule, error) {
+	rules := make([]*domain.FeeRule, len(input.Rules))
+	for i, rule := range input.Rules {
+		feeRule, err := domain.NewFeeRule(
+			accountID,
+			domain.CardBrand(rule.CardBrand),
+			rule.PaymentType,
+			rule.MinInstallments,
+			rule.MaxInstallments,
+			rule.Percentage,
+			rule.FixedAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = feeRule
+	}
+	return rules, nil
+}
+
+func FromFeeRules(rules []*domain.FeeRule) []*FeeRuleOutput {
+	output := make([]*FeeRuleOutput, len(rules))
+	for i, rule := range rules {
+		output[i] = &FeeRuleOutput{
+			ID:              rule.ID,
+			CardBrand:       string(rule.CardBrand),
+			PaymentType:     rule.PaymentType,
+			MinInstallments: rule.MinInstallments,
+			MaxInstallments: rule.MaxInstallments,
+			Percentage:      rule.Percentage,
+			FixedAmount:     rule.FixedAmount,
+			CreatedAt:       rule.CreatedAt,
+		}
+	}
+	return output
+}
+
+type InvoiceFeeOutput struct {
+	ID          string    `json:"id"`
+	InvoiceID   string    `json:"invoice_id"`
+	Type        string    `json:"type"`
+	Description string    `json:"description"`
+	Percentage  float64   `json:"percentage"`
+	FixedAmount float64   `json:"fixed_amount"`
+	Amount      float64   `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func FromInvoiceFee(fee *domain.InvoiceFee) *InvoiceFeeOutput {
+	return &InvoiceFeeOutput{
+		ID:          fee.ID,
+		InvoiceID:   fee.InvoiceID,
+		Type:        string(fee.Type),
+		Description: fee.Description,
+		Percentage:  fee.Percentage,
+		FixedAmount: fee.FixedAmount,
+		Amount:      fee.Amount,
+		CreatedAt:   fee.CreatedAt,
+	}
+}
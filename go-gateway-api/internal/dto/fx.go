@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type ExchangeRateOutput struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      float64   `json:"rate"`   // 1 base = rate quote
+	Source    string    `json:"source"` // provedor, override ou identity
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExchangeRateOverrideInput fixa a cotação do par até ExpiresAt (no máximo 30 dias)
+type ExchangeRateOverrideInput struct {
+	Rate      float64   `json:"rate"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Reason    string    `json:"reason"`
+}
+
+type ExchangeRateOverrideOutput struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      float64   `json:"rate"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
+	Active    bool      `json:"active"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func FromExchangeRate(rate *domain.ExchangeRate) *ExchangeRateOutput {
+	return &ExchangeRateOutput{
+		Base:      string(rate.Base),
+		Quote:     string(rate.Quote),
+		Rate:      rate.Rate,
+		Source:    rate.Source,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+func FromExchangeRateOverride(override *domain.ExchangeRateOverride) *ExchangeRateOverrideOutput {
+	return &ExchangeRateOverrideOutput{
+		Base:      string(override.Base),
+		Quote:     string(override.Quote),
+		Rate:      override.Rate,
+		Reason:    override.Reason,
+		CreatedBy: override.CreatedBy,
+		Active:    override.Active(time.Now()),
+		ExpiresAt: override.ExpiresAt,
+		CreatedAt: override.CreatedAt,
+	}
+}
@@ -10,30 +10,155 @@ const (
 	StatusPending  = string(domain.StatusPending)
 	StatusApproved = string(domain.StatusApproved)
 	StatusRejected = string(domain.StatusRejected)
+	StatusExpired  = string(domain.StatusExpired)
 )
 
 type CreateInvoiceInput struct {
 	APIKey          string
 	Amount          float64 `json:"amount"`
 	Description     string  `json:"description"`
+	Currency        string  `json:"currency"` // BRL (padrão), USD ou EUR; Pix e boleto só aceitam BRL
 	PaymentType     string  `json:"payment_type"`
 	CardNumber      string  `json:"card_number"`
 	CVV             string  `json:"cvv"`
 	ExpirationMonth int     `json:"expiry_month"`
 	ExpirationYear  int     `json:"expiry_year"`
 	CardholderName  string  `json:"cardholder_name"`
+	Installments    int     `json:"installments"`   // 1 a 12; vazio é à vista
+	InterestMode    string  `json:"interest_mode"`  // merchant (padrão) ou buyer
+	PixType         string  `json:"pix_type"`       // dynamic (padrão) ou static, apenas para payment_type=pix
+	PixExpiresIn    int     `json:"pix_expires_in"` // prazo de pagamento do Pix em segundos
+	// Pagador: obrigatório no boleto e opcional nas demais formas de pagamento
+	PayerName      string               `json:"payer_name"`
+	PayerDocument  string               `json:"payer_document"` // CPF ou CNPJ, com ou sem pontuação
+	PayerEmail     string               `json:"payer_email"`
+	BillingAddress *BillingAddressInput `json:"billing_address"`
+	// Campos do boleto, apenas para payment_type=boleto
+	DueDate            string  `json:"due_date"`            // AAAA-MM-DD; vazio usa o vencimento padrão
+	FinePercentage     float64 `json:"fine_percentage"`     // multa após o vencimento
+	InterestPercentage float64 `json:"interest_percentage"` // juros de mora ao mês
+	// Detalhamento opcional: com itens, amount pode ficar vazio ou deve ser igual ao total calculado
+	LineItems  []LineItemInput `json:"line_items"`
+	CouponCode string          `json:"coupon_code"`
+	Discount   *DiscountInput  `json:"discount"` // desconto avulso, não combina com coupon_code
+	TaxLines   []TaxLineInput  `json:"tax_lines"`
+}
+
+type LineItemInput struct {
+	SKU         string  `json:"sku"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+type DiscountInput struct {
+	Type  string  `json:"type"` // percent ou fixed
+	Value float64 `json:"value"`
+}
+
+// TaxLineInput informa o percentual sobre o subtotal com desconto ou o valor fixo do imposto
+type TaxLineInput struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
 }
 
 type InvoiceOutput struct {
-	ID             string    `json:"id"`
-	AccountID      string    `json:"account_id"`
-	Amount         float64   `json:"amount"`
-	Status         string    `json:"status"`
-	Description    string    `json:"description"`
-	PaymentType    string    `json:"payment_type"`
-	CardLastDigits string    `json:"card_last_digits"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID                 string              `json:"id"`
+	AccountID          string              `json:"account_id"`
+	Amount             float64             `json:"amount"`
+	Status             string              `json:"status"`
+	Description        string              `json:"description"`
+	PaymentType        string              `json:"payment_type"`
+	CardLastDigits     string              `json:"card_last_digits"`
+	CardBrand          string              `json:"card_brand"`
+	Installments       int                 `json:"installments"`
+	InstallmentAmount  float64             `json:"installment_amount"` // valor de cada parcela cobrada do comprador
+	InterestMode       string              `json:"interest_mode"`
+	InterestRate       float64             `json:"interest_rate"`
+	InterestAmount     float64             `json:"interest_amount"`
+	TotalAmount        float64             `json:"total_amount"` // valor cobrado do comprador
+	Currency           string              `json:"currency"`
+	SettlementCurrency string              `json:"settlement_currency"` // moeda da conta, de fee_amount e net_amount
+	ExchangeRate       float64             `json:"exchange_rate"`       // zero até a aprovação
+	SettlementAmount   float64             `json:"settlement_amount"`   // zero até a aprovação
+	FeeAmount          float64             `json:"fee_amount"`
+	NetAmount          float64             `json:"net_amount"`            // valor creditado à conta, zero até a aprovação
+	Receivables        []*ReceivableOutput `json:"receivables,omitempty"` // apenas na consulta individual
+	Pix                *PixChargeOutput    `json:"pix,omitempty"`
+	Boleto             *BoletoOutput       `json:"boleto,omitempty"`
+	Payer              *PayerOutput        `json:"payer,omitempty"`
+	DeclineReason      string              `json:"decline_reason,omitempty"`
+	SubscriptionID     string              `json:"subscription_id,omitempty"`
+	Breakdown          *BreakdownOutput    `json:"breakdown,omitempty"` // apenas nas faturas com itens
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+}
+
+// BreakdownOutput detalha amount: subtotal - discount_amount + tax_amount
+type BreakdownOutput struct {
+	LineItems      []LineItemOutput `json:"line_items"`
+	Subtotal       float64          `json:"subtotal"`
+	Discount       *DiscountOutput  `json:"discount,omitempty"`
+	DiscountAmount float64          `json:"discount_amount"`
+	TaxLines       []TaxLineOutput  `json:"tax_lines"`
+	TaxAmount      float64          `json:"tax_amount"`
+	Total          float64          `json:"total"`
+}
+
+type LineItemOutput struct {
+	SKU         string  `json:"sku,omitempty"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+type DiscountOutput struct {
+	Type       string  `json:"type"`
+	Value      float64 `json:"value"`
+	CouponCode string  `json:"coupon_code,omitempty"`
+	Amount     float64 `json:"amount"`
+}
+
+type TaxLineOutput struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate,omitempty"`
+	Amount float64 `json:"amount"`
+}
+
+type BillingAddressInput struct {
+	Street     string `json:"street"`
+	Number     string `json:"number"`
+	Complement string `json:"complement"`
+	District   string `json:"district"`
+	City       string `json:"city"`
+	State      string `json:"state"`       // UF
+	PostalCode string `json:"postal_code"` // CEP
+}
+
+// PayerOutput expõe o pagador mascarado: o documento e o e-mail completos ficam apenas no
+// banco de dados
+type PayerOutput struct {
+	Name           string                `json:"name"`
+	DocumentType   string                `json:"document_type"`
+	Document       string                `json:"document"` // ex.: ***.456.789-**
+	Email          string                `json:"email,omitempty"`
+	BillingAddress *BillingAddressOutput `json:"billing_address,omitempty"`
+}
+
+// BillingAddressOutput omite a rua e o número
+type BillingAddressOutput struct {
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"` // apenas o prefixo, ex.: 01310-***
+}
+
+type ReceivableOutput struct {
+	InstallmentNumber int     `json:"installment_number"`
+	Amount            float64 `json:"amount"`
+	DueDate           string  `json:"due_date"` // AAAA-MM-DD
+	Status            string  `json:"status"`
 }
 
 func ToInvoice(input CreateInvoiceInput, accountID string) (*domain.Invoice, error) {
@@ -54,16 +179,159 @@ func ToInvoice(input CreateInvoiceInput, accountID string) (*domain.Invoice, err
 	)
 }
 
+// ToPayer monta o pagador informado na fatura; retorna nil quando nenhum campo foi preenchido
+func ToPayer(input CreateInvoiceInput) (*domain.Payer, error) {
+	if input.PayerName == "" && input.PayerDocument == "" && input.PayerEmail == "" && input.BillingAddress == nil {
+		return nil, nil
+	}
+
+	var address *domain.BillingAddress
+	if input.BillingAddress != nil {
+		address = &domain.BillingAddress{
+			Street:     input.BillingAddress.Street,
+			Number:     input.BillingAddress.Number,
+			Complement: input.BillingAddress.Complement,
+			District:   input.BillingAddress.District,
+			City:       input.BillingAddress.City,
+			State:      input.BillingAddress.State,
+			PostalCode: input.BillingAddress.PostalCode,
+		}
+	}
+
+	return domain.NewPayer(input.PayerName, input.PayerDocument, input.PayerEmail, address)
+}
+
+func FromPayer(payer *domain.Payer) *PayerOutput {
+	output := &PayerOutput{
+		Name:         payer.Name,
+		DocumentType: string(payer.DocumentType()),
+		Document:     payer.MaskedDocument(),
+		Email:        payer.MaskedEmail(),
+	}
+
+	if address := payer.BillingAddress; address != nil {
+		output.BillingAddress = &BillingAddressOutput{
+			City:       address.City,
+			State:      address.State,
+			PostalCode: address.PostalCode[:5] + "-***",
+		}
+	}
+
+	return output
+}
+
 func FromInvoice(invoice *domain.Invoice) *InvoiceOutput {
-	return &InvoiceOutput{
-		ID:             invoice.ID,
-		AccountID:      invoice.AccountID,
-		Amount:         invoice.Amount,
-		Status:         string(invoice.Status),
-		Description:    invoice.Description,
-		PaymentType:    invoice.PaymentType,
-		CardLastDigits: invoice.CardLastDigits,
-		CreatedAt:      invoice.CreatedAt,
-		UpdatedAt:      invoice.UpdatedAt,
-	}
-}
\ No newline at end of file
+	output := &InvoiceOutput{
+		ID:                 invoice.ID,
+		AccountID:          invoice.AccountID,
+		Amount:             invoice.Amount,
+		Status:             string(invoice.Status),
+		Description:        invoice.Description,
+		PaymentType:        invoice.PaymentType,
+		CardLastDigits:     invoice.CardLastDigits,
+		CardBrand:          string(invoice.CardBrand),
+		Installments:       invoice.Installments,
+		InstallmentAmount:  invoice.InstallmentAmount(),
+		InterestMode:       string(invoice.InterestMode),
+		InterestRate:       invoice.InterestRate,
+		InterestAmount:     invoice.InterestAmount,
+		TotalAmount:        invoice.TotalAmount,
+		Currency:           string(invoice.Currency),
+		SettlementCurrency: string(invoice.SettlementCurrency),
+		ExchangeRate:       invoice.ExchangeRate,
+		SettlementAmount:   invoice.SettlementAmount,
+		FeeAmount:          invoice.FeeAmount,
+		NetAmount:          invoice.NetAmount,
+		DeclineReason:      string(invoice.DeclineReason),
+		SubscriptionID:     invoice.SubscriptionID,
+		CreatedAt:          invoice.CreatedAt,
+		UpdatedAt:          invoice.UpdatedAt,
+	}
+
+	if invoice.Payer != nil {
+		output.Payer = FromPayer(invoice.Payer)
+	}
+
+	if invoice.Breakdown != nil {
+		output.Breakdown = FromBreakdown(invoice.Breakdown)
+	}
+
+	return output
+}
+
+// ToLineItems valida os itens, o desconto avulso e os impostos informados na fatura; o cupom
+// é resolvido pelo serviço, que conhece a conta
+func ToLineItems(input CreateInvoiceInput) ([]domain.LineItem, *domain.Discount, []domain.TaxLine, error) {
+	items := make([]domain.LineItem, len(input.LineItems))
+	for i, item := range input.LineItems {
+		var err error
+		if items[i], err = domain.NewLineItem(item.SKU, item.Description, item.Quantity, item.UnitPrice); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var discount *domain.Discount
+	if input.Discount != nil {
+		if input.CouponCode != "" {
+			return nil, nil, nil, domain.ErrInvalidDiscount
+		}
+
+		var err error
+		if discount, err = domain.NewDiscount(domain.DiscountType(input.Discount.Type), input.Discount.Value); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	taxes := make([]domain.TaxLine, len(input.TaxLines))
+	for i, tax := range input.TaxLines {
+		var err error
+		if taxes[i], err = domain.NewTaxLine(tax.Name, tax.Rate, tax.Amount); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return items, discount, taxes, nil
+}
+
+func FromBreakdown(breakdown *domain.InvoiceBreakdown) *BreakdownOutput {
+	output := &BreakdownOutput{
+		LineItems:      make([]LineItemOutput, len(breakdown.LineItems)),
+		Subtotal:       breakdown.Subtotal,
+		DiscountAmount: breakdown.DiscountAmount(),
+		TaxLines:       make([]TaxLineOutput, len(breakdown.TaxLines)),
+		TaxAmount:      breakdown.TaxAmount,
+		Total:          breakdown.Total,
+	}
+
+	for i, item := range breakdown.LineItems {
+		output.LineItems[i] = LineItemOutput(item)
+	}
+
+	if discount := breakdown.Discount; discount != nil {
+		output.Discount = &DiscountOutput{
+			Type:       string(discount.Type),
+			Value:      discount.Value,
+			CouponCode: discount.CouponCode,
+			Amount:     discount.Amount,
+		}
+	}
+
+	for i, tax := range breakdown.TaxLines {
+		output.TaxLines[i] = TaxLineOutput(tax)
+	}
+
+	return output
+}
+
+func FromReceivables(receivables []*domain.Receivable) []*ReceivableOutput {
+	output := make([]*ReceivableOutput, len(receivables))
+	for i, receivable := range receivables {
+		output[i] = &ReceivableOutput{
+			InstallmentNumber: receivable.InstallmentNumber,
+			Amount:            receivable.Amount,
+			DueDate:           receivable.DueDate.Format(time.DateOnly),
+			Status:            string(receivable.Status),
+		}
+	}
+	return output
+}
@@ -0,0 +1,147 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type BankAccountInput struct {
+	BankCode       string `json:"bank_code"`
+	Branch         string `json:"branch"`
+	Number         string `json:"number"`
+	Type           string `json:"type"`
+	HolderName     string `json:"holder_name"`
+	HolderDocument string `json:"holder_document"`
+}
+
+type PixKeyInput struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+}
+
+// Apenas um entre bank_account e pix_key deve ser informado, conforme o type
+type CreatePayoutDestinationInput struct {
+	Type        string            `json:"type"`
+	BankAccount *BankAccountInput `json:"bank_account,omitempty"`
+	PixKey      *PixKeyInput      `json:"pix_key,omitempty"`
+}
+
+type BankAccountOutput struct {
+	BankCode       string `json:"bank_code"`
+	Branch         string `json:"branch"`
+	Number         string `json:"number"`
+	Type           string `json:"type"`
+	HolderName     string `json:"holder_name"`
+	HolderDocument string `json:"holder_document"` // mascarado
+}
+
+type PixKeyOutput struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+}
+
+type PayoutDestinationOutput struct {
+	ID          string             `json:"id"`
+	AccountID   string             `json:"account_id"`
+	Type        string             `json:"type"`
+	BankAccount *BankAccountOutput `json:"bank_account,omitempty"`
+	PixKey      *PixKeyOutput      `json:"pix_key,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type CreatePayoutInput struct {
+	DestinationID string  `json:"destination_id"`
+	Amount        float64 `json:"amount"`
+}
+
+type PayoutOutput struct {
+	ID                string    `json:"id"`
+	AccountID         string    `json:"account_id"`
+	DestinationID     string    `json:"destination_id"`
+	Amount            float64   `json:"amount"`
+	Status            string    `json:"status"`
+	ProviderReference string    `json:"provider_reference,omitempty"`
+	FailureReason     string    `json:"failure_reason,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func ToPayoutDestination(input CreatePayoutDestinationInput, accountID string) (*domain.PayoutDestination, error) {
+	switch domain.PayoutDestinationType(input.Type) {
+	case domain.PayoutDestinationBankAccount:
+		if input.BankAccount == nil || input.PixKey != nil {
+			return nil, domain.ErrInvalidPayoutDestination
+		}
+		return domain.NewBankAccountDestination(accountID, domain.BankAccount{
+			BankCode:       input.BankAccount.BankCode,
+			Branch:         input.BankAccount.Branch,
+			Number:         input.BankAccount.Number,
+			Type:           domain.BankAccountType(input.BankAccount.Type),
+			HolderName:     input.BankAccount.HolderName,
+			HolderDocument: input.BankAccount.HolderDocument,
+		})
+	case domain.PayoutDestinationPixKey:
+		if input.PixKey == nil || input.BankAccount != nil {
+			return nil, domain.ErrInvalidPayoutDestination
+		}
+		return domain.NewPixKeyDestination(accountID, domain.PixKey{
+			Type: domain.PixKeyType(input.PixKey.Type),
+			Key:  input.PixKey.Key,
+		})
+	default:
+		return nil, domain.ErrInvalidPayoutDestination
+	}
+}
+
+func FromPayoutDestination(destination *domain.PayoutDestination) *PayoutDestinationOutput {
+	output := &PayoutDestinationOutput{
+		ID:        destination.ID,
+		AccountID: destination.AccountID,
+		Type:      string(destination.Type),
+		CreatedAt: destination.CreatedAt,
+	}
+
+	if bankAccount := destination.BankAccount; bankAccount != nil {
+		output.BankAccount = &BankAccountOutput{
+			BankCode:       bankAccount.BankCode,
+			Branch:         bankAccount.Branch,
+			Number:         bankAccount.Number,
+			Type:           string(bankAccount.Type),
+			HolderName:     bankAccount.HolderName,
+			HolderDocument: maskDocument(bankAccount.HolderDocument),
+		}
+	}
+
+	if destination.PixKey != nil {
+		output.PixKey = &PixKeyOutput{
+			Type: string(destination.PixKey.Type),
+			Key:  destination.PixKey.Key,
+		}
+	}
+
+	return output
+}
+
+func FromPayout(payout *domain.Payout) *PayoutOutput {
+	return &PayoutOutput{
+		ID:                payout.ID,
+		AccountID:         payout.AccountID,
+		DestinationID:     payout.DestinationID,
+		Amount:            payout.Amount,
+		Status:            string(payout.Status),
+		ProviderReference: payout.ProviderReference,
+		FailureReason:     payout.FailureReason,
+		CreatedAt:         payout.CreatedAt,
+		UpdatedAt:         payout.UpdatedAt,
+	}
+}
+
+// maskDocument mantém visíveis apenas os dois últimos dígitos do CPF/CNPJ
+func maskDocument(document string) string {
+	if len(document) <= 2 {
+		return document
+	}
+	return strings.Repeat("*", len(document)-2) + document[len(document)-2:]
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// PixCallbackInput é o aviso de pagamento enviado pelo PSP
+type PixCallbackInput struct {
+	TxID       string    `json:"txid"`
+	EndToEndID string    `json:"end_to_end_id"`
+	Amount     float64   `json:"amount"`
+	PaidAt     time.Time `json:"paid_at"`
+}
+
+type PixChargeOutput struct {
+	TxID       string     `json:"txid"`
+	Type       string     `json:"type"`
+	Status     string     `json:"status"`
+	Payload    string     `json:"payload"` // BR Code (copia e cola)
+	QRCodeURL  string     `json:"qr_code_url"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	EndToEndID string     `json:"end_to_end_id,omitempty"`
+	PaidAt     *time.Time `json:"paid_at,omitempty"`
+}
+
+func FromPixCharge(charge *domain.PixCharge) *PixChargeOutput {
+	output := &PixChargeOutput{
+		TxID:       charge.TxID,
+		Type:       string(charge.Type),
+		Status:     string(charge.Status),
+		Payload:    charge.Payload,
+		QRCodeURL:  "/invoice/" + charge.InvoiceID + "/pix/qrcode",
+		ExpiresAt:  charge.ExpiresAt,
+		EndToEndID: charge.EndToEndID,
+	}
+
+	if !charge.PaidAt.IsZero() {
+		output.PaidAt = &charge.PaidAt
+	}
+
+	return output
+}
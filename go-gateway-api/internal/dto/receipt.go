@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// UpdateReceiptSettingsInput substitui toda a personalização dos recibos; logo vazio remove o
+// logo e cores vazias voltam às padrão
+type UpdateReceiptSettingsInput struct {
+	Logo           string `json:"logo"`            // PNG ou JPEG em base64, com ou sem o prefixo data:
+	PrimaryColor   string `json:"primary_color"`   // #RRGGBB
+	SecondaryColor string `json:"secondary_color"` // #RRGGBB
+	Footer         string `json:"footer"`
+}
+
+// ReceiptSettingsOutput não devolve o logo, apenas indica se ele existe
+type ReceiptSettingsOutput struct {
+	HasLogo         bool       `json:"has_logo"`
+	LogoContentType string     `json:"logo_content_type,omitempty"`
+	PrimaryColor    string     `json:"primary_color"`
+	SecondaryColor  string     `json:"secondary_color"`
+	Footer          string     `json:"footer"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"` // vazio enquanto a conta usa a personalização padrão
+}
+
+func ToReceiptSettings(input UpdateReceiptSettingsInput, accountID string, now time.Time) (*domain.ReceiptSettings, error) {
+	var logo []byte
+	if encoded := strings.TrimSpace(input.Logo); encoded != "" {
+		if _, data, found := strings.Cut(encoded, ";base64,"); found && strings.HasPrefix(encoded, "data:") {
+			encoded = data
+		}
+
+		var err error
+		if logo, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+			return nil, domain.ErrInvalidReceiptLogo
+		}
+	}
+
+	return domain.NewReceiptSettings(accountID, logo, input.PrimaryColor, input.SecondaryColor, input.Footer, now)
+}
+
+func FromReceiptSettings(settings *domain.ReceiptSettings) *ReceiptSettingsOutput {
+	output := &ReceiptSettingsOutput{
+		HasLogo:         len(settings.Logo) > 0,
+		LogoContentType: settings.LogoContentType,
+		PrimaryColor:    settings.PrimaryColor,
+		SecondaryColor:  settings.SecondaryColor,
+		Footer:          settings.Footer,
+	}
+
+	if !settings.UpdatedAt.IsZero() {
+		output.UpdatedAt = &settings.UpdatedAt
+	}
+
+	return output
+}
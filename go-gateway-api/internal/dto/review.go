@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type ReviewDecisionInput struct {
+	Reason string `json:"reason"`
+}
+
+type ReviewClaimOutput struct {
+	InvoiceID string    `json:"invoice_id"`
+	Reviewer  string    `json:"reviewer"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ReviewRiskContext resume o histórico da conta para apoiar a decisão do analista
+type ReviewRiskContext struct {
+	AccountName      string    `json:"account_name"`
+	AccountEmail     string    `json:"account_email"`
+	AccountBalance   float64   `json:"account_balance"`
+	AccountCreatedAt time.Time `json:"account_created_at"`
+	ApprovedCount    int       `json:"approved_count"`
+	ApprovedTotal    float64   `json:"approved_total"`
+	RejectedCount    int       `json:"rejected_count"`
+	PendingCount     int       `json:"pending_count"`
+	PendingFor       string    `json:"pending_for"`
+}
+
+type ReviewQueueItemOutput struct {
+	Invoice *InvoiceOutput     `json:"invoice"`
+	Risk    ReviewRiskContext  `json:"risk"`
+	Claim   *ReviewClaimOutput `json:"claim,omitempty"`
+}
+
+type ReviewActionOutput struct {
+	ID        string    `json:"id"`
+	InvoiceID string    `json:"invoice_id"`
+	Reviewer  string    `json:"reviewer"`
+	Action    string    `json:"action"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func FromReviewClaim(claim *domain.ReviewClaim) *ReviewClaimOutput {
+	return &ReviewClaimOutput{
+		InvoiceID: claim.InvoiceID,
+		Reviewer:  claim.Reviewer,
+		ClaimedAt: claim.ClaimedAt,
+		ExpiresAt: claim.ExpiresAt,
+	}
+}
+
+func FromReviewQueueItem(item *domain.ReviewQueueItem) *ReviewQueueItemOutput {
+	output := &ReviewQueueItemOutput{
+		Invoice: FromInvoice(item.Invoice),
+		Risk: ReviewRiskContext{
+			AccountName:      item.AccountName,
+			AccountEmail:     item.AccountEmail,
+			AccountBalance:   item.AccountBalance,
+			AccountCreatedAt: item.AccountCreatedAt,
+			ApprovedCount:    item.ApprovedCount,
+			ApprovedTotal:    item.ApprovedTotal,
+			RejectedCount:    item.RejectedCount,
+			PendingCount:     item.PendingCount,
+			PendingFor:       time.Since(item.Invoice.CreatedAt).Round(time.Second).String(),
+		},
+	}
+
+	if item.Claim != nil {
+		output.Claim = FromReviewClaim(item.Claim)
+	}
+
+	return output
+}
+
+func FromReviewActionEntry(entry *domain.ReviewActionEntry) *ReviewActionOutput {
+	return &ReviewActionOutput{
+		ID:        entry.ID,
+		InvoiceID: entry.InvoiceID,
+		Reviewer:  entry.Reviewer,
+		Action:    string(entry.Action),
+		Reason:    entry.Reason,
+		CreatedAt: entry.CreatedAt,
+	}
+}
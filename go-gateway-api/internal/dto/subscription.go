@@ -0,0 +1,131 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CreatePlanInput struct {
+	Name          string  `json:"name"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`       // BRL quando não informada
+	Interval      string  `json:"interval"`       // day, week, month ou year
+	IntervalCount int     `json:"interval_count"` // 1 quando não informado
+	TrialDays     int     `json:"trial_days"`
+}
+
+type PlanOutput struct {
+	ID            string    `json:"id"`
+	AccountID     string    `json:"account_id"`
+	Name          string    `json:"name"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	Interval      string    `json:"interval"`
+	IntervalCount int       `json:"interval_count"`
+	TrialDays     int       `json:"trial_days"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type CreateSubscriptionInput struct {
+	CustomerID      string `json:"customer_id"`
+	PlanID          string `json:"plan_id"`
+	PaymentMethodID string `json:"payment_method_id"`
+}
+
+type ChangeSubscriptionPlanInput struct {
+	PlanID string `json:"plan_id"`
+}
+
+type ChangeSubscriptionPaymentMethodInput struct {
+	PaymentMethodID string `json:"payment_method_id"`
+}
+
+type CancelSubscriptionInput struct {
+	AtPeriodEnd bool `json:"at_period_end"` // mantém a assinatura até o fim do período já pago
+}
+
+type SubscriptionOutput struct {
+	ID                 string     `json:"id"`
+	AccountID          string     `json:"account_id"`
+	CustomerID         string     `json:"customer_id"`
+	PlanID             string     `json:"plan_id"`
+	PaymentMethodID    string     `json:"payment_method_id"`
+	Status             string     `json:"status"`
+	CurrentPeriodStart time.Time  `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time  `json:"current_period_end"` // data da próxima cobrança
+	TrialEnd           *time.Time `json:"trial_end,omitempty"`
+	CancelAtPeriodEnd  bool       `json:"cancel_at_period_end"`
+	CanceledAt         *time.Time `json:"canceled_at,omitempty"`
+	ProrationAmount    float64    `json:"proration_amount"` // ajuste das trocas de plano na próxima cobrança
+	LatestInvoiceID    string     `json:"latest_invoice_id,omitempty"`
+	FailedAttempts     int        `json:"failed_attempts"` // cobranças recusadas seguidas do período atual
+	NextRetryAt        *time.Time `json:"next_retry_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+func ToPlan(input CreatePlanInput, accountID string) (*domain.Plan, error) {
+	currency, err := domain.ParseCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewPlan(
+		accountID,
+		input.Name,
+		input.Amount,
+		currency,
+		domain.BillingInterval(strings.ToLower(input.Interval)),
+		input.IntervalCount,
+		input.TrialDays,
+	)
+}
+
+func FromPlan(plan *domain.Plan) *PlanOutput {
+	return &PlanOutput{
+		ID:            plan.ID,
+		AccountID:     plan.AccountID,
+		Name:          plan.Name,
+		Amount:        plan.Amount,
+		Currency:      string(plan.Currency),
+		Interval:      string(plan.Interval),
+		IntervalCount: plan.IntervalCount,
+		TrialDays:     plan.TrialDays,
+		CreatedAt:     plan.CreatedAt,
+	}
+}
+
+func FromSubscription(subscription *domain.Subscription) *SubscriptionOutput {
+	output := &SubscriptionOutput{
+		ID:                 subscription.ID,
+		AccountID:          subscription.AccountID,
+		CustomerID:         subscription.CustomerID,
+		PlanID:             subscription.PlanID,
+		PaymentMethodID:    subscription.PaymentMethodID,
+		Status:             string(subscription.Status),
+		CurrentPeriodStart: subscription.CurrentPeriodStart,
+		CurrentPeriodEnd:   subscription.CurrentPeriodEnd,
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+		ProrationAmount:    subscription.ProrationAmount,
+		LatestInvoiceID:    subscription.LatestInvoiceID,
+		FailedAttempts:     subscription.FailedAttempts,
+		CreatedAt:          subscription.CreatedAt,
+		UpdatedAt:          subscription.UpdatedAt,
+	}
+
+	if !subscription.TrialEnd.IsZero() {
+		output.TrialEnd = &subscription.TrialEnd
+	}
+
+	if !subscription.CanceledAt.IsZero() {
+		output.CanceledAt = &subscription.CanceledAt
+	}
+
+	if !subscription.NextRetryAt.IsZero() {
+		output.NextRetryAt = &subscription.NextRetryAt
+	}
+
+	return output
+}
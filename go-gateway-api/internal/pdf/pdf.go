@@ -0,0 +1,229 @@
+// Package pdf gera documentos PDF simples (texto, retângulos, linhas e imagens) sem
+// dependências externas. O texto usa as fontes padrão Helvetica, que todo leitor de PDF
+// possui, com a codificação WinAnsi (Windows-1252), suficiente para o português.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// Tamanho da página A4 em pontos (1/72 de polegada)
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Color struct {
+	R, G, B uint8
+}
+
+// Document acumula as páginas e as imagens; as coordenadas das páginas são em pontos a
+// partir do canto superior esquerdo
+type Document struct {
+	pages  []*Page
+	images []*Image
+}
+
+type Page struct {
+	content bytes.Buffer
+}
+
+// Image é uma imagem incorporada ao documento, que pode ser desenhada em várias páginas
+type Image struct {
+	name   string
+	width  int
+	height int
+	rgb    []byte // pixels RGB, sem transparência
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// AddImage incorpora a imagem; pixels transparentes são compostos sobre fundo branco
+func (d *Document) AddImage(img image.Image) *Image {
+	bounds := img.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			// RGBA retorna valores pré-multiplicados em 16 bits
+			white := 0xffff - a
+			rgb = append(rgb, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+	}
+
+	embedded := &Image{
+		name:   fmt.Sprintf("Im%d", len(d.images)+1),
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		rgb:    rgb,
+	}
+	d.images = append(d.images, embedded)
+	return embedded
+}
+
+// Size é a largura e a altura da imagem em pixels
+func (i *Image) Size() (int, int) {
+	return i.width, i.height
+}
+
+// Text escreve uma linha com a base em y
+func (p *Page) Text(x, y, size float64, bold bool, color Color, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	fmt.Fprintf(&p.content, "BT %s rg /%s %s Tf %s %s Td (%s) Tj ET\n",
+		color.operands(), font, number(size), number(x), number(PageHeight-y), escape(encode(text)))
+}
+
+// TextRight escreve uma linha alinhada à direita em x
+func (p *Page) TextRight(x, y, size float64, bold bool, color Color, text string) {
+	p.Text(x-TextWidth(text, size, bold), y, size, bold, color, text)
+}
+
+// Rect preenche um retângulo cujo canto superior esquerdo é (x, y)
+func (p *Page) Rect(x, y, width, height float64, fill Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n",
+		fill.operands(), number(x), number(PageHeight-y-height), number(width), number(height))
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s m %s %s l S\n",
+		color.operands(), number(width), number(x1), number(PageHeight-y1), number(x2), number(PageHeight-y2))
+}
+
+// Image desenha a imagem no retângulo cujo canto superior esquerdo é (x, y)
+func (p *Page) Image(img *Image, x, y, width, height float64) {
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /%s Do Q\n",
+		number(width), number(height), number(x), number(PageHeight-y-height), img.name)
+}
+
+// WriteTo grava o documento: os objetos, a tabela de referências cruzadas com a posição de
+// cada um e o trailer
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string, stream []byte) int {
+		offsets = append(offsets, out.Len())
+		id := len(offsets)
+		fmt.Fprintf(&out, "%d 0 obj\n%s\n", id, body)
+		if stream != nil {
+			out.WriteString("stream\n")
+			out.Write(stream)
+			out.WriteString("\nendstream\n")
+		}
+		out.WriteString("endobj\n")
+		return id
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Os objetos 1 e 2 (catálogo e árvore de páginas) são reservados: as páginas apontam para
+	// a árvore, que só é conhecida no final
+	offsets = append(offsets, 0, 0)
+	regular := object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>", nil)
+	bold := object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>", nil)
+
+	var xObjects strings.Builder
+	for _, img := range d.images {
+		data := deflate(img.rgb)
+		id := object(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>",
+			img.width, img.height, len(data)), data)
+		fmt.Fprintf(&xObjects, " /%s %d 0 R", img.name, id)
+	}
+
+	resources := fmt.Sprintf("<< /Font << /F1 %d 0 R /F2 %d 0 R >> /XObject <<%s >> >>", regular, bold, xObjects.String())
+
+	var kids strings.Builder
+	for _, page := range d.pages {
+		data := deflate(page.content.Bytes())
+		content := object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>", len(data)), data)
+		id := object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			number(PageWidth), number(PageHeight), resources, content), nil)
+		fmt.Fprintf(&kids, "%d 0 R ", id)
+	}
+
+	offsets[0] = out.Len()
+	out.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	offsets[1] = out.Len()
+	fmt.Fprintf(&out, "2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.TrimSpace(kids.String()), len(d.pages))
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.WriteTo(w)
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	writer := zlib.NewWriter(&buf)
+	writer.Write(data)
+	writer.Close()
+	return buf.Bytes()
+}
+
+func (c Color) operands() string {
+	return fmt.Sprintf("%s %s %s", number(float64(c.R)/255), number(float64(c.G)/255), number(float64(c.B)/255))
+}
+
+// number formata sem notação científica e sem zeros desnecessários
+func number(value float64) string {
+	formatted := strings.TrimRight(fmt.Sprintf("%.3f", value), "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+// windows1252 mapeia os caracteres de 0x80 a 0x9F do Windows-1252 que diferem do Latin-1
+var windows1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converte o texto para Windows-1252; caracteres sem representação viram ?
+func encode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			encoded = append(encoded, byte(r))
+		case windows1252[r] != 0:
+			encoded = append(encoded, windows1252[r])
+		case r == '\t' || r == '\n' || r == '\r':
+			encoded = append(encoded, ' ')
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+func escape(text []byte) string {
+	var escaped strings.Builder
+	for _, b := range text {
+		if b == '\\' || b == '(' || b == ')' {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteByte(b)
+	}
+	return escaped.String()
+}
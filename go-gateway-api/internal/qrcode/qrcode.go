@@ -0,0 +1,524 @@
+// Package qrcode gera QR Codes (modo byte, correção de erros nível M) sem dependências
+// externas, seguindo a ISO/IEC 18004. É usado para os QR Codes do Pix.
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+var ErrContentTooLong = errors.New("content too long for a qr code")
+
+const (
+	minVersion = 1
+	maxVersion = 40
+	quietZone  = 4 // módulos claros ao redor do símbolo exigidos pela norma
+)
+
+// Blocos de correção de erros do nível M por versão (índice 0 não usado)
+var (
+	eccCodewordsPerBlock = [maxVersion + 1]int{0,
+		10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26,
+		26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28}
+	numErrorCorrectionBlocks = [maxVersion + 1]int{0,
+		1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16,
+		17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49}
+)
+
+// Code é a matriz de módulos de um QR Code; true é um módulo escuro
+type Code struct {
+	Version int
+	Size    int
+	modules [][]bool
+}
+
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode gera o QR Code da menor versão que comporta o conteúdo, escolhendo a máscara de
+// menor penalidade
+func Encode(content string) (*Code, error) {
+	data := []byte(content)
+
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if 4+charCountBits(version)+len(data)*8 <= numDataCodewords(version)*8 {
+			break
+		}
+	}
+
+	if version > maxVersion {
+		return nil, ErrContentTooLong
+	}
+
+	codewords := addEccAndInterleave(encodeData(data, version), version)
+
+	var best *Code
+	bestPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		code := newCode(version)
+		code.drawFunctionPatterns(mask)
+		code.drawCodewords(codewords)
+		code.applyMask(mask)
+
+		if penalty := code.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = code.Code, penalty
+		}
+	}
+
+	return best, nil
+}
+
+// PNG desenha o QR Code com scale pixels por módulo e a zona de silêncio da norma
+func (c *Code) PNG(scale int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+
+	size := (c.Size + 2*quietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			mx, my := x/scale-quietZone, y/scale-quietZone
+			value := color.Gray{Y: 255}
+			if mx >= 0 && my >= 0 && mx < c.Size && my < c.Size && c.modules[my][mx] {
+				value = color.Gray{Y: 0}
+			}
+			img.SetGray(x, y, value)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// numRawDataModules conta os módulos livres para dados e correção depois dos padrões fixos
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[version]*numErrorCorrectionBlocks[version]
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 == 1)
+	}
+}
+
+// encodeData monta o segmento em modo byte com terminador e bytes de preenchimento
+func encodeData(data []byte, version int) []byte {
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := numDataCodewords(version) * 8
+	terminator := min(4, capacity-len(bits))
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	result := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			result[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+	return result
+}
+
+// addEccAndInterleave divide os dados em blocos, calcula a correção Reed-Solomon de cada
+// um e intercala os codewords como exige a norma
+func addEccAndInterleave(data []byte, version int) []byte {
+	numBlocks := numErrorCorrectionBlocks[version]
+	blockEccLen := eccCodewordsPerBlock[version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		length := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			length++
+		}
+
+		block := append([]byte{}, data[k:k+length]...)
+		k += length
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0) // posição vazia, ignorada na intercalação
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplica no GF(2^8) com o polinômio 0x11D
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+type matrix struct {
+	*Code
+	isFunction [][]bool
+}
+
+func newCode(version int) *matrix {
+	size := version*4 + 17
+	code := &Code{Version: version, Size: size, modules: make([][]bool, size)}
+	isFunction := make([][]bool, size)
+	for i := range code.modules {
+		code.modules[i] = make([]bool, size)
+		isFunction[i] = make([]bool, size)
+	}
+	return &matrix{Code: code, isFunction: isFunction}
+}
+
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.isFunction[y][x] = true
+}
+
+func (m *matrix) drawFunctionPatterns(mask int) {
+	for i := 0; i < m.Size; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+
+	m.drawFinderPattern(3, 3)
+	m.drawFinderPattern(m.Size-4, 3)
+	m.drawFinderPattern(3, m.Size-4)
+
+	positions := alignmentPatternPositions(m.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// Os cantos já são ocupados pelos padrões de localização
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			m.drawAlignmentPattern(x, y)
+		}
+	}
+
+	m.drawFormatBits(mask)
+	m.drawVersion()
+}
+
+func (m *matrix) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			distance := max(abs(dx), abs(dy))
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < m.Size && yy >= 0 && yy < m.Size {
+				m.setFunction(xx, yy, distance != 2 && distance != 4)
+			}
+		}
+	}
+}
+
+func (m *matrix) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, position := numAlign-1, version*4+17-7; i >= 1; i, position = i-1, position-step {
+		result[i] = position
+	}
+	return result
+}
+
+// drawFormatBits grava o nível de correção (M) e a máscara, com o código BCH da norma
+func (m *matrix) drawFormatBits(mask int) {
+	const eccLevelM = 0b00
+	data := eccLevelM<<3 | mask
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+	bits := (data<<10 | remainder) ^ 0x5412
+
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(bits, i))
+	}
+	m.setFunction(8, 7, bit(bits, 6))
+	m.setFunction(8, 8, bit(bits, 7))
+	m.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.setFunction(m.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.Size-15+i, bit(bits, i))
+	}
+	m.setFunction(8, m.Size-8, true) // módulo escuro fixo
+}
+
+func (m *matrix) drawVersion() {
+	if m.Version < 7 {
+		return
+	}
+
+	remainder := m.Version
+	for i := 0; i < 12; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1F25)
+	}
+	bits := m.Version<<12 | remainder
+
+	for i := 0; i < 18; i++ {
+		dark := bit(bits, i)
+		a, b := m.Size-11+i%3, i/3
+		m.setFunction(a, b, dark)
+		m.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords percorre a matriz em zigue-zague de duas colunas, da direita para a esquerda
+func (m *matrix) drawCodewords(data []byte) {
+	i := 0
+	for right := m.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // pula a coluna do padrão de temporização
+		}
+
+		for vertical := 0; vertical < m.Size; vertical++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vertical
+				if (right+1)&2 == 0 {
+					y = m.Size - 1 - vertical
+				}
+
+				if !m.isFunction[y][x] && i < len(data)*8 {
+					m.modules[y][x] = bit(int(data[i>>3]), 7-i&7)
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.Size; y++ {
+		for x := 0; x < m.Size; x++ {
+			if m.isFunction[y][x] {
+				continue
+			}
+
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+
+			m.modules[y][x] = m.modules[y][x] != invert
+		}
+	}
+}
+
+// penalty aplica as quatro regras de penalidade da norma para escolher a máscara
+func (m *matrix) penalty() int {
+	result := 0
+
+	// Regra 1: sequências de 5 ou mais módulos da mesma cor em linhas e colunas
+	for y := 0; y < m.Size; y++ {
+		result += runPenalty(func(i int) bool { return m.modules[y][i] }, m.Size)
+	}
+	for x := 0; x < m.Size; x++ {
+		result += runPenalty(func(i int) bool { return m.modules[i][x] }, m.Size)
+	}
+
+	// Regra 2: blocos 2x2 da mesma cor
+	for y := 0; y < m.Size-1; y++ {
+		for x := 0; x < m.Size-1; x++ {
+			color := m.modules[y][x]
+			if color == m.modules[y][x+1] && color == m.modules[y+1][x] && color == m.modules[y+1][x+1] {
+				result += 3
+			}
+		}
+	}
+
+	// Regra 3: padrões parecidos com o de localização (1:1:3:1:1 com 4 módulos claros)
+	for y := 0; y < m.Size; y++ {
+		for x := 0; x < m.Size; x++ {
+			if m.finderLike(x, y, 1, 0) {
+				result += 40
+			}
+			if m.finderLike(x, y, 0, 1) {
+				result += 40
+			}
+		}
+	}
+
+	// Regra 4: proporção de módulos escuros distante de 50%
+	dark := 0
+	for y := 0; y < m.Size; y++ {
+		for x := 0; x < m.Size; x++ {
+			if m.modules[y][x] {
+				dark++
+			}
+		}
+	}
+	total := m.Size * m.Size
+	result += ((abs(dark*20-total*10)+total-1)/total - 1) * 10
+
+	return result
+}
+
+func runPenalty(dark func(int) bool, size int) int {
+	result := 0
+	run := 1
+	for i := 1; i <= size; i++ {
+		if i < size && dark(i) == dark(i-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			result += 3 + run - 5
+		}
+		run = 1
+	}
+	return result
+}
+
+var finderPatterns = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+func (m *matrix) finderLike(x, y, dx, dy int) bool {
+	length := len(finderPatterns[0])
+	if x+dx*(length-1) >= m.Size || y+dy*(length-1) >= m.Size {
+		return false
+	}
+
+	for _, pattern := range finderPatterns {
+		matches := true
+		for i, dark := range pattern {
+			if m.modules[y+dy*i][x+dx*i] != dark {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func bit(value, i int) bool {
+	return (value>>i)&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,181 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// "HELLO WORLD" na versão 1-M (exemplo do tutorial da thonky.com, conforme a ISO/IEC 18004)
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("reedSolomonRemainder() = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeVersionCapacity(t *testing.T) {
+	// Capacidade em bytes do nível M (tabela 7 da ISO/IEC 18004)
+	tests := []struct {
+		version  int
+		capacity int
+	}{
+		{version: 1, capacity: 14},
+		{version: 2, capacity: 26},
+		{version: 3, capacity: 42},
+		{version: 4, capacity: 62},
+		{version: 5, capacity: 84},
+		{version: 6, capacity: 106},
+		{version: 7, capacity: 122},
+		{version: 10, capacity: 213},
+		{version: 40, capacity: 2331},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode(strings.Repeat("a", tt.capacity))
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", tt.capacity, err)
+		}
+		if code.Version != tt.version {
+			t.Errorf("Encode(%d bytes).Version = %d, want %d", tt.capacity, code.Version, tt.version)
+		}
+		if code.Size != 17+4*tt.version {
+			t.Errorf("Encode(%d bytes).Size = %d, want %d", tt.capacity, code.Size, 17+4*tt.version)
+		}
+
+		if tt.version == maxVersion {
+			continue
+		}
+		if code, err := Encode(strings.Repeat("a", tt.capacity+1)); err != nil || code.Version != tt.version+1 {
+			t.Errorf("Encode(%d bytes) deveria usar a versão %d", tt.capacity+1, tt.version+1)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("a", 2332)); err != ErrContentTooLong {
+		t.Errorf("Encode(2332 bytes) err = %v, want %v", err, ErrContentTooLong)
+	}
+}
+
+func TestEncodeFormatBits(t *testing.T) {
+	// Informação de formato do nível M para as máscaras 0 a 7 (tabela C.1 da ISO/IEC 18004)
+	formats := map[int]int{
+		0b101010000010010: 0,
+		0b101000100100101: 1,
+		0b101111001111100: 2,
+		0b101101101001011: 3,
+		0b100010111111001: 4,
+		0b100000011001110: 5,
+		0b100111110010111: 6,
+		0b100101010100000: 7,
+	}
+
+	code, err := Encode("00020101021126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***630448CD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cópia ao redor do padrão de posição superior esquerdo
+	var first int
+	for i := 0; i <= 5; i++ {
+		first |= moduleBit(code, 8, i) << i
+	}
+	first |= moduleBit(code, 8, 7) << 6
+	first |= moduleBit(code, 8, 8) << 7
+	first |= moduleBit(code, 7, 8) << 8
+	for i := 9; i < 15; i++ {
+		first |= moduleBit(code, 14-i, 8) << i
+	}
+
+	// Cópia dividida entre os padrões superior direito e inferior esquerdo
+	var second int
+	for i := 0; i < 8; i++ {
+		second |= moduleBit(code, code.Size-1-i, 8) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= moduleBit(code, 8, code.Size-15+i) << i
+	}
+
+	if _, ok := formats[first]; !ok {
+		t.Errorf("informação de formato %015b não é do nível M", first)
+	}
+	if first != second {
+		t.Errorf("cópias da informação de formato diferentes: %015b e %015b", first, second)
+	}
+	if !code.Dark(8, code.Size-8) {
+		t.Error("módulo escuro fixo ausente")
+	}
+}
+
+func TestEncodeVersionBits(t *testing.T) {
+	// Informação de versão das versões 7 e 40 (tabela D.1 da ISO/IEC 18004)
+	tests := []struct {
+		content int
+		version int
+		bits    int
+	}{
+		{content: 122, version: 7, bits: 0b000111110010010100},
+		{content: 2331, version: 40, bits: 0b101000110001101001},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode(strings.Repeat("a", tt.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code.Version != tt.version {
+			t.Fatalf("Version = %d, want %d", code.Version, tt.version)
+		}
+
+		var topRight, bottomLeft int
+		for i := 0; i < 18; i++ {
+			a, b := code.Size-11+i%3, i/3
+			topRight |= moduleBit(code, a, b) << i
+			bottomLeft |= moduleBit(code, b, a) << i
+		}
+
+		if topRight != tt.bits || bottomLeft != tt.bits {
+			t.Errorf("versão %d: informação de versão %018b e %018b, want %018b", tt.version, topRight, bottomLeft, tt.bits)
+		}
+	}
+}
+
+func TestEncodeFinderPatterns(t *testing.T) {
+	code, err := Encode("https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Padrões de posição 7x7 nos três cantos, com o separador claro em volta
+	for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+		for dy := -1; dy <= 7; dy++ {
+			for dx := -1; dx <= 7; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || y < 0 || x >= code.Size || y >= code.Size {
+					continue
+				}
+
+				ring := max(abs(dx-3), abs(dy-3))
+				want := ring != 2 && ring != 4
+				if code.Dark(x, y) != want {
+					t.Fatalf("módulo (%d, %d) do padrão de posição em %v = %v, want %v", x, y, corner, code.Dark(x, y), want)
+				}
+			}
+		}
+	}
+
+	// Padrões de temporização alternados na linha e na coluna 6
+	for i := 8; i < code.Size-8; i++ {
+		if code.Dark(i, 6) != (i%2 == 0) || code.Dark(6, i) != (i%2 == 0) {
+			t.Fatalf("padrão de temporização incorreto na posição %d", i)
+		}
+	}
+}
+
+func moduleBit(code *Code, x, y int) int {
+	if code.Dark(x, y) {
+		return 1
+	}
+	return 0
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/lib/pq"
 )
 
 type AccountRepository struct {
@@ -15,34 +17,26 @@ func NewAccountRepository(db *sql.DB) *AccountRepository {
 	return &AccountRepository{db: db}
 }
 
-func (r *AccountRepository) Save(account *domain.Account) error {
-	stmt, err := r.db.Prepare(`INSERT INTO accounts (id, name, email, api_key, balance, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`)
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-	_, err = stmt.Exec(account.ID, account.Name, account.Email, account.APIKey, account.Balance, account.CreatedAt, account.UpdatedAt)
-	if err != nil {
-		return err
-	}
-	return nil // O go não possui try-catch, portanto verificamos se o erro é nil (se ele esta em branco)
-}
+// accountColumns e scanAccount mantêm a ordem das colunas igual em todas as consultas
+const accountColumns = `id, name, email, api_key, balance, pending_balance, settlement_delay_days, settlement_per_installment, status, created_at, updated_at, currency, publishable_key`
 
-func (r *AccountRepository) FindByAPIKey(apiKey string) (*domain.Account, error) {
+func scanAccount(row rowScanner) (*domain.Account, error) {
 	var account domain.Account
 	var createdAt, updatedAt time.Time
-	err := r.db.QueryRow(`
-		SELECT id, name, email, api_key, balance, created_at, updated_at 
-		FROM accounts 
-		WHERE api_key = $1
-	`, apiKey).Scan( // O método scan permite alterar o valor de account diretamente na memória
-		&account.ID, 
-		&account.Name, 
-		&account.Email, 
-		&account.APIKey, 
-		&account.Balance, 
-		&createdAt, 
-		&updatedAt) 
+	err := row.Scan( // O método scan permite alterar o valor de account diretamente na memória
+		&account.ID,
+		&account.Name,
+		&account.Email,
+		&account.APIKey,
+		&account.Balance,
+		&account.PendingBalance,
+		&account.SettlementSchedule.DelayDays,
+		&account.SettlementSchedule.PerInstallment,
+		&account.Status,
+		&createdAt,
+		&updatedAt,
+		&account.Currency,
+		&account.PublishableKey)
 
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrAccountNotFound // Se não encontrar, retorna nil
@@ -58,64 +52,313 @@ func (r *AccountRepository) FindByAPIKey(apiKey string) (*domain.Account, error)
 	return &account, nil // Retorna o ponteiro para a struct Account
 }
 
+func (r *AccountRepository) Save(account *domain.Account, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO accounts (`+accountColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+			account.ID, account.Name, account.Email, account.APIKey, account.Balance, account.PendingBalance, account.SettlementSchedule.DelayDays, account.SettlementSchedule.PerInstallment, account.Status, account.CreatedAt, account.UpdatedAt, account.Currency, account.PublishableKey)
+		if isUniqueViolation(err, "accounts_email_key") {
+			return domain.ErrDuplicatedEmail
+		}
+		return err // O go não possui try-catch, portanto verificamos se o erro é nil (se ele esta em branco)
+	})
+}
+
+func (r *AccountRepository) FindByAPIKey(apiKey string) (*domain.Account, error) {
+	return scanAccount(r.db.QueryRow(`
+		SELECT `+accountColumns+`
+		FROM accounts 
+		WHERE api_key = $1
+	`, apiKey))
+}
+
+func (r *AccountRepository) FindByPublishableKey(publishableKey string) (*domain.Account, error) {
+	return scanAccount(r.db.QueryRow(`
+		SELECT `+accountColumns+`
+		FROM accounts
+		WHERE publishable_key = $1
+	`, publishableKey))
+}
+
 func (r *AccountRepository) FindByID(id string) (*domain.Account, error) {
-	var account domain.Account
-	var createdAt, updatedAt time.Time
-	err := r.db.QueryRow(`
-		SELECT id, name, email, api_key, balance, created_at, updated_at 
+	return scanAccount(r.db.QueryRow(`
+		SELECT `+accountColumns+`
 		FROM accounts 
 		WHERE id = $1
-	`, id).Scan( // O método scan permite alterar o valor de account diretamente na memória
-		&account.ID, 
-		&account.Name, 
-		&account.Email, 
-		&account.APIKey, 
-		&account.Balance, 
-		&createdAt, 
-		&updatedAt) 
+	`, id))
+}
 
-	if err == sql.ErrNoRows {
-		return nil, domain.ErrAccountNotFound // Se não encontrar, retorna nil
-	}
+func (r *AccountRepository) UpdateStatus(account *domain.Account, from domain.AccountStatus, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE accounts SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4
+		`, account.Status, account.UpdatedAt, account.ID, from)
+		if err != nil {
+			return err
+		}
 
-	if err != nil {
-		return nil, err // Se houver outro erro, retorna o erro
-	}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
 
-	account.CreatedAt = createdAt
-	account.UpdatedAt = updatedAt
+		// Outro operador alterou o status entre a leitura e a atualização
+		if rowsAffected == 0 {
+			return domain.ErrInvalidAccountStatus
+		}
 
-	return &account, nil // Retorna o ponteiro para a struct Account
+		return nil
+	})
 }
 
-// devemos aplicar um lock no banco de dados para evitar concorrência
-func (r *AccountRepository) UpdateBalance(account *domain.Account) error {
+// AdjustBalance trava a conta, aplica o ajuste e registra o saldo antes e depois. O evento de
+// auditoria é montado por audit depois que os saldos são conhecidos.
+func (r *AccountRepository) AdjustBalance(adjustment *domain.BalanceAdjustment, audit func() (*domain.AuditEvent, error)) error {
 	tx, err := r.db.Begin()
-	if err != nil {	
+	if err != nil {
 		return err
 	}
-	defer tx.Rollback() // Garante que a transação será revertida em caso de erro
-
-	var currentBalance float64
-	err = tx.QueryRow(`SELECT balance FROM accounts WHERE id = $1 FOR UPDATE`, account.ID).Scan(&currentBalance)
+	defer tx.Rollback()
 
+	var balance float64
+	err = tx.QueryRow(`SELECT balance FROM accounts WHERE id = $1 FOR UPDATE`, adjustment.AccountID).Scan(&balance)
 	if err == sql.ErrNoRows {
 		return domain.ErrAccountNotFound
 	}
 
 	if err != nil {
-		return	err 
+		return err
+	}
+
+	newBalance := balance + adjustment.Amount
+	if newBalance < 0 {
+		return domain.ErrInsufficientBalance
 	}
 
 	_, err = tx.Exec(`
-		UPDATE accounts
-		SET balance = $1, updated_at = $2
-		WHERE id = $3
-	`, account.Balance, time.Now(), account.ID)
+		UPDATE accounts SET balance = $1, updated_at = $2 WHERE id = $3
+	`, newBalance, adjustment.CreatedAt, adjustment.AccountID)
+	if err != nil {
+		return err
+	}
 
+	_, err = tx.Exec(`
+		INSERT INTO balance_adjustments (id, account_id, amount, reason, operator, balance_before, balance_after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, adjustment.ID, adjustment.AccountID, adjustment.Amount, adjustment.Reason, adjustment.Operator, balance, newBalance, adjustment.CreatedAt)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit() // Confirma transação
-}
\ No newline at end of file
+	adjustment.BalanceBefore = balance
+	adjustment.BalanceAfter = newBalance
+
+	event, err := audit()
+	if err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, []*domain.AuditEvent{event}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *AccountRepository) UpdateProfile(account *domain.Account, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE accounts SET name = $1, email = $2, updated_at = $3 WHERE id = $4
+		`, account.Name, account.Email, account.UpdatedAt, account.ID)
+		if isUniqueViolation(err, "accounts_email_key") {
+			return domain.ErrDuplicatedEmail
+		}
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrAccountNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *AccountRepository) FindAdjustmentsByAccountID(accountID string) ([]*domain.BalanceAdjustment, error) {
+	rows, err := r.db.Query(`
+		SELECT id, account_id, amount, reason, operator, balance_before, balance_after, created_at
+		FROM balance_adjustments
+		WHERE account_id = $1
+		ORDER BY created_at
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	adjustments := []*domain.BalanceAdjustment{}
+	for rows.Next() {
+		var adjustment domain.BalanceAdjustment
+		err := rows.Scan(
+			&adjustment.ID,
+			&adjustment.AccountID,
+			&adjustment.Amount,
+			&adjustment.Reason,
+			&adjustment.Operator,
+			&adjustment.BalanceBefore,
+			&adjustment.BalanceAfter,
+			&adjustment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		adjustments = append(adjustments, &adjustment)
+	}
+
+	return adjustments, rows.Err()
+}
+
+func (r *AccountRepository) FindLedgerEntriesByAccountID(accountID string) ([]*domain.LedgerEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, account_id, type, amount, reference_id, description, created_at
+		FROM ledger_entries
+		WHERE account_id = $1
+		ORDER BY created_at
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	entries := []*domain.LedgerEntry{}
+	for rows.Next() {
+		var entry domain.LedgerEntry
+		var referenceID sql.NullString
+		err := rows.Scan(
+			&entry.ID,
+			&entry.AccountID,
+			&entry.Type,
+			&entry.Amount,
+			&referenceID,
+			&entry.Description,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.ReferenceID = referenceID.String
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *AccountRepository) Close(account *domain.Account, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE accounts SET status = $1, updated_at = $2 WHERE id = $3 AND balance = 0 AND pending_balance = 0
+		`, account.Status, account.UpdatedAt, account.ID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		// O saldo mudou entre a leitura e o encerramento
+		if rowsAffected == 0 {
+			return domain.ErrNonZeroBalance
+		}
+
+		return nil
+	})
+}
+
+func (r *AccountRepository) UpdateSettlementSchedule(account *domain.Account, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE accounts SET settlement_delay_days = $1, settlement_per_installment = $2, updated_at = $3 WHERE id = $4
+		`, account.SettlementSchedule.DelayDays, account.SettlementSchedule.PerInstallment, account.UpdatedAt, account.ID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrAccountNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *AccountRepository) UpdateAPIKey(account *domain.Account, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE accounts SET api_key = $1, updated_at = $2 WHERE id = $3
+		`, account.APIKey, account.UpdatedAt, account.ID)
+		if isUniqueViolation(err, "accounts_api_key_key") {
+			return domain.ErrDuplicatedAPIKey
+		}
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrAccountNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *AccountRepository) UpdatePublishableKey(account *domain.Account, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE accounts SET publishable_key = $1, updated_at = $2 WHERE id = $3
+		`, account.PublishableKey, account.UpdatedAt, account.ID)
+		if isUniqueViolation(err, "accounts_publishable_key_key") {
+			return domain.ErrDuplicatedPublishableKey
+		}
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrAccountNotFound
+		}
+
+		return nil
+	})
+}
+
+// isUniqueViolation identifica a violação da constraint unique informada
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/lib/pq"
+)
+
+type AnticipationRepository struct {
+	db *sql.DB
+}
+
+func NewAnticipationRepository(db *sql.DB) *AnticipationRepository {
+	return &AnticipationRepository{db: db}
+}
+
+const anticipationColumns = `id, account_id, monthly_rate, gross_amount, discount_amount, net_amount, created_at`
+
+func scanAnticipation(row rowScanner) (*domain.Anticipation, error) {
+	var anticipation domain.Anticipation
+	err := row.Scan(
+		&anticipation.ID,
+		&anticipation.AccountID,
+		&anticipation.MonthlyRate,
+		&anticipation.GrossAmount,
+		&anticipation.DiscountAmount,
+		&anticipation.NetAmount,
+		&anticipation.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAnticipationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &anticipation, nil
+}
+
+func (r *AnticipationRepository) FindAnticipatable(accountID string, ids []string, date time.Time) ([]*domain.Receivable, error) {
+	query := `
+		SELECT ` + receivableColumns + `
+		FROM receivables
+		WHERE account_id = $1 AND status = $2 AND due_date > $3
+	`
+	args := []any{accountID, domain.ReceivableStatusScheduled, date}
+	if len(ids) > 0 {
+		query += ` AND id = ANY($4)`
+		args = append(args, pq.Array(ids))
+	}
+	query += ` ORDER BY due_date, installment_number`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	receivables := []*domain.Receivable{}
+	for rows.Next() {
+		receivable, err := scanReceivable(rows)
+		if err != nil {
+			return nil, err
+		}
+		receivables = append(receivables, receivable)
+	}
+
+	return receivables, rows.Err()
+}
+
+func (r *AnticipationRepository) Create(anticipation *domain.Anticipation, entries []*domain.LedgerEntry, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO anticipations (`+anticipationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		anticipation.ID,
+		anticipation.AccountID,
+		anticipation.MonthlyRate,
+		anticipation.GrossAmount,
+		anticipation.DiscountAmount,
+		anticipation.NetAmount,
+		anticipation.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	// A condição de status garante que um recebível liquidado pelo job ou antecipado em outra
+	// requisição não seja creditado duas vezes
+	result, err := tx.Exec(`
+		UPDATE receivables SET status = $1, settled_at = $2, anticipation_id = $3
+		WHERE id = ANY($4) AND account_id = $5 AND status = $6
+	`, domain.ReceivableStatusAnticipated, anticipation.CreatedAt, anticipation.ID,
+		pq.Array(anticipation.ReceivableIDs()), anticipation.AccountID, domain.ReceivableStatusScheduled)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected != int64(len(anticipation.Items)) {
+		return domain.ErrReceivableNotAnticipatable
+	}
+
+	for _, item := range anticipation.Items {
+		_, err := tx.Exec(`
+			INSERT INTO anticipation_items (anticipation_id, receivable_id, days, amount, discount, net_amount)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, anticipation.ID, item.ReceivableID, item.Days, item.Amount, item.Discount, item.NetAmount)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE accounts
+		SET pending_balance = pending_balance - $1, balance = balance + $2, updated_at = $3
+		WHERE id = $4
+	`, anticipation.GrossAmount, anticipation.NetAmount, anticipation.CreatedAt, anticipation.AccountID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		_, err := tx.Exec(`
+			INSERT INTO ledger_entries (id, account_id, type, amount, reference_id, description, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, entry.ID, entry.AccountID, entry.Type, entry.Amount, entry.ReferenceID, entry.Description, entry.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		// O saldo da conta antecipada já foi movido acima; lançamentos de outras contas
+		// (receita da plataforma) creditam o saldo disponível delas
+		if entry.AccountID == anticipation.AccountID {
+			continue
+		}
+
+		result, err := tx.Exec(`
+			UPDATE accounts SET balance = balance + $1, updated_at = $2 WHERE id = $3
+		`, entry.Amount, entry.CreatedAt, entry.AccountID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrAccountNotFound
+		}
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *AnticipationRepository) FindByID(id string) (*domain.Anticipation, error) {
+	anticipation, err := scanAnticipation(r.db.QueryRow(`
+		SELECT `+anticipationColumns+`
+		FROM anticipations
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT i.receivable_id, rc.invoice_id, rc.installment_number, rc.due_date, i.days, i.amount, i.discount, i.net_amount
+		FROM anticipation_items i
+		JOIN receivables rc ON rc.id = i.receivable_id
+		WHERE i.anticipation_id = $1
+		ORDER BY rc.due_date, rc.installment_number
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.AnticipationItem
+		err := rows.Scan(
+			&item.ReceivableID,
+			&item.InvoiceID,
+			&item.InstallmentNumber,
+			&item.DueDate,
+			&item.Days,
+			&item.Amount,
+			&item.Discount,
+			&item.NetAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		anticipation.Items = append(anticipation.Items, &item)
+	}
+
+	return anticipation, rows.Err()
+}
+
+func (r *AnticipationRepository) FindByAccountID(accountID string) ([]*domain.Anticipation, error) {
+	rows, err := r.db.Query(`
+		SELECT `+anticipationColumns+`
+		FROM anticipations
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	anticipations := []*domain.Anticipation{}
+	for rows.Next() {
+		anticipation, err := scanAnticipation(rows)
+		if err != nil {
+			return nil, err
+		}
+		anticipations = append(anticipations, anticipation)
+	}
+
+	return anticipations, rows.Err()
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// auditChainLock serializa as escritas para que cada evento aponte para o anterior
+const auditChainLock = "audit_events_chain"
+
+const auditEventColumns = `id, sequence, actor_type, actor_id, actor_ip, action, entity_type, entity_id, before_state, after_state, occurred_at, prev_hash, hash`
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// appendAuditEvents encadeia os eventos ao último gravado dentro da transação da operação
+// auditada: a operação e a trilha são confirmadas juntas ou nenhuma delas é. Deve ser chamada
+// logo antes do commit, já que o lock da cadeia fica com a transação até o fim.
+func appendAuditEvents(tx *sql.Tx, events []*domain.AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Lock liberado automaticamente no commit/rollback
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, lockKey(auditChainLock)); err != nil {
+		return err
+	}
+
+	var prevSequence int64
+	var prevHash string
+	err := tx.QueryRow(`
+		SELECT sequence, hash FROM audit_events ORDER BY sequence DESC LIMIT 1
+	`).Scan(&prevSequence, &prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	for _, event := range events {
+		event.Chain(prevSequence, prevHash)
+
+		_, err = tx.Exec(`
+			INSERT INTO audit_events (`+auditEventColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		`,
+			event.ID,
+			event.Sequence,
+			event.Actor.Type,
+			event.Actor.ID,
+			event.Actor.IP,
+			event.Action,
+			event.EntityType,
+			event.EntityID,
+			nullableJSON(event.Before),
+			nullableJSON(event.After),
+			event.OccurredAt,
+			event.PrevHash,
+			event.Hash,
+		)
+		if err != nil {
+			return err
+		}
+
+		prevSequence = event.Sequence
+		prevHash = event.Hash
+	}
+
+	return nil
+}
+
+// inTx executa fn em uma transação e grava os eventos de auditoria antes do commit
+func inTx(db *sql.DB, audit []*domain.AuditEvent, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Find retorna os eventos mais recentes primeiro aplicando os filtros informados
+func (r *AuditRepository) Find(filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	conditions := []string{}
+	args := []any{}
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.Replace(condition, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+
+	if filter.EntityType != "" {
+		addCondition("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		addCondition("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != "" {
+		addCondition("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		addCondition("action = ?", filter.Action)
+	}
+	if !filter.From.IsZero() {
+		addCondition("occurred_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		addCondition("occurred_at < ?", filter.To.UTC())
+	}
+
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query += ` ORDER BY sequence DESC LIMIT $` + strconv.Itoa(len(args))
+
+	return r.findMany(query, args...)
+}
+
+func (r *AuditRepository) FindAfterSequence(after int64, limit int) ([]*domain.AuditEvent, error) {
+	return r.findMany(`
+		SELECT `+auditEventColumns+`
+		FROM audit_events
+		WHERE sequence > $1
+		ORDER BY sequence
+		LIMIT $2
+	`, after, limit)
+}
+
+func (r *AuditRepository) findMany(query string, args ...any) ([]*domain.AuditEvent, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	events := []*domain.AuditEvent{}
+	for rows.Next() {
+		var event domain.AuditEvent
+		var before, after []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.Sequence,
+			&event.Actor.Type,
+			&event.Actor.ID,
+			&event.Actor.IP,
+			&event.Action,
+			&event.EntityType,
+			&event.EntityID,
+			&before,
+			&after,
+			&event.OccurredAt,
+			&event.PrevHash,
+			&event.Hash,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Before = before
+		event.After = after
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// nullableJSON grava NULL em vez de um JSON vazio
+func nullableJSON(value []byte) any {
+	if len(value) == 0 {
+		return nil
+	}
+	return string(value)
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type BoletoRepository struct {
+	db *sql.DB
+}
+
+func NewBoletoRepository(db *sql.DB) *BoletoRepository {
+	return &BoletoRepository{db: db}
+}
+
+const boletoColumns = `id, invoice_id, account_id, bank_code, our_number, payer_name, payer_document, amount, due_date, payment_limit_date, fine_percentage, interest_percentage, barcode, digitable_line, status, paid_amount, paid_at, created_at, updated_at`
+
+func scanBoleto(row rowScanner) (*domain.Boleto, error) {
+	var boleto domain.Boleto
+	var paidAt sql.NullTime
+	err := row.Scan(
+		&boleto.ID,
+		&boleto.InvoiceID,
+		&boleto.AccountID,
+		&boleto.BankCode,
+		&boleto.OurNumber,
+		&boleto.Payer.Name,
+		&boleto.Payer.Document,
+		&boleto.Amount,
+		&boleto.DueDate,
+		&boleto.PaymentLimitDate,
+		&boleto.FinePercentage,
+		&boleto.InterestPercentage,
+		&boleto.Barcode,
+		&boleto.DigitableLine,
+		&boleto.Status,
+		&boleto.PaidAmount,
+		&paidAt,
+		&boleto.CreatedAt,
+		&boleto.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrBoletoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	boleto.PaidAt = paidAt.Time
+	return &boleto, nil
+}
+
+// NextOurNumber reserva o próximo nosso número; a sequência nunca repete valores, mesmo
+// que a emissão falhe depois
+func (r *BoletoRepository) NextOurNumber() (int64, error) {
+	var ourNumber int64
+	err := r.db.QueryRow(`SELECT nextval('boleto_our_number_seq')`).Scan(&ourNumber)
+	return ourNumber, err
+}
+
+// Create grava a fatura e o boleto na mesma transação
+func (r *BoletoRepository) Create(invoice *domain.Invoice, boleto *domain.Boleto, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertInvoice(tx, invoice); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO boletos (`+boletoColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`,
+		boleto.ID,
+		boleto.InvoiceID,
+		boleto.AccountID,
+		boleto.BankCode,
+		boleto.OurNumber,
+		boleto.Payer.Name,
+		boleto.Payer.Document,
+		boleto.Amount,
+		boleto.DueDate,
+		boleto.PaymentLimitDate,
+		boleto.FinePercentage,
+		boleto.InterestPercentage,
+		boleto.Barcode,
+		boleto.DigitableLine,
+		boleto.Status,
+		boleto.PaidAmount,
+		nil,
+		boleto.CreatedAt,
+		boleto.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *BoletoRepository) FindByInvoiceID(invoiceID string) (*domain.Boleto, error) {
+	return scanBoleto(r.db.QueryRow(`
+		SELECT `+boletoColumns+`
+		FROM boletos
+		WHERE invoice_id = $1
+	`, invoiceID))
+}
+
+func (r *BoletoRepository) FindByOurNumber(bankCode, ourNumber string) (*domain.Boleto, error) {
+	return scanBoleto(r.db.QueryRow(`
+		SELECT `+boletoColumns+`
+		FROM boletos
+		WHERE bank_code = $1 AND our_number = $2
+	`, bankCode, ourNumber))
+}
+
+// ConfirmPayment marca o boleto como pago e aprova a fatura, com os encargos de atraso no
+// valor cobrado, na mesma transação. Retorna ErrBoletoAlreadyPaid se outra confirmação ou a
+// expiração alterou o boleto nesse meio tempo.
+func (r *BoletoRepository) ConfirmPayment(boleto *domain.Boleto, invoice *domain.Invoice, approval *domain.InvoiceApproval, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE boletos SET status = $1, paid_amount = $2, paid_at = $3, updated_at = $4
+		WHERE id = $5 AND status = $6
+	`, boleto.Status, boleto.PaidAmount, boleto.PaidAt, boleto.UpdatedAt, boleto.ID, domain.BoletoStatusRegistered)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrBoletoAlreadyPaid
+	}
+
+	result, err = tx.Exec(`
+		UPDATE invoices SET status = $1, total_amount = $2, exchange_rate = $3, settlement_amount = $4, fee_amount = $5, net_amount = $6, updated_at = $7
+		WHERE id = $8 AND status = $9
+	`, invoice.Status, invoice.TotalAmount, invoice.ExchangeRate, invoice.SettlementAmount, invoice.FeeAmount, invoice.NetAmount, invoice.UpdatedAt, invoice.ID, domain.StatusAwaitingPayment)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidStatus
+	}
+
+	if err := recordApproval(tx, approval); err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *BoletoRepository) FindByAccountID(accountID string) ([]*domain.Boleto, error) {
+	rows, err := r.db.Query(`
+		SELECT `+boletoColumns+`
+		FROM boletos
+		WHERE account_id = $1
+		ORDER BY created_at
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	boletos := []*domain.Boleto{}
+	for rows.Next() {
+		boleto, err := scanBoleto(rows)
+		if err != nil {
+			return nil, err
+		}
+		boletos = append(boletos, boleto)
+	}
+
+	return boletos, rows.Err()
+}
+
+// FindExpired retorna os boletos em aberto cuja data limite de pagamento é anterior a date
+func (r *BoletoRepository) FindExpired(date time.Time, limit int) ([]*domain.Boleto, error) {
+	rows, err := r.db.Query(`
+		SELECT `+boletoColumns+`
+		FROM boletos
+		WHERE status = $1 AND payment_limit_date < $2
+		ORDER BY payment_limit_date
+		LIMIT $3
+	`, domain.BoletoStatusRegistered, date, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	boletos := []*domain.Boleto{}
+	for rows.Next() {
+		boleto, err := scanBoleto(rows)
+		if err != nil {
+			return nil, err
+		}
+		boletos = append(boletos, boleto)
+	}
+
+	return boletos, rows.Err()
+}
+
+// Expire encerra o boleto e a fatura; retorna ErrInvalidStatus se o pagamento foi
+// confirmado nesse meio tempo
+func (r *BoletoRepository) Expire(boleto *domain.Boleto, invoice *domain.Invoice, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE boletos SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`, boleto.Status, boleto.UpdatedAt, boleto.ID, domain.BoletoStatusRegistered)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidStatus
+	}
+
+	_, err = tx.Exec(`
+		UPDATE invoices SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`, invoice.Status, invoice.UpdatedAt, invoice.ID, domain.StatusAwaitingPayment)
+	if err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
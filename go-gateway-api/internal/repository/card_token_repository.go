@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CardTokenRepository struct {
+	db *sql.DB
+}
+
+func NewCardTokenRepository(db *sql.DB) *CardTokenRepository {
+	return &CardTokenRepository{db: db}
+}
+
+const cardTokenColumns = `id, account_id, card_brand, card_last_digits, expiration_month, expiration_year, cardholder_name, sealed_card, expires_at, used_at, created_at`
+
+func scanCardToken(row rowScanner) (*domain.CardToken, error) {
+	var token domain.CardToken
+	var usedAt sql.NullTime
+	err := row.Scan(
+		&token.ID,
+		&token.AccountID,
+		&token.CardBrand,
+		&token.CardLastDigits,
+		&token.ExpirationMonth,
+		&token.ExpirationYear,
+		&token.CardholderName,
+		&token.SealedCard,
+		&token.ExpiresAt,
+		&usedAt,
+		&token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCardTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token.UsedAt = usedAt.Time
+	return &token, nil
+}
+
+func (r *CardTokenRepository) Create(token *domain.CardToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Os tokens vencidos não servem mais para nada; apagá-los aqui evita um job só para isso
+	if _, err := tx.Exec(`DELETE FROM card_tokens WHERE expires_at < $1`, token.CreatedAt); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO card_tokens (`+cardTokenColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		token.ID,
+		token.AccountID,
+		token.CardBrand,
+		token.CardLastDigits,
+		token.ExpirationMonth,
+		token.ExpirationYear,
+		token.CardholderName,
+		token.SealedCard,
+		token.ExpiresAt,
+		nullTime(token.UsedAt),
+		token.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *CardTokenRepository) FindByID(id string) (*domain.CardToken, error) {
+	return scanCardToken(r.db.QueryRow(`
+		SELECT `+cardTokenColumns+`
+		FROM card_tokens
+		WHERE id = $1
+	`, id))
+}
+
+// MarkUsed consome o token uma única vez, mesmo com dois pagamentos simultâneos
+func (r *CardTokenRepository) MarkUsed(token *domain.CardToken) error {
+	result, err := r.db.Exec(`
+		UPDATE card_tokens SET used_at = $1, sealed_card = NULL
+		WHERE id = $2 AND used_at IS NULL
+	`, token.UsedAt, token.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCardTokenUsed
+	}
+
+	token.SealedCard = nil
+	return nil
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CheckoutRepository struct {
+	db *sql.DB
+}
+
+func NewCheckoutRepository(db *sql.DB) *CheckoutRepository {
+	return &CheckoutRepository{db: db}
+}
+
+const checkoutSessionColumns = `id, account_id, payment_link_id, amount, currency, description, success_url, cancel_url, status, invoice_id, failed_attempts, expires_at, created_at, updated_at`
+
+func scanCheckoutSession(row rowScanner) (*domain.CheckoutSession, error) {
+	var session domain.CheckoutSession
+	var paymentLinkID, invoiceID sql.NullString
+	err := row.Scan(
+		&session.ID,
+		&session.AccountID,
+		&paymentLinkID,
+		&session.Amount,
+		&session.Currency,
+		&session.Description,
+		&session.SuccessURL,
+		&session.CancelURL,
+		&session.Status,
+		&invoiceID,
+		&session.FailedAttempts,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCheckoutSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session.PaymentLinkID = paymentLinkID.String
+	session.InvoiceID = invoiceID.String
+	return &session, nil
+}
+
+func (r *CheckoutRepository) CreateSession(session *domain.CheckoutSession, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO checkout_sessions (`+checkoutSessionColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`,
+			session.ID,
+			session.AccountID,
+			nullString(session.PaymentLinkID),
+			session.Amount,
+			session.Currency,
+			session.Description,
+			session.SuccessURL,
+			session.CancelURL,
+			session.Status,
+			nullString(session.InvoiceID),
+			session.FailedAttempts,
+			session.ExpiresAt,
+			session.CreatedAt,
+			session.UpdatedAt,
+		)
+		return err
+	})
+}
+
+func (r *CheckoutRepository) FindSessionByID(id string) (*domain.CheckoutSession, error) {
+	return scanCheckoutSession(r.db.QueryRow(`
+		SELECT `+checkoutSessionColumns+`
+		FROM checkout_sessions
+		WHERE id = $1
+	`, id))
+}
+
+func (r *CheckoutRepository) FindSessionsByAccountID(accountID string) ([]*domain.CheckoutSession, error) {
+	rows, err := r.db.Query(`
+		SELECT `+checkoutSessionColumns+`
+		FROM checkout_sessions
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*domain.CheckoutSession{}
+	for rows.Next() {
+		session, err := scanCheckoutSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// CountOpenSessionsByPaymentLinkID conta as sessões do link que ainda podem ser pagas: abertas
+// ou com um pagamento em andamento, dentro do prazo
+func (r *CheckoutRepository) CountOpenSessionsByPaymentLinkID(paymentLinkID string, now time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM checkout_sessions
+		WHERE payment_link_id = $1
+		AND status IN ($2, $3) AND expires_at > $4
+	`, paymentLinkID, domain.CheckoutSessionStatusOpen, domain.CheckoutSessionStatusProcessing, now).Scan(&count)
+	return count, err
+}
+
+// UpdateSessionStatus usa o status anterior como trava: dois pagamentos simultâneos da mesma
+// sessão não conseguem ambos passar de open para processing
+func (r *CheckoutRepository) UpdateSessionStatus(session *domain.CheckoutSession, from domain.CheckoutSessionStatus) error {
+	return updateSessionStatus(r.db, session, from)
+}
+
+// SavePayment grava a fatura do pagamento, a aprovação (se aprovada) e a sessão concluída na
+// mesma transação: a fatura nunca fica sem a sessão que a gerou
+func (r *CheckoutRepository) SavePayment(session *domain.CheckoutSession, from domain.CheckoutSessionStatus, invoice *domain.Invoice, approval *domain.InvoiceApproval, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertInvoice(tx, invoice); err != nil {
+		return err
+	}
+
+	if err := recordApproval(tx, approval); err != nil {
+		return err
+	}
+
+	if err := updateSessionStatus(tx, session, from); err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func updateSessionStatus(db execer, session *domain.CheckoutSession, from domain.CheckoutSessionStatus) error {
+	result, err := db.Exec(`
+		UPDATE checkout_sessions SET status = $1, invoice_id = $2, failed_attempts = $3, updated_at = $4
+		WHERE id = $5 AND status = $6
+	`, session.Status, nullString(session.InvoiceID), session.FailedAttempts, session.UpdatedAt, session.ID, from)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCheckoutSessionNotOpen
+	}
+
+	return nil
+}
+
+const paymentLinkColumns = `id, account_id, amount, currency, description, success_url, cancel_url, active, created_at, updated_at`
+
+func scanPaymentLink(row rowScanner) (*domain.PaymentLink, error) {
+	var link domain.PaymentLink
+	err := row.Scan(
+		&link.ID,
+		&link.AccountID,
+		&link.Amount,
+		&link.Currency,
+		&link.Description,
+		&link.SuccessURL,
+		&link.CancelURL,
+		&link.Active,
+		&link.CreatedAt,
+		&link.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPaymentLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *CheckoutRepository) SavePaymentLink(link *domain.PaymentLink, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO payment_links (`+paymentLinkColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, link.ID, link.AccountID, link.Amount, link.Currency, link.Description, link.SuccessURL, link.CancelURL, link.Active, link.CreatedAt, link.UpdatedAt)
+		return err
+	})
+}
+
+func (r *CheckoutRepository) FindPaymentLinkByID(id string) (*domain.PaymentLink, error) {
+	return scanPaymentLink(r.db.QueryRow(`
+		SELECT `+paymentLinkColumns+`
+		FROM payment_links
+		WHERE id = $1
+	`, id))
+}
+
+func (r *CheckoutRepository) FindPaymentLinksByAccountID(accountID string) ([]*domain.PaymentLink, error) {
+	rows, err := r.db.Query(`
+		SELECT `+paymentLinkColumns+`
+		FROM payment_links
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []*domain.PaymentLink{}
+	for rows.Next() {
+		link, err := scanPaymentLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+func (r *CheckoutRepository) UpdatePaymentLink(link *domain.PaymentLink, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE payment_links SET active = $1, updated_at = $2 WHERE id = $3
+		`, link.Active, link.UpdatedAt, link.ID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrPaymentLinkNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CNABRepository struct {
+	db *sql.DB
+}
+
+func NewCNABRepository(db *sql.DB) *CNABRepository {
+	return &CNABRepository{db: db}
+}
+
+const cnabFileColumns = `id, type, kind, layout, sequence, file_name, checksum, content, status, record_count, errors, created_at`
+
+// cnabFileSummaryColumns omite o conteúdo, que pode ser grande, nas listagens
+const cnabFileSummaryColumns = `id, type, kind, layout, sequence, file_name, checksum, '', status, record_count, errors, created_at`
+
+func scanCNABFile(row rowScanner) (*domain.CNABFile, error) {
+	var file domain.CNABFile
+	var errors []byte
+	err := row.Scan(
+		&file.ID,
+		&file.Type,
+		&file.Kind,
+		&file.Layout,
+		&file.Sequence,
+		&file.FileName,
+		&file.Checksum,
+		&file.Content,
+		&file.Status,
+		&file.RecordCount,
+		&errors,
+		&file.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCNABFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(errors, &file.Errors); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+func insertCNABFile(db execer, file *domain.CNABFile) error {
+	errors, err := json.Marshal(file.Errors)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO cnab_files (`+cnabFileColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`,
+		file.ID,
+		file.Type,
+		file.Kind,
+		file.Layout,
+		file.Sequence,
+		file.FileName,
+		file.Checksum,
+		file.Content,
+		file.Status,
+		file.RecordCount,
+		errors,
+		file.CreatedAt,
+	)
+	return err
+}
+
+// NextRemittanceSequence reserva o número sequencial (NSA) da próxima remessa
+func (r *CNABRepository) NextRemittanceSequence() (int64, error) {
+	var sequence int64
+	err := r.db.QueryRow(`SELECT nextval('cnab_remittance_seq')`).Scan(&sequence)
+	return sequence, err
+}
+
+// FindBoletosToRemit retorna os boletos em aberto que ainda não foram enviados em uma remessa
+func (r *CNABRepository) FindBoletosToRemit(limit int) ([]*domain.Boleto, error) {
+	rows, err := r.db.Query(`
+		SELECT `+boletoColumns+`
+		FROM boletos
+		WHERE status = $1 AND remittance_file_id IS NULL
+		ORDER BY created_at
+		LIMIT $2
+	`, domain.BoletoStatusRegistered, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	boletos := []*domain.Boleto{}
+	for rows.Next() {
+		boleto, err := scanBoleto(rows)
+		if err != nil {
+			return nil, err
+		}
+		boletos = append(boletos, boleto)
+	}
+
+	return boletos, rows.Err()
+}
+
+// FindPayoutsToRemit retorna os saques pendentes para contas bancárias, com o destino;
+// saques para chaves Pix não podem ser pagos por arquivo
+func (r *CNABRepository) FindPayoutsToRemit(limit int) ([]domain.PayoutRemittanceEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT p.id, p.account_id, p.destination_id, p.amount, p.status, p.provider_reference, p.failure_reason, p.created_at, p.updated_at,
+			d.id, d.account_id, d.type, d.bank_code, d.branch, d.account_number, d.account_type, d.holder_name, d.holder_document, d.pix_key_type, d.pix_key, d.created_at
+		FROM payouts p
+		JOIN payout_destinations d ON d.id = p.destination_id
+		WHERE p.status = $1 AND d.type = $2
+		ORDER BY p.updated_at
+		LIMIT $3
+	`, domain.PayoutStatusPending, domain.PayoutDestinationBankAccount, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	entries := []domain.PayoutRemittanceEntry{}
+	for rows.Next() {
+		var payout domain.Payout
+		var destination domain.PayoutDestination
+		var bankAccount domain.BankAccount
+		var pixKeyType, pixKey sql.NullString
+		err := rows.Scan(
+			&payout.ID,
+			&payout.AccountID,
+			&payout.DestinationID,
+			&payout.Amount,
+			&payout.Status,
+			&payout.ProviderReference,
+			&payout.FailureReason,
+			&payout.CreatedAt,
+			&payout.UpdatedAt,
+			&destination.ID,
+			&destination.AccountID,
+			&destination.Type,
+			&bankAccount.BankCode,
+			&bankAccount.Branch,
+			&bankAccount.Number,
+			&bankAccount.Type,
+			&bankAccount.HolderName,
+			&bankAccount.HolderDocument,
+			&pixKeyType,
+			&pixKey,
+			&destination.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		destination.BankAccount = &bankAccount
+		entries = append(entries, domain.PayoutRemittanceEntry{Payout: &payout, Destination: &destination})
+	}
+
+	return entries, rows.Err()
+}
+
+// CreateRemittance grava a remessa, marca os boletos como enviados e os saques como em
+// trânsito na mesma transação. Retorna ErrInvalidStatus ou ErrInvalidPayoutStatus se algum
+// item foi alterado nesse meio tempo; a remessa deve então ser gerada de novo.
+func (r *CNABRepository) CreateRemittance(file *domain.CNABFile, boletos []*domain.Boleto, payouts []*domain.Payout, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertCNABFile(tx, file); err != nil {
+		return err
+	}
+
+	for _, boleto := range boletos {
+		result, err := tx.Exec(`
+			UPDATE boletos SET remittance_file_id = $1, updated_at = $2
+			WHERE id = $3 AND status = $4 AND remittance_file_id IS NULL
+		`, file.ID, file.CreatedAt, boleto.ID, domain.BoletoStatusRegistered)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrInvalidStatus
+		}
+	}
+
+	for _, payout := range payouts {
+		if err := updatePayoutStatus(tx, payout, domain.PayoutStatusPending); err != nil {
+			return err
+		}
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateReturn grava o retorno processado; retorna ErrDuplicateCNABFile se o mesmo conteúdo
+// já foi processado
+func (r *CNABRepository) CreateReturn(file *domain.CNABFile, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		err := insertCNABFile(tx, file)
+		if isUniqueViolation(err, "cnab_files_type_checksum_key") {
+			return domain.ErrDuplicateCNABFile
+		}
+		return err
+	})
+}
+
+func (r *CNABRepository) FindByID(id string) (*domain.CNABFile, error) {
+	return scanCNABFile(r.db.QueryRow(`
+		SELECT `+cnabFileColumns+`
+		FROM cnab_files
+		WHERE id = $1
+	`, id))
+}
+
+func (r *CNABRepository) FindByChecksum(fileType domain.CNABFileType, checksum string) (*domain.CNABFile, error) {
+	return scanCNABFile(r.db.QueryRow(`
+		SELECT `+cnabFileColumns+`
+		FROM cnab_files
+		WHERE type = $1 AND checksum = $2
+	`, fileType, checksum))
+}
+
+// List retorna os arquivos mais recentes primeiro, sem o conteúdo
+func (r *CNABRepository) List(limit int) ([]*domain.CNABFile, error) {
+	rows, err := r.db.Query(`
+		SELECT `+cnabFileSummaryColumns+`
+		FROM cnab_files
+		ORDER BY created_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	files := []*domain.CNABFile{}
+	for rows.Next() {
+		file, err := scanCNABFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+// FindPayoutByReference localiza o saque enviado em remessa pelo "seu número" devolvido no retorno
+func (r *CNABRepository) FindPayoutByReference(reference string) (*domain.Payout, error) {
+	payout, err := scanPayout(r.db.QueryRow(`
+		SELECT `+payoutColumns+`
+		FROM payouts
+		WHERE provider_reference = $1
+	`, reference))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPayoutNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return payout, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CouponRepository struct {
+	db *sql.DB
+}
+
+func NewCouponRepository(db *sql.DB) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+const couponColumns = `id, account_id, code, discount_type, value, currency, max_redemptions, redemptions, expires_at, active, created_at, updated_at`
+
+func scanCoupon(row rowScanner) (*domain.Coupon, error) {
+	var coupon domain.Coupon
+	var expiresAt sql.NullTime
+	err := row.Scan(
+		&coupon.ID,
+		&coupon.AccountID,
+		&coupon.Code,
+		&coupon.DiscountType,
+		&coupon.Value,
+		&coupon.Currency,
+		&coupon.MaxRedemptions,
+		&coupon.Redemptions,
+		&expiresAt,
+		&coupon.Active,
+		&coupon.CreatedAt,
+		&coupon.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCouponNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	coupon.ExpiresAt = expiresAt.Time
+	return &coupon, nil
+}
+
+func (r *CouponRepository) Save(coupon *domain.Coupon, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO coupons (`+couponColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`,
+			coupon.ID,
+			coupon.AccountID,
+			coupon.Code,
+			coupon.DiscountType,
+			coupon.Value,
+			coupon.Currency,
+			coupon.MaxRedemptions,
+			coupon.Redemptions,
+			nullTime(coupon.ExpiresAt),
+			coupon.Active,
+			coupon.CreatedAt,
+			coupon.UpdatedAt,
+		)
+		if isUniqueViolation(err, "coupons_account_id_code_key") {
+			return domain.ErrDuplicatedCouponCode
+		}
+		return err
+	})
+}
+
+func (r *CouponRepository) FindByID(id string) (*domain.Coupon, error) {
+	return scanCoupon(r.db.QueryRow(`
+		SELECT `+couponColumns+`
+		FROM coupons
+		WHERE id = $1
+	`, id))
+}
+
+func (r *CouponRepository) FindByCode(accountID, code string) (*domain.Coupon, error) {
+	return scanCoupon(r.db.QueryRow(`
+		SELECT `+couponColumns+`
+		FROM coupons
+		WHERE account_id = $1 AND code = $2
+	`, accountID, code))
+}
+
+func (r *CouponRepository) FindByAccountID(accountID string) ([]*domain.Coupon, error) {
+	rows, err := r.db.Query(`
+		SELECT `+couponColumns+`
+		FROM coupons
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coupons := []*domain.Coupon{}
+	for rows.Next() {
+		coupon, err := scanCoupon(rows)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, coupon)
+	}
+
+	return coupons, rows.Err()
+}
+
+// Update grava apenas a desativação; os resgates são contados por insertInvoice
+func (r *CouponRepository) Update(coupon *domain.Coupon, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			UPDATE coupons SET active = $1, updated_at = $2 WHERE id = $3
+		`, coupon.Active, coupon.UpdatedAt, coupon.ID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrCouponNotFound
+		}
+
+		return nil
+	})
+}
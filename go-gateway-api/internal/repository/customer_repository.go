@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type CustomerRepository struct {
+	db *sql.DB
+}
+
+func NewCustomerRepository(db *sql.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+const customerColumns = `id, account_id, name, email, document, document_hash, created_at`
+
+func scanCustomer(row rowScanner) (*domain.Customer, error) {
+	var customer domain.Customer
+	var document, documentHash sql.NullString
+	err := row.Scan(
+		&customer.ID,
+		&customer.AccountID,
+		&customer.Name,
+		&customer.Email,
+		&document,
+		&documentHash,
+		&customer.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	customer.Document = document.String
+	customer.DocumentHash = documentHash.String
+	return &customer, nil
+}
+
+func (r *CustomerRepository) Save(customer *domain.Customer, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		// Clientes sem documento deixam as colunas nulas
+		var document, documentHash sql.NullString
+		if customer.Document != "" {
+			document = sql.NullString{String: customer.Document, Valid: true}
+			documentHash = sql.NullString{String: customer.DocumentHash, Valid: true}
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO customers (`+customerColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, customer.ID, customer.AccountID, customer.Name, customer.Email, document, documentHash, customer.CreatedAt)
+		return err
+	})
+}
+
+func (r *CustomerRepository) FindByID(id string) (*domain.Customer, error) {
+	return scanCustomer(r.db.QueryRow(`
+		SELECT `+customerColumns+`
+		FROM customers
+		WHERE id = $1
+	`, id))
+}
+
+func (r *CustomerRepository) FindByAccountID(accountID string) ([]*domain.Customer, error) {
+	rows, err := r.db.Query(`
+		SELECT `+customerColumns+`
+		FROM customers
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []*domain.Customer{}
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+
+	return customers, rows.Err()
+}
+
+const paymentMethodColumns = `id, account_id, customer_id, card_brand, card_last_digits, expiration_month, expiration_year, cardholder_name, created_at`
+
+func scanPaymentMethod(row rowScanner) (*domain.PaymentMethod, error) {
+	var method domain.PaymentMethod
+	err := row.Scan(
+		&method.ID,
+		&method.AccountID,
+		&method.CustomerID,
+		&method.CardBrand,
+		&method.CardLastDigits,
+		&method.ExpirationMonth,
+		&method.ExpirationYear,
+		&method.CardholderName,
+		&method.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPaymentMethodNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+func (r *CustomerRepository) SavePaymentMethod(method *domain.PaymentMethod, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO payment_methods (`+paymentMethodColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, method.ID, method.AccountID, method.CustomerID, method.CardBrand, method.CardLastDigits, method.ExpirationMonth, method.ExpirationYear, method.CardholderName, method.CreatedAt)
+		return err
+	})
+}
+
+func (r *CustomerRepository) FindPaymentMethodByID(id string) (*domain.PaymentMethod, error) {
+	return scanPaymentMethod(r.db.QueryRow(`
+		SELECT `+paymentMethodColumns+`
+		FROM payment_methods
+		WHERE id = $1
+	`, id))
+}
+
+func (r *CustomerRepository) FindPaymentMethodsByCustomerID(customerID string) ([]*domain.PaymentMethod, error) {
+	rows, err := r.db.Query(`
+		SELECT `+paymentMethodColumns+`
+		FROM payment_methods
+		WHERE customer_id = $1
+		ORDER BY created_at DESC
+	`, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	methods := []*domain.PaymentMethod{}
+	for rows.Next() {
+		method, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+
+	return methods, rows.Err()
+}
+
+func (r *CustomerRepository) FindPaymentMethodsByAccountID(accountID string) ([]*domain.PaymentMethod, error) {
+	rows, err := r.db.Query(`
+		SELECT `+paymentMethodColumns+`
+		FROM payment_methods
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	methods := []*domain.PaymentMethod{}
+	for rows.Next() {
+		method, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+
+	return methods, rows.Err()
+}
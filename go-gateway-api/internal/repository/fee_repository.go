@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type FeeRepository struct {
+	db *sql.DB
+}
+
+func NewFeeRepository(db *sql.DB) *FeeRepository {
+	return &FeeRepository{db: db}
+}
+
+const feeRuleColumns = `id, account_id, card_brand, payment_type, min_installments, max_installments, percentage, fixed_amount, created_at`
+
+func scanFeeRule(row rowScanner) (*domain.FeeRule, error) {
+	var rule domain.FeeRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.AccountID,
+		&rule.CardBrand,
+		&rule.PaymentType,
+		&rule.MinInstallments,
+		&rule.MaxInstallments,
+		&rule.Percentage,
+		&rule.FixedAmount,
+		&rule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *FeeRepository) FindRulesByAccountID(accountID string) ([]*domain.FeeRule, error) {
+	rows, err := r.db.Query(`
+		SELECT `+feeRuleColumns+`
+		FROM fee_rules
+		WHERE account_id = $1
+		ORDER BY created_at
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	rules := []*domain.FeeRule{}
+	for rows.Next() {
+		rule, err := scanFeeRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// ReplaceRules apaga as regras atuais e grava as novas na mesma transação, para que uma
+// aprovação concorrente nunca veja a tabela pela metade
+func (r *FeeRepository) ReplaceRules(accountID string, rules []*domain.FeeRule, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM fee_rules WHERE account_id = $1`, accountID); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		_, err := tx.Exec(`
+			INSERT INTO fee_rules (`+feeRuleColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`,
+			rule.ID,
+			rule.AccountID,
+			rule.CardBrand,
+			rule.PaymentType,
+			rule.MinInstallments,
+			rule.MaxInstallments,
+			rule.Percentage,
+			rule.FixedAmount,
+			rule.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindInvoiceFeesByAccountID retorna as taxas cobradas das faturas da conta
+func (r *FeeRepository) FindInvoiceFeesByAccountID(accountID string) ([]*domain.InvoiceFee, error) {
+	rows, err := r.db.Query(`
+		SELECT id, invoice_id, account_id, type, description, percentage, fixed_amount, amount, created_at
+		FROM invoice_fees
+		WHERE account_id = $1
+		ORDER BY created_at
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	fees := []*domain.InvoiceFee{}
+	for rows.Next() {
+		var fee domain.InvoiceFee
+		err := rows.Scan(
+			&fee.ID,
+			&fee.InvoiceID,
+			&fee.AccountID,
+			&fee.Type,
+			&fee.Description,
+			&fee.Percentage,
+			&fee.FixedAmount,
+			&fee.Amount,
+			&fee.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		fees = append(fees, &fee)
+	}
+
+	return fees, rows.Err()
+}
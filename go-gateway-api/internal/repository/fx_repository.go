@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type FXRepository struct {
+	db *sql.DB
+}
+
+func NewFXRepository(db *sql.DB) *FXRepository {
+	return &FXRepository{db: db}
+}
+
+const fxRateOverrideColumns = `base, quote, rate, reason, created_by, expires_at, created_at`
+
+func scanExchangeRateOverride(row rowScanner) (*domain.ExchangeRateOverride, error) {
+	var override domain.ExchangeRateOverride
+	err := row.Scan(
+		&override.Base,
+		&override.Quote,
+		&override.Rate,
+		&override.Reason,
+		&override.CreatedBy,
+		&override.ExpiresAt,
+		&override.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrExchangeRateOverrideNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+func (r *FXRepository) FindRate(base, quote domain.Currency) (*domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+	err := r.db.QueryRow(`
+		SELECT base, quote, rate, source, updated_at
+		FROM fx_rates
+		WHERE base = $1 AND quote = $2
+	`, base, quote).Scan(&rate.Base, &rate.Quote, &rate.Rate, &rate.Source, &rate.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrExchangeRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *FXRepository) FindOverride(base, quote domain.Currency) (*domain.ExchangeRateOverride, error) {
+	return scanExchangeRateOverride(r.db.QueryRow(`
+		SELECT `+fxRateOverrideColumns+`
+		FROM fx_rate_overrides
+		WHERE base = $1 AND quote = $2
+	`, base, quote))
+}
+
+func (r *FXRepository) ListOverrides() ([]*domain.ExchangeRateOverride, error) {
+	rows, err := r.db.Query(`
+		SELECT ` + fxRateOverrideColumns + `
+		FROM fx_rate_overrides
+		ORDER BY base, quote
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []*domain.ExchangeRateOverride{}
+	for rows.Next() {
+		override, err := scanExchangeRateOverride(rows)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+
+	return overrides, rows.Err()
+}
+
+func (r *FXRepository) SaveOverride(override *domain.ExchangeRateOverride, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO fx_rate_overrides (`+fxRateOverrideColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (base, quote) DO UPDATE SET
+				rate = EXCLUDED.rate,
+				reason = EXCLUDED.reason,
+				created_by = EXCLUDED.created_by,
+				expires_at = EXCLUDED.expires_at,
+				created_at = EXCLUDED.created_at
+		`, override.Base, override.Quote, override.Rate, override.Reason, override.CreatedBy, override.ExpiresAt, override.CreatedAt)
+		return err
+	})
+}
+
+func (r *FXRepository) DeleteOverride(base, quote domain.Currency, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM fx_rate_overrides WHERE base = $1 AND quote = $2`, base, quote)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrExchangeRateOverrideNotFound
+		}
+		return nil
+	})
+}
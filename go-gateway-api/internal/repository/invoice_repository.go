@@ -2,8 +2,11 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain/events"
 )
 
 type InvoiceRepository struct {
@@ -14,22 +17,21 @@ func NewInvoiceRepository(db *sql.DB) *InvoiceRepository {
 	return &InvoiceRepository{db: db}
 }
 
-func (r *InvoiceRepository) Save(invoice *domain.Invoice) error {
-	_, err := r.db.Exec(`INSERT INTO invoices (id, account_id, amount, status, description, payment_type, card_last_digits, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`, invoice.ID, invoice.AccountID, invoice.Amount, invoice.Status, invoice.Description, invoice.PaymentType, invoice.CardLastDigits, invoice.CreatedAt, invoice.UpdatedAt)
-	if err != nil {
-		return err
-	}
+// invoiceColumns e scanInvoice mantêm a ordem das colunas igual em todas as consultas
+const invoiceColumns = `id, account_id, amount, status, description, payment_type, card_last_digits, card_brand, installments, interest_mode, interest_rate, interest_amount, total_amount, fee_amount, net_amount, publish_attempts, last_published_at, created_at, updated_at, payer_name, payer_document, payer_document_hash, payer_email, billing_address, currency, settlement_currency, exchange_rate, settlement_amount, subscription_id, decline_reason, breakdown`
 
-	return nil
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-func (r *InvoiceRepository) FindByID(id string) (*domain.Invoice, error) {
+func scanInvoice(row rowScanner) (*domain.Invoice, error) {
 	var invoice domain.Invoice
-	err := r.db.QueryRow(`
-		SELECT id, account_id, amount, status, description, payment_type, card_last_digits, created_at, updated_at 
-		FROM invoices 
-		WHERE id = $1
-	`, id).Scan(
+	var lastPublishedAt sql.NullTime
+	var payerName, payerDocument, payerDocumentHash, payerEmail sql.NullString
+	var billingAddress []byte
+	var subscriptionID sql.NullString
+	var breakdown []byte
+	err := row.Scan(
 		&invoice.ID,
 		&invoice.AccountID,
 		&invoice.Amount,
@@ -37,9 +39,167 @@ func (r *InvoiceRepository) FindByID(id string) (*domain.Invoice, error) {
 		&invoice.Description,
 		&invoice.PaymentType,
 		&invoice.CardLastDigits,
+		&invoice.CardBrand,
+		&invoice.Installments,
+		&invoice.InterestMode,
+		&invoice.InterestRate,
+		&invoice.InterestAmount,
+		&invoice.TotalAmount,
+		&invoice.FeeAmount,
+		&invoice.NetAmount,
+		&invoice.PublishAttempts,
+		&lastPublishedAt,
 		&invoice.CreatedAt,
 		&invoice.UpdatedAt,
+		&payerName,
+		&payerDocument,
+		&payerDocumentHash,
+		&payerEmail,
+		&billingAddress,
+		&invoice.Currency,
+		&invoice.SettlementCurrency,
+		&invoice.ExchangeRate,
+		&invoice.SettlementAmount,
+		&subscriptionID,
+		&invoice.DeclineReason,
+		&breakdown,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.LastPublishedAt = lastPublishedAt.Time
+	invoice.SubscriptionID = subscriptionID.String
+
+	if breakdown != nil {
+		if err := json.Unmarshal(breakdown, &invoice.Breakdown); err != nil {
+			return nil, err
+		}
+	}
+
+	if payerDocument.Valid {
+		invoice.Payer = &domain.Payer{
+			Name:         payerName.String,
+			Document:     payerDocument.String,
+			DocumentHash: payerDocumentHash.String,
+			Email:        payerEmail.String,
+		}
+
+		if billingAddress != nil {
+			if err := json.Unmarshal(billingAddress, &invoice.Payer.BillingAddress); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &invoice, nil
+}
+
+// Save grava a fatura e, quando ela já nasce aprovada, grava as taxas e agenda os recebíveis
+// na mesma transação
+func (r *InvoiceRepository) Save(invoice *domain.Invoice, approval *domain.InvoiceApproval, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertInvoice(tx, invoice); err != nil {
+		return err
+	}
+
+	if err := recordApproval(tx, approval); err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertInvoice grava a fatura; é compartilhado pelos repositórios que criam a fatura junto
+// com os dados da forma de pagamento
+func insertInvoice(db execer, invoice *domain.Invoice) error {
+	var lastPublishedAt sql.NullTime
+	if !invoice.LastPublishedAt.IsZero() {
+		lastPublishedAt = sql.NullTime{Time: invoice.LastPublishedAt, Valid: true}
+	}
+
+	// Faturas sem pagador identificado deixam as colunas do pagador nulas
+	var payerName, payerDocument, payerDocumentHash, payerEmail sql.NullString
+	var billingAddress []byte
+	if payer := invoice.Payer; payer != nil {
+		payerName = sql.NullString{String: payer.Name, Valid: true}
+		payerDocument = sql.NullString{String: payer.Document, Valid: true}
+		payerDocumentHash = sql.NullString{String: payer.DocumentHash, Valid: true}
+		payerEmail = sql.NullString{String: payer.Email, Valid: payer.Email != ""}
+
+		if payer.BillingAddress != nil {
+			var err error
+			if billingAddress, err = json.Marshal(payer.BillingAddress); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Faturas sem itens deixam o detalhamento nulo
+	var breakdown []byte
+	if invoice.Breakdown != nil {
+		var err error
+		if breakdown, err = json.Marshal(invoice.Breakdown); err != nil {
+			return err
+		}
+	}
+
+	// Faturas avulsas deixam a assinatura nula
+	var subscriptionID sql.NullString
+	if invoice.SubscriptionID != "" {
+		subscriptionID = sql.NullString{String: invoice.SubscriptionID, Valid: true}
+	}
+
+	_, err := db.Exec(`INSERT INTO invoices (`+invoiceColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31)`, invoice.ID, invoice.AccountID, invoice.Amount, invoice.Status, invoice.Description, invoice.PaymentType, invoice.CardLastDigits, invoice.CardBrand, invoice.Installments, invoice.InterestMode, invoice.InterestRate, invoice.InterestAmount, invoice.TotalAmount, invoice.FeeAmount, invoice.NetAmount, invoice.PublishAttempts, lastPublishedAt, invoice.CreatedAt, invoice.UpdatedAt, payerName, payerDocument, payerDocumentHash, payerEmail, billingAddress, invoice.Currency, invoice.SettlementCurrency, invoice.ExchangeRate, invoice.SettlementAmount, subscriptionID, invoice.DeclineReason, breakdown)
+	if err != nil {
+		return err
+	}
+
+	return redeemCoupon(db, invoice)
+}
+
+// redeemCoupon conta o resgate do cupom da fatura. A condição do UPDATE repete as regras do
+// cupom, de modo que faturas simultâneas não ultrapassam o limite de resgates.
+func redeemCoupon(db execer, invoice *domain.Invoice) error {
+	if invoice.Breakdown == nil || invoice.Breakdown.Discount == nil || invoice.Breakdown.Discount.CouponID == "" {
+		return nil
+	}
+
+	result, err := db.Exec(`
+		UPDATE coupons SET redemptions = redemptions + 1, updated_at = $2
+		WHERE id = $1 AND active AND (max_redemptions = 0 OR redemptions < max_redemptions) AND (expires_at IS NULL OR expires_at > $2)
+	`, invoice.Breakdown.Discount.CouponID, invoice.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrCouponRedemptionLimitReached
+	}
+
+	return nil
+}
+
+func (r *InvoiceRepository) FindByID(id string) (*domain.Invoice, error) {
+	invoice, err := scanInvoice(r.db.QueryRow(`
+		SELECT `+invoiceColumns+`
+		FROM invoices 
+		WHERE id = $1
+	`, id))
 
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrInvoiceNotFound
@@ -49,15 +209,51 @@ func (r *InvoiceRepository) FindByID(id string) (*domain.Invoice, error) {
 		return nil, err
 	}
 
-	return &invoice, nil
+	return invoice, nil
 }
 
 func (r *InvoiceRepository) FindByAccountID(accountID string) ([]*domain.Invoice, error) {
-	rows, err := r.db.Query(`
-		SELECT id, account_id, amount, status, description, payment_type, card_last_digits, created_at, updated_at 
+	return r.findMany(`
+		SELECT `+invoiceColumns+`
 		FROM invoices 
 		WHERE account_id = $1
 	`, accountID)
+}
+
+// FindByPayerDocumentHash busca as faturas de um pagador, das mais recentes para as mais
+// antigas; accountID vazio busca em todas as contas
+func (r *InvoiceRepository) FindByPayerDocumentHash(hash, accountID string) ([]*domain.Invoice, error) {
+	if accountID == "" {
+		return r.findMany(`
+			SELECT `+invoiceColumns+`
+			FROM invoices
+			WHERE payer_document_hash = $1
+			ORDER BY created_at DESC
+		`, hash)
+	}
+
+	return r.findMany(`
+		SELECT `+invoiceColumns+`
+		FROM invoices
+		WHERE payer_document_hash = $1 AND account_id = $2
+		ORDER BY created_at DESC
+	`, hash, accountID)
+}
+
+// FindPendingPublishedBefore busca as faturas pendentes cuja última publicação para a
+// análise de fraude aconteceu antes de before, das mais antigas para as mais recentes
+func (r *InvoiceRepository) FindPendingPublishedBefore(before time.Time, limit int) ([]*domain.Invoice, error) {
+	return r.findMany(`
+		SELECT `+invoiceColumns+`
+		FROM invoices
+		WHERE status = $1 AND last_published_at < $2
+		ORDER BY last_published_at
+		LIMIT $3
+	`, domain.StatusPending, before, limit)
+}
+
+func (r *InvoiceRepository) findMany(query string, args ...any) ([]*domain.Invoice, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,31 +262,20 @@ func (r *InvoiceRepository) FindByAccountID(accountID string) ([]*domain.Invoice
 
 	invoices := []*domain.Invoice{}
 	for rows.Next() {
-		var invoice domain.Invoice
-		err := rows.Scan(
-			&invoice.ID,
-			&invoice.AccountID,
-			&invoice.Amount,
-			&invoice.Status,
-			&invoice.Description,
-			&invoice.PaymentType,
-			&invoice.CardLastDigits,
-			&invoice.CreatedAt,
-			&invoice.UpdatedAt,
-		)
+		invoice, err := scanInvoice(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		invoices = append(invoices, &invoice)
+		invoices = append(invoices, invoice)
 	}
 
-	return invoices, nil
+	return invoices, rows.Err()
 }
 
 func (r *InvoiceRepository) UpdateStatus(invoice *domain.Invoice) error {
 	rows, err := r.db.Exec(`
-		UPDATE invoices SET status = $1, updated_at = $2 WHERE id = $3`, invoice.Status, invoice.UpdatedAt, invoice.ID,
+		UPDATE invoices SET status = $1, decline_reason = $2, updated_at = $3 WHERE id = $4`, invoice.Status, invoice.DeclineReason, invoice.UpdatedAt, invoice.ID,
 	)
 	if err != nil {
 		return err 
@@ -106,4 +291,169 @@ func (r *InvoiceRepository) UpdateStatus(invoice *domain.Invoice) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ApplyTransactionResult aplica o resultado da análise de fraude de forma idempotente:
+// o evento é registrado em processed_events, o status só muda se a fatura ainda estiver
+// pendente e as taxas e recebíveis da aprovação são gravados na mesma transação
+func (r *InvoiceRepository) ApplyTransactionResult(eventID string, invoice *domain.Invoice, approval *domain.InvoiceApproval, review *domain.ReviewActionEntry, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // Garante que a transação será revertida em caso de erro
+
+	result, err := tx.Exec(`
+		INSERT INTO processed_events (event_id, event_type, processed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id) DO NOTHING
+	`, eventID, events.TransactionResultEventType, invoice.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrEventAlreadyProcessed
+	}
+
+	result, err = tx.Exec(`
+		UPDATE invoices SET status = $1, exchange_rate = $2, settlement_amount = $3, fee_amount = $4, net_amount = $5, decline_reason = $6, updated_at = $7
+		WHERE id = $8 AND status = $9
+	`, invoice.Status, invoice.ExchangeRate, invoice.SettlementAmount, invoice.FeeAmount, invoice.NetAmount, invoice.DeclineReason, invoice.UpdatedAt, invoice.ID, domain.StatusPending)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// Outra entrega já decidiu a fatura
+	if rowsAffected == 0 {
+		return domain.ErrEventAlreadyProcessed
+	}
+
+	if err := recordApproval(tx, approval); err != nil {
+		return err
+	}
+
+	// A decisão manual só vale junto com o registro da ação do revisor
+	if review != nil {
+		if err := insertReviewAction(tx, review); err != nil {
+			return err
+		}
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// recordApproval grava as linhas de taxa, credita a receita na conta da plataforma e agenda
+// os recebíveis do valor líquido; approval é nil quando a fatura não foi aprovada
+func recordApproval(db execer, approval *domain.InvoiceApproval) error {
+	if approval == nil {
+		return nil
+	}
+
+	var platformAccountID sql.NullString
+	if approval.PlatformAccountID != "" {
+		platformAccountID = sql.NullString{String: approval.PlatformAccountID, Valid: true}
+	}
+
+	var feeTotal float64
+	for _, fee := range approval.Fees {
+		_, err := db.Exec(`
+			INSERT INTO invoice_fees (id, invoice_id, account_id, platform_account_id, type, description, percentage, fixed_amount, amount, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, fee.ID, fee.InvoiceID, fee.AccountID, platformAccountID, fee.Type, fee.Description, fee.Percentage, fee.FixedAmount, fee.Amount, fee.CreatedAt)
+		if err != nil {
+			return err
+		}
+		feeTotal += fee.Amount
+	}
+
+	if platformAccountID.Valid && feeTotal > 0 {
+		result, err := db.Exec(`
+			UPDATE accounts SET balance = balance + $1, updated_at = $2 WHERE id = $3
+		`, feeTotal, time.Now(), approval.PlatformAccountID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return domain.ErrAccountNotFound
+		}
+	}
+
+	return scheduleReceivables(db, approval.Receivables)
+}
+
+// MarkPublished registra uma nova publicação da transação pendente
+func (r *InvoiceRepository) MarkPublished(invoice *domain.Invoice) error {
+	return updatePending(r.db, `
+		UPDATE invoices SET publish_attempts = $1, last_published_at = $2, updated_at = $3
+		WHERE id = $4 AND status = $5
+	`, invoice.PublishAttempts, invoice.LastPublishedAt, invoice.UpdatedAt, invoice.ID, domain.StatusPending)
+}
+
+// Expire só altera a fatura se ela ainda estiver pendente, evitando sobrescrever um
+// resultado da análise de fraude que chegou ao mesmo tempo. O evento invoice.expired fica
+// marcado como pendente na mesma transação e é publicado depois pelo sweeper.
+func (r *InvoiceRepository) Expire(invoice *domain.Invoice, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		return updatePending(tx, `
+			UPDATE invoices SET status = $1, decline_reason = $2, updated_at = $3, expired_event_pending = TRUE
+			WHERE id = $4 AND status = $5
+		`, invoice.Status, invoice.DeclineReason, invoice.UpdatedAt, invoice.ID, domain.StatusPending)
+	})
+}
+
+// FindExpiredEventPending busca as faturas expiradas cujo evento invoice.expired ainda não
+// foi publicado, das mais antigas para as mais recentes
+func (r *InvoiceRepository) FindExpiredEventPending(limit int) ([]*domain.Invoice, error) {
+	return r.findMany(`
+		SELECT `+invoiceColumns+`
+		FROM invoices
+		WHERE expired_event_pending
+		ORDER BY updated_at
+		LIMIT $1
+	`, limit)
+}
+
+func (r *InvoiceRepository) MarkExpiredEventPublished(invoice *domain.Invoice) error {
+	_, err := r.db.Exec(`UPDATE invoices SET expired_event_pending = FALSE WHERE id = $1`, invoice.ID)
+	return err
+}
+
+// updatePending retorna ErrInvalidStatus quando a fatura já não está mais pendente
+func updatePending(db execer, query string, args ...any) error {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidStatus
+	}
+
+	return nil
+}
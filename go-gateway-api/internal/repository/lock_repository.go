@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+)
+
+// LockRepository implementa domain.Locker com advisory locks do Postgres
+type LockRepository struct {
+	db *sql.DB
+}
+
+func NewLockRepository(db *sql.DB) *LockRepository {
+	return &LockRepository{db: db}
+}
+
+// TryLock usa um lock de sessão, por isso mantém uma conexão dedicada até o release
+func (r *LockRepository) TryLock(ctx context.Context, name string) (func() error, bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := lockKey(name)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() error {
+		defer conn.Close()
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key)
+		return err
+	}
+
+	return release, true, nil
+}
+
+// lockKey converte o nome do lock na chave bigint esperada pelo Postgres
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
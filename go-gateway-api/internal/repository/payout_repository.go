@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type PayoutRepository struct {
+	db *sql.DB
+}
+
+func NewPayoutRepository(db *sql.DB) *PayoutRepository {
+	return &PayoutRepository{db: db}
+}
+
+const payoutDestinationColumns = `id, account_id, type, bank_code, branch, account_number, account_type, holder_name, holder_document, pix_key_type, pix_key, created_at`
+
+const payoutColumns = `id, account_id, destination_id, amount, status, provider_reference, failure_reason, created_at, updated_at`
+
+func scanPayoutDestination(row rowScanner) (*domain.PayoutDestination, error) {
+	var destination domain.PayoutDestination
+	var bankCode, branch, accountNumber, accountType, holderName, holderDocument sql.NullString
+	var pixKeyType, pixKey sql.NullString
+	err := row.Scan(
+		&destination.ID,
+		&destination.AccountID,
+		&destination.Type,
+		&bankCode,
+		&branch,
+		&accountNumber,
+		&accountType,
+		&holderName,
+		&holderDocument,
+		&pixKeyType,
+		&pixKey,
+		&destination.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	switch destination.Type {
+	case domain.PayoutDestinationBankAccount:
+		destination.BankAccount = &domain.BankAccount{
+			BankCode:       bankCode.String,
+			Branch:         branch.String,
+			Number:         accountNumber.String,
+			Type:           domain.BankAccountType(accountType.String),
+			HolderName:     holderName.String,
+			HolderDocument: holderDocument.String,
+		}
+	case domain.PayoutDestinationPixKey:
+		destination.PixKey = &domain.PixKey{
+			Type: domain.PixKeyType(pixKeyType.String),
+			Key:  pixKey.String,
+		}
+	}
+
+	return &destination, nil
+}
+
+func scanPayout(row rowScanner) (*domain.Payout, error) {
+	var payout domain.Payout
+	err := row.Scan(
+		&payout.ID,
+		&payout.AccountID,
+		&payout.DestinationID,
+		&payout.Amount,
+		&payout.Status,
+		&payout.ProviderReference,
+		&payout.FailureReason,
+		&payout.CreatedAt,
+		&payout.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+func (r *PayoutRepository) SaveDestination(destination *domain.PayoutDestination, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		var bankCode, branch, accountNumber, accountType, holderName, holderDocument sql.NullString
+		if bankAccount := destination.BankAccount; bankAccount != nil {
+			bankCode = sql.NullString{String: bankAccount.BankCode, Valid: true}
+			branch = sql.NullString{String: bankAccount.Branch, Valid: true}
+			accountNumber = sql.NullString{String: bankAccount.Number, Valid: true}
+			accountType = sql.NullString{String: string(bankAccount.Type), Valid: true}
+			holderName = sql.NullString{String: bankAccount.HolderName, Valid: true}
+			holderDocument = sql.NullString{String: bankAccount.HolderDocument, Valid: true}
+		}
+
+		var pixKeyType, pixKey sql.NullString
+		if destination.PixKey != nil {
+			pixKeyType = sql.NullString{String: string(destination.PixKey.Type), Valid: true}
+			pixKey = sql.NullString{String: destination.PixKey.Key, Valid: true}
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO payout_destinations (`+payoutDestinationColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`,
+			destination.ID,
+			destination.AccountID,
+			destination.Type,
+			bankCode,
+			branch,
+			accountNumber,
+			accountType,
+			holderName,
+			holderDocument,
+			pixKeyType,
+			pixKey,
+			destination.CreatedAt,
+		)
+		return err
+	})
+}
+
+func (r *PayoutRepository) FindDestinationByID(id string) (*domain.PayoutDestination, error) {
+	destination, err := scanPayoutDestination(r.db.QueryRow(`
+		SELECT `+payoutDestinationColumns+` FROM payout_destinations WHERE id = $1
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPayoutDestinationNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return destination, nil
+}
+
+func (r *PayoutRepository) FindDestinationsByAccountID(accountID string) ([]*domain.PayoutDestination, error) {
+	rows, err := r.db.Query(`
+		SELECT `+payoutDestinationColumns+`
+		FROM payout_destinations
+		WHERE account_id = $1
+		ORDER BY created_at
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	destinations := []*domain.PayoutDestination{}
+	for rows.Next() {
+		destination, err := scanPayoutDestination(rows)
+		if err != nil {
+			return nil, err
+		}
+		destinations = append(destinations, destination)
+	}
+
+	return destinations, rows.Err()
+}
+
+func (r *PayoutRepository) Create(payout *domain.Payout, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// O débito condicional evita que saques simultâneos deixem o saldo negativo
+	result, err := tx.Exec(`
+		UPDATE accounts SET balance = balance - $1, updated_at = $2
+		WHERE id = $3 AND balance >= $1
+	`, payout.Amount, payout.CreatedAt, payout.AccountID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInsufficientBalance
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO payouts (`+payoutColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		payout.ID,
+		payout.AccountID,
+		payout.DestinationID,
+		payout.Amount,
+		payout.Status,
+		payout.ProviderReference,
+		payout.FailureReason,
+		payout.CreatedAt,
+		payout.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PayoutRepository) FindByID(id string) (*domain.Payout, error) {
+	payout, err := scanPayout(r.db.QueryRow(`SELECT `+payoutColumns+` FROM payouts WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPayoutNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return payout, nil
+}
+
+func (r *PayoutRepository) FindByAccountID(accountID string) ([]*domain.Payout, error) {
+	return r.findMany(`
+		SELECT `+payoutColumns+`
+		FROM payouts
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+}
+
+// FindByStatus lista os saques mais antigos primeiro, na ordem em que devem ser processados
+func (r *PayoutRepository) FindByStatus(status domain.PayoutStatus, limit int) ([]*domain.Payout, error) {
+	return r.findMany(`
+		SELECT `+payoutColumns+`
+		FROM payouts
+		WHERE status = $1
+		ORDER BY updated_at
+		LIMIT $2
+	`, status, limit)
+}
+
+func (r *PayoutRepository) UpdateStatus(payout *domain.Payout, from domain.PayoutStatus, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		return updatePayoutStatus(tx, payout, from)
+	})
+}
+
+func (r *PayoutRepository) Fail(payout *domain.Payout, from domain.PayoutStatus, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updatePayoutStatus(tx, payout, from); err != nil {
+		return err
+	}
+
+	// O valor reservado na criação do saque volta para o saldo
+	_, err = tx.Exec(`
+		UPDATE accounts SET balance = balance + $1, updated_at = $2 WHERE id = $3
+	`, payout.Amount, payout.UpdatedAt, payout.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PayoutRepository) findMany(query string, args ...any) ([]*domain.Payout, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	payouts := []*domain.Payout{}
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+
+	return payouts, rows.Err()
+}
+
+// updatePayoutStatus só altera o saque se ele ainda estiver no status esperado
+func updatePayoutStatus(db execer, payout *domain.Payout, from domain.PayoutStatus) error {
+	result, err := db.Exec(`
+		UPDATE payouts
+		SET status = $1, provider_reference = $2, failure_reason = $3, updated_at = $4
+		WHERE id = $5 AND status = $6
+	`, payout.Status, payout.ProviderReference, payout.FailureReason, payout.UpdatedAt, payout.ID, from)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidPayoutStatus
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type PixRepository struct {
+	db *sql.DB
+}
+
+func NewPixRepository(db *sql.DB) *PixRepository {
+	return &PixRepository{db: db}
+}
+
+const pixChargeColumns = `id, invoice_id, account_id, txid, type, payload, location, amount, status, expires_at, end_to_end_id, paid_at, created_at, updated_at`
+
+func scanPixCharge(row rowScanner) (*domain.PixCharge, error) {
+	var charge domain.PixCharge
+	var endToEndID sql.NullString
+	var paidAt sql.NullTime
+	err := row.Scan(
+		&charge.ID,
+		&charge.InvoiceID,
+		&charge.AccountID,
+		&charge.TxID,
+		&charge.Type,
+		&charge.Payload,
+		&charge.Location,
+		&charge.Amount,
+		&charge.Status,
+		&charge.ExpiresAt,
+		&endToEndID,
+		&paidAt,
+		&charge.CreatedAt,
+		&charge.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPixChargeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	charge.EndToEndID = endToEndID.String
+	charge.PaidAt = paidAt.Time
+	return &charge, nil
+}
+
+// Create grava a fatura e a cobrança Pix na mesma transação
+func (r *PixRepository) Create(invoice *domain.Invoice, charge *domain.PixCharge, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertInvoice(tx, invoice); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO pix_charges (`+pixChargeColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`,
+		charge.ID,
+		charge.InvoiceID,
+		charge.AccountID,
+		charge.TxID,
+		charge.Type,
+		charge.Payload,
+		charge.Location,
+		charge.Amount,
+		charge.Status,
+		charge.ExpiresAt,
+		nil,
+		nil,
+		charge.CreatedAt,
+		charge.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PixRepository) FindByInvoiceID(invoiceID string) (*domain.PixCharge, error) {
+	return scanPixCharge(r.db.QueryRow(`
+		SELECT `+pixChargeColumns+`
+		FROM pix_charges
+		WHERE invoice_id = $1
+	`, invoiceID))
+}
+
+func (r *PixRepository) FindByTxID(txID string) (*domain.PixCharge, error) {
+	return scanPixCharge(r.db.QueryRow(`
+		SELECT `+pixChargeColumns+`
+		FROM pix_charges
+		WHERE txid = $1
+	`, txID))
+}
+
+// ConfirmPayment marca a cobrança como paga e aprova a fatura com as taxas e os recebíveis
+// na mesma transação. Retorna ErrPixChargeAlreadyPaid se outro callback ou a expiração
+// alterou a cobrança nesse meio tempo.
+func (r *PixRepository) ConfirmPayment(charge *domain.PixCharge, invoice *domain.Invoice, approval *domain.InvoiceApproval, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE pix_charges SET status = $1, end_to_end_id = $2, paid_at = $3, updated_at = $4
+		WHERE id = $5 AND status = $6
+	`, charge.Status, charge.EndToEndID, charge.PaidAt, charge.UpdatedAt, charge.ID, domain.PixChargeStatusActive)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrPixChargeAlreadyPaid
+	}
+
+	result, err = tx.Exec(`
+		UPDATE invoices SET status = $1, exchange_rate = $2, settlement_amount = $3, fee_amount = $4, net_amount = $5, updated_at = $6
+		WHERE id = $7 AND status = $8
+	`, invoice.Status, invoice.ExchangeRate, invoice.SettlementAmount, invoice.FeeAmount, invoice.NetAmount, invoice.UpdatedAt, invoice.ID, domain.StatusAwaitingPayment)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidStatus
+	}
+
+	if err := recordApproval(tx, approval); err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PixRepository) FindByAccountID(accountID string) ([]*domain.PixCharge, error) {
+	rows, err := r.db.Query(`
+		SELECT `+pixChargeColumns+`
+		FROM pix_charges
+		WHERE account_id = $1
+		ORDER BY created_at
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	charges := []*domain.PixCharge{}
+	for rows.Next() {
+		charge, err := scanPixCharge(rows)
+		if err != nil {
+			return nil, err
+		}
+		charges = append(charges, charge)
+	}
+
+	return charges, rows.Err()
+}
+
+// FindExpired retorna as cobranças ativas com prazo vencido
+func (r *PixRepository) FindExpired(now time.Time, limit int) ([]*domain.PixCharge, error) {
+	rows, err := r.db.Query(`
+		SELECT `+pixChargeColumns+`
+		FROM pix_charges
+		WHERE status = $1 AND expires_at < $2
+		ORDER BY expires_at
+		LIMIT $3
+	`, domain.PixChargeStatusActive, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	charges := []*domain.PixCharge{}
+	for rows.Next() {
+		charge, err := scanPixCharge(rows)
+		if err != nil {
+			return nil, err
+		}
+		charges = append(charges, charge)
+	}
+
+	return charges, rows.Err()
+}
+
+// Expire encerra a cobrança e a fatura; retorna ErrInvalidStatus se o pagamento foi
+// confirmado nesse meio tempo
+func (r *PixRepository) Expire(charge *domain.PixCharge, invoice *domain.Invoice, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE pix_charges SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`, charge.Status, charge.UpdatedAt, charge.ID, domain.PixChargeStatusActive)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidStatus
+	}
+
+	_, err = tx.Exec(`
+		UPDATE invoices SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`, invoice.Status, invoice.UpdatedAt, invoice.ID, domain.StatusAwaitingPayment)
+	if err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
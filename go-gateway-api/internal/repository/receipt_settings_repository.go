@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type ReceiptSettingsRepository struct {
+	db *sql.DB
+}
+
+func NewReceiptSettingsRepository(db *sql.DB) *ReceiptSettingsRepository {
+	return &ReceiptSettingsRepository{db: db}
+}
+
+func (r *ReceiptSettingsRepository) FindByAccountID(accountID string) (*domain.ReceiptSettings, error) {
+	var settings domain.ReceiptSettings
+	err := r.db.QueryRow(`
+		SELECT account_id, logo, logo_content_type, primary_color, secondary_color, footer, updated_at
+		FROM receipt_settings
+		WHERE account_id = $1
+	`, accountID).Scan(
+		&settings.AccountID,
+		&settings.Logo,
+		&settings.LogoContentType,
+		&settings.PrimaryColor,
+		&settings.SecondaryColor,
+		&settings.Footer,
+		&settings.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrReceiptSettingsNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *ReceiptSettingsRepository) Save(settings *domain.ReceiptSettings, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		// Contas sem logo gravam a coluna nula
+		var logo []byte
+		if len(settings.Logo) > 0 {
+			logo = settings.Logo
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO receipt_settings (account_id, logo, logo_content_type, primary_color, secondary_color, footer, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (account_id) DO UPDATE SET
+				logo = EXCLUDED.logo,
+				logo_content_type = EXCLUDED.logo_content_type,
+				primary_color = EXCLUDED.primary_color,
+				secondary_color = EXCLUDED.secondary_color,
+				footer = EXCLUDED.footer,
+				updated_at = EXCLUDED.updated_at
+		`, settings.AccountID, logo, settings.LogoContentType, settings.PrimaryColor, settings.SecondaryColor, settings.Footer, settings.UpdatedAt)
+		return err
+	})
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type ReceivableRepository struct {
+	db *sql.DB
+}
+
+func NewReceivableRepository(db *sql.DB) *ReceivableRepository {
+	return &ReceivableRepository{db: db}
+}
+
+const receivableColumns = `id, account_id, invoice_id, installment_number, amount, due_date, status, settled_at, anticipation_id, created_at`
+
+func scanReceivable(row rowScanner) (*domain.Receivable, error) {
+	var receivable domain.Receivable
+	var settledAt sql.NullTime
+	var anticipationID sql.NullString
+	err := row.Scan(
+		&receivable.ID,
+		&receivable.AccountID,
+		&receivable.InvoiceID,
+		&receivable.InstallmentNumber,
+		&receivable.Amount,
+		&receivable.DueDate,
+		&receivable.Status,
+		&settledAt,
+		&anticipationID,
+		&receivable.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	receivable.SettledAt = settledAt.Time
+	receivable.AnticipationID = anticipationID.String
+	return &receivable, nil
+}
+
+func (r *ReceivableRepository) FindByInvoiceID(invoiceID string) ([]*domain.Receivable, error) {
+	rows, err := r.db.Query(`
+		SELECT `+receivableColumns+`
+		FROM receivables
+		WHERE invoice_id = $1
+		ORDER BY installment_number
+	`, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	receivables := []*domain.Receivable{}
+	for rows.Next() {
+		receivable, err := scanReceivable(rows)
+		if err != nil {
+			return nil, err
+		}
+		receivables = append(receivables, receivable)
+	}
+
+	return receivables, rows.Err()
+}
+
+func (r *ReceivableRepository) FindByAccountID(accountID string) ([]*domain.Receivable, error) {
+	rows, err := r.db.Query(`
+		SELECT `+receivableColumns+`
+		FROM receivables
+		WHERE account_id = $1
+		ORDER BY invoice_id, installment_number
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	receivables := []*domain.Receivable{}
+	for rows.Next() {
+		receivable, err := scanReceivable(rows)
+		if err != nil {
+			return nil, err
+		}
+		receivables = append(receivables, receivable)
+	}
+
+	return receivables, rows.Err()
+}
+
+func (r *ReceivableRepository) FindUpcomingByAccountID(accountID string) ([]*domain.UpcomingSettlement, error) {
+	rows, err := r.db.Query(`
+		SELECT due_date, SUM(amount), COUNT(*)
+		FROM receivables
+		WHERE account_id = $1 AND status = $2
+		GROUP BY due_date
+		ORDER BY due_date
+	`, accountID, domain.ReceivableStatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	settlements := []*domain.UpcomingSettlement{}
+	for rows.Next() {
+		var settlement domain.UpcomingSettlement
+		if err := rows.Scan(&settlement.Date, &settlement.Amount, &settlement.Receivables); err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, &settlement)
+	}
+
+	return settlements, rows.Err()
+}
+
+func (r *ReceivableRepository) SettleDue(date time.Time, limit int) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	settledAt := time.Now()
+
+	// SKIP LOCKED evita esperar por recebíveis travados por outra transação
+	rows, err := tx.Query(`
+		UPDATE receivables SET status = $1, settled_at = $2
+		WHERE id IN (
+			SELECT id FROM receivables
+			WHERE status = $3 AND due_date <= $4
+			ORDER BY due_date
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING account_id, amount
+	`, domain.ReceivableStatusSettled, settledAt, domain.ReceivableStatusScheduled, date, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	totals := make(map[string]float64)
+	settled := 0
+	for rows.Next() {
+		var accountID string
+		var amount float64
+		if err := rows.Scan(&accountID, &amount); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		totals[accountID] += amount
+		settled++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for accountID, total := range totals {
+		_, err := tx.Exec(`
+			UPDATE accounts
+			SET pending_balance = pending_balance - $1, balance = balance + $1, updated_at = $2
+			WHERE id = $3
+		`, total, settledAt, accountID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return settled, nil
+}
+
+// scheduleReceivables grava os recebíveis de uma aprovação e credita o saldo pendente da
+// conta; deve rodar na mesma transação que aprova a fatura
+func scheduleReceivables(db execer, receivables []*domain.Receivable) error {
+	if len(receivables) == 0 {
+		return nil
+	}
+
+	for _, receivable := range receivables {
+		_, err := db.Exec(`
+			INSERT INTO receivables (`+receivableColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`,
+			receivable.ID,
+			receivable.AccountID,
+			receivable.InvoiceID,
+			receivable.InstallmentNumber,
+			receivable.Amount,
+			receivable.DueDate,
+			receivable.Status,
+			nil,
+			nil,
+			receivable.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	first := receivables[0]
+	result, err := db.Exec(`
+		UPDATE accounts SET pending_balance = pending_balance + $1, updated_at = $2
+		WHERE id = $3
+	`, domain.SumReceivables(receivables), first.CreatedAt, first.AccountID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAccountNotFound
+	}
+
+	return nil
+}
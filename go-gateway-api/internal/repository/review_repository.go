@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type ReviewRepository struct {
+	db *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// FindPendingQueue lista as faturas pendentes (mais antigas primeiro) com o histórico da
+// conta e o claim vigente de cada uma
+func (r *ReviewRepository) FindPendingQueue(limit int) ([]*domain.ReviewQueueItem, error) {
+	rows, err := r.db.Query(`
+		SELECT i.id, i.account_id, i.amount, i.currency, i.settlement_currency, i.status, i.description, i.payment_type, i.card_last_digits,
+			i.publish_attempts, i.last_published_at, i.created_at, i.updated_at,
+			a.name, a.email, a.balance, a.created_at,
+			COUNT(h.id) FILTER (WHERE h.status = $2),
+			COALESCE(SUM(h.settlement_amount) FILTER (WHERE h.status = $2), 0),
+			COUNT(h.id) FILTER (WHERE h.status = $3),
+			COUNT(h.id) FILTER (WHERE h.status = $1),
+			c.reviewer, c.claimed_at, c.expires_at
+		FROM invoices i
+		JOIN accounts a ON a.id = i.account_id
+		LEFT JOIN invoices h ON h.account_id = i.account_id AND h.id <> i.id
+		LEFT JOIN invoice_review_claims c ON c.invoice_id = i.id AND c.expires_at > NOW()
+		WHERE i.status = $1
+		GROUP BY i.id, a.id, c.invoice_id
+		ORDER BY i.created_at
+		LIMIT $4
+	`, domain.StatusPending, domain.StatusApproved, domain.StatusRejected, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	items := []*domain.ReviewQueueItem{}
+	for rows.Next() {
+		var item domain.ReviewQueueItem
+		var invoice domain.Invoice
+		var lastPublishedAt sql.NullTime
+		var reviewer sql.NullString
+		var claimedAt, expiresAt sql.NullTime
+		err := rows.Scan(
+			&invoice.ID,
+			&invoice.AccountID,
+			&invoice.Amount,
+			&invoice.Currency,
+			&invoice.SettlementCurrency,
+			&invoice.Status,
+			&invoice.Description,
+			&invoice.PaymentType,
+			&invoice.CardLastDigits,
+			&invoice.PublishAttempts,
+			&lastPublishedAt,
+			&invoice.CreatedAt,
+			&invoice.UpdatedAt,
+			&item.AccountName,
+			&item.AccountEmail,
+			&item.AccountBalance,
+			&item.AccountCreatedAt,
+			&item.ApprovedCount,
+			&item.ApprovedTotal,
+			&item.RejectedCount,
+			&item.PendingCount,
+			&reviewer,
+			&claimedAt,
+			&expiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		invoice.LastPublishedAt = lastPublishedAt.Time
+		item.Invoice = &invoice
+
+		if reviewer.Valid {
+			item.Claim = &domain.ReviewClaim{
+				InvoiceID: invoice.ID,
+				Reviewer:  reviewer.String,
+				ClaimedAt: claimedAt.Time,
+				ExpiresAt: expiresAt.Time,
+			}
+		}
+
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+// FindClaim retorna ErrReviewClaimRequired quando a fatura não possui claim
+func (r *ReviewRepository) FindClaim(invoiceID string) (*domain.ReviewClaim, error) {
+	var claim domain.ReviewClaim
+	err := r.db.QueryRow(`
+		SELECT invoice_id, reviewer, claimed_at, expires_at
+		FROM invoice_review_claims
+		WHERE invoice_id = $1
+	`, invoiceID).Scan(
+		&claim.InvoiceID,
+		&claim.Reviewer,
+		&claim.ClaimedAt,
+		&claim.ExpiresAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrReviewClaimRequired
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &claim, nil
+}
+
+func (r *ReviewRepository) Claim(claim *domain.ReviewClaim, entry *domain.ReviewActionEntry, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Só sobrescreve o claim se ele já expirou ou pertence ao mesmo analista
+	result, err := tx.Exec(`
+		INSERT INTO invoice_review_claims (invoice_id, reviewer, claimed_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (invoice_id) DO UPDATE
+		SET reviewer = EXCLUDED.reviewer, claimed_at = EXCLUDED.claimed_at, expires_at = EXCLUDED.expires_at
+		WHERE invoice_review_claims.reviewer = EXCLUDED.reviewer
+			OR invoice_review_claims.expires_at <= EXCLUDED.claimed_at
+	`, claim.InvoiceID, claim.Reviewer, claim.ClaimedAt, claim.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvoiceAlreadyClaimed
+	}
+
+	if err := insertReviewAction(tx, entry); err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ReviewRepository) Release(invoiceID string, entry *domain.ReviewActionEntry, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		DELETE FROM invoice_review_claims WHERE invoice_id = $1 AND reviewer = $2
+	`, invoiceID, entry.Reviewer)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrReviewClaimRequired
+	}
+
+	if err := insertReviewAction(tx, entry); err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ReviewRepository) FindActionsByInvoiceID(invoiceID string) ([]*domain.ReviewActionEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, invoice_id, reviewer, action, reason, created_at
+		FROM invoice_review_actions
+		WHERE invoice_id = $1
+		ORDER BY created_at
+	`, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	entries := []*domain.ReviewActionEntry{}
+	for rows.Next() {
+		var entry domain.ReviewActionEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.InvoiceID,
+			&entry.Reviewer,
+			&entry.Action,
+			&entry.Reason,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// execer é satisfeito tanto por *sql.DB quanto por *sql.Tx
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func insertReviewAction(db execer, entry *domain.ReviewActionEntry) error {
+	_, err := db.Exec(`
+		INSERT INTO invoice_review_actions (id, invoice_id, reviewer, action, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, entry.ID, entry.InvoiceID, entry.Reviewer, entry.Action, entry.Reason, entry.CreatedAt)
+	return err
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+type SubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepository(db *sql.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+const planColumns = `id, account_id, name, amount, currency, interval, interval_count, trial_days, created_at`
+
+func scanPlan(row rowScanner) (*domain.Plan, error) {
+	var plan domain.Plan
+	err := row.Scan(
+		&plan.ID,
+		&plan.AccountID,
+		&plan.Name,
+		&plan.Amount,
+		&plan.Currency,
+		&plan.Interval,
+		&plan.IntervalCount,
+		&plan.TrialDays,
+		&plan.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPlanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *SubscriptionRepository) SavePlan(plan *domain.Plan, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO plans (`+planColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, plan.ID, plan.AccountID, plan.Name, plan.Amount, plan.Currency, plan.Interval, plan.IntervalCount, plan.TrialDays, plan.CreatedAt)
+		return err
+	})
+}
+
+func (r *SubscriptionRepository) FindPlanByID(id string) (*domain.Plan, error) {
+	return scanPlan(r.db.QueryRow(`
+		SELECT `+planColumns+`
+		FROM plans
+		WHERE id = $1
+	`, id))
+}
+
+func (r *SubscriptionRepository) FindPlansByAccountID(accountID string) ([]*domain.Plan, error) {
+	rows, err := r.db.Query(`
+		SELECT `+planColumns+`
+		FROM plans
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := []*domain.Plan{}
+	for rows.Next() {
+		plan, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+
+	return plans, rows.Err()
+}
+
+const subscriptionColumns = `id, account_id, customer_id, plan_id, payment_method_id, status, current_period_start, current_period_end, billing_cycle_anchor, trial_end, cancel_at_period_end, canceled_at, proration_amount, latest_invoice_id, charge_pending, failed_attempts, next_retry_at, version, created_at, updated_at`
+
+func scanSubscription(row rowScanner) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	var trialEnd, canceledAt, nextRetryAt sql.NullTime
+	var latestInvoiceID sql.NullString
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.AccountID,
+		&subscription.CustomerID,
+		&subscription.PlanID,
+		&subscription.PaymentMethodID,
+		&subscription.Status,
+		&subscription.CurrentPeriodStart,
+		&subscription.CurrentPeriodEnd,
+		&subscription.BillingCycleAnchor,
+		&trialEnd,
+		&subscription.CancelAtPeriodEnd,
+		&canceledAt,
+		&subscription.ProrationAmount,
+		&latestInvoiceID,
+		&subscription.ChargePending,
+		&subscription.FailedAttempts,
+		&nextRetryAt,
+		&subscription.Version,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.TrialEnd = trialEnd.Time
+	subscription.CanceledAt = canceledAt.Time
+	subscription.NextRetryAt = nextRetryAt.Time
+	subscription.LatestInvoiceID = latestInvoiceID.String
+	return &subscription, nil
+}
+
+func (r *SubscriptionRepository) Create(subscription *domain.Subscription, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		subscription.Version = 1
+
+		_, err := tx.Exec(`
+			INSERT INTO subscriptions (`+subscriptionColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		`,
+			subscription.ID,
+			subscription.AccountID,
+			subscription.CustomerID,
+			subscription.PlanID,
+			subscription.PaymentMethodID,
+			subscription.Status,
+			subscription.CurrentPeriodStart,
+			subscription.CurrentPeriodEnd,
+			subscription.BillingCycleAnchor,
+			nullTime(subscription.TrialEnd),
+			subscription.CancelAtPeriodEnd,
+			nullTime(subscription.CanceledAt),
+			subscription.ProrationAmount,
+			nullString(subscription.LatestInvoiceID),
+			subscription.ChargePending,
+			subscription.FailedAttempts,
+			nullTime(subscription.NextRetryAt),
+			subscription.Version,
+			subscription.CreatedAt,
+			subscription.UpdatedAt,
+		)
+		return err
+	})
+}
+
+func (r *SubscriptionRepository) FindByID(id string) (*domain.Subscription, error) {
+	return scanSubscription(r.db.QueryRow(`
+		SELECT `+subscriptionColumns+`
+		FROM subscriptions
+		WHERE id = $1
+	`, id))
+}
+
+func (r *SubscriptionRepository) FindByAccountID(accountID string) ([]*domain.Subscription, error) {
+	return r.findMany(`
+		SELECT `+subscriptionColumns+`
+		FROM subscriptions
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+	`, accountID)
+}
+
+// FindDue lista as cobranças vencidas e as novas tentativas das cobranças em atraso, das mais
+// antigas para as mais recentes. As assinaturas de contas suspensas ou encerradas ficam de
+// fora até a conta ser reativada.
+func (r *SubscriptionRepository) FindDue(now time.Time, limit int) ([]*domain.Subscription, error) {
+	return r.findMany(`
+		SELECT `+subscriptionColumns+`
+		FROM subscriptions s
+		WHERE NOT charge_pending
+			AND (
+				(status IN ($1, $2) AND current_period_end <= $3)
+				OR (status = $4 AND next_retry_at <= $3)
+			)
+			AND EXISTS (
+				SELECT 1 FROM accounts a WHERE a.id = s.account_id AND a.status = $5
+			)
+		ORDER BY COALESCE(next_retry_at, current_period_end)
+		LIMIT $6
+	`, domain.SubscriptionStatusTrialing, domain.SubscriptionStatusActive, now, domain.SubscriptionStatusPastDue, domain.AccountStatusActive, limit)
+}
+
+// FindDecidedCharges lista as assinaturas cuja fatura de cobrança já saiu da análise de fraude
+func (r *SubscriptionRepository) FindDecidedCharges(limit int) ([]*domain.Subscription, error) {
+	return r.findMany(`
+		SELECT `+subscriptionColumns+`
+		FROM subscriptions s
+		WHERE charge_pending AND EXISTS (
+			SELECT 1 FROM invoices i WHERE i.id = s.latest_invoice_id AND i.status <> $1
+		)
+		ORDER BY updated_at
+		LIMIT $2
+	`, domain.StatusPending, limit)
+}
+
+func (r *SubscriptionRepository) findMany(query string, args ...any) ([]*domain.Subscription, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []*domain.Subscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *SubscriptionRepository) Update(subscription *domain.Subscription, audit ...*domain.AuditEvent) error {
+	return inTx(r.db, audit, func(tx *sql.Tx) error {
+		return updateSubscription(tx, subscription)
+	})
+}
+
+// SaveCharge grava a fatura antes da assinatura, que passa a referenciá-la como a última
+// cobrança
+func (r *SubscriptionRepository) SaveCharge(subscription *domain.Subscription, invoice *domain.Invoice, approval *domain.InvoiceApproval, audit ...*domain.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertInvoice(tx, invoice); err != nil {
+		return err
+	}
+
+	if err := recordApproval(tx, approval); err != nil {
+		return err
+	}
+
+	if err := updateSubscription(tx, subscription); err != nil {
+		return err
+	}
+
+	if err := appendAuditEvents(tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateSubscription grava a assinatura se a versão ainda for a lida, evitando que a cobrança
+// do agendador e uma alteração do lojista sobrescrevam uma à outra
+func updateSubscription(db execer, subscription *domain.Subscription) error {
+	result, err := db.Exec(`
+		UPDATE subscriptions SET
+			plan_id = $1,
+			payment_method_id = $2,
+			status = $3,
+			current_period_start = $4,
+			current_period_end = $5,
+			cancel_at_period_end = $6,
+			canceled_at = $7,
+			proration_amount = $8,
+			latest_invoice_id = $9,
+			charge_pending = $10,
+			failed_attempts = $11,
+			next_retry_at = $12,
+			version = version + 1,
+			updated_at = $13
+		WHERE id = $14 AND version = $15
+	`,
+		subscription.PlanID,
+		subscription.PaymentMethodID,
+		subscription.Status,
+		subscription.CurrentPeriodStart,
+		subscription.CurrentPeriodEnd,
+		subscription.CancelAtPeriodEnd,
+		nullTime(subscription.CanceledAt),
+		subscription.ProrationAmount,
+		nullString(subscription.LatestInvoiceID),
+		subscription.ChargePending,
+		subscription.FailedAttempts,
+		nullTime(subscription.NextRetryAt),
+		subscription.UpdatedAt,
+		subscription.ID,
+		subscription.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrSubscriptionConflict
+	}
+
+	subscription.Version++
+	return nil
+}
+
+func nullTime(value time.Time) sql.NullTime {
+	return sql.NullTime{Time: value, Valid: !value.IsZero()}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
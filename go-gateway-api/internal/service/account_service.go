@@ -1,46 +1,47 @@
 package service
 
 import (
+	"log/slog"
+
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
 )
 
 type AccountService struct {
-	repository domain.AccountRepository
+	repository   domain.AccountRepository
+	auditService *AuditService
 }
 
-func NewAccountService(repository domain.AccountRepository) *AccountService {
-	return &AccountService{repository: repository}
+func NewAccountService(repository domain.AccountRepository, auditService *AuditService) *AccountService {
+	return &AccountService{repository: repository, auditService: auditService}
 }
 
-func (s *AccountService) CreateAccount(input dto.CreateAccountInput) (*dto.AccountOutput, error) {
-	account := dto.ToAccount(input)
+func (s *AccountService) CreateAccount(input dto.CreateAccountInput, actor domain.Actor) (*dto.AccountOutput, error) {
+	account, err := dto.ToAccount(input)
+	if err != nil {
+		return nil, err
+	}
+
 	existingAccount, err := s.repository.FindByAPIKey(account.APIKey)
 	if err != nil && err != domain.ErrAccountNotFound {
-		return nil, err 
+		return nil, err
 	}
 
 	if existingAccount != nil {
 		return nil, domain.ErrDuplicatedAPIKey // Se já existir uma conta com a mesma API Key, retorna erro
 	}
 
-	err = s.repository.Save(account)
+	created, err := s.auditService.Event(actor, domain.AuditActionAccountCreated, auditEntityAccount, account.ID, nil, accountAuditState(account))
 	if err != nil {
 		return nil, err
 	}
 
-	output := dto.FromAccount(account)
-	return &output, nil // Retorna o DTO da conta criada
-}
-
-func (s *AccountService) UpdateBalance(apiKey string, amount float64) (*dto.AccountOutput, error) {
-	account, err := s.repository.FindByAPIKey(apiKey)
+	keyCreated, err := s.auditService.Event(actor, domain.AuditActionAPIKeyCreated, auditEntityAccount, account.ID, nil, apiKeyAuditState(account.APIKey))
 	if err != nil {
 		return nil, err
 	}
 
-	account.AddBalance(amount)
-	err = s.repository.UpdateBalance(account)
+	err = s.repository.Save(account, created, keyCreated)
 	if err != nil {
 		return nil, err
 	}
@@ -67,4 +68,277 @@ func (s *AccountService) FindByID(id string) (*dto.AccountOutput, error) {
 
 	output := dto.FromAccount(account)
 	return &output, nil // Retorna o DTO da conta encontrada
-}
\ No newline at end of file
+}
+
+// Authenticate valida a API key e bloqueia contas que não estão ativas
+func (s *AccountService) Authenticate(apiKey string) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return activeAccount(account)
+}
+
+// AuthenticatePublishable valida a chave pública usada pelo navegador, com as mesmas
+// restrições de status do Authenticate
+func (s *AccountService) AuthenticatePublishable(publishableKey string) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByPublishableKey(publishableKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return activeAccount(account)
+}
+
+// FindActiveByID busca a conta das operações sem API key, como o pagamento de uma sessão de
+// checkout, bloqueando contas que não estão ativas como o Authenticate
+func (s *AccountService) FindActiveByID(id string) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return activeAccount(account)
+}
+
+func activeAccount(account *domain.Account) (*dto.AccountOutput, error) {
+	switch account.Status {
+	case domain.AccountStatusSuspended:
+		return nil, domain.ErrAccountSuspended
+	case domain.AccountStatusClosed:
+		return nil, domain.ErrAccountClosed
+	}
+
+	output := dto.FromAccount(account)
+	return &output, nil
+}
+
+func (s *AccountService) Suspend(id string, input dto.AccountStatusInput, actor domain.Actor) (*dto.AccountOutput, error) {
+	return s.changeStatus(id, input, actor, domain.AuditActionAccountSuspended, (*domain.Account).Suspend)
+}
+
+func (s *AccountService) Reactivate(id string, input dto.AccountStatusInput, actor domain.Actor) (*dto.AccountOutput, error) {
+	return s.changeStatus(id, input, actor, domain.AuditActionAccountReactivated, (*domain.Account).Reactivate)
+}
+
+func (s *AccountService) changeStatus(
+	id string,
+	input dto.AccountStatusInput,
+	actor domain.Actor,
+	action domain.AuditAction,
+	transition func(*domain.Account) error,
+) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := account.Status
+	before := accountAuditState(account)
+	if err := transition(account); err != nil {
+		return nil, err
+	}
+
+	after := accountAuditState(account)
+	after["reason"] = input.Reason
+	audit, err := s.auditService.Event(actor, action, auditEntityAccount, account.ID, before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateStatus(account, from, audit); err != nil {
+		return nil, err
+	}
+
+	slog.Info("status da conta alterado",
+		"account_id", account.ID,
+		"status", account.Status,
+		"operator", actor.ID,
+		"reason", input.Reason)
+
+	output := dto.FromAccount(account)
+	return &output, nil
+}
+
+// AdjustBalance credita ou debita manualmente o saldo, sempre com justificativa
+func (s *AccountService) AdjustBalance(id string, input dto.BalanceAdjustmentInput, actor domain.Actor) (*dto.BalanceAdjustmentOutput, error) {
+	adjustment, err := domain.NewBalanceAdjustment(id, input.Amount, input.Reason, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Os saldos só são conhecidos com a conta travada, por isso o evento é montado pelo repositório
+	audit := func() (*domain.AuditEvent, error) {
+		return s.auditService.Event(actor, domain.AuditActionBalanceAdjusted, auditEntityAccount, id,
+			map[string]any{"balance": adjustment.BalanceBefore},
+			map[string]any{
+				"balance":       adjustment.BalanceAfter,
+				"amount":        adjustment.Amount,
+				"reason":        adjustment.Reason,
+				"adjustment_id": adjustment.ID,
+			})
+	}
+
+	if err := s.repository.AdjustBalance(adjustment, audit); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromBalanceAdjustment(adjustment)
+	return &output, nil
+}
+
+// UpdateProfile altera nome e/ou email da conta autenticada
+func (s *AccountService) UpdateProfile(apiKey string, input dto.UpdateAccountInput, actor domain.Actor) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	before := accountAuditState(account)
+	if err := account.UpdateProfile(input.Name, input.Email); err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionAccountProfileUpdated, auditEntityAccount, account.ID, before, accountAuditState(account))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateProfile(account, audit); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromAccount(account)
+	return &output, nil
+}
+
+// Close encerra a conta definitivamente; a API key deixa de ser aceita
+func (s *AccountService) Close(id string, actor domain.Actor) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := accountAuditState(account)
+	if err := account.Close(); err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionAccountClosed, auditEntityAccount, account.ID, before, accountAuditState(account))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Close(account, audit); err != nil {
+		return nil, err
+	}
+	slog.Info("conta encerrada", "account_id", account.ID)
+
+	output := dto.FromAccount(account)
+	return &output, nil
+}
+
+// UpdateSettlementSchedule altera o prazo de liquidação usado nas próximas aprovações
+func (s *AccountService) UpdateSettlementSchedule(id string, input dto.SettlementScheduleInput, actor domain.Actor) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := settlementScheduleAuditState(account.SettlementSchedule)
+	schedule := domain.SettlementSchedule{DelayDays: input.DelayDays, PerInstallment: input.PerInstallment}
+	if err := account.UpdateSettlementSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionSettlementScheduleUpdated, auditEntityAccount, account.ID,
+		before, settlementScheduleAuditState(account.SettlementSchedule))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateSettlementSchedule(account, audit); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromAccount(account)
+	return &output, nil
+}
+
+// RotateAPIKey gera uma nova API key; a anterior deixa de funcionar imediatamente
+func (s *AccountService) RotateAPIKey(apiKey string, actor domain.Actor) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	before := apiKeyAuditState(account.APIKey)
+	account.RotateAPIKey()
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionAPIKeyRotated, auditEntityAccount, account.ID, before, apiKeyAuditState(account.APIKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateAPIKey(account, audit); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromAccount(account)
+	return &output, nil
+}
+
+// RotatePublishableKey gera uma nova chave pública; os navegadores com a anterior param de
+// tokenizar e pagar imediatamente
+func (s *AccountService) RotatePublishableKey(apiKey string, actor domain.Actor) (*dto.AccountOutput, error) {
+	account, err := s.repository.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	before := map[string]any{"publishable_key": account.PublishableKey}
+	account.RotatePublishableKey()
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionPublishableKeyRotated, auditEntityAccount, account.ID, before, map[string]any{"publishable_key": account.PublishableKey})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdatePublishableKey(account, audit); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromAccount(account)
+	return &output, nil
+}
+
+const auditEntityAccount = "account"
+
+// accountAuditState resume a conta para a auditoria, sem credenciais
+func accountAuditState(account *domain.Account) map[string]any {
+	return map[string]any{
+		"name":            account.Name,
+		"email":           account.Email,
+		"status":          account.Status,
+		"currency":        account.Currency,
+		"balance":         account.Balance,
+		"pending_balance": account.PendingBalance,
+	}
+}
+
+func settlementScheduleAuditState(schedule domain.SettlementSchedule) map[string]any {
+	return map[string]any{
+		"delay_days":      schedule.DelayDays,
+		"per_installment": schedule.PerInstallment,
+	}
+}
+
+// apiKeyAuditState guarda apenas o final da chave, suficiente para identificá-la
+func apiKeyAuditState(apiKey string) map[string]any {
+	suffix := apiKey
+	if len(suffix) > 4 {
+		suffix = suffix[len(suffix)-4:]
+	}
+	return map[string]any{"api_key_suffix": suffix}
+}
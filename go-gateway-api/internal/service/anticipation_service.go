@@ -0,0 +1,180 @@
+package service
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const auditEntityAnticipation = "anticipation"
+
+type AnticipationConfig struct {
+	MonthlyRate float64 // taxa de desconto a.m., aplicada pro rata pelos dias até o vencimento
+}
+
+func NewAnticipationConfig() *AnticipationConfig {
+	config := &AnticipationConfig{
+		MonthlyRate: 2.5,
+	}
+
+	if value, err := strconv.ParseFloat(os.Getenv("ANTICIPATION_MONTHLY_RATE"), 64); err == nil && value >= 0 && value <= 100 {
+		config.MonthlyRate = value
+	}
+
+	return config
+}
+
+// AnticipationService simula e executa a antecipação dos recebíveis futuros de uma conta
+type AnticipationService struct {
+	anticipationRepository domain.AnticipationRepository
+	accountService         *AccountService
+	feeService             *FeeService
+	auditService           *AuditService
+	config                 *AnticipationConfig
+}
+
+func NewAnticipationService(
+	anticipationRepository domain.AnticipationRepository,
+	accountService *AccountService,
+	feeService *FeeService,
+	auditService *AuditService,
+	config *AnticipationConfig,
+) *AnticipationService {
+	return &AnticipationService{
+		anticipationRepository: anticipationRepository,
+		accountService:         accountService,
+		feeService:             feeService,
+		auditService:           auditService,
+		config:                 config,
+	}
+}
+
+// ListAnticipatable lista os recebíveis agendados que ainda podem ser antecipados
+func (s *AnticipationService) ListAnticipatable(apiKey string) ([]*dto.AnticipatableReceivableOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	receivables, err := s.anticipationRepository.FindAnticipatable(account.ID, nil, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromAnticipatableReceivables(receivables), nil
+}
+
+// Simulate calcula a antecipação sem gravar nada
+func (s *AnticipationService) Simulate(apiKey string, input dto.AnticipationInput) (*dto.AnticipationOutput, error) {
+	account, err := s.accountService.Authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	anticipation, err := s.build(account.ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromAnticipation(anticipation)
+	output.ID = ""
+	return output, nil
+}
+
+// Request antecipa os recebíveis: o valor bruto sai do saldo pendente e o líquido entra no
+// saldo disponível
+func (s *AnticipationService) Request(apiKey string, input dto.AnticipationInput, actor domain.Actor) (*dto.AnticipationOutput, error) {
+	account, err := s.accountService.Authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	anticipation, err := s.build(account.ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	platformAccountID, err := s.feeService.PlatformAccountFor(domain.Currency(account.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionAnticipationRequested, auditEntityAnticipation, anticipation.ID, nil, map[string]any{
+		"account_id":      anticipation.AccountID,
+		"receivables":     anticipation.ReceivableIDs(),
+		"monthly_rate":    anticipation.MonthlyRate,
+		"gross_amount":    anticipation.GrossAmount,
+		"discount_amount": anticipation.DiscountAmount,
+		"net_amount":      anticipation.NetAmount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := anticipation.LedgerEntries(platformAccountID)
+	if err := s.anticipationRepository.Create(anticipation, entries, audit); err != nil {
+		return nil, err
+	}
+	slog.Info("recebíveis antecipados",
+		"anticipation_id", anticipation.ID,
+		"account_id", anticipation.AccountID,
+		"gross_amount", anticipation.GrossAmount,
+		"net_amount", anticipation.NetAmount)
+
+	return dto.FromAnticipation(anticipation), nil
+}
+
+func (s *AnticipationService) GetByID(id, apiKey string) (*dto.AnticipationOutput, error) {
+	anticipation, err := s.anticipationRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if anticipation.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	return dto.FromAnticipation(anticipation), nil
+}
+
+func (s *AnticipationService) ListByAccountAPIKey(apiKey string) ([]*dto.AnticipationOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	anticipations, err := s.anticipationRepository.FindByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.AnticipationOutput, len(anticipations))
+	for i, anticipation := range anticipations {
+		output[i] = dto.FromAnticipation(anticipation)
+	}
+	return output, nil
+}
+
+func (s *AnticipationService) build(accountID string, input dto.AnticipationInput) (*domain.Anticipation, error) {
+	now := time.Now()
+	receivables, err := s.anticipationRepository.FindAnticipatable(accountID, input.ReceivableIDs, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	// Algum recebível selecionado não existe, é de outra conta ou já foi liquidado
+	if len(input.ReceivableIDs) > 0 && len(receivables) != len(input.ReceivableIDs) {
+		return nil, domain.ErrReceivableNotAnticipatable
+	}
+
+	return domain.NewAnticipation(accountID, receivables, s.config.MonthlyRate, now)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const (
+	defaultAuditQueryLimit = 100
+	auditVerifyBatchSize   = 500
+)
+
+// AuditService grava e consulta a trilha de auditoria das operações sensíveis
+type AuditService struct {
+	repository domain.AuditRepository
+}
+
+func NewAuditService(repository domain.AuditRepository) *AuditService {
+	return &AuditService{repository: repository}
+}
+
+// Event monta o evento de auditoria de uma operação. O evento é entregue ao repositório da
+// operação e gravado na mesma transação: sem a trilha, a operação também não é confirmada.
+func (s *AuditService) Event(actor domain.Actor, action domain.AuditAction, entityType, entityID string, before, after any) (*domain.AuditEvent, error) {
+	return domain.NewAuditEvent(actor, action, entityType, entityID, before, after)
+}
+
+func (s *AuditService) Find(filter domain.AuditFilter) ([]*dto.AuditEventOutput, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditQueryLimit
+	}
+
+	events, err := s.repository.Find(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.AuditEventOutput, len(events))
+	for i, event := range events {
+		output[i] = dto.FromAuditEvent(event)
+	}
+	return output, nil
+}
+
+// Verify percorre toda a cadeia recalculando os hashes e aponta o primeiro evento adulterado
+func (s *AuditService) Verify() (*dto.AuditVerificationOutput, error) {
+	output := &dto.AuditVerificationOutput{Valid: true}
+
+	var lastSequence int64
+	var lastHash string
+	for {
+		events, err := s.repository.FindAfterSequence(lastSequence, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			if event.Sequence != lastSequence+1 || event.PrevHash != lastHash || event.ComputeHash() != event.Hash {
+				output.Valid = false
+				output.BrokenAtSequence = &event.Sequence
+				return output, nil
+			}
+
+			lastSequence = event.Sequence
+			lastHash = event.Hash
+			output.CheckedEvents++
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			output.LastHash = lastHash
+			return output, nil
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+// BoletoBank é a integração com o banco emissor dos boletos. Cada banco tem o seu formato de
+// aviso de liquidação, por isso a interpretação do callback também fica no adaptador.
+type BoletoBank interface {
+	// Register registra o boleto no banco para que ele possa ser pago
+	Register(ctx context.Context, boleto *domain.Boleto) error
+	// ParsePayment valida a assinatura e interpreta o aviso de liquidação recebido em /webhooks/boleto
+	ParsePayment(body []byte, signature string) (*domain.BoletoPayment, error)
+}
+
+// BoletoCallbackSignatureHeader carrega o HMAC-SHA256 (hex) do corpo do aviso de liquidação
+const BoletoCallbackSignatureHeader = "X-Boleto-Signature"
+
+type SimulatedBoletoBankConfig struct {
+	PaymentDelay time.Duration // tempo até o pagamento simulado; zero desativa o pagamento automático
+	CallbackURL  string
+	// WebhookSecret é compartilhado entre o simulador, que assina os avisos, e o gateway
+	WebhookSecret string
+}
+
+func NewSimulatedBoletoBankConfig() *SimulatedBoletoBankConfig {
+	config := &SimulatedBoletoBankConfig{
+		PaymentDelay:  30 * time.Second,
+		CallbackURL:   "http://localhost:8080/webhooks/boleto",
+		WebhookSecret: os.Getenv("BOLETO_WEBHOOK_SECRET"),
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("BOLETO_SIMULATOR_PAYMENT_DELAY")); err == nil && value >= 0 {
+		config.PaymentDelay = value
+	}
+
+	if value := os.Getenv("BOLETO_SIMULATOR_CALLBACK_URL"); value != "" {
+		config.CallbackURL = value
+	}
+
+	if config.WebhookSecret == "" {
+		slog.Warn("BOLETO_WEBHOOK_SECRET não configurado, avisos de liquidação serão recusados")
+	}
+
+	return config
+}
+
+// SimulatedBoletoBank simula o banco emissor para desenvolvimento: aceita todos os registros
+// e, depois de PaymentDelay, liquida cada boleto pelo valor devido chamando o callback assinado
+type SimulatedBoletoBank struct {
+	config *SimulatedBoletoBankConfig
+	client *http.Client
+}
+
+func NewSimulatedBoletoBank(config *SimulatedBoletoBankConfig) *SimulatedBoletoBank {
+	return &SimulatedBoletoBank{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (b *SimulatedBoletoBank) Register(ctx context.Context, boleto *domain.Boleto) error {
+	if b.config.PaymentDelay > 0 {
+		payment := dto.BoletoCallbackInput{
+			BankCode:  boleto.BankCode,
+			OurNumber: boleto.OurNumber,
+		}
+		time.AfterFunc(b.config.PaymentDelay, func() {
+			payment.PaidAt = time.Now()
+			payment.Amount = boleto.AmountDue(payment.PaidAt)
+			b.pay(payment)
+		})
+	}
+
+	slog.Info("boleto registrado no banco simulado", "bank_code", boleto.BankCode, "our_number", boleto.OurNumber)
+	return nil
+}
+
+func (b *SimulatedBoletoBank) ParsePayment(body []byte, signature string) (*domain.BoletoPayment, error) {
+	if b.config.WebhookSecret == "" || !hmac.Equal([]byte(signature), []byte(SignCallback(b.config.WebhookSecret, body))) {
+		return nil, domain.ErrInvalidBoletoSignature
+	}
+
+	var input dto.BoletoCallbackInput
+	if err := json.Unmarshal(body, &input); err != nil {
+		return nil, err
+	}
+
+	return &domain.BoletoPayment{
+		BankCode:  input.BankCode,
+		OurNumber: input.OurNumber,
+		Amount:    input.Amount,
+		PaidAt:    input.PaidAt,
+	}, nil
+}
+
+func (b *SimulatedBoletoBank) pay(payment dto.BoletoCallbackInput) {
+	body, err := json.Marshal(payment)
+	if err != nil {
+		slog.Error("erro ao montar aviso do banco simulado", "error", err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, b.config.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("erro ao montar aviso do banco simulado", "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(BoletoCallbackSignatureHeader, SignCallback(b.config.WebhookSecret, body))
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		slog.Error("erro ao enviar aviso do banco simulado", "error", err, "our_number", payment.OurNumber)
+		return
+	}
+	resp.Body.Close()
+
+	slog.Info("pagamento de boleto simulado", "our_number", payment.OurNumber, "status_code", resp.StatusCode)
+}
+
+// CNABBoletoBank é o banco que recebe os registros e informa as liquidações por arquivo: os
+// boletos seguem na remessa CNAB e os pagamentos chegam pelo retorno, não por webhook
+type CNABBoletoBank struct{}
+
+func NewCNABBoletoBank() *CNABBoletoBank {
+	return &CNABBoletoBank{}
+}
+
+// Register não faz nada: o boleto fica pendente até ser incluído em uma remessa
+func (b *CNABBoletoBank) Register(ctx context.Context, boleto *domain.Boleto) error {
+	return nil
+}
+
+func (b *CNABBoletoBank) ParsePayment(body []byte, signature string) (*domain.BoletoPayment, error) {
+	return nil, domain.ErrInvalidBoletoSignature
+}
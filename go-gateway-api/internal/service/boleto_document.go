@@ -0,0 +1,97 @@
+package service
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/barcode"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// boletoTemplate segue o leiaute da ficha de compensação: o navegador imprime ou salva em PDF
+var boletoTemplate = template.Must(template.New("boleto").Funcs(template.FuncMap{
+	"brl":  formatBRL,
+	"date": func(value time.Time) string { return value.Format("02/01/2006") },
+}).Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>Boleto {{.Boleto.OurNumber}}</title>
+<style>
+  body { font-family: Arial, Helvetica, sans-serif; font-size: 11px; margin: 24px; }
+  table { border-collapse: collapse; width: 680px; }
+  td { border: 1px solid #000; padding: 2px 4px; vertical-align: top; }
+  .label { display: block; font-size: 9px; color: #333; }
+  .header td { border: none; border-bottom: 2px solid #000; font-size: 16px; font-weight: bold; }
+  .line { text-align: right; }
+  .right { text-align: right; }
+  .barcode { border: none; padding-top: 8px; }
+  @media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<table>
+  <tr class="header">
+    <td>{{.Boleto.BankCode}}</td>
+    <td colspan="3" class="line">{{.DigitableLine}}</td>
+  </tr>
+  <tr>
+    <td colspan="3"><span class="label">Local de pagamento</span>Pagável em qualquer banco até o vencimento</td>
+    <td class="right"><span class="label">Vencimento</span>{{date .Boleto.DueDate}}</td>
+  </tr>
+  <tr>
+    <td colspan="3"><span class="label">Beneficiário</span>{{.Issuer.Name}}{{if .Issuer.Document}} - {{.Issuer.Document}}{{end}}</td>
+    <td class="right"><span class="label">Agência / Código do beneficiário</span>{{.Issuer.Agency}} / {{.Issuer.Account}}</td>
+  </tr>
+  <tr>
+    <td><span class="label">Data do documento</span>{{date .Boleto.CreatedAt}}</td>
+    <td><span class="label">Número do documento</span>{{.Invoice.ID}}</td>
+    <td><span class="label">Carteira</span>{{.Issuer.Wallet}}</td>
+    <td class="right"><span class="label">Nosso número</span>{{.Boleto.OurNumber}}</td>
+  </tr>
+  <tr>
+    <td colspan="3" rowspan="2"><span class="label">Instruções</span>
+      {{if .Invoice.Description}}{{.Invoice.Description}}<br>{{end}}
+      {{if gt .Boleto.FinePercentage 0.0}}Após o vencimento, cobrar multa de {{brl .Boleto.FinePercentage}}%.<br>{{end}}
+      {{if gt .Boleto.InterestPercentage 0.0}}Após o vencimento, cobrar juros de mora de {{brl .Boleto.InterestPercentage}}% ao mês.<br>{{end}}
+      Não receber após {{date .Boleto.PaymentLimitDate}}.
+    </td>
+    <td class="right"><span class="label">(=) Valor do documento</span>R$ {{brl .Boleto.Amount}}</td>
+  </tr>
+  <tr>
+    <td class="right"><span class="label">(=) Valor cobrado</span>{{if .Boleto.PaidAmount}}R$ {{brl .Boleto.PaidAmount}}{{end}}</td>
+  </tr>
+  <tr>
+    <td colspan="4"><span class="label">Pagador</span>{{.Boleto.Payer.Name}} - {{.Boleto.Payer.Document}}</td>
+  </tr>
+  <tr>
+    <td colspan="4" class="barcode">{{.Barcode}}</td>
+  </tr>
+</table>
+</body>
+</html>
+`))
+
+type boletoDocument struct {
+	Boleto        *domain.Boleto
+	Invoice       *domain.Invoice
+	Issuer        domain.BoletoIssuer
+	DigitableLine string
+	Barcode       template.HTML // SVG gerado a partir dos dígitos do código de barras
+}
+
+func renderBoleto(w io.Writer, boleto *domain.Boleto, invoice *domain.Invoice, issuer domain.BoletoIssuer) error {
+	code, err := barcode.EncodeITF(boleto.Barcode)
+	if err != nil {
+		return err
+	}
+
+	return boletoTemplate.Execute(w, boletoDocument{
+		Boleto:        boleto,
+		Invoice:       invoice,
+		Issuer:        issuer,
+		DigitableLine: boleto.FormattedDigitableLine(),
+		Barcode:       template.HTML(code.SVG(1, 50)),
+	})
+}
@@ -0,0 +1,408 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+const boletoExpirationJobLock = "boleto_expiration"
+
+type BoletoConfig struct {
+	Issuer           domain.BoletoIssuer
+	DefaultDueDays   int // vencimento usado quando a fatura não informa a data
+	PaymentLimitDays int // dias após o vencimento em que o banco ainda aceita o pagamento
+}
+
+func NewBoletoConfig() *BoletoConfig {
+	config := &BoletoConfig{
+		Issuer: domain.BoletoIssuer{
+			BankCode: "237",
+			Agency:   "0001",
+			Account:  "1234567",
+			Wallet:   "09",
+			Name:     "PAYMENT GATEWAY",
+			Document: os.Getenv("BOLETO_BENEFICIARY_DOCUMENT"),
+		},
+		DefaultDueDays:   3,
+		PaymentLimitDays: 30,
+	}
+
+	if value := os.Getenv("BOLETO_BANK_CODE"); len(value) == 3 {
+		config.Issuer.BankCode = value
+	}
+
+	if value := os.Getenv("BOLETO_AGENCY"); value != "" && len(value) <= 4 {
+		config.Issuer.Agency = value
+	}
+
+	if value := os.Getenv("BOLETO_ACCOUNT"); value != "" && len(value) <= 7 {
+		config.Issuer.Account = value
+	}
+
+	if value := os.Getenv("BOLETO_WALLET"); value != "" && len(value) <= 2 {
+		config.Issuer.Wallet = value
+	}
+
+	if value := os.Getenv("BOLETO_BENEFICIARY_NAME"); value != "" {
+		config.Issuer.Name = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("BOLETO_DEFAULT_DUE_DAYS")); err == nil && value >= 0 {
+		config.DefaultDueDays = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("BOLETO_PAYMENT_LIMIT_DAYS")); err == nil && value >= 0 {
+		config.PaymentLimitDays = value
+	}
+
+	return config
+}
+
+// BoletoInput são os dados de emissão informados na criação da fatura
+type BoletoInput struct {
+	Payer              domain.BoletoPayer
+	DueDate            time.Time // zero usa o vencimento padrão
+	FinePercentage     float64
+	InterestPercentage float64
+}
+
+// BoletoService emite os boletos das faturas pelo banco emissor e aprova as faturas quando o
+// banco informa a liquidação
+type BoletoService struct {
+	boletoRepository  domain.BoletoRepository
+	invoiceRepository domain.InvoiceRepository
+	accountService    AccountService
+	fxService         *FXService
+	feeService        *FeeService
+	settlementService *SettlementService
+	bank              BoletoBank
+	auditService      *AuditService
+	config            *BoletoConfig
+}
+
+func NewBoletoService(
+	boletoRepository domain.BoletoRepository,
+	invoiceRepository domain.InvoiceRepository,
+	accountService AccountService,
+	fxService *FXService,
+	feeService *FeeService,
+	settlementService *SettlementService,
+	bank BoletoBank,
+	auditService *AuditService,
+	config *BoletoConfig,
+) *BoletoService {
+	return &BoletoService{
+		boletoRepository:  boletoRepository,
+		invoiceRepository: invoiceRepository,
+		accountService:    accountService,
+		fxService:         fxService,
+		feeService:        feeService,
+		settlementService: settlementService,
+		bank:              bank,
+		auditService:      auditService,
+		config:            config,
+	}
+}
+
+// Create registra o boleto no banco e grava a fatura com o boleto
+func (s *BoletoService) Create(invoice *domain.Invoice, input BoletoInput, audit ...*domain.AuditEvent) (*domain.Boleto, error) {
+	now := time.Now()
+	if input.DueDate.IsZero() {
+		input.DueDate = now.AddDate(0, 0, s.config.DefaultDueDays)
+	}
+
+	ourNumber, err := s.boletoRepository.NextOurNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	boleto, err := domain.NewBoleto(
+		invoice,
+		s.config.Issuer,
+		ourNumber,
+		input.Payer,
+		input.DueDate,
+		s.config.PaymentLimitDays,
+		input.FinePercentage,
+		input.InterestPercentage,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.bank.Register(context.Background(), boleto); err != nil {
+		return nil, err
+	}
+
+	if err := s.boletoRepository.Create(invoice, boleto, audit...); err != nil {
+		return nil, err
+	}
+
+	return boleto, nil
+}
+
+// BoletoFor retorna o boleto da fatura, ou nil se a fatura não for de boleto
+func (s *BoletoService) BoletoFor(invoice *domain.Invoice) (*domain.Boleto, error) {
+	if invoice.PaymentType != domain.PaymentTypeBoleto {
+		return nil, nil
+	}
+
+	return s.boletoRepository.FindByInvoiceID(invoice.ID)
+}
+
+// Document gera o boleto em HTML para impressão
+func (s *BoletoService) Document(invoiceID, apiKey string) ([]byte, error) {
+	accountOutput, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	boleto, err := s.boletoRepository.FindByInvoiceID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if boleto.AccountID != accountOutput.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	invoice, err := s.invoiceRepository.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var document bytes.Buffer
+	if err := renderBoleto(&document, boleto, invoice, s.config.Issuer); err != nil {
+		return nil, err
+	}
+
+	return document.Bytes(), nil
+}
+
+// HandleCallback confirma a liquidação informada pelo banco no webhook
+func (s *BoletoService) HandleCallback(body []byte, signature string) error {
+	payment, err := s.bank.ParsePayment(body, signature)
+	if err != nil {
+		return err
+	}
+
+	return s.ConfirmPayment(payment, domain.NewSystemActor("boleto-bank"))
+}
+
+// ConfirmPayment aprova a fatura do boleto liquidado. Avisos repetidos de um boleto já pago
+// são ignorados.
+func (s *BoletoService) ConfirmPayment(payment *domain.BoletoPayment, actor domain.Actor) error {
+	boleto, err := s.boletoRepository.FindByOurNumber(payment.BankCode, payment.OurNumber)
+	if err != nil {
+		return err
+	}
+
+	if boleto.Status == domain.BoletoStatusPaid {
+		slog.Info("liquidação de boleto duplicada ignorada", "our_number", boleto.OurNumber)
+		return nil
+	}
+
+	if payment.PaidAt.IsZero() {
+		payment.PaidAt = time.Now()
+	}
+
+	if err := boleto.Pay(payment.Amount, payment.PaidAt); err != nil {
+		return err
+	}
+
+	invoice, err := s.invoiceRepository.FindByID(boleto.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	before := invoiceAuditState(invoice)
+	if err := invoice.ConfirmPayment(); err != nil {
+		return err
+	}
+
+	// Multa e juros pagos pelo atraso entram no valor cobrado e, portanto, no valor líquido
+	invoice.AddLateCharges(boleto.LateCharges())
+
+	approval, err := approveInvoice(s.fxService, s.feeService, s.settlementService, invoice)
+	if err != nil {
+		return err
+	}
+
+	after := invoiceAuditState(invoice)
+	after["our_number"] = boleto.OurNumber
+	audit, err := s.auditService.Event(actor, domain.AuditActionInvoiceStatusChanged, auditEntityInvoice, invoice.ID, before, after)
+	if err != nil {
+		return err
+	}
+
+	if err := s.boletoRepository.ConfirmPayment(boleto, invoice, approval, audit); err != nil {
+		return err
+	}
+
+	slog.Info("pagamento de boleto confirmado",
+		"invoice_id", invoice.ID,
+		"our_number", boleto.OurNumber,
+		"paid_amount", boleto.PaidAmount)
+	return nil
+}
+
+type BoletoExpirationJobConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+func NewBoletoExpirationJobConfig() *BoletoExpirationJobConfig {
+	config := &BoletoExpirationJobConfig{
+		Interval:  time.Hour,
+		BatchSize: 100,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("BOLETO_EXPIRATION_INTERVAL")); err == nil && value > 0 {
+		config.Interval = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("BOLETO_EXPIRATION_BATCH_SIZE")); err == nil && value > 0 {
+		config.BatchSize = value
+	}
+
+	return config
+}
+
+// BoletoExpirationJob expira os boletos não pagos até a data limite e as suas faturas.
+// Apenas uma réplica executa cada rodada.
+type BoletoExpirationJob struct {
+	boletoRepository  domain.BoletoRepository
+	invoiceRepository domain.InvoiceRepository
+	locker            domain.Locker
+	auditService      *AuditService
+	config            *BoletoExpirationJobConfig
+}
+
+func NewBoletoExpirationJob(
+	boletoRepository domain.BoletoRepository,
+	invoiceRepository domain.InvoiceRepository,
+	locker domain.Locker,
+	auditService *AuditService,
+	config *BoletoExpirationJobConfig,
+) *BoletoExpirationJob {
+	return &BoletoExpirationJob{
+		boletoRepository:  boletoRepository,
+		invoiceRepository: invoiceRepository,
+		locker:            locker,
+		auditService:      auditService,
+		config:            config,
+	}
+}
+
+// Run executa a expiração imediatamente e depois a cada intervalo, até o contexto ser cancelado
+func (j *BoletoExpirationJob) Run(ctx context.Context) error {
+	slog.Info("job de expiração de boletos iniciado", "interval", j.config.Interval)
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.ExpireDue(ctx, time.Now()); err != nil {
+			slog.Error("erro na expiração de boletos", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireDue expira os boletos cuja data limite é anterior à data de now (UTC)
+func (j *BoletoExpirationJob) ExpireDue(ctx context.Context, now time.Time) error {
+	release, acquired, err := j.locker.TryLock(ctx, boletoExpirationJobLock)
+	if err != nil {
+		return err
+	}
+
+	if !acquired {
+		slog.Debug("expiração de boletos em execução em outra réplica")
+		return nil
+	}
+	defer release()
+
+	date, _ := time.Parse(time.DateOnly, now.UTC().Format(time.DateOnly))
+	boletos, err := j.boletoRepository.FindExpired(date, j.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, boleto := range boletos {
+		// Uma falha não impede o tratamento dos demais boletos
+		if err := j.expire(boleto); err != nil {
+			slog.Error("erro ao expirar boleto", "error", err, "invoice_id", boleto.InvoiceID)
+		}
+	}
+
+	return nil
+}
+
+func (j *BoletoExpirationJob) expire(boleto *domain.Boleto) error {
+	invoice, err := j.invoiceRepository.FindByID(boleto.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	before := invoiceAuditState(invoice)
+	if err := boleto.Expire(); err != nil {
+		return err
+	}
+
+	if err := invoice.ExpirePayment(); err != nil {
+		return err
+	}
+
+	audit, err := j.auditService.Event(domain.NewSystemActor(boletoExpirationJobLock), domain.AuditActionInvoiceStatusChanged,
+		auditEntityInvoice, invoice.ID, before, invoiceAuditState(invoice))
+	if err != nil {
+		return err
+	}
+
+	if err := j.boletoRepository.Expire(boleto, invoice, audit); err != nil {
+		if err == domain.ErrInvalidStatus {
+			return nil // a liquidação foi confirmada antes da expiração
+		}
+		return err
+	}
+
+	slog.Info("boleto expirado", "invoice_id", invoice.ID, "our_number", boleto.OurNumber)
+	return nil
+}
+
+// formatBRL formata o valor no padrão brasileiro (1.234,56)
+func formatBRL(value float64) string {
+	cents := fmt.Sprintf("%.2f", value)
+	integer, decimal := cents[:len(cents)-3], cents[len(cents)-2:]
+
+	negative := integer[0] == '-'
+	if negative {
+		integer = integer[1:]
+	}
+
+	var grouped []byte
+	for i := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped = append(grouped, '.')
+		}
+		grouped = append(grouped, integer[i])
+	}
+
+	if negative {
+		return "-" + string(grouped) + "," + decimal
+	}
+	return string(grouped) + "," + decimal
+}
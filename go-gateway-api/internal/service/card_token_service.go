@@ -0,0 +1,162 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+type CardTokenConfig struct {
+	TTL time.Duration // prazo para o token ser usado no pagamento
+	// EncryptionKey é a chave AES-256 que cifra os cartões tokenizados; todas as réplicas
+	// precisam da mesma chave
+	EncryptionKey []byte
+}
+
+func NewCardTokenConfig() *CardTokenConfig {
+	config := &CardTokenConfig{
+		TTL: 15 * time.Minute,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("CARD_TOKEN_TTL")); err == nil && value > 0 && value <= time.Hour {
+		config.TTL = value
+	}
+
+	key, err := hex.DecodeString(os.Getenv("CARD_TOKEN_ENCRYPTION_KEY"))
+	if err != nil || len(key) != 32 {
+		slog.Warn("CARD_TOKEN_ENCRYPTION_KEY ausente ou inválida (64 caracteres hexadecimais), usando uma chave temporária: os tokens só valem nesta réplica até ela reiniciar")
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+	config.EncryptionKey = key
+
+	return config
+}
+
+// sealedCard é a parte do cartão que só existe cifrada
+type sealedCard struct {
+	Number string `json:"number"`
+	CVV    string `json:"cvv"`
+}
+
+// CardTokenService tokeniza os cartões digitados no navegador com a chave pública da conta,
+// para que o número e o CVV não passem pelo servidor do lojista
+type CardTokenService struct {
+	cardTokenRepository domain.CardTokenRepository
+	config              *CardTokenConfig
+}
+
+func NewCardTokenService(cardTokenRepository domain.CardTokenRepository, config *CardTokenConfig) *CardTokenService {
+	return &CardTokenService{
+		cardTokenRepository: cardTokenRepository,
+		config:              config,
+	}
+}
+
+func (s *CardTokenService) Create(accountID string, input dto.CreateCardTokenInput) (*dto.CardTokenOutput, error) {
+	card := dto.ToTokenCard(input)
+	token, err := domain.NewCardToken(accountID, card, s.config.TTL, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	token.SealedCard, err = s.seal(token.ID, sealedCard{Number: card.Number, CVV: card.CVV})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cardTokenRepository.Create(token); err != nil {
+		return nil, err
+	}
+
+	return dto.FromCardToken(token), nil
+}
+
+// redeem consome o token da conta e devolve o cartão para o pagamento
+func (s *CardTokenService) redeem(id, accountID string) (domain.CreditCard, error) {
+	token, err := s.cardTokenRepository.FindByID(id)
+	if err != nil {
+		return domain.CreditCard{}, err
+	}
+
+	if err := token.Use(accountID, time.Now()); err != nil {
+		return domain.CreditCard{}, err
+	}
+
+	secret, err := s.open(token.ID, token.SealedCard)
+	if err != nil {
+		return domain.CreditCard{}, err
+	}
+
+	if err := s.cardTokenRepository.MarkUsed(token); err != nil {
+		return domain.CreditCard{}, err
+	}
+
+	return domain.CreditCard{
+		Number:          secret.Number,
+		CVV:             secret.CVV,
+		ExpirationMonth: token.ExpirationMonth,
+		ExpirationYear:  token.ExpirationYear,
+		CardHolderName:  token.CardholderName,
+	}, nil
+}
+
+// seal cifra o cartão com AES-GCM; o ID do token entra como dado autenticado, então o
+// conteúdo cifrado não pode ser copiado para outro token
+func (s *CardTokenService) seal(tokenID string, card sealedCard) ([]byte, error) {
+	aead, err := s.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(card)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, []byte(tokenID)), nil
+}
+
+func (s *CardTokenService) open(tokenID string, sealed []byte) (*sealedCard, error) {
+	aead, err := s.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, domain.ErrCardTokenUsed
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(tokenID))
+	if err != nil {
+		return nil, errors.New("card token could not be decrypted")
+	}
+
+	var card sealedCard
+	if err := json.Unmarshal(plaintext, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (s *CardTokenService) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.config.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
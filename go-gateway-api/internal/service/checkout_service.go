@@ -0,0 +1,293 @@
+package service
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const (
+	auditEntityCheckoutSession = "checkout_session"
+	auditEntityPaymentLink     = "payment_link"
+)
+
+type CheckoutConfig struct {
+	SessionTTL          time.Duration // prazo para o comprador pagar a sessão
+	MaxPaymentAttempts  int           // pagamentos recusados até a sessão ficar failed
+	MaxLinkOpenSessions int           // sessões abertas ao mesmo tempo por link de pagamento
+}
+
+func NewCheckoutConfig() *CheckoutConfig {
+	config := &CheckoutConfig{
+		SessionTTL:          30 * time.Minute,
+		MaxPaymentAttempts:  3,
+		MaxLinkOpenSessions: 100,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("CHECKOUT_SESSION_TTL")); err == nil && value > 0 && value <= 24*time.Hour {
+		config.SessionTTL = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("CHECKOUT_MAX_PAYMENT_ATTEMPTS")); err == nil && value > 0 {
+		config.MaxPaymentAttempts = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("CHECKOUT_LINK_MAX_OPEN_SESSIONS")); err == nil && value > 0 {
+		config.MaxLinkOpenSessions = value
+	}
+
+	return config
+}
+
+// CheckoutService cria as sessões de checkout pagas pelo comprador no navegador, sem a API key
+// do lojista, e os links de pagamento que abrem uma sessão a cada acesso
+type CheckoutService struct {
+	checkoutRepository domain.CheckoutRepository
+	accountService     *AccountService
+	invoiceService     *InvoiceService
+	cardTokenService   *CardTokenService
+	auditService       *AuditService
+	config             *CheckoutConfig
+}
+
+func NewCheckoutService(
+	checkoutRepository domain.CheckoutRepository,
+	accountService *AccountService,
+	invoiceService *InvoiceService,
+	cardTokenService *CardTokenService,
+	auditService *AuditService,
+	config *CheckoutConfig,
+) *CheckoutService {
+	return &CheckoutService{
+		checkoutRepository: checkoutRepository,
+		accountService:     accountService,
+		invoiceService:     invoiceService,
+		cardTokenService:   cardTokenService,
+		auditService:       auditService,
+		config:             config,
+	}
+}
+
+func (s *CheckoutService) CreateSession(apiKey string, input dto.CreateCheckoutSessionInput, actor domain.Actor) (*dto.CheckoutSessionOutput, error) {
+	// Contas suspensas ou encerradas não podem receber pagamentos
+	account, err := s.accountService.Authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session, err := dto.ToCheckoutSession(input, account.ID, s.config.SessionTTL, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createSession(session, actor, now)
+}
+
+func (s *CheckoutService) createSession(session *domain.CheckoutSession, actor domain.Actor, now time.Time) (*dto.CheckoutSessionOutput, error) {
+	output := dto.FromCheckoutSession(session, now)
+	audit, err := s.auditService.Event(actor, domain.AuditActionCheckoutSessionCreated, auditEntityCheckoutSession, session.ID, nil, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.CreateSession(session, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// GetSession é público: o ID da sessão basta para exibir a página de pagamento
+func (s *CheckoutService) GetSession(id string) (*dto.CheckoutSessionOutput, error) {
+	session, err := s.checkoutRepository.FindSessionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromCheckoutSession(session, time.Now()), nil
+}
+
+// Confirm paga a sessão com o cartão do comprador, autenticado pela chave pública da conta
+// da sessão. A sessão fica em processing durante a criação da fatura para que dois envios
+// não cobrem o comprador duas vezes e é concluída na mesma transação que grava a fatura; um
+// cartão recusado a reabre para uma nova tentativa, até o limite de tentativas da configuração.
+func (s *CheckoutService) Confirm(id, accountID string, input dto.ConfirmCheckoutSessionInput, actor domain.Actor) (*dto.CheckoutPaymentOutput, error) {
+	session, err := s.checkoutRepository.FindSessionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.AccountID != accountID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	if err := session.StartPayment(time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.UpdateSessionStatus(session, domain.CheckoutSessionStatusOpen); err != nil {
+		return nil, err
+	}
+
+	// A sessão concluída só substitui a lida depois que a transação da fatura foi confirmada
+	finished := *session
+	invoice, err := s.pay(session, input, actor, func(invoice *domain.Invoice, approval *domain.InvoiceApproval, audit ...*domain.AuditEvent) error {
+		finished.FinishPayment(invoice.ID, invoice.Status, s.config.MaxPaymentAttempts, time.Now())
+		return s.checkoutRepository.SavePayment(&finished, domain.CheckoutSessionStatusProcessing, invoice, approval, audit...)
+	})
+	if err != nil {
+		s.failPayment(session)
+		return nil, err
+	}
+
+	return dto.FromCheckoutPayment(&finished, invoice), nil
+}
+
+func (s *CheckoutService) pay(session *domain.CheckoutSession, input dto.ConfirmCheckoutSessionInput, actor domain.Actor, save saveInvoiceFunc) (*dto.InvoiceOutput, error) {
+	account, err := s.accountService.FindActiveByID(session.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.CardToken != "" {
+		card, err := s.cardTokenService.redeem(input.CardToken, session.AccountID)
+		if err != nil {
+			return nil, err
+		}
+
+		input.CardNumber = card.Number
+		input.CVV = card.CVV
+		input.ExpirationMonth = card.ExpirationMonth
+		input.ExpirationYear = card.ExpirationYear
+		input.CardholderName = card.CardHolderName
+	}
+
+	// A fatura é registrada na auditoria em nome do comprador da sessão
+	customer := domain.Actor{Type: domain.ActorTypeCustomer, ID: session.ID, IP: actor.IP}
+	return s.invoiceService.create(dto.ToCheckoutInvoiceInput(session, input), account, customer, save)
+}
+
+// failPayment reabre a sessão após um pagamento que não gravou fatura, contando a tentativa.
+// Uma falha aqui é apenas registrada: a sessão fica em processing e aparece como expirada
+// quando o prazo termina.
+func (s *CheckoutService) failPayment(session *domain.CheckoutSession) {
+	session.FinishPayment("", "", s.config.MaxPaymentAttempts, time.Now())
+	if err := s.checkoutRepository.UpdateSessionStatus(session, domain.CheckoutSessionStatusProcessing); err != nil {
+		slog.Error("erro ao liberar a sessão de checkout",
+			"error", err,
+			"session_id", session.ID)
+	}
+}
+
+func (s *CheckoutService) CreatePaymentLink(apiKey string, input dto.CreatePaymentLinkInput, actor domain.Actor) (*dto.PaymentLinkOutput, error) {
+	account, err := s.accountService.Authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := dto.ToPaymentLink(input, account.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPaymentLink(link)
+	audit, err := s.auditService.Event(actor, domain.AuditActionPaymentLinkCreated, auditEntityPaymentLink, link.ID, nil, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.SavePaymentLink(link, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (s *CheckoutService) ListPaymentLinks(apiKey string) ([]*dto.PaymentLinkOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.checkoutRepository.FindPaymentLinksByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.PaymentLinkOutput, len(links))
+	for i, link := range links {
+		output[i] = dto.FromPaymentLink(link)
+	}
+	return output, nil
+}
+
+func (s *CheckoutService) DeactivatePaymentLink(id, apiKey string, actor domain.Actor) (*dto.PaymentLinkOutput, error) {
+	link, err := s.checkoutRepository.FindPaymentLinkByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	before := dto.FromPaymentLink(link)
+	if err := link.Deactivate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPaymentLink(link)
+	audit, err := s.auditService.Event(actor, domain.AuditActionPaymentLinkDeactivated, auditEntityPaymentLink, link.ID, before, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.UpdatePaymentLink(link, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// CreateSessionFromLink é público: cada comprador que abre o link recebe a própria sessão
+func (s *CheckoutService) CreateSessionFromLink(linkID string, actor domain.Actor) (*dto.CheckoutSessionOutput, error) {
+	link, err := s.checkoutRepository.FindPaymentLinkByID(linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Links de contas suspensas ou encerradas param de abrir sessões
+	if _, err := s.accountService.FindActiveByID(link.AccountID); err != nil {
+		return nil, err
+	}
+
+	// O link é público: o limite de sessões abertas impede que acessos repetidos encham a
+	// tabela de sessões do lojista
+	now := time.Now()
+	open, err := s.checkoutRepository.CountOpenSessionsByPaymentLinkID(link.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if open >= s.config.MaxLinkOpenSessions {
+		return nil, domain.ErrPaymentLinkSessionLimit
+	}
+
+	session, err := link.NewSession(s.config.SessionTTL, now)
+	if err != nil {
+		return nil, err
+	}
+
+	actor = domain.Actor{Type: domain.ActorTypeCustomer, ID: session.ID, IP: actor.IP}
+	return s.createSession(session, actor, now)
+}
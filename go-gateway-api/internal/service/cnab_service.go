@@ -0,0 +1,394 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/cnab"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const auditEntityCNABFile = "cnab_file"
+
+// cnabFileListLimit limita a listagem de arquivos aos mais recentes
+const cnabFileListLimit = 100
+
+type CNABConfig struct {
+	RemittanceBatchSize int  // máximo de boletos ou saques por remessa
+	PayoutRemittances   bool // saques vão por remessa apenas com PAYOUT_PROVIDER=cnab; nos demais casos o PayoutProcessor os envia
+}
+
+func NewCNABConfig() *CNABConfig {
+	config := &CNABConfig{
+		RemittanceBatchSize: 500,
+		PayoutRemittances:   os.Getenv("PAYOUT_PROVIDER") == "cnab",
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("CNAB_REMITTANCE_BATCH_SIZE")); err == nil && value > 0 {
+		config.RemittanceBatchSize = value
+	}
+
+	return config
+}
+
+// CNABService integra boletos e saques com bancos que trabalham por arquivo: gera as remessas
+// com o que está pendente e aplica os arquivos de retorno
+type CNABService struct {
+	cnabRepository   domain.CNABRepository
+	payoutRepository domain.PayoutRepository
+	boletoService    *BoletoService
+	auditService     *AuditService
+	issuer           domain.BoletoIssuer // beneficiário da cobrança e pagador dos saques
+	config           *CNABConfig
+}
+
+func NewCNABService(
+	cnabRepository domain.CNABRepository,
+	payoutRepository domain.PayoutRepository,
+	boletoService *BoletoService,
+	auditService *AuditService,
+	issuer domain.BoletoIssuer,
+	config *CNABConfig,
+) *CNABService {
+	return &CNABService{
+		cnabRepository:   cnabRepository,
+		payoutRepository: payoutRepository,
+		boletoService:    boletoService,
+		auditService:     auditService,
+		issuer:           issuer,
+		config:           config,
+	}
+}
+
+// GenerateRemittance gera a remessa com os boletos ainda não enviados ou com os saques
+// pendentes para contas bancárias. Os saques incluídos passam a in_transit.
+func (s *CNABService) GenerateRemittance(input dto.CNABRemittanceInput, actor domain.Actor) (*dto.CNABFileOutput, error) {
+	kind := domain.CNABFileKind(input.Kind)
+	layout := domain.CNABLayout(input.Layout)
+	if layout == "" {
+		layout = domain.CNABLayout240
+	}
+
+	if !layout.Valid() {
+		return nil, domain.ErrInvalidCNABLayout
+	}
+
+	var file *domain.CNABFile
+	var err error
+	switch kind {
+	case domain.CNABFileKindBoleto:
+		file, err = s.boletoRemittance(layout, actor)
+	case domain.CNABFileKindPayout:
+		if layout != domain.CNABLayout240 {
+			return nil, domain.ErrInvalidCNABLayout
+		}
+		// O PayoutProcessor também envia os saques pendentes; os dois não podem pagar o mesmo saque
+		if !s.config.PayoutRemittances {
+			return nil, domain.ErrCNABPayoutsDisabled
+		}
+		file, err = s.payoutRemittance(actor)
+	default:
+		return nil, domain.ErrInvalidCNABKind
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("remessa CNAB gerada",
+		"file_id", file.ID,
+		"kind", file.Kind,
+		"layout", file.Layout,
+		"sequence", file.Sequence,
+		"records", file.RecordCount)
+	return dto.FromCNABFile(file), nil
+}
+
+func (s *CNABService) boletoRemittance(layout domain.CNABLayout, actor domain.Actor) (*domain.CNABFile, error) {
+	boletos, err := s.cnabRepository.FindBoletosToRemit(s.config.RemittanceBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(boletos) == 0 {
+		return nil, domain.ErrNothingToRemit
+	}
+
+	sequence, err := s.cnabRepository.NextRemittanceSequence()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	content, err := cnab.BoletoRemittance(layout, s.issuer, sequence, boletos, now)
+	if err != nil {
+		return nil, err
+	}
+
+	file := domain.NewCNABRemittance(domain.CNABFileKindBoleto, layout, sequence, content, len(boletos), now)
+	audit, err := s.auditService.Event(actor, domain.AuditActionCNABRemittanceGenerated, auditEntityCNABFile, file.ID, nil, cnabFileAuditState(file))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cnabRepository.CreateRemittance(file, boletos, nil, audit); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *CNABService) payoutRemittance(actor domain.Actor) (*domain.CNABFile, error) {
+	entries, err := s.cnabRepository.FindPayoutsToRemit(s.config.RemittanceBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, domain.ErrNothingToRemit
+	}
+
+	// O "seu número" da remessa é a referência usada para localizar o saque no retorno
+	payouts := make([]*domain.Payout, len(entries))
+	before := make([]map[string]any, len(entries))
+	for i, entry := range entries {
+		payouts[i] = entry.Payout
+		before[i] = payoutAuditState(entry.Payout)
+		if err := entry.Payout.MarkInTransit(domain.CNABPayoutReference(entry.Payout)); err != nil {
+			return nil, err
+		}
+	}
+
+	sequence, err := s.cnabRepository.NextRemittanceSequence()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	content := cnab.PayoutRemittance(s.issuer, sequence, entries, now)
+	file := domain.NewCNABRemittance(domain.CNABFileKindPayout, domain.CNABLayout240, sequence, content, len(entries), now)
+	generated, err := s.auditService.Event(actor, domain.AuditActionCNABRemittanceGenerated, auditEntityCNABFile, file.ID, nil, cnabFileAuditState(file))
+	if err != nil {
+		return nil, err
+	}
+
+	audit := []*domain.AuditEvent{generated}
+	for i, payout := range payouts {
+		event, err := s.auditService.Event(actor, domain.AuditActionPayoutStatusChanged, auditEntityPayout, payout.ID, before[i], payoutAuditState(payout))
+		if err != nil {
+			return nil, err
+		}
+		audit = append(audit, event)
+	}
+
+	if err := s.cnabRepository.CreateRemittance(file, nil, payouts, audit...); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// ProcessReturn aplica o arquivo de retorno do banco. O mesmo conteúdo enviado de novo não é
+// reaplicado: o resultado do primeiro processamento é devolvido com duplicate verdadeiro.
+// Erros de leitura ou de aplicação de cada linha não interrompem as demais e ficam
+// registrados no arquivo.
+func (s *CNABService) ProcessReturn(fileName string, content []byte, actor domain.Actor) (*dto.CNABFileOutput, bool, error) {
+	file := domain.NewCNABReturn(fileName, string(content), time.Now())
+
+	existing, err := s.cnabRepository.FindByChecksum(domain.CNABFileTypeReturn, file.Checksum)
+	if err == nil {
+		return s.duplicateReturn(existing)
+	}
+
+	if err != domain.ErrCNABFileNotFound {
+		return nil, false, err
+	}
+
+	parsed, lineErrors, err := cnab.ParseReturn(file.Content)
+	if err != nil {
+		return nil, false, err
+	}
+
+	applied := 0
+	for _, boleto := range parsed.Boletos {
+		ok, err := s.applyBoletoReturn(parsed.BankCode, boleto, actor)
+		if err != nil {
+			lineErrors = append(lineErrors, domain.CNABLineError{Line: boleto.Line, Message: err.Error()})
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	for _, payout := range parsed.Payouts {
+		ok, err := s.applyPayoutReturn(payout, actor)
+		if err != nil {
+			lineErrors = append(lineErrors, domain.CNABLineError{Line: payout.Line, Message: err.Error()})
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	file.Finish(parsed.Kind, parsed.Layout, applied, lineErrors)
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionCNABReturnProcessed, auditEntityCNABFile, file.ID, nil, cnabFileAuditState(file))
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Envios simultâneos do mesmo arquivo são seguros: cada item só muda de status uma vez
+	if err := s.cnabRepository.CreateReturn(file, audit); err == domain.ErrDuplicateCNABFile {
+		existing, err := s.cnabRepository.FindByChecksum(domain.CNABFileTypeReturn, file.Checksum)
+		if err != nil {
+			return nil, false, err
+		}
+		return s.duplicateReturn(existing)
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	slog.Info("retorno CNAB processado",
+		"file_id", file.ID,
+		"kind", file.Kind,
+		"layout", file.Layout,
+		"applied", file.RecordCount,
+		"errors", len(file.Errors))
+	return dto.FromCNABFile(file), false, nil
+}
+
+func (s *CNABService) duplicateReturn(file *domain.CNABFile) (*dto.CNABFileOutput, bool, error) {
+	slog.Info("retorno CNAB já processado", "file_id", file.ID, "checksum", file.Checksum)
+	output := dto.FromCNABFile(file)
+	output.Duplicate = true
+	return output, true, nil
+}
+
+// applyBoletoReturn trata a ocorrência de um título; ok indica que ela alterou ou confirmou
+// o boleto
+func (s *CNABService) applyBoletoReturn(bankCode string, boleto cnab.BoletoReturn, actor domain.Actor) (bool, error) {
+	switch boleto.Occurrence {
+	case cnab.BoletoOccurrencePaid, cnab.BoletoOccurrencePaidAfterWriteOff:
+		err := s.boletoService.ConfirmPayment(&domain.BoletoPayment{
+			BankCode:  bankCode,
+			OurNumber: boleto.OurNumber,
+			Amount:    boleto.PaidAmount,
+			PaidAt:    boleto.Date,
+		}, actor)
+		if err != nil {
+			return false, fmt.Errorf("boleto %s: %w", boleto.OurNumber, err)
+		}
+		return true, nil
+	case cnab.BoletoOccurrenceRegistered:
+		return true, nil
+	case cnab.BoletoOccurrenceRejected:
+		return false, fmt.Errorf("boleto %s rejected by the bank, reasons %q", boleto.OurNumber, boleto.Reasons)
+	default:
+		slog.Info("ocorrência de boleto ignorada", "our_number", boleto.OurNumber, "occurrence", boleto.Occurrence)
+		return false, nil
+	}
+}
+
+// applyPayoutReturn conclui o saque enviado em remessa. Saques que já saíram de in_transit
+// foram tratados por um retorno anterior e são ignorados.
+func (s *CNABService) applyPayoutReturn(result cnab.PayoutReturn, actor domain.Actor) (bool, error) {
+	payout, err := s.cnabRepository.FindPayoutByReference(result.Reference)
+	if err != nil {
+		return false, fmt.Errorf("payout %s: %w", result.Reference, err)
+	}
+
+	if payout.Status != domain.PayoutStatusInTransit {
+		slog.Info("retorno de saque já concluído ignorado", "payout_id", payout.ID, "status", payout.Status)
+		return false, nil
+	}
+
+	// Os códigos seguintes ao primeiro apenas detalham a ocorrência
+	occurrence := result.Occurrences
+	if len(occurrence) > 2 {
+		occurrence = occurrence[:2]
+	}
+
+	before := payoutAuditState(payout)
+	switch occurrence {
+	case cnab.PayoutOccurrenceScheduled:
+		return true, nil
+	case cnab.PayoutOccurrencePaid:
+		if err := payout.MarkPaid(); err != nil {
+			return false, err
+		}
+
+		audit, err := s.auditService.Event(actor, domain.AuditActionPayoutStatusChanged, auditEntityPayout, payout.ID, before, payoutAuditState(payout))
+		if err != nil {
+			return false, err
+		}
+
+		if err := s.payoutRepository.UpdateStatus(payout, domain.PayoutStatusInTransit, audit); err != nil {
+			return false, fmt.Errorf("payout %s: %w", result.Reference, err)
+		}
+		slog.Info("saque pago", "payout_id", payout.ID, "amount", payout.Amount)
+	default:
+		if err := payout.MarkFailed("cnab occurrence " + result.Occurrences); err != nil {
+			return false, err
+		}
+
+		audit, err := s.auditService.Event(actor, domain.AuditActionPayoutStatusChanged, auditEntityPayout, payout.ID, before, payoutAuditState(payout))
+		if err != nil {
+			return false, err
+		}
+
+		// O valor reservado volta para o saldo
+		if err := s.payoutRepository.Fail(payout, domain.PayoutStatusInTransit, audit); err != nil {
+			return false, fmt.Errorf("payout %s: %w", result.Reference, err)
+		}
+		slog.Warn("saque falhou, valor devolvido ao saldo",
+			"payout_id", payout.ID,
+			"amount", payout.Amount,
+			"reason", payout.FailureReason)
+	}
+
+	return true, nil
+}
+
+func (s *CNABService) GetFile(id string) (*dto.CNABFileOutput, error) {
+	file, err := s.cnabRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromCNABFile(file), nil
+}
+
+// Content retorna o arquivo como foi gerado ou recebido, para envio ao banco ou conferência
+func (s *CNABService) Content(id string) (*domain.CNABFile, error) {
+	return s.cnabRepository.FindByID(id)
+}
+
+func (s *CNABService) ListFiles() ([]*dto.CNABFileOutput, error) {
+	files, err := s.cnabRepository.List(cnabFileListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.CNABFileOutput, len(files))
+	for i, file := range files {
+		output[i] = dto.FromCNABFile(file)
+	}
+	return output, nil
+}
+
+func cnabFileAuditState(file *domain.CNABFile) map[string]any {
+	return map[string]any{
+		"type":         file.Type,
+		"kind":         file.Kind,
+		"layout":       file.Layout,
+		"sequence":     file.Sequence,
+		"file_name":    file.FileName,
+		"checksum":     file.Checksum,
+		"status":       file.Status,
+		"record_count": file.RecordCount,
+		"errors":       len(file.Errors),
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const auditEntityCoupon = "coupon"
+
+// CouponService mantém os cupons de desconto do lojista e os resolve na emissão das faturas
+type CouponService struct {
+	couponRepository domain.CouponRepository
+	accountService   *AccountService
+	auditService     *AuditService
+}
+
+func NewCouponService(couponRepository domain.CouponRepository, accountService *AccountService, auditService *AuditService) *CouponService {
+	return &CouponService{
+		couponRepository: couponRepository,
+		accountService:   accountService,
+		auditService:     auditService,
+	}
+}
+
+func (s *CouponService) Create(apiKey string, input dto.CreateCouponInput, actor domain.Actor) (*dto.CouponOutput, error) {
+	account, err := s.accountService.Authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon, err := dto.ToCoupon(input, account.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromCoupon(coupon)
+	audit, err := s.auditService.Event(actor, domain.AuditActionCouponCreated, auditEntityCoupon, coupon.ID, nil, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepository.Save(coupon, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (s *CouponService) List(apiKey string) ([]*dto.CouponOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, err := s.couponRepository.FindByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.CouponOutput, len(coupons))
+	for i, coupon := range coupons {
+		output[i] = dto.FromCoupon(coupon)
+	}
+	return output, nil
+}
+
+func (s *CouponService) Deactivate(id, apiKey string, actor domain.Actor) (*dto.CouponOutput, error) {
+	coupon, err := s.couponRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if coupon.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	before := dto.FromCoupon(coupon)
+	if err := coupon.Deactivate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	output := dto.FromCoupon(coupon)
+	audit, err := s.auditService.Event(actor, domain.AuditActionCouponDeactivated, auditEntityCoupon, coupon.ID, before, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepository.Update(coupon, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// discountFor busca o cupom da conta pelo código digitado e gera o desconto da fatura
+func (s *CouponService) discountFor(accountID, code string, currency domain.Currency) (*domain.Discount, error) {
+	coupon, err := s.couponRepository.FindByCode(accountID, domain.NormalizeCouponCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	return coupon.Apply(currency, time.Now())
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const (
+	auditEntityCustomer      = "customer"
+	auditEntityPaymentMethod = "payment_method"
+)
+
+// CustomerService cadastra os clientes dos lojistas e os cartões salvos usados nas assinaturas
+type CustomerService struct {
+	customerRepository domain.CustomerRepository
+	accountService     *AccountService
+	auditService       *AuditService
+	payerConfig        *PayerConfig
+}
+
+func NewCustomerService(
+	customerRepository domain.CustomerRepository,
+	accountService *AccountService,
+	auditService *AuditService,
+	payerConfig *PayerConfig,
+) *CustomerService {
+	return &CustomerService{
+		customerRepository: customerRepository,
+		accountService:     accountService,
+		auditService:       auditService,
+		payerConfig:        payerConfig,
+	}
+}
+
+func (s *CustomerService) Create(apiKey string, input dto.CreateCustomerInput, actor domain.Actor) (*dto.CustomerOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := dto.ToCustomer(input, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// O documento vai para as faturas das assinaturas, indexado pelo mesmo HMAC dos pagadores
+	if customer.Document != "" {
+		customer.DocumentHash = domain.HashTaxID(s.payerConfig.DocumentHashKey, customer.Document)
+	}
+
+	output := dto.FromCustomer(customer)
+	audit, err := s.auditService.Event(actor, domain.AuditActionCustomerCreated, auditEntityCustomer, customer.ID, nil, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.customerRepository.Save(customer, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (s *CustomerService) List(apiKey string) ([]*dto.CustomerOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	customers, err := s.customerRepository.FindByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.CustomerOutput, len(customers))
+	for i, customer := range customers {
+		output[i] = dto.FromCustomer(customer)
+	}
+	return output, nil
+}
+
+func (s *CustomerService) GetByID(id, apiKey string) (*dto.CustomerOutput, error) {
+	customer, err := s.findOwned(id, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromCustomer(customer), nil
+}
+
+// AddPaymentMethod salva um cartão do cliente; apenas a bandeira, os últimos dígitos e a
+// validade são gravados
+func (s *CustomerService) AddPaymentMethod(customerID, apiKey string, input dto.CreatePaymentMethodInput, actor domain.Actor) (*dto.PaymentMethodOutput, error) {
+	customer, err := s.findOwned(customerID, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := domain.NewCardPaymentMethod(customer, dto.ToCreditCard(input), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPaymentMethod(method)
+	audit, err := s.auditService.Event(actor, domain.AuditActionPaymentMethodAdded, auditEntityPaymentMethod, method.ID, nil, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.customerRepository.SavePaymentMethod(method, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (s *CustomerService) ListPaymentMethods(customerID, apiKey string) ([]*dto.PaymentMethodOutput, error) {
+	customer, err := s.findOwned(customerID, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := s.customerRepository.FindPaymentMethodsByCustomerID(customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.PaymentMethodOutput, len(methods))
+	for i, method := range methods {
+		output[i] = dto.FromPaymentMethod(method)
+	}
+	return output, nil
+}
+
+func (s *CustomerService) findOwned(id, apiKey string) (*domain.Customer, error) {
+	customer, err := s.customerRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	return customer, nil
+}
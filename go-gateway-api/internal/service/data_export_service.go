@@ -0,0 +1,324 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+// exportedTables liga cada tabela com account_id ao campo da exportação que a contém; uma
+// tabela nova da conta precisa entrar aqui (e em ExportAccount) ou em unexportedTables
+var exportedTables = map[string]string{
+	"invoices":            "invoices",
+	"receivables":         "invoices",
+	"pix_charges":         "invoices",
+	"boletos":             "invoices",
+	"invoice_fees":        "invoice_fees",
+	"balance_adjustments": "balance_adjustments",
+	"ledger_entries":      "ledger_entries",
+	"payout_destinations": "payout_destinations",
+	"payouts":             "payouts",
+	"fee_rules":           "fee_rules",
+	"anticipations":       "anticipations",
+	"customers":           "customers",
+	"payment_methods":     "payment_methods",
+	"plans":               "plans",
+	"subscriptions":       "subscriptions",
+	"payment_links":       "payment_links",
+	"checkout_sessions":   "checkout_sessions",
+	"coupons":             "coupons",
+	"receipt_settings":    "receipt_settings",
+}
+
+// unexportedTables são as tabelas da conta deixadas de fora de propósito, com o motivo
+var unexportedTables = map[string]string{
+	"card_tokens": "cartão cifrado de uso único, apagado no uso e vencido em minutos",
+}
+
+// DataExportService monta a exportação completa dos dados de uma conta
+type DataExportService struct {
+	accountRepository         domain.AccountRepository
+	invoiceRepository         domain.InvoiceRepository
+	payoutRepository          domain.PayoutRepository
+	receivableRepository      domain.ReceivableRepository
+	pixRepository             domain.PixRepository
+	boletoRepository          domain.BoletoRepository
+	feeRepository             domain.FeeRepository
+	anticipationRepository    domain.AnticipationRepository
+	customerRepository        domain.CustomerRepository
+	subscriptionRepository    domain.SubscriptionRepository
+	checkoutRepository        domain.CheckoutRepository
+	couponRepository          domain.CouponRepository
+	receiptSettingsRepository domain.ReceiptSettingsRepository
+}
+
+func NewDataExportService(
+	accountRepository domain.AccountRepository,
+	invoiceRepository domain.InvoiceRepository,
+	payoutRepository domain.PayoutRepository,
+	receivableRepository domain.ReceivableRepository,
+	pixRepository domain.PixRepository,
+	boletoRepository domain.BoletoRepository,
+	feeRepository domain.FeeRepository,
+	anticipationRepository domain.AnticipationRepository,
+	customerRepository domain.CustomerRepository,
+	subscriptionRepository domain.SubscriptionRepository,
+	checkoutRepository domain.CheckoutRepository,
+	couponRepository domain.CouponRepository,
+	receiptSettingsRepository domain.ReceiptSettingsRepository,
+) *DataExportService {
+	return &DataExportService{
+		accountRepository:         accountRepository,
+		invoiceRepository:         invoiceRepository,
+		payoutRepository:          payoutRepository,
+		receivableRepository:      receivableRepository,
+		pixRepository:             pixRepository,
+		boletoRepository:          boletoRepository,
+		feeRepository:             feeRepository,
+		anticipationRepository:    anticipationRepository,
+		customerRepository:        customerRepository,
+		subscriptionRepository:    subscriptionRepository,
+		checkoutRepository:        checkoutRepository,
+		couponRepository:          couponRepository,
+		receiptSettingsRepository: receiptSettingsRepository,
+	}
+}
+
+func (s *DataExportService) ExportAccount(accountID string) (*dto.AccountExportOutput, error) {
+	account, err := s.accountRepository.FindByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &dto.AccountExportOutput{
+		ExportedAt: time.Now(),
+		Account:    dto.FromAccount(account),
+	}
+
+	// A API key é uma credencial, não um dado pessoal
+	output.Account.APIKey = ""
+
+	if output.Invoices, err = s.exportInvoices(accountID); err != nil {
+		return nil, err
+	}
+
+	fees, err := s.feeRepository.FindInvoiceFeesByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.InvoiceFees = make([]*dto.InvoiceFeeOutput, len(fees))
+	for i, fee := range fees {
+		output.InvoiceFees[i] = dto.FromInvoiceFee(fee)
+	}
+
+	adjustments, err := s.accountRepository.FindAdjustmentsByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.BalanceAdjustments = make([]dto.BalanceAdjustmentOutput, len(adjustments))
+	for i, adjustment := range adjustments {
+		output.BalanceAdjustments[i] = dto.FromBalanceAdjustment(adjustment)
+	}
+
+	entries, err := s.accountRepository.FindLedgerEntriesByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.LedgerEntries = make([]*dto.LedgerEntryOutput, len(entries))
+	for i, entry := range entries {
+		output.LedgerEntries[i] = dto.FromLedgerEntry(entry)
+	}
+
+	destinations, err := s.payoutRepository.FindDestinationsByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.PayoutDestinations = make([]*dto.PayoutDestinationOutput, len(destinations))
+	for i, destination := range destinations {
+		output.PayoutDestinations[i] = dto.FromPayoutDestination(destination)
+	}
+
+	payouts, err := s.payoutRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.Payouts = make([]*dto.PayoutOutput, len(payouts))
+	for i, payout := range payouts {
+		output.Payouts[i] = dto.FromPayout(payout)
+	}
+
+	rules, err := s.feeRepository.FindRulesByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.FeeRules = dto.FromFeeRules(rules)
+
+	if output.Anticipations, err = s.exportAnticipations(accountID); err != nil {
+		return nil, err
+	}
+
+	customers, err := s.customerRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.Customers = make([]*dto.CustomerOutput, len(customers))
+	for i, customer := range customers {
+		output.Customers[i] = dto.FromCustomer(customer)
+	}
+
+	methods, err := s.customerRepository.FindPaymentMethodsByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.PaymentMethods = make([]*dto.PaymentMethodOutput, len(methods))
+	for i, method := range methods {
+		output.PaymentMethods[i] = dto.FromPaymentMethod(method)
+	}
+
+	plans, err := s.subscriptionRepository.FindPlansByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.Plans = make([]*dto.PlanOutput, len(plans))
+	for i, plan := range plans {
+		output.Plans[i] = dto.FromPlan(plan)
+	}
+
+	subscriptions, err := s.subscriptionRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.Subscriptions = make([]*dto.SubscriptionOutput, len(subscriptions))
+	for i, subscription := range subscriptions {
+		output.Subscriptions[i] = dto.FromSubscription(subscription)
+	}
+
+	links, err := s.checkoutRepository.FindPaymentLinksByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.PaymentLinks = make([]*dto.PaymentLinkOutput, len(links))
+	for i, link := range links {
+		output.PaymentLinks[i] = dto.FromPaymentLink(link)
+	}
+
+	sessions, err := s.checkoutRepository.FindSessionsByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.CheckoutSessions = make([]*dto.CheckoutSessionOutput, len(sessions))
+	for i, session := range sessions {
+		output.CheckoutSessions[i] = dto.FromCheckoutSession(session, output.ExportedAt)
+	}
+
+	coupons, err := s.couponRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output.Coupons = make([]*dto.CouponOutput, len(coupons))
+	for i, coupon := range coupons {
+		output.Coupons[i] = dto.FromCoupon(coupon)
+	}
+
+	// Contas que nunca personalizaram os recibos não têm o que exportar
+	settings, err := s.receiptSettingsRepository.FindByAccountID(accountID)
+	if err != nil && err != domain.ErrReceiptSettingsNotFound {
+		return nil, err
+	}
+
+	if settings != nil {
+		output.ReceiptSettings = dto.FromReceiptSettings(settings)
+	}
+
+	return output, nil
+}
+
+// exportInvoices monta as faturas com os recebíveis, a cobrança Pix e o boleto de cada uma
+func (s *DataExportService) exportInvoices(accountID string) ([]*dto.InvoiceOutput, error) {
+	invoices, err := s.invoiceRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	receivables, err := s.receivableRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	receivablesByInvoice := make(map[string][]*domain.Receivable)
+	for _, receivable := range receivables {
+		receivablesByInvoice[receivable.InvoiceID] = append(receivablesByInvoice[receivable.InvoiceID], receivable)
+	}
+
+	charges, err := s.pixRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	chargesByInvoice := make(map[string]*domain.PixCharge, len(charges))
+	for _, charge := range charges {
+		chargesByInvoice[charge.InvoiceID] = charge
+	}
+
+	boletos, err := s.boletoRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	boletosByInvoice := make(map[string]*domain.Boleto, len(boletos))
+	for _, boleto := range boletos {
+		boletosByInvoice[boleto.InvoiceID] = boleto
+	}
+
+	output := make([]*dto.InvoiceOutput, len(invoices))
+	for i, invoice := range invoices {
+		output[i] = dto.FromInvoice(invoice)
+
+		if receivables := receivablesByInvoice[invoice.ID]; len(receivables) > 0 {
+			output[i].Receivables = dto.FromReceivables(receivables)
+		}
+
+		if charge, ok := chargesByInvoice[invoice.ID]; ok {
+			output[i].Pix = dto.FromPixCharge(charge)
+		}
+
+		if boleto, ok := boletosByInvoice[invoice.ID]; ok {
+			output[i].Boleto = dto.FromBoleto(boleto)
+		}
+	}
+
+	return output, nil
+}
+
+// exportAnticipations busca cada antecipação com os recebíveis antecipados
+func (s *DataExportService) exportAnticipations(accountID string) ([]*dto.AnticipationOutput, error) {
+	anticipations, err := s.anticipationRepository.FindByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.AnticipationOutput, len(anticipations))
+	for i, anticipation := range anticipations {
+		anticipation, err := s.anticipationRepository.FindByID(anticipation.ID)
+		if err != nil {
+			return nil, err
+		}
+		output[i] = dto.FromAnticipation(anticipation)
+	}
+
+	return output, nil
+}
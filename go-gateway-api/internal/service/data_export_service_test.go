@@ -0,0 +1,98 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+var (
+	sqlComment       = regexp.MustCompile(`--[^\n]*`)
+	createTable      = regexp.MustCompile(`(?is)^\s*CREATE TABLE (?:IF NOT EXISTS )?(\w+)\s*\((.*)\)\s*$`)
+	alterTable       = regexp.MustCompile(`(?is)^\s*ALTER TABLE (\w+)\s`)
+	accountIDColumn  = regexp.MustCompile(`(?im)^\s*account_id\s`)
+	addAccountColumn = regexp.MustCompile(`(?i)\bADD COLUMN (?:IF NOT EXISTS )?account_id\s`)
+)
+
+// accountScopedTables lê as migrations e retorna as tabelas com a coluna account_id
+func accountScopedTables(t *testing.T) map[string]bool {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("nenhuma migration encontrada")
+	}
+
+	tables := make(map[string]bool)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, statement := range strings.Split(sqlComment.ReplaceAllString(string(content), ""), ";") {
+			if match := createTable.FindStringSubmatch(statement); match != nil {
+				if accountIDColumn.MatchString(match[2]) {
+					tables[strings.ToLower(match[1])] = true
+				}
+				continue
+			}
+
+			if match := alterTable.FindStringSubmatch(statement); match != nil && addAccountColumn.MatchString(statement) {
+				tables[strings.ToLower(match[1])] = true
+			}
+		}
+	}
+
+	return tables
+}
+
+func TestExportCoversAccountScopedTables(t *testing.T) {
+	tables := accountScopedTables(t)
+
+	for table := range tables {
+		_, exported := exportedTables[table]
+		_, skipped := unexportedTables[table]
+		if !exported && !skipped {
+			t.Errorf("tabela %s tem account_id mas não está na exportação da conta nem em unexportedTables", table)
+		}
+		if exported && skipped {
+			t.Errorf("tabela %s está em exportedTables e em unexportedTables", table)
+		}
+	}
+
+	for table := range exportedTables {
+		if !tables[table] {
+			t.Errorf("exportedTables lista %s, que não é uma tabela com account_id", table)
+		}
+	}
+
+	for table := range unexportedTables {
+		if !tables[table] {
+			t.Errorf("unexportedTables lista %s, que não é uma tabela com account_id", table)
+		}
+	}
+}
+
+func TestExportedTablesHaveExportField(t *testing.T) {
+	fields := make(map[string]bool)
+	output := reflect.TypeOf(dto.AccountExportOutput{})
+	for i := 0; i < output.NumField(); i++ {
+		name, _, _ := strings.Cut(output.Field(i).Tag.Get("json"), ",")
+		fields[name] = true
+	}
+
+	for table, field := range exportedTables {
+		if !fields[field] {
+			t.Errorf("tabela %s é exportada em %q, que não é um campo de AccountExportOutput", table, field)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+type FeeConfig struct {
+	DefaultPercentage  float64 // taxa aplicada às contas sem regra para a transação
+	DefaultFixedAmount float64
+	FXMarkupPercentage float64 // spread sobre o valor convertido das faturas em outra moeda
+	PlatformAccountID  string  // conta que recebe a receita das taxas
+}
+
+func NewFeeConfig() *FeeConfig {
+	config := &FeeConfig{
+		DefaultPercentage:  2.99,
+		DefaultFixedAmount: 0,
+		FXMarkupPercentage: 2,
+		PlatformAccountID:  os.Getenv("PLATFORM_ACCOUNT_ID"),
+	}
+
+	if value, err := strconv.ParseFloat(os.Getenv("FEE_DEFAULT_PERCENTAGE"), 64); err == nil && value >= 0 && value <= 100 {
+		config.DefaultPercentage = value
+	}
+
+	if value, err := strconv.ParseFloat(os.Getenv("FEE_DEFAULT_FIXED_AMOUNT"), 64); err == nil && value >= 0 {
+		config.DefaultFixedAmount = value
+	}
+
+	if value, err := strconv.ParseFloat(os.Getenv("FEE_FX_MARKUP_PERCENTAGE"), 64); err == nil && value >= 0 && value <= 100 {
+		config.FXMarkupPercentage = value
+	}
+
+	return config
+}
+
+// FeeService mantém as regras de MDR das contas e calcula as taxas de uma fatura aprovada
+type FeeService struct {
+	feeRepository     domain.FeeRepository
+	accountRepository domain.AccountRepository
+	auditService      *AuditService
+	config            *FeeConfig
+}
+
+func NewFeeService(
+	feeRepository domain.FeeRepository,
+	accountRepository domain.AccountRepository,
+	auditService *AuditService,
+	config *FeeConfig,
+) *FeeService {
+	if config.PlatformAccountID == "" {
+		slog.Warn("PLATFORM_ACCOUNT_ID não configurado, a receita das taxas não será creditada")
+	}
+
+	return &FeeService{
+		feeRepository:     feeRepository,
+		accountRepository: accountRepository,
+		auditService:      auditService,
+		config:            config,
+	}
+}
+
+// FeesFor calcula as taxas de uma fatura aprovada agora pelas regras vigentes da conta,
+// incluindo os juros do parcelamento e o spread de câmbio. A cotação já deve estar travada.
+func (s *FeeService) FeesFor(invoice *domain.Invoice) ([]*domain.InvoiceFee, error) {
+	rules, err := s.feeRepository.FindRulesByAccountID(invoice.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := domain.FeeSchedule{
+		Rules: rules,
+		Default: domain.FeeRule{
+			Percentage:  s.config.DefaultPercentage,
+			FixedAmount: s.config.DefaultFixedAmount,
+		},
+	}
+
+	fees := []*domain.InvoiceFee{schedule.MDR(invoice)}
+	if interest := domain.InterestFee(invoice); interest != nil {
+		fees = append(fees, interest)
+	}
+	if markup := domain.FXMarkupFee(invoice, s.config.FXMarkupPercentage); markup != nil {
+		fees = append(fees, markup)
+	}
+
+	return fees, nil
+}
+
+// PlatformAccountFor retorna a conta que recebe a receita cobrada na moeda informada. A receita
+// em uma moeda diferente da conta da plataforma fica registrada nas linhas de taxa, mas não é
+// creditada, para não somar moedas diferentes no mesmo saldo.
+func (s *FeeService) PlatformAccountFor(currency domain.Currency) (string, error) {
+	if s.config.PlatformAccountID == "" {
+		return "", nil
+	}
+
+	account, err := s.accountRepository.FindByID(s.config.PlatformAccountID)
+	if err != nil {
+		return "", err
+	}
+
+	if account.Currency != currency {
+		slog.Warn("receita em moeda diferente da conta da plataforma não creditada",
+			"platform_account_id", account.ID,
+			"currency", currency)
+		return "", nil
+	}
+
+	return account.ID, nil
+}
+
+func (s *FeeService) ListRules(accountID string) ([]*dto.FeeRuleOutput, error) {
+	if _, err := s.accountRepository.FindByID(accountID); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.feeRepository.FindRulesByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromFeeRules(rules), nil
+}
+
+// ReplaceRules troca as regras da conta; vale apenas para as próximas aprovações
+func (s *FeeService) ReplaceRules(accountID string, input dto.FeeRulesInput, actor domain.Actor) ([]*dto.FeeRuleOutput, error) {
+	if _, err := s.accountRepository.FindByID(accountID); err != nil {
+		return nil, err
+	}
+
+	rules, err := dto.ToFeeRules(input, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.feeRepository.FindRulesByAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromFeeRules(rules)
+	audit, err := s.auditService.Event(actor, domain.AuditActionFeeRulesUpdated, auditEntityAccount, accountID,
+		dto.FromFeeRules(before), output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.feeRepository.ReplaceRules(accountID, rules, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
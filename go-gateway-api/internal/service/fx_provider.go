@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+// FXRateProvider fornece as cotações de mercado. Rate retorna domain.ErrExchangeRateNotFound
+// quando o par não é cotado; o FXService tenta então o par inverso.
+type FXRateProvider interface {
+	Rate(ctx context.Context, base, quote domain.Currency) (*domain.ExchangeRate, error)
+}
+
+// DatabaseFXRateProvider lê as cotações importadas para a tabela fx_rates
+type DatabaseFXRateProvider struct {
+	repository domain.FXRepository
+}
+
+func NewDatabaseFXRateProvider(repository domain.FXRepository) *DatabaseFXRateProvider {
+	return &DatabaseFXRateProvider{repository: repository}
+}
+
+func (p *DatabaseFXRateProvider) Rate(ctx context.Context, base, quote domain.Currency) (*domain.ExchangeRate, error) {
+	return p.repository.FindRate(base, quote)
+}
+
+// FileFXRateProvider lê as cotações de um arquivo CSV com as colunas base, quote e rate
+// (ex.: "USD,BRL,5.4321"). O arquivo é relido quando é modificado, e a data das cotações é
+// a da última modificação.
+type FileFXRateProvider struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	rates   map[[2]domain.Currency]float64
+}
+
+func NewFileFXRateProvider(path string) *FileFXRateProvider {
+	return &FileFXRateProvider{path: path}
+}
+
+func (p *FileFXRateProvider) Rate(ctx context.Context, base, quote domain.Currency) (*domain.ExchangeRate, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+
+	rate, found := p.rates[[2]domain.Currency{base, quote}]
+	if !found {
+		return nil, domain.ErrExchangeRateNotFound
+	}
+
+	return &domain.ExchangeRate{Base: base, Quote: quote, Rate: rate, Source: "file", UpdatedAt: p.modTime}, nil
+}
+
+// reload lê o arquivo de novo apenas se ele mudou desde a última leitura
+func (p *FileFXRateProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+
+	if p.rates != nil && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	file, err := os.Open(p.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	rates := make(map[[2]domain.Currency]float64)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// Cabeçalho opcional
+		if strings.EqualFold(record[0], "base") {
+			continue
+		}
+
+		base, quote := domain.Currency(strings.ToUpper(record[0])), domain.Currency(strings.ToUpper(record[1]))
+		rate, err := strconv.ParseFloat(record[2], 64)
+		if !base.Valid() || !quote.Valid() || err != nil || rate <= 0 {
+			slog.Warn("linha inválida no arquivo de cotações ignorada", "path", p.path, "record", record)
+			continue
+		}
+
+		rates[[2]domain.Currency{base, quote}] = rate
+	}
+
+	p.rates = rates
+	p.modTime = info.ModTime()
+	return nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+type FXConfig struct {
+	MaxRateAge time.Duration // cotações do provedor mais antigas que isso não são usadas
+}
+
+func NewFXConfig() *FXConfig {
+	config := &FXConfig{
+		MaxRateAge: 24 * time.Hour,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("FX_MAX_RATE_AGE")); err == nil && value > 0 {
+		config.MaxRateAge = value
+	}
+
+	return config
+}
+
+// FXService fornece a cotação usada para converter as faturas para a moeda da conta.
+// Cotações manuais vigentes prevalecem sobre o provedor, e um par sem cotação direta usa o
+// inverso do par oposto.
+type FXService struct {
+	repository   domain.FXRepository
+	provider     FXRateProvider
+	auditService *AuditService
+	config       *FXConfig
+}
+
+func NewFXService(repository domain.FXRepository, provider FXRateProvider, auditService *AuditService, config *FXConfig) *FXService {
+	return &FXService{
+		repository:   repository,
+		provider:     provider,
+		auditService: auditService,
+		config:       config,
+	}
+}
+
+// Rate retorna a cotação vigente de base em quote; ErrExchangeRateNotFound quando não há
+// cotação manual nem cotação recente do provedor
+func (s *FXService) Rate(ctx context.Context, base, quote domain.Currency) (*domain.ExchangeRate, error) {
+	now := time.Now()
+	if base == quote {
+		return domain.IdentityRate(base, now), nil
+	}
+
+	override, err := s.activeOverride(base, quote, now)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		return override, nil
+	}
+
+	inverseOverride, err := s.activeOverride(quote, base, now)
+	if err != nil {
+		return nil, err
+	}
+	if inverseOverride != nil {
+		return inverseOverride.Inverse(), nil
+	}
+
+	rate, err := s.provider.Rate(ctx, base, quote)
+	if err == domain.ErrExchangeRateNotFound {
+		if rate, err = s.provider.Rate(ctx, quote, base); err == nil {
+			rate = rate.Inverse()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if now.Sub(rate.UpdatedAt) > s.config.MaxRateAge {
+		slog.Warn("cotação desatualizada não utilizada",
+			"base", base,
+			"quote", quote,
+			"updated_at", rate.UpdatedAt)
+		return nil, domain.ErrExchangeRateNotFound
+	}
+
+	return rate, nil
+}
+
+func (s *FXService) activeOverride(base, quote domain.Currency, now time.Time) (*domain.ExchangeRate, error) {
+	override, err := s.repository.FindOverride(base, quote)
+	if err == domain.ErrExchangeRateOverrideNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !override.Active(now) {
+		return nil, nil
+	}
+	return override.ExchangeRate(), nil
+}
+
+// LockRate trava na fatura a cotação vigente para a moeda da conta
+func (s *FXService) LockRate(invoice *domain.Invoice) error {
+	rate, err := s.Rate(context.Background(), invoice.Currency, invoice.SettlementCurrency)
+	if err != nil {
+		return err
+	}
+
+	return invoice.LockExchangeRate(rate)
+}
+
+// Quote é a consulta dos operadores à cotação que seria usada agora
+func (s *FXService) Quote(base, quote string) (*dto.ExchangeRateOutput, error) {
+	baseCurrency, quoteCurrency, err := parseCurrencyPair(base, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := s.Rate(context.Background(), baseCurrency, quoteCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromExchangeRate(rate), nil
+}
+
+func (s *FXService) ListOverrides() ([]*dto.ExchangeRateOverrideOutput, error) {
+	overrides, err := s.repository.ListOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.ExchangeRateOverrideOutput, len(overrides))
+	for i, override := range overrides {
+		output[i] = dto.FromExchangeRateOverride(override)
+	}
+	return output, nil
+}
+
+// SetOverride fixa a cotação do par, substituindo a cotação manual anterior. Vale apenas para
+// as próximas aprovações: faturas já aprovadas mantêm a cotação travada.
+func (s *FXService) SetOverride(base, quote string, input dto.ExchangeRateOverrideInput, actor domain.Actor) (*dto.ExchangeRateOverrideOutput, error) {
+	baseCurrency, quoteCurrency, err := parseCurrencyPair(base, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	override, err := domain.NewExchangeRateOverride(baseCurrency, quoteCurrency, input.Rate, input.Reason, actor.ID, input.ExpiresAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var before any
+	previous, err := s.repository.FindOverride(baseCurrency, quoteCurrency)
+	if err != nil && err != domain.ErrExchangeRateOverrideNotFound {
+		return nil, err
+	}
+	if previous != nil {
+		before = dto.FromExchangeRateOverride(previous)
+	}
+
+	output := dto.FromExchangeRateOverride(override)
+	audit, err := s.auditService.Event(actor, domain.AuditActionFXRateOverridden, auditEntityFXRate, fxPairID(baseCurrency, quoteCurrency), before, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.SaveOverride(override, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// DeleteOverride remove a cotação manual; o par volta a usar o provedor
+func (s *FXService) DeleteOverride(base, quote string, actor domain.Actor) error {
+	baseCurrency, quoteCurrency, err := parseCurrencyPair(base, quote)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.repository.FindOverride(baseCurrency, quoteCurrency)
+	if err != nil {
+		return err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionFXRateOverrideRemoved, auditEntityFXRate, fxPairID(baseCurrency, quoteCurrency),
+		dto.FromExchangeRateOverride(previous), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.DeleteOverride(baseCurrency, quoteCurrency, audit); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const auditEntityFXRate = "fx_rate"
+
+// fxPairID identifica o par na auditoria, ex.: USD/BRL
+func fxPairID(base, quote domain.Currency) string {
+	return string(base) + "/" + string(quote)
+}
+
+func parseCurrencyPair(base, quote string) (domain.Currency, domain.Currency, error) {
+	baseCurrency := domain.Currency(strings.ToUpper(base))
+	quoteCurrency := domain.Currency(strings.ToUpper(quote))
+	if !baseCurrency.Valid() || !quoteCurrency.Valid() {
+		return "", "", domain.ErrUnsupportedCurrency
+	}
+	return baseCurrency, quoteCurrency, nil
+}
@@ -2,72 +2,220 @@ package service
 
 import (
 	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain/events"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
 )
 
+type InstallmentConfig struct {
+	InterestTable domain.InterestTable // percentual total de juros por número de parcelas
+}
+
+// NewInstallmentConfig monta a tabela Price a partir da taxa mensal e aplica as sobrescritas de
+// INSTALLMENT_INTEREST_TABLE, no formato "parcelas:percentual" separado por vírgulas (ex.: "2:2.5,3:3.8")
+func NewInstallmentConfig() *InstallmentConfig {
+	monthlyRate := 1.99
+	if value, err := strconv.ParseFloat(os.Getenv("INSTALLMENT_MONTHLY_INTEREST_RATE"), 64); err == nil && value >= 0 {
+		monthlyRate = value
+	}
+
+	table := domain.NewInterestTable(monthlyRate)
+	for _, entry := range strings.Split(os.Getenv("INSTALLMENT_INTEREST_TABLE"), ",") {
+		installments, rate, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found {
+			continue
+		}
+
+		n, err := strconv.Atoi(installments)
+		if err != nil || n < 1 || n > domain.MaxInstallments {
+			slog.Warn("entrada inválida na tabela de juros ignorada", "entry", entry)
+			continue
+		}
+
+		value, err := strconv.ParseFloat(rate, 64)
+		if err != nil || value < 0 {
+			slog.Warn("entrada inválida na tabela de juros ignorada", "entry", entry)
+			continue
+		}
+
+		table[n] = value
+	}
+
+	return &InstallmentConfig{InterestTable: table}
+}
+
+type PayerConfig struct {
+	// DocumentHashKey é a chave do HMAC que indexa os documentos dos pagadores; trocá-la
+	// invalida as buscas das faturas já gravadas
+	DocumentHashKey []byte
+}
+
+func NewPayerConfig() *PayerConfig {
+	config := &PayerConfig{
+		DocumentHashKey: []byte(os.Getenv("PAYER_DOCUMENT_HASH_KEY")),
+	}
+
+	if len(config.DocumentHashKey) == 0 {
+		slog.Warn("PAYER_DOCUMENT_HASH_KEY não configurado, documentos dos pagadores serão indexados sem chave")
+	}
+
+	return config
+}
+
 type InvoiceService struct {
 	invoiceRepository domain.InvoiceRepository
 	accountService    AccountService
 	kafkaProducer     KafkaProducerInterface
+	settlementService *SettlementService
+	fxService         *FXService
+	feeService        *FeeService
+	auditService      *AuditService
+	installmentConfig *InstallmentConfig
+	pixService        *PixService
+	boletoService     *BoletoService
+	payerConfig       *PayerConfig
+	couponService     *CouponService
 }
 
 func NewInvoiceService(
 	invoiceRepository domain.InvoiceRepository,
 	accountService AccountService,
 	kafkaProducer KafkaProducerInterface,
+	settlementService *SettlementService,
+	fxService *FXService,
+	feeService *FeeService,
+	auditService *AuditService,
+	installmentConfig *InstallmentConfig,
+	pixService *PixService,
+	boletoService *BoletoService,
+	payerConfig *PayerConfig,
+	couponService *CouponService,
 ) *InvoiceService {
 	return &InvoiceService{
 		invoiceRepository: invoiceRepository,
 		accountService:    accountService,
 		kafkaProducer:     kafkaProducer,
+		settlementService: settlementService,
+		fxService:         fxService,
+		feeService:        feeService,
+		auditService:      auditService,
+		installmentConfig: installmentConfig,
+		pixService:        pixService,
+		boletoService:     boletoService,
+		payerConfig:       payerConfig,
+		couponService:     couponService,
 	}
 }
 
-func (s *InvoiceService) Create(input dto.CreateInvoiceInput) (*dto.InvoiceOutput, error) {
-	accountOutput, err := s.accountService.FindByAPIKey(input.APIKey)
+func (s *InvoiceService) Create(input dto.CreateInvoiceInput, actor domain.Actor) (*dto.InvoiceOutput, error) {
+	// Contas suspensas ou encerradas não podem emitir faturas
+	accountOutput, err := s.accountService.Authenticate(input.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.create(input, accountOutput, actor, s.invoiceRepository.Save)
+}
+
+// saveInvoiceFunc grava a fatura de cartão criada por create com a aprovação e a auditoria
+type saveInvoiceFunc func(invoice *domain.Invoice, approval *domain.InvoiceApproval, audit ...*domain.AuditEvent) error
+
+// create emite a fatura para uma conta já autenticada; também usado no pagamento das sessões
+// de checkout, que gravam a sessão concluída junto com a fatura de cartão por meio de save.
+// Faturas Pix e boleto são gravadas pelos seus próprios serviços.
+func (s *InvoiceService) create(input dto.CreateInvoiceInput, accountOutput *dto.AccountOutput, actor domain.Actor, save saveInvoiceFunc) (*dto.InvoiceOutput, error) {
+	payer, err := s.payerFor(input)
 	if err != nil {
 		return nil, err
 	}
 
+	// Com itens, o valor da fatura é o total calculado do detalhamento
+	breakdown, err := s.breakdownFor(input, accountOutput.ID)
+	if err != nil {
+		return nil, err
+	}
+	if breakdown != nil {
+		if input.Amount, err = breakdown.ResolveAmount(input.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	switch input.PaymentType {
+	case domain.PaymentTypePix:
+		return s.createPix(input, accountOutput, payer, breakdown, actor)
+	case domain.PaymentTypeBoleto:
+		return s.createBoleto(input, accountOutput, payer, breakdown, actor)
+	}
+
 	invoice, err := dto.ToInvoice(input, accountOutput.ID)
 	if err != nil {
 		return nil, err
 	}
+	invoice.Breakdown = breakdown
+
+	rate, err := s.applyCurrency(invoice, input, accountOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	// O pagador entra na análise de risco e no evento enviado para a análise de fraude
+	invoice.Payer = payer
+
+	// Os juros são definidos na criação para que o comprador saiba o valor das parcelas
+	err = invoice.ApplyInstallments(input.Installments, domain.InterestMode(input.InterestMode), s.installmentConfig.InterestTable)
+	if err != nil {
+		return nil, err
+	}
+
+	approval, err := s.decide(invoice, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionInvoiceCreated, auditEntityInvoice, invoice.ID, nil, invoiceAuditState(invoice))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := save(invoice, approval, audit); err != nil {
+		return nil, err
+	}
 
-	if err := invoice.Process(); err != nil {
+	output := dto.FromInvoice(invoice)
+	if approval != nil {
+		output.Receivables = dto.FromReceivables(approval.Receivables)
+	}
+	return output, nil
+}
+
+// decide processa a fatura de cartão: as pendentes vão para a análise de fraude e as aprovadas
+// na hora têm as taxas e os recebíveis calculados. Também usado nas cobranças das assinaturas.
+func (s *InvoiceService) decide(invoice *domain.Invoice, rate *domain.ExchangeRate) (*domain.InvoiceApproval, error) {
+	if err := invoice.Process(rate.Rate); err != nil {
 		return nil, err
 	}
 
 	// Se o status for pending, significa que é uma transação de alto valor
 	if invoice.Status == domain.StatusPending {
 		// Criar e publicar evento de transação pendente
-		pendingTransaction := events.NewPendingTransaction(
-			invoice.AccountID,
-			invoice.ID,
-			invoice.Amount,
-		)
+		pendingTransaction := events.NewPendingTransaction(invoice)
 
 		if err := s.kafkaProducer.SendingPendingTransaction(context.Background(), *pendingTransaction); err != nil {
 			return nil, err
 		}
+		invoice.MarkPublished()
 	}
 
-	// Para transações aprovadas, atualizar o saldo
 	if invoice.Status == domain.StatusApproved {
-		_, err = s.accountService.UpdateBalance(input.APIKey, invoice.Amount)
-		if err != nil {
-			return nil, err
-		}
+		return s.approve(invoice)
 	}
-
-	if err := s.invoiceRepository.Save(invoice); err != nil {
-		return nil, err
-	}
-
-	return dto.FromInvoice(invoice), nil
+	return nil, nil
 }
 
 func (s *InvoiceService) GetByID(id, apiKey string) (*dto.InvoiceOutput, error) {
@@ -85,57 +233,360 @@ func (s *InvoiceService) GetByID(id, apiKey string) (*dto.InvoiceOutput, error)
 		return nil, domain.ErrUnauthorizedAccess
 	}
 
-	return dto.FromInvoice(invoice), nil
+	receivables, err := s.settlementService.ReceivablesFor(invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromInvoice(invoice)
+	output.Receivables = dto.FromReceivables(receivables)
+
+	charge, err := s.pixService.ChargeFor(invoice)
+	if err != nil {
+		return nil, err
+	}
+	if charge != nil {
+		output.Pix = dto.FromPixCharge(charge)
+	}
+
+	boleto, err := s.boletoService.BoletoFor(invoice)
+	if err != nil {
+		return nil, err
+	}
+	if boleto != nil {
+		output.Boleto = dto.FromBoleto(boleto)
+	}
+	return output, nil
 }
 
-func (s *InvoiceService) ListByAccount(accountID string) ([]*dto.InvoiceOutput, error) {
-	invoices, err := s.invoiceRepository.FindByAccountID(accountID)
+// createPix cria uma fatura Pix aguardando o pagamento; ela não passa pela análise de fraude
+// e é aprovada quando o PSP confirma o pagamento
+func (s *InvoiceService) createPix(input dto.CreateInvoiceInput, account *dto.AccountOutput, payer *domain.Payer, breakdown *domain.InvoiceBreakdown, actor domain.Actor) (*dto.InvoiceOutput, error) {
+	invoice, err := domain.NewPixInvoice(account.ID, input.Amount, input.Description)
 	if err != nil {
 		return nil, err
 	}
+	invoice.Payer = payer
+	invoice.Breakdown = breakdown
 
-	output := make([]*dto.InvoiceOutput, len(invoices))
-	for i, invoice := range invoices {
-		output[i] = dto.FromInvoice(invoice)
+	if _, err := s.applyCurrency(invoice, input, account); err != nil {
+		return nil, err
+	}
+
+	// Pix é sempre à vista; ApplyInstallments recusa parcelamento
+	err = invoice.ApplyInstallments(input.Installments, domain.InterestMode(input.InterestMode), s.installmentConfig.InterestTable)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionInvoiceCreated, auditEntityInvoice, invoice.ID, nil, invoiceAuditState(invoice))
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := s.pixService.Create(invoice, domain.PixChargeType(input.PixType), time.Duration(input.PixExpiresIn)*time.Second, audit)
+	if err != nil {
+		return nil, err
 	}
+
+	output := dto.FromInvoice(invoice)
+	output.Pix = dto.FromPixCharge(charge)
 	return output, nil
 }
 
+// ListByAccount lista as faturas da conta; com payerDocument, apenas as desse pagador
+func (s *InvoiceService) ListByAccount(accountID, payerDocument string) ([]*dto.InvoiceOutput, error) {
+	var invoices []*domain.Invoice
+	var err error
+	if payerDocument != "" {
+		invoices, err = s.findByPayerDocument(payerDocument, accountID)
+	} else {
+		invoices, err = s.invoiceRepository.FindByAccountID(accountID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return fromInvoices(invoices), nil
+}
+
 // ListByAccountAPIKey lista as faturas de uma conta através de uma API Key
-func (s *InvoiceService) ListByAccountAPIKey(apiKey string) ([]*dto.InvoiceOutput, error) {
+func (s *InvoiceService) ListByAccountAPIKey(apiKey, payerDocument string) ([]*dto.InvoiceOutput, error) {
 	accountOutput, err := s.accountService.FindByAPIKey(apiKey)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.ListByAccount(accountOutput.ID)
+	return s.ListByAccount(accountOutput.ID, payerDocument)
 }
 
-// ProcessTransactionResult processa o resultado de uma transação após análise de fraude
-func (s *InvoiceService) ProcessTransactionResult(invoiceID string, status domain.Status) error {
+// ListByPayerDocument busca as faturas de um pagador em todas as contas, para os operadores
+func (s *InvoiceService) ListByPayerDocument(payerDocument string) ([]*dto.InvoiceOutput, error) {
+	invoices, err := s.findByPayerDocument(payerDocument, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return fromInvoices(invoices), nil
+}
+
+// findByPayerDocument busca pelo HMAC do documento, já que o documento não é indexado em claro
+func (s *InvoiceService) findByPayerDocument(payerDocument, accountID string) ([]*domain.Invoice, error) {
+	document := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, payerDocument)
+
+	if !domain.ValidTaxID(document) {
+		return nil, domain.ErrInvalidTaxID
+	}
+
+	return s.invoiceRepository.FindByPayerDocumentHash(domain.HashTaxID(s.payerConfig.DocumentHashKey, document), accountID)
+}
+
+// applyCurrency define a moeda da fatura (BRL quando não informada) e a moeda da conta, que
+// recebe o valor convertido. Retorna a cotação atual, o que também recusa na criação as moedas
+// sem cotação disponível, antes de o comprador pagar.
+func (s *InvoiceService) applyCurrency(invoice *domain.Invoice, input dto.CreateInvoiceInput, account *dto.AccountOutput) (*domain.ExchangeRate, error) {
+	currency, err := domain.ParseCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invoice.SetCurrency(currency, domain.Currency(account.Currency)); err != nil {
+		return nil, err
+	}
+
+	return s.fxService.Rate(context.Background(), invoice.Currency, invoice.SettlementCurrency)
+}
+
+// payerFor valida o pagador informado e calcula o hash usado nas buscas
+func (s *InvoiceService) payerFor(input dto.CreateInvoiceInput) (*domain.Payer, error) {
+	payer, err := dto.ToPayer(input)
+	if err != nil || payer == nil {
+		return nil, err
+	}
+
+	payer.DocumentHash = domain.HashTaxID(s.payerConfig.DocumentHashKey, payer.Document)
+	return payer, nil
+}
+
+// breakdownFor calcula o detalhamento da fatura a partir dos itens, do desconto (avulso ou do
+// cupom da conta) e dos impostos; retorna nil quando a fatura não tem itens
+func (s *InvoiceService) breakdownFor(input dto.CreateInvoiceInput, accountID string) (*domain.InvoiceBreakdown, error) {
+	items, discount, taxes, err := dto.ToLineItems(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.CouponCode != "" {
+		if len(items) == 0 {
+			return nil, domain.ErrLineItemsRequired
+		}
+
+		// O desconto fixo do cupom precisa estar na moeda da fatura
+		currency, err := domain.ParseCurrency(input.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		if discount, err = s.couponService.discountFor(accountID, input.CouponCode, currency); err != nil {
+			return nil, err
+		}
+	}
+
+	return domain.NewInvoiceBreakdown(items, discount, taxes)
+}
+
+func fromInvoices(invoices []*domain.Invoice) []*dto.InvoiceOutput {
+	output := make([]*dto.InvoiceOutput, len(invoices))
+	for i, invoice := range invoices {
+		output[i] = dto.FromInvoice(invoice)
+	}
+	return output
+}
+
+// ProcessTransactionResult processa o resultado de uma transação após análise de fraude.
+// Reentregas do mesmo evento (ou de um resultado para uma fatura já decidida) são ignoradas.
+func (s *InvoiceService) ProcessTransactionResult(eventID, invoiceID string, status domain.Status, actor domain.Actor) error {
+	_, err := s.applyTransactionResult(eventID, invoiceID, status, actor, nil)
+	return err
+}
+
+// applyTransactionResult decide a fatura pendente; applied é false quando outro resultado já
+// a decidiu ou o evento é repetido. review é a ação do revisor nas decisões manuais e audit os
+// eventos de auditoria do chamador, gravados na mesma transação da mudança de status.
+func (s *InvoiceService) applyTransactionResult(eventID, invoiceID string, status domain.Status, actor domain.Actor, review *domain.ReviewActionEntry, audit ...*domain.AuditEvent) (bool, error) {
 	invoice, err := s.invoiceRepository.FindByID(invoiceID)
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	if invoice.Status != domain.StatusPending {
+		slog.Info("fatura já processada, evento ignorado",
+			"event_id", eventID,
+			"invoice_id", invoiceID,
+			"status", invoice.Status)
+		return false, nil
+	}
+
+	before := invoiceAuditState(invoice)
 	if err := invoice.UpdateStatus(status); err != nil {
-		return err
+		return false, err
+	}
+
+	var approval *domain.InvoiceApproval
+	if invoice.Status == domain.StatusApproved {
+		approval, err = s.approve(invoice)
+		if err != nil {
+			return false, err
+		}
 	}
 
-	if err := s.invoiceRepository.UpdateStatus(invoice); err != nil {
-		return err
+	after := invoiceAuditState(invoice)
+	after["event_id"] = eventID
+	changed, err := s.auditService.Event(actor, domain.AuditActionInvoiceStatusChanged, auditEntityInvoice, invoice.ID, before, after)
+	if err != nil {
+		return false, err
 	}
 
-	if status == domain.StatusApproved {
-		account, err := s.accountService.FindByID(invoice.AccountID)
+	err = s.invoiceRepository.ApplyTransactionResult(eventID, invoice, approval, review, append([]*domain.AuditEvent{changed}, audit...)...)
+	if err == domain.ErrEventAlreadyProcessed {
+		slog.Info("evento duplicado ignorado",
+			"event_id", eventID,
+			"invoice_id", invoiceID)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// approve calcula as taxas da fatura aprovada; apenas o valor líquido entra no saldo
+// pendente da conta, dividido em um recebível por parcela, e a receita das taxas vai para
+// a conta da plataforma
+func (s *InvoiceService) approve(invoice *domain.Invoice) (*domain.InvoiceApproval, error) {
+	return approveInvoice(s.fxService, s.feeService, s.settlementService, invoice)
+}
+
+// approveInvoice é compartilhado pelas formas de pagamento confirmadas fora da análise de
+// fraude (ex.: Pix). A cotação da moeda da fatura para a da conta é travada aqui, e as taxas
+// e os recebíveis são calculados sobre o valor convertido.
+func approveInvoice(fxService *FXService, feeService *FeeService, settlementService *SettlementService, invoice *domain.Invoice) (*domain.InvoiceApproval, error) {
+	if err := fxService.LockRate(invoice); err != nil {
+		return nil, err
+	}
+
+	fees, err := feeService.FeesFor(invoice)
+	if err != nil {
+		return nil, err
+	}
+	invoice.ApplyFees(fees)
+
+	receivables, err := settlementService.ScheduleFor(invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	platformAccountID, err := feeService.PlatformAccountFor(invoice.SettlementCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.InvoiceApproval{
+		Fees:              fees,
+		PlatformAccountID: platformAccountID,
+		Receivables:       receivables,
+	}, nil
+}
+
+// createBoleto cria uma fatura de boleto aguardando o pagamento; ela não passa pela análise de
+// fraude e é aprovada quando o banco informa a liquidação
+func (s *InvoiceService) createBoleto(input dto.CreateInvoiceInput, account *dto.AccountOutput, payer *domain.Payer, breakdown *domain.InvoiceBreakdown, actor domain.Actor) (*dto.InvoiceOutput, error) {
+	invoice, err := domain.NewBoletoInvoice(account.ID, input.Amount, input.Description)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Payer = payer
+	invoice.Breakdown = breakdown
+
+	if _, err := s.applyCurrency(invoice, input, account); err != nil {
+		return nil, err
+	}
+
+	// Boleto é sempre à vista; ApplyInstallments recusa parcelamento
+	err = invoice.ApplyInstallments(input.Installments, domain.InterestMode(input.InterestMode), s.installmentConfig.InterestTable)
+	if err != nil {
+		return nil, err
+	}
+
+	boletoInput := BoletoInput{
+		Payer:              domain.BoletoPayer{Name: input.PayerName, Document: input.PayerDocument},
+		FinePercentage:     input.FinePercentage,
+		InterestPercentage: input.InterestPercentage,
+	}
+
+	if input.DueDate != "" {
+		boletoInput.DueDate, err = time.Parse(time.DateOnly, input.DueDate)
 		if err != nil {
-			return err
+			return nil, domain.ErrInvalidBoletoDueDate
 		}
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionInvoiceCreated, auditEntityInvoice, invoice.ID, nil, invoiceAuditState(invoice))
+	if err != nil {
+		return nil, err
+	}
+
+	boleto, err := s.boletoService.Create(invoice, boletoInput, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromInvoice(invoice)
+	output.Boleto = dto.FromBoleto(boleto)
+	return output, nil
+}
+
+const auditEntityInvoice = "invoice"
+
+// invoiceAuditState resume a fatura para a auditoria, sem dados do cartão e com o documento
+// do pagador mascarado
+func invoiceAuditState(invoice *domain.Invoice) map[string]any {
+	state := map[string]any{
+		"account_id":   invoice.AccountID,
+		"amount":       invoice.Amount,
+		"status":       invoice.Status,
+		"installments": invoice.Installments,
+		"total_amount": invoice.TotalAmount,
+		"currency":     invoice.Currency,
+		"fee_amount":   invoice.FeeAmount,
+		"net_amount":   invoice.NetAmount,
+	}
+
+	// A cotação só existe depois da aprovação
+	if invoice.ExchangeRate > 0 {
+		state["settlement_currency"] = invoice.SettlementCurrency
+		state["exchange_rate"] = invoice.ExchangeRate
+		state["settlement_amount"] = invoice.SettlementAmount
+	}
+
+	if invoice.Payer != nil {
+		state["payer_document"] = invoice.Payer.MaskedDocument()
+	}
 
-		if _, err := s.accountService.UpdateBalance(account.APIKey, invoice.Amount); err != nil {
-			return err
+	if breakdown := invoice.Breakdown; breakdown != nil {
+		state["subtotal"] = breakdown.Subtotal
+		state["discount_amount"] = breakdown.DiscountAmount()
+		state["tax_amount"] = breakdown.TaxAmount
+		if breakdown.Discount != nil && breakdown.Discount.CouponCode != "" {
+			state["coupon_code"] = breakdown.Discount.CouponCode
 		}
 	}
 
-	return nil
+	return state
 }
\ No newline at end of file
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain/events"
+)
+
+const pendingInvoiceSweeperLock = "pending_invoice_sweeper"
+
+type SweeperConfig struct {
+	Interval time.Duration // intervalo entre as varreduras
+	SLA      time.Duration // tempo máximo de espera pela análise de fraude a cada publicação
+	// MaxPublishAttempts inclui a publicação feita na criação da fatura; ao atingir o
+	// limite a fatura é expirada em vez de publicada novamente
+	MaxPublishAttempts int
+	BatchSize          int
+}
+
+func NewSweeperConfig() *SweeperConfig {
+	config := &SweeperConfig{
+		Interval:           time.Minute,
+		SLA:                30 * time.Minute,
+		MaxPublishAttempts: 3,
+		BatchSize:          100,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("INVOICE_SWEEP_INTERVAL")); err == nil && value > 0 {
+		config.Interval = value
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("INVOICE_PENDING_SLA")); err == nil && value > 0 {
+		config.SLA = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("INVOICE_MAX_PUBLISH_ATTEMPTS")); err == nil && value > 0 {
+		config.MaxPublishAttempts = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("INVOICE_SWEEP_BATCH_SIZE")); err == nil && value > 0 {
+		config.BatchSize = value
+	}
+
+	return config
+}
+
+// PendingInvoiceSweeper reenvia para a análise de fraude as faturas pendentes sem resposta
+// e expira as que esgotaram as tentativas, publicando invoice.expired para cada uma. Apenas
+// uma réplica executa cada varredura.
+type PendingInvoiceSweeper struct {
+	invoiceRepository domain.InvoiceRepository
+	locker            domain.Locker
+	kafkaProducer     KafkaProducerInterface
+	eventPublisher    EventPublisher
+	auditService      *AuditService
+	config            *SweeperConfig
+}
+
+func NewPendingInvoiceSweeper(
+	invoiceRepository domain.InvoiceRepository,
+	locker domain.Locker,
+	kafkaProducer KafkaProducerInterface,
+	eventPublisher EventPublisher,
+	auditService *AuditService,
+	config *SweeperConfig,
+) *PendingInvoiceSweeper {
+	return &PendingInvoiceSweeper{
+		invoiceRepository: invoiceRepository,
+		locker:            locker,
+		kafkaProducer:     kafkaProducer,
+		eventPublisher:    eventPublisher,
+		auditService:      auditService,
+		config:            config,
+	}
+}
+
+// Run executa a varredura periodicamente até o contexto ser cancelado
+func (s *PendingInvoiceSweeper) Run(ctx context.Context) error {
+	slog.Info("sweeper de faturas pendentes iniciado",
+		"interval", s.config.Interval,
+		"sla", s.config.SLA,
+		"max_publish_attempts", s.config.MaxPublishAttempts)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.Sweep(ctx); err != nil {
+				slog.Error("erro na varredura de faturas pendentes", "error", err)
+			}
+		}
+	}
+}
+
+func (s *PendingInvoiceSweeper) Sweep(ctx context.Context) error {
+	release, acquired, err := s.locker.TryLock(ctx, pendingInvoiceSweeperLock)
+	if err != nil {
+		return err
+	}
+
+	if !acquired {
+		slog.Debug("varredura em execução em outra réplica")
+		return nil
+	}
+	defer release()
+
+	invoices, err := s.invoiceRepository.FindPendingPublishedBefore(time.Now().Add(-s.config.SLA), s.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		if invoice.PublishAttempts < s.config.MaxPublishAttempts {
+			err = s.republish(ctx, invoice)
+		} else {
+			err = s.expire(ctx, invoice)
+		}
+
+		// Uma falha não impede o tratamento das demais faturas
+		if err != nil {
+			slog.Error("erro ao tratar fatura pendente", "error", err, "invoice_id", invoice.ID)
+		}
+	}
+
+	return s.publishExpired(ctx)
+}
+
+func (s *PendingInvoiceSweeper) republish(ctx context.Context, invoice *domain.Invoice) error {
+	pendingTransaction := events.NewPendingTransaction(invoice)
+	if err := s.kafkaProducer.SendingPendingTransaction(ctx, *pendingTransaction); err != nil {
+		return err
+	}
+
+	invoice.MarkPublished()
+	if err := s.invoiceRepository.MarkPublished(invoice); err != nil {
+		if err == domain.ErrInvalidStatus {
+			return nil // o resultado chegou durante a republicação
+		}
+		return err
+	}
+
+	slog.Info("transação pendente republicada",
+		"invoice_id", invoice.ID,
+		"publish_attempts", invoice.PublishAttempts)
+	return nil
+}
+
+func (s *PendingInvoiceSweeper) expire(ctx context.Context, invoice *domain.Invoice) error {
+	before := invoiceAuditState(invoice)
+	if err := invoice.Expire(); err != nil {
+		return err
+	}
+
+	audit, err := s.auditService.Event(domain.NewSystemActor(pendingInvoiceSweeperLock), domain.AuditActionInvoiceStatusChanged,
+		auditEntityInvoice, invoice.ID, before, invoiceAuditState(invoice))
+	if err != nil {
+		return err
+	}
+
+	if err := s.invoiceRepository.Expire(invoice, audit); err != nil {
+		if err == domain.ErrInvalidStatus {
+			return nil // o resultado chegou antes da expiração
+		}
+		return err
+	}
+
+	slog.Warn("fatura expirada sem resposta da análise de fraude",
+		"invoice_id", invoice.ID,
+		"publish_attempts", invoice.PublishAttempts)
+	return nil
+}
+
+// publishExpired publica invoice.expired das faturas expiradas, inclusive das que ficaram
+// pendentes em varreduras anteriores por falha na publicação. A marca só é limpa depois da
+// publicação, então o evento pode ser entregue mais de uma vez, mas nunca é perdido.
+func (s *PendingInvoiceSweeper) publishExpired(ctx context.Context) error {
+	invoices, err := s.invoiceRepository.FindExpiredEventPending(s.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		if err := s.publishExpiredEvent(ctx, invoice); err != nil {
+			slog.Error("erro ao publicar expiração da fatura", "error", err, "invoice_id", invoice.ID)
+		}
+	}
+
+	return nil
+}
+
+func (s *PendingInvoiceSweeper) publishExpiredEvent(ctx context.Context, invoice *domain.Invoice) error {
+	expired := events.NewInvoiceExpired(
+		invoice.AccountID,
+		invoice.ID,
+		invoice.Amount,
+		invoice.PublishAttempts,
+		invoice.CreatedAt,
+		invoice.UpdatedAt,
+	)
+
+	if err := s.eventPublisher.Publish(ctx, invoice.AccountID, events.InvoiceExpiredEventType, events.InvoiceExpiredVersion, expired); err != nil {
+		return err
+	}
+
+	return s.invoiceRepository.MarkExpiredEventPublished(invoice)
+}
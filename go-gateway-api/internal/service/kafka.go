@@ -3,10 +3,15 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain/events"
 	"github.com/segmentio/kafka-go"
 )
@@ -16,21 +21,40 @@ type KafkaProducerInterface interface {
 	Close() error
 }
 
+// EventPublisher publica eventos de domínio em um tópico
+type EventPublisher interface {
+	Publish(ctx context.Context, key, eventType, version string, payload any) error
+}
+
 type KafkaConsumerInterface interface {
 	Consume(ctx context.Context) error
 	Close() error
 }
 
+// Formas de publicar o envelope CloudEvents no Kafka
+const (
+	// ContentModeBinary coloca os atributos do envelope nos headers e mantém o payload
+	// no corpo, compatível com consumidores que ainda esperam o formato antigo
+	ContentModeBinary = "binary"
+	// ContentModeStructured publica o envelope completo como JSON no corpo da mensagem
+	ContentModeStructured = "structured"
+)
+
+// antiFraudActor identifica na auditoria as decisões vindas da análise de fraude
+var antiFraudActor = domain.NewSystemActor("anti-fraud")
+
 type KafkaConfig struct {
-	Brokers []string
-	Topic   string
+	Brokers     []string
+	Topic       string
+	ContentMode string
 }
 
 // WithTopic cria uma nova configuração com um tópico diferente
 func (c *KafkaConfig) WithTopic(topic string) *KafkaConfig {
 	return &KafkaConfig{
-		Brokers: c.Brokers,
-		Topic:   topic,
+		Brokers:     c.Brokers,
+		Topic:       topic,
+		ContentMode: c.ContentMode,
 	}
 }
 
@@ -45,47 +69,71 @@ func NewKafkaConfig() *KafkaConfig {
 		topic = "pending_transactions"
 	}
 
+	contentMode := os.Getenv("KAFKA_EVENT_CONTENT_MODE")
+	if contentMode != ContentModeStructured {
+		contentMode = ContentModeBinary
+	}
+
 	return &KafkaConfig{
-		Brokers: strings.Split(broker, ","),
-		Topic:   topic,
+		Brokers:     strings.Split(broker, ","),
+		Topic:       topic,
+		ContentMode: contentMode,
 	}
 }
 
 type KafkaProducer struct {
-	writer  *kafka.Writer
-	topic   string
-	brokers []string
+	writer      *kafka.Writer
+	topic       string
+	brokers     []string
+	contentMode string
 }
 
 func NewKafkaProducer(config *KafkaConfig) *KafkaProducer {
+	// As mensagens são particionadas pela chave (id da conta), mantendo a ordem por conta.
+	// O CRC32 é o mesmo particionador padrão do librdkafka usado pelo serviço anti-fraude.
 	writer := &kafka.Writer{
 		Addr:     kafka.TCP(config.Brokers...),
 		Topic:    config.Topic,
-		Balancer: &kafka.LeastBytes{},
+		Balancer: &kafka.CRC32Balancer{},
 	}
 
-	slog.Info("kafka producer iniciado", "brokers", config.Brokers, "topic", config.Topic)
+	slog.Info("kafka producer iniciado",
+		"brokers", config.Brokers,
+		"topic", config.Topic,
+		"content_mode", config.ContentMode)
 	return &KafkaProducer{
-		writer:  writer,
-		topic:   config.Topic,
-		brokers: config.Brokers,
+		writer:      writer,
+		topic:       config.Topic,
+		brokers:     config.Brokers,
+		contentMode: config.ContentMode,
 	}
 }
 
 func (s *KafkaProducer) SendingPendingTransaction(ctx context.Context, event events.PendingTransaction) error {
-	value, err := json.Marshal(event)
+	return s.Publish(ctx, event.AccountID, events.PendingTransactionEventType, events.PendingTransactionVersion, event)
+}
+
+// Publish envolve o payload no envelope e o publica usando key como chave de partição
+func (s *KafkaProducer) Publish(ctx context.Context, key, eventType, version string, payload any) error {
+	envelope, err := events.NewEnvelope(eventType, version, payload)
 	if err != nil {
 		slog.Error("erro ao converter evento para json", "error", err)
 		return err
 	}
 
-	msg := kafka.Message{
-		Value: value,
+	msg, err := s.newMessage(envelope)
+	if err != nil {
+		slog.Error("erro ao converter evento para json", "error", err)
+		return err
 	}
+	msg.Key = []byte(key)
 
 	slog.Info("enviando mensagem para o kafka",
 		"topic", s.topic,
-		"message", string(value))
+		"type", eventType,
+		"event_id", envelope.ID,
+		"key", key,
+		"message", string(msg.Value))
 
 	if err := s.writer.WriteMessages(ctx, msg); err != nil {
 		slog.Error("erro ao enviar mensagem para o kafka", "error", err)
@@ -96,75 +144,394 @@ func (s *KafkaProducer) SendingPendingTransaction(ctx context.Context, event eve
 	return nil
 }
 
+// newMessage monta a mensagem conforme o content mode configurado
+func (s *KafkaProducer) newMessage(envelope *events.Envelope) (kafka.Message, error) {
+	if s.contentMode == ContentModeStructured {
+		value, err := json.Marshal(envelope)
+		if err != nil {
+			return kafka.Message{}, err
+		}
+
+		return kafka.Message{
+			Value: value,
+			Headers: []kafka.Header{
+				{Key: events.HeaderContentType, Value: []byte(events.CloudEventsContentType)},
+			},
+		}, nil
+	}
+
+	return kafka.Message{
+		Value:   envelope.Data,
+		Headers: mapToHeaders(envelope.BinaryHeaders()),
+	}, nil
+}
+
 func (s *KafkaProducer) Close() error {
 	slog.Info("fechando conexao com o kafka")
 	return s.writer.Close()
 }
 
+// RetryPolicy define quantas vezes e com qual intervalo o consumer tenta
+// reprocessar uma mensagem antes de enviá-la para a DLQ
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func NewRetryPolicy() *RetryPolicy {
+	policy := &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("KAFKA_CONSUMER_MAX_ATTEMPTS")); err == nil && value > 0 {
+		policy.MaxAttempts = value
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("KAFKA_CONSUMER_INITIAL_BACKOFF")); err == nil && value > 0 {
+		policy.InitialBackoff = value
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("KAFKA_CONSUMER_MAX_BACKOFF")); err == nil && value > 0 {
+		policy.MaxBackoff = value
+	}
+
+	return policy
+}
+
+// Backoff retorna o tempo de espera exponencial após a tentativa informada (começando em 1)
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// WorkerPoolConfig controla o processamento concorrente do consumer. Cada partição é
+// atendida sempre pelo mesmo worker, preservando a ordem dentro da partição.
+type WorkerPoolConfig struct {
+	Workers int
+	// QueueSize é o limite de mensagens aguardando por worker; com a fila cheia o
+	// consumer para de buscar mensagens (backpressure)
+	QueueSize int
+}
+
+func NewWorkerPoolConfig() *WorkerPoolConfig {
+	config := &WorkerPoolConfig{
+		Workers:   4,
+		QueueSize: 100,
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("KAFKA_CONSUMER_CONCURRENCY")); err == nil && value > 0 {
+		config.Workers = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("KAFKA_CONSUMER_QUEUE_SIZE")); err == nil && value > 0 {
+		config.QueueSize = value
+	}
+
+	return config
+}
+
+// messageReader é a parte do kafka.Reader usada pelo consumer
+type messageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
 type KafkaConsumer struct {
-	reader         *kafka.Reader
+	mu     sync.Mutex
+	reader messageReader
+	// newReader cria um reader que retoma o grupo a partir do último offset confirmado
+	newReader      func() messageReader
+	handle         func(ctx context.Context, msg kafka.Message) error
 	topic          string
 	brokers        []string
 	groupID        string
 	invoiceService *InvoiceService
+	dlqProducer    *KafkaDLQProducer
+	retryPolicy    *RetryPolicy
+	poolConfig     *WorkerPoolConfig
 }
 
-func NewKafkaConsumer(config *KafkaConfig, groupID string, invoiceService *InvoiceService) *KafkaConsumer {
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers: config.Brokers,
-		Topic:   config.Topic,
-		GroupID: groupID,
-	})
+func NewKafkaConsumer(
+	config *KafkaConfig,
+	groupID string,
+	invoiceService *InvoiceService,
+	dlqProducer *KafkaDLQProducer,
+	retryPolicy *RetryPolicy,
+	poolConfig *WorkerPoolConfig,
+) *KafkaConsumer {
+	// Sem CommitInterval o commit é síncrono e só acontece via CommitMessages
+	newReader := func() messageReader {
+		return kafka.NewReader(kafka.ReaderConfig{
+			Brokers: config.Brokers,
+			Topic:   config.Topic,
+			GroupID: groupID,
+		})
+	}
 
 	slog.Info("kafka consumer iniciado",
 		"brokers", config.Brokers,
 		"topic", config.Topic,
-		"group_id", groupID)
+		"group_id", groupID,
+		"dlq_topic", dlqProducer.topic,
+		"max_attempts", retryPolicy.MaxAttempts,
+		"workers", poolConfig.Workers)
 
-	return &KafkaConsumer{
-		reader:         reader,
+	consumer := &KafkaConsumer{
+		reader:         newReader(),
+		newReader:      newReader,
 		topic:          config.Topic,
 		brokers:        config.Brokers,
 		groupID:        groupID,
 		invoiceService: invoiceService,
+		dlqProducer:    dlqProducer,
+		retryPolicy:    retryPolicy,
+		poolConfig:     poolConfig,
 	}
+	consumer.handle = consumer.handleMessage
+	return consumer
 }
 
+// Consume busca as mensagens sem commit automático e as distribui entre os workers por
+// partição. O offset só é confirmado depois que a mensagem foi processada com sucesso ou
+// encaminhada para a DLQ; se um worker falhar, todo o consumer é interrompido.
 func (c *KafkaConsumer) Consume(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c.mu.Lock()
+	reader := c.reader
+	c.mu.Unlock()
+
+	queues := make([]chan kafka.Message, c.poolConfig.Workers)
+	workerErrs := make(chan error, c.poolConfig.Workers)
+	var wg sync.WaitGroup
+
+	for i := range queues {
+		queues[i] = make(chan kafka.Message, c.poolConfig.QueueSize)
+		wg.Add(1)
+		go func(queue <-chan kafka.Message) {
+			defer wg.Done()
+			if err := c.work(ctx, reader, queue); err != nil {
+				workerErrs <- err
+				cancel()
+			}
+		}(queues[i])
+	}
+
+	err := c.dispatch(ctx, reader, queues)
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+	close(workerErrs)
+
+	// O erro do worker é a causa real; o do dispatch é só o cancelamento
+	if workerErr, ok := <-workerErrs; ok {
+		return workerErr
+	}
+	return err
+}
+
+// Run mantém o consumer ativo: quando Consume é interrompido por uma falha (DLQ ou commit
+// indisponíveis), ele é reiniciado com espera crescente e um novo reader. O reader antigo já
+// avançou além de todas as mensagens buscadas, inclusive as que estavam nas filas dos
+// workers; o novo retoma do último offset confirmado de cada partição, e assim as mensagens
+// não confirmadas são processadas de novo. Só retorna quando ctx é cancelado.
+func (c *KafkaConsumer) Run(ctx context.Context) {
+	restarts := 0
+	for {
+		started := time.Now()
+		err := c.Consume(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Uma execução mais longa que a espera máxima indica que a falha anterior foi superada
+		if time.Since(started) > c.retryPolicy.MaxBackoff {
+			restarts = 0
+		}
+		restarts++
+
+		backoff := c.retryPolicy.Backoff(restarts)
+		slog.Error("consumer kafka interrompido, reiniciando",
+			"error", err,
+			"restarts", restarts,
+			"backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		c.resetReader()
+	}
+}
+
+// resetReader fecha o reader atual e cria outro a partir dos offsets confirmados no grupo
+func (c *KafkaConsumer) resetReader() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.reader.Close(); err != nil {
+		slog.Error("erro ao fechar o reader do kafka", "error", err)
+	}
+	c.reader = c.newReader()
+}
+
+// dispatch envia cada mensagem para a fila do worker responsável pela partição
+func (c *KafkaConsumer) dispatch(ctx context.Context, reader messageReader, queues []chan kafka.Message) error {
 	for {
-		msg, err := c.reader.ReadMessage(ctx)
+		msg, err := reader.FetchMessage(ctx)
 		if err != nil {
 			slog.Error("erro ao ler mensagem do kafka", "error", err)
 			return err
 		}
 
-		var result events.TransactionResult
-		if err := json.Unmarshal(msg.Value, &result); err != nil {
-			slog.Error("erro ao converter mensagem para TransactionResult", "error", err)
-			continue
+		// Bloqueia enquanto a fila do worker estiver cheia
+		select {
+		case queues[msg.Partition%len(queues)] <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+	}
+}
 
-		slog.Info("mensagem recebida do kafka",
-			"topic", c.topic,
-			"invoice_id", result.InvoiceID,
-			"status", result.Status)
+// work processa as mensagens da fila em ordem, confirmando cada uma antes da próxima
+func (c *KafkaConsumer) work(ctx context.Context, reader messageReader, queue <-chan kafka.Message) error {
+	for msg := range queue {
+		// Mensagens restantes após o cancelamento não são confirmadas; o próximo reader as
+		// busca de novo a partir do offset confirmado
+		if ctx.Err() != nil {
+			return nil
+		}
 
-		// Processa o resultado da transação
-		if err := c.invoiceService.ProcessTransactionResult(result.InvoiceID, result.ToDomainStatus()); err != nil {
-			slog.Error("erro ao processar resultado da transação",
+		if err := c.handle(ctx, msg); err != nil {
+			slog.Error("mensagem não confirmada, o consumer será interrompido",
 				"error", err,
-				"invoice_id", result.InvoiceID,
-				"status", result.Status)
-			continue
+				"partition", msg.Partition,
+				"offset", msg.Offset)
+			return err
 		}
 
-		slog.Info("transação processada com sucesso",
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			slog.Error("erro ao confirmar offset no kafka",
+				"error", err,
+				"partition", msg.Partition,
+				"offset", msg.Offset)
+			return err
+		}
+	}
+	return nil
+}
+
+// handleMessage só retorna erro quando a mensagem não pode ser confirmada
+// (nem processada, nem enviada para a DLQ)
+func (c *KafkaConsumer) handleMessage(ctx context.Context, msg kafka.Message) error {
+	result, err := events.DecodeTransactionResult(headersToMap(msg.Headers), msg.Value)
+	if err != nil {
+		slog.Error("mensagem inválida para TransactionResult", "error", err)
+		return c.dlqProducer.Send(ctx, msg, err, 0)
+	}
+
+	// Já validado em DecodeTransactionResult
+	status, _ := result.ToDomainStatus()
+
+	slog.Info("mensagem recebida do kafka",
+		"topic", c.topic,
+		"event_id", result.IdempotencyKey(),
+		"invoice_id", result.InvoiceID,
+		"status", result.Status)
+
+	attempts, err := c.processWithRetry(ctx, func() error {
+		return c.invoiceService.ProcessTransactionResult(result.IdempotencyKey(), result.InvoiceID, status, antiFraudActor)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		slog.Error("erro ao processar resultado da transação, enviando para a DLQ",
+			"error", err,
 			"invoice_id", result.InvoiceID,
-			"status", result.Status)
+			"status", result.Status,
+			"attempts", attempts)
+		return c.dlqProducer.Send(ctx, msg, err, attempts)
 	}
+
+	slog.Info("transação processada com sucesso",
+		"invoice_id", result.InvoiceID,
+		"status", result.Status,
+		"attempts", attempts)
+	return nil
+}
+
+// processWithRetry executa fn até ter sucesso, até o erro ser permanente ou até esgotar as tentativas
+func (c *KafkaConsumer) processWithRetry(ctx context.Context, fn func() error) (int, error) {
+	var err error
+	attempt := 0
+	for attempt < c.retryPolicy.MaxAttempts {
+		attempt++
+		if err = fn(); err == nil || isPermanentError(err) {
+			return attempt, err
+		}
+
+		if attempt == c.retryPolicy.MaxAttempts {
+			break
+		}
+
+		backoff := c.retryPolicy.Backoff(attempt)
+		slog.Warn("falha ao processar mensagem, tentando novamente",
+			"error", err,
+			"attempt", attempt,
+			"backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+	return attempt, err
+}
+
+// isPermanentError identifica erros que não se resolvem com uma nova tentativa
+func isPermanentError(err error) bool {
+	return errors.Is(err, domain.ErrInvoiceNotFound) ||
+		errors.Is(err, domain.ErrInvalidStatus) ||
+		errors.Is(err, domain.ErrInvalidEvent)
 }
 
 func (c *KafkaConsumer) Close() error {
 	slog.Info("fechando conexao com o kafka consumer")
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.reader.Close()
-}
\ No newline at end of file
+}
+
+func headersToMap(headers []kafka.Header) map[string]string {
+	values := make(map[string]string, len(headers))
+	for _, header := range headers {
+		values[header.Key] = string(header.Value)
+	}
+	return values
+}
+
+func mapToHeaders(values map[string]string) []kafka.Header {
+	headers := make([]kafka.Header, 0, len(values))
+	for key, value := range values {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	return headers
+}
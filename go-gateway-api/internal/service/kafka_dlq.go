@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers adicionados às mensagens enviadas para a dead-letter queue
+const (
+	HeaderDLQOriginalTopic     = "x-dlq-original-topic"
+	HeaderDLQOriginalPartition = "x-dlq-original-partition"
+	HeaderDLQOriginalOffset    = "x-dlq-original-offset"
+	HeaderDLQError             = "x-dlq-error"
+	HeaderDLQAttempts          = "x-dlq-attempts"
+	HeaderDLQFailedAt          = "x-dlq-failed-at"
+	HeaderDLQReplayedAt        = "x-dlq-replayed-at"
+
+	dlqHeaderPrefix = "x-dlq-"
+)
+
+type KafkaDLQProducer struct {
+	writer *kafka.Writer
+	topic  string
+}
+
+func NewKafkaDLQProducer(config *KafkaConfig) *KafkaDLQProducer {
+	// Mantém a chave original para que o replay preserve o particionamento
+	writer := &kafka.Writer{
+		Addr:     kafka.TCP(config.Brokers...),
+		Topic:    config.Topic,
+		Balancer: &kafka.CRC32Balancer{},
+	}
+
+	slog.Info("kafka dlq producer iniciado", "brokers", config.Brokers, "topic", config.Topic)
+	return &KafkaDLQProducer{
+		writer: writer,
+		topic:  config.Topic,
+	}
+}
+
+// Send copia a mensagem original para a DLQ junto com os metadados do erro
+func (p *KafkaDLQProducer) Send(ctx context.Context, msg kafka.Message, cause error, attempts int) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers)+6)
+	headers = append(headers, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: HeaderDLQOriginalTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: HeaderDLQOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: HeaderDLQOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kafka.Header{Key: HeaderDLQError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderDLQFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+
+	dlqMsg := kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+
+	if err := p.writer.WriteMessages(ctx, dlqMsg); err != nil {
+		slog.Error("erro ao enviar mensagem para a DLQ", "error", err, "topic", p.topic)
+		return err
+	}
+
+	slog.Warn("mensagem enviada para a DLQ",
+		"topic", p.topic,
+		"original_topic", msg.Topic,
+		"partition", msg.Partition,
+		"offset", msg.Offset,
+		"error", cause)
+	return nil
+}
+
+func (p *KafkaDLQProducer) Close() error {
+	slog.Info("fechando conexao com o kafka dlq producer")
+	return p.writer.Close()
+}
+
+// KafkaDLQReplayer lê as mensagens da DLQ e as publica novamente no tópico de origem
+type KafkaDLQReplayer struct {
+	reader   *kafka.Reader
+	writer   *kafka.Writer
+	dlqTopic string
+}
+
+func NewKafkaDLQReplayer(config *KafkaConfig, groupID string) *KafkaDLQReplayer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: config.Brokers,
+		Topic:   config.Topic,
+		GroupID: groupID,
+	})
+
+	// Sem tópico fixo: cada mensagem é enviada para o tópico de onde veio
+	writer := &kafka.Writer{
+		Addr:     kafka.TCP(config.Brokers...),
+		Balancer: &kafka.CRC32Balancer{},
+	}
+
+	return &KafkaDLQReplayer{
+		reader:   reader,
+		writer:   writer,
+		dlqTopic: config.Topic,
+	}
+}
+
+// Replay reenvia até limit mensagens (0 = sem limite) e para quando a DLQ fica
+// sem mensagens novas por idleTimeout. targetTopic é usado quando a mensagem não
+// possui o header com o tópico de origem.
+func (r *KafkaDLQReplayer) Replay(ctx context.Context, targetTopic string, limit int, idleTimeout time.Duration) (int, error) {
+	replayed := 0
+	for limit == 0 || replayed < limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, idleTimeout)
+		msg, err := r.reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				slog.Info("nenhuma mensagem nova na DLQ", "topic", r.dlqTopic)
+				return replayed, nil
+			}
+			return replayed, err
+		}
+
+		topic := headerValue(msg.Headers, HeaderDLQOriginalTopic)
+		if topic == "" {
+			topic = targetTopic
+		}
+
+		headers := append(stripDLQHeaders(msg.Headers), kafka.Header{
+			Key:   HeaderDLQReplayedAt,
+			Value: []byte(time.Now().UTC().Format(time.RFC3339)),
+		})
+
+		replayMsg := kafka.Message{
+			Topic:   topic,
+			Key:     msg.Key,
+			Value:   msg.Value,
+			Headers: headers,
+		}
+
+		if err := r.writer.WriteMessages(ctx, replayMsg); err != nil {
+			return replayed, err
+		}
+
+		if err := r.reader.CommitMessages(ctx, msg); err != nil {
+			return replayed, err
+		}
+
+		replayed++
+		slog.Info("mensagem da DLQ reenviada",
+			"topic", topic,
+			"dlq_offset", msg.Offset,
+			"error", headerValue(msg.Headers, HeaderDLQError))
+	}
+	return replayed, nil
+}
+
+func (r *KafkaDLQReplayer) Close() error {
+	slog.Info("fechando conexao com o kafka dlq replayer")
+	if err := r.writer.Close(); err != nil {
+		return err
+	}
+	return r.reader.Close()
+}
+
+func headerValue(headers []kafka.Header, key string) string {
+	for _, header := range headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func stripDLQHeaders(headers []kafka.Header) []kafka.Header {
+	stripped := make([]kafka.Header, 0, len(headers))
+	for _, header := range headers {
+		if !strings.HasPrefix(header.Key, dlqHeaderPrefix) {
+			stripped = append(stripped, header)
+		}
+	}
+	return stripped
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeBroker guarda as mensagens de cada partição e o offset confirmado pelo grupo
+type fakeBroker struct {
+	mu        sync.Mutex
+	messages  map[int][]kafka.Message
+	committed map[int]int64
+}
+
+func newFakeBroker(partitions, perPartition int) *fakeBroker {
+	broker := &fakeBroker{
+		messages:  make(map[int][]kafka.Message),
+		committed: make(map[int]int64),
+	}
+	for partition := 0; partition < partitions; partition++ {
+		for offset := 0; offset < perPartition; offset++ {
+			broker.messages[partition] = append(broker.messages[partition], kafka.Message{
+				Partition: partition,
+				Offset:    int64(offset),
+			})
+		}
+	}
+	return broker
+}
+
+// newReader imita um kafka.Reader com GroupID: começa no offset confirmado de cada partição
+func (b *fakeBroker) newReader() messageReader {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	next := make(map[int]int64, len(b.committed))
+	for partition, offset := range b.committed {
+		next[partition] = offset
+	}
+	return &fakeReader{broker: b, next: next}
+}
+
+func (b *fakeBroker) allCommitted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for partition, messages := range b.messages {
+		if b.committed[partition] != int64(len(messages)) {
+			return false
+		}
+	}
+	return true
+}
+
+type fakeReader struct {
+	broker *fakeBroker
+	next   map[int]int64
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	for {
+		r.broker.mu.Lock()
+		for partition := 0; partition < len(r.broker.messages); partition++ {
+			if offset := r.next[partition]; offset < int64(len(r.broker.messages[partition])) {
+				r.next[partition] = offset + 1
+				msg := r.broker.messages[partition][offset]
+				r.broker.mu.Unlock()
+				return msg, nil
+			}
+		}
+		r.broker.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.broker.mu.Lock()
+	defer r.broker.mu.Unlock()
+
+	for _, msg := range msgs {
+		r.broker.committed[msg.Partition] = msg.Offset + 1
+	}
+	return nil
+}
+
+func (r *fakeReader) Close() error {
+	return nil
+}
+
+func TestKafkaConsumerReprocessesFailedMessageAfterRestart(t *testing.T) {
+	broker := newFakeBroker(2, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type position struct{ partition, offset int }
+	var mu sync.Mutex
+	handled := make(map[position]int)
+	failed := false
+
+	consumer := &KafkaConsumer{
+		reader:      broker.newReader(),
+		newReader:   broker.newReader,
+		retryPolicy: &RetryPolicy{MaxAttempts: 1, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond},
+		poolConfig:  &WorkerPoolConfig{Workers: 2, QueueSize: 10},
+	}
+	consumer.handle = func(ctx context.Context, msg kafka.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		handled[position{msg.Partition, int(msg.Offset)}]++
+
+		// A primeira entrega da mensagem do meio falha como se a DLQ estivesse fora do ar
+		if msg.Partition == 0 && msg.Offset == 1 && !failed {
+			failed = true
+			return errors.New("dlq indisponível")
+		}
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		consumer.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for !broker.allCommitted() {
+		select {
+		case <-deadline:
+			t.Fatalf("offsets não foram todos confirmados: %v", broker.committed)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if handled[position{0, 1}] != 2 {
+		t.Errorf("mensagem que falhou foi processada %d vezes, esperado 2", handled[position{0, 1}])
+	}
+
+	for partition, messages := range broker.messages {
+		for _, msg := range messages {
+			if handled[position{partition, int(msg.Offset)}] == 0 {
+				t.Errorf("mensagem partição %d offset %d nunca foi processada", partition, msg.Offset)
+			}
+		}
+	}
+}
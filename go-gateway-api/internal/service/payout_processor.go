@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+)
+
+const payoutProcessorLock = "payout_processor"
+
+type PayoutProcessorConfig struct {
+	Interval  time.Duration // intervalo entre os ciclos de envio e consulta ao provedor
+	BatchSize int
+}
+
+func NewPayoutProcessorConfig() *PayoutProcessorConfig {
+	config := &PayoutProcessorConfig{
+		Interval:  30 * time.Second,
+		BatchSize: 100,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("PAYOUT_PROCESS_INTERVAL")); err == nil && value > 0 {
+		config.Interval = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("PAYOUT_BATCH_SIZE")); err == nil && value > 0 {
+		config.BatchSize = value
+	}
+
+	return config
+}
+
+// PayoutProcessor move os saques pela máquina de estados: reserva os pendentes e os envia ao
+// provedor (pending -> submitting -> in_transit) e acompanha os enviados até paid ou failed.
+// Apenas uma réplica executa cada ciclo.
+type PayoutProcessor struct {
+	payoutRepository domain.PayoutRepository
+	locker           domain.Locker
+	provider         PayoutProvider
+	auditService     *AuditService
+	config           *PayoutProcessorConfig
+}
+
+func NewPayoutProcessor(
+	payoutRepository domain.PayoutRepository,
+	locker domain.Locker,
+	provider PayoutProvider,
+	auditService *AuditService,
+	config *PayoutProcessorConfig,
+) *PayoutProcessor {
+	return &PayoutProcessor{
+		payoutRepository: payoutRepository,
+		locker:           locker,
+		provider:         provider,
+		auditService:     auditService,
+		config:           config,
+	}
+}
+
+// Run executa os ciclos periodicamente até o contexto ser cancelado
+func (p *PayoutProcessor) Run(ctx context.Context) error {
+	slog.Info("processador de saques iniciado", "interval", p.config.Interval)
+
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := p.Process(ctx); err != nil {
+				slog.Error("erro no processamento de saques", "error", err)
+			}
+		}
+	}
+}
+
+func (p *PayoutProcessor) Process(ctx context.Context) error {
+	release, acquired, err := p.locker.TryLock(ctx, payoutProcessorLock)
+	if err != nil {
+		return err
+	}
+
+	if !acquired {
+		slog.Debug("processamento de saques em execução em outra réplica")
+		return nil
+	}
+	defer release()
+
+	pending, err := p.payoutRepository.FindByStatus(domain.PayoutStatusPending, p.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, payout := range pending {
+		// Uma falha não impede o tratamento dos demais saques
+		if err := p.claim(payout); err != nil {
+			slog.Error("erro ao reservar saque para envio", "error", err, "payout_id", payout.ID)
+		}
+	}
+
+	// Inclui os reservados em ciclos anteriores cujo envio falhou; o Submit é idempotente
+	submitting, err := p.payoutRepository.FindByStatus(domain.PayoutStatusSubmitting, p.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, payout := range submitting {
+		if err := p.submit(ctx, payout); err != nil {
+			slog.Error("erro ao enviar saque ao provedor", "error", err, "payout_id", payout.ID)
+		}
+	}
+
+	inTransit, err := p.payoutRepository.FindByStatus(domain.PayoutStatusInTransit, p.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, payout := range inTransit {
+		if err := p.track(ctx, payout); err != nil {
+			slog.Error("erro ao consultar saque no provedor", "error", err, "payout_id", payout.ID)
+		}
+	}
+
+	return nil
+}
+
+// claim reserva o saque antes do envio ao provedor; a atualização condicional garante que um
+// saque incluído numa remessa CNAB nesse meio tempo não seja enviado de novo
+func (p *PayoutProcessor) claim(payout *domain.Payout) error {
+	before := payoutAuditState(payout)
+	if err := payout.MarkSubmitting(); err != nil {
+		return err
+	}
+
+	audit, err := p.statusChange(payout, before)
+	if err != nil {
+		return err
+	}
+
+	err = p.payoutRepository.UpdateStatus(payout, domain.PayoutStatusPending, audit)
+	if err == domain.ErrInvalidPayoutStatus {
+		slog.Info("saque já saiu de pending, envio ignorado", "payout_id", payout.ID)
+		return nil
+	}
+	return err
+}
+
+func (p *PayoutProcessor) submit(ctx context.Context, payout *domain.Payout) error {
+	destination, err := p.payoutRepository.FindDestinationByID(payout.DestinationID)
+	if err != nil {
+		return err
+	}
+
+	reference, err := p.provider.Submit(ctx, payout, destination)
+	var rejected *PayoutRejectedError
+	if errors.As(err, &rejected) {
+		return p.fail(payout, domain.PayoutStatusSubmitting, rejected.Reason)
+	}
+
+	// Erros transitórios deixam o saque reservado para um novo envio no próximo ciclo
+	if err != nil {
+		return err
+	}
+
+	before := payoutAuditState(payout)
+	if err := payout.MarkInTransit(reference); err != nil {
+		return err
+	}
+
+	audit, err := p.statusChange(payout, before)
+	if err != nil {
+		return err
+	}
+
+	if err := p.payoutRepository.UpdateStatus(payout, domain.PayoutStatusSubmitting, audit); err != nil {
+		return err
+	}
+
+	slog.Info("saque enviado ao provedor", "payout_id", payout.ID, "provider_reference", reference)
+	return nil
+}
+
+func (p *PayoutProcessor) track(ctx context.Context, payout *domain.Payout) error {
+	status, err := p.provider.Status(ctx, payout.ProviderReference)
+	if err != nil {
+		return err
+	}
+
+	switch status.Status {
+	case domain.PayoutStatusPaid:
+		before := payoutAuditState(payout)
+		if err := payout.MarkPaid(); err != nil {
+			return err
+		}
+
+		audit, err := p.statusChange(payout, before)
+		if err != nil {
+			return err
+		}
+
+		if err := p.payoutRepository.UpdateStatus(payout, domain.PayoutStatusInTransit, audit); err != nil {
+			return err
+		}
+
+		slog.Info("saque pago", "payout_id", payout.ID, "amount", payout.Amount)
+		return nil
+	case domain.PayoutStatusFailed:
+		return p.fail(payout, domain.PayoutStatusInTransit, status.FailureReason)
+	default:
+		return nil // ainda em trânsito
+	}
+}
+
+// fail encerra o saque e devolve o valor reservado ao saldo
+func (p *PayoutProcessor) fail(payout *domain.Payout, from domain.PayoutStatus, reason string) error {
+	before := payoutAuditState(payout)
+	if err := payout.MarkFailed(reason); err != nil {
+		return err
+	}
+
+	audit, err := p.statusChange(payout, before)
+	if err != nil {
+		return err
+	}
+
+	if err := p.payoutRepository.Fail(payout, from, audit); err != nil {
+		return err
+	}
+
+	slog.Warn("saque falhou, valor devolvido ao saldo",
+		"payout_id", payout.ID,
+		"amount", payout.Amount,
+		"reason", reason)
+	return nil
+}
+
+// statusChange monta o evento de auditoria gravado junto com o novo status do saque
+func (p *PayoutProcessor) statusChange(payout *domain.Payout, before map[string]any) (*domain.AuditEvent, error) {
+	return p.auditService.Event(domain.NewSystemActor(payoutProcessorLock), domain.AuditActionPayoutStatusChanged,
+		auditEntityPayout, payout.ID, before, payoutAuditState(payout))
+}
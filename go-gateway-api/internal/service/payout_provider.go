@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PayoutProvider é a integração com quem efetivamente transfere o dinheiro (banco ou PSP Pix)
+type PayoutProvider interface {
+	// Submit envia o saque e retorna a referência do provedor. Deve ser idempotente pelo
+	// ID do saque, pois o envio é repetido quando a resposta anterior se perdeu.
+	// Um *PayoutRejectedError indica recusa definitiva; outros erros são retentados.
+	Submit(ctx context.Context, payout *domain.Payout, destination *domain.PayoutDestination) (string, error)
+	// Status consulta um saque aceito; retorna in_transit enquanto não houver liquidação
+	Status(ctx context.Context, reference string) (*PayoutProviderStatus, error)
+}
+
+type PayoutProviderStatus struct {
+	Status        domain.PayoutStatus // in_transit, paid ou failed
+	FailureReason string
+}
+
+// PayoutRejectedError é a recusa definitiva de um saque pelo provedor
+type PayoutRejectedError struct {
+	Reason string
+}
+
+func (e *PayoutRejectedError) Error() string {
+	return "payout rejected by provider: " + e.Reason
+}
+
+type SimulatedPayoutProviderConfig struct {
+	SettlementDelay time.Duration // tempo até o saque ser liquidado
+	FailureRate     float64       // fração dos saques que falham após serem aceitos
+}
+
+func NewSimulatedPayoutProviderConfig() *SimulatedPayoutProviderConfig {
+	config := &SimulatedPayoutProviderConfig{
+		SettlementDelay: 10 * time.Second,
+		FailureRate:     0.1,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("PAYOUT_SIMULATOR_SETTLEMENT_DELAY")); err == nil && value >= 0 {
+		config.SettlementDelay = value
+	}
+
+	if value, err := strconv.ParseFloat(os.Getenv("PAYOUT_SIMULATOR_FAILURE_RATE"), 64); err == nil && value >= 0 && value <= 1 {
+		config.FailureRate = value
+	}
+
+	return config
+}
+
+type simulatedPayout struct {
+	submittedAt time.Time
+	fails       bool
+}
+
+// SimulatedPayoutProvider simula um provedor local para desenvolvimento: aceita todos os
+// saques e os liquida (ou falha) depois de SettlementDelay. O estado fica em memória.
+type SimulatedPayoutProvider struct {
+	config  *SimulatedPayoutProviderConfig
+	mu      sync.Mutex
+	random  *rand.Rand
+	payouts map[string]*simulatedPayout // referência -> saque
+	byID    map[string]string           // ID do saque -> referência, para o Submit idempotente
+}
+
+func NewSimulatedPayoutProvider(config *SimulatedPayoutProviderConfig) *SimulatedPayoutProvider {
+	return &SimulatedPayoutProvider{
+		config:  config,
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		payouts: make(map[string]*simulatedPayout),
+		byID:    make(map[string]string),
+	}
+}
+
+func (p *SimulatedPayoutProvider) Submit(ctx context.Context, payout *domain.Payout, destination *domain.PayoutDestination) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if reference, ok := p.byID[payout.ID]; ok {
+		return reference, nil
+	}
+
+	reference := "sim_" + uuid.New().String()
+	p.payouts[reference] = &simulatedPayout{
+		submittedAt: time.Now(),
+		fails:       p.random.Float64() < p.config.FailureRate,
+	}
+	p.byID[payout.ID] = reference
+	return reference, nil
+}
+
+func (p *SimulatedPayoutProvider) Status(ctx context.Context, reference string) (*PayoutProviderStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	simulated, ok := p.payouts[reference]
+	if !ok {
+		// Referências anteriores a um restart não estão em memória e são dadas como pagas
+		return &PayoutProviderStatus{Status: domain.PayoutStatusPaid}, nil
+	}
+
+	if time.Since(simulated.submittedAt) < p.config.SettlementDelay {
+		return &PayoutProviderStatus{Status: domain.PayoutStatusInTransit}, nil
+	}
+
+	if simulated.fails {
+		return &PayoutProviderStatus{Status: domain.PayoutStatusFailed, FailureReason: "destination account rejected the transfer"}, nil
+	}
+
+	return &PayoutProviderStatus{Status: domain.PayoutStatusPaid}, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const (
+	auditEntityPayout            = "payout"
+	auditEntityPayoutDestination = "payout_destination"
+)
+
+type PayoutConfig struct {
+	Provider string // simulator ou cnab
+}
+
+func NewPayoutConfig() *PayoutConfig {
+	config := &PayoutConfig{
+		Provider: "simulator",
+	}
+
+	if value := os.Getenv("PAYOUT_PROVIDER"); value != "" {
+		config.Provider = value
+	}
+
+	return config
+}
+
+// SupportsDestination indica se o provedor paga o tipo de destino; remessas CNAB só pagam
+// contas bancárias
+func (c *PayoutConfig) SupportsDestination(destinationType domain.PayoutDestinationType) bool {
+	return c.Provider != "cnab" || destinationType == domain.PayoutDestinationBankAccount
+}
+
+// PayoutService cadastra os destinos de saque e reserva o saldo dos saques solicitados.
+// O envio ao provedor e o acompanhamento ficam com o PayoutProcessor.
+type PayoutService struct {
+	payoutRepository domain.PayoutRepository
+	accountService   *AccountService
+	auditService     *AuditService
+	config           *PayoutConfig
+}
+
+func NewPayoutService(
+	payoutRepository domain.PayoutRepository,
+	accountService *AccountService,
+	auditService *AuditService,
+	config *PayoutConfig,
+) *PayoutService {
+	return &PayoutService{
+		payoutRepository: payoutRepository,
+		accountService:   accountService,
+		auditService:     auditService,
+		config:           config,
+	}
+}
+
+func (s *PayoutService) CreateDestination(apiKey string, input dto.CreatePayoutDestinationInput, actor domain.Actor) (*dto.PayoutDestinationOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := dto.ToPayoutDestination(input, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.config.SupportsDestination(destination.Type) {
+		return nil, domain.ErrPayoutDestinationNotSupported
+	}
+
+	output := dto.FromPayoutDestination(destination)
+	audit, err := s.auditService.Event(actor, domain.AuditActionPayoutDestinationAdded, auditEntityPayoutDestination, destination.ID, nil, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.payoutRepository.SaveDestination(destination, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (s *PayoutService) ListDestinations(apiKey string) ([]*dto.PayoutDestinationOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	destinations, err := s.payoutRepository.FindDestinationsByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.PayoutDestinationOutput, len(destinations))
+	for i, destination := range destinations {
+		output[i] = dto.FromPayoutDestination(destination)
+	}
+	return output, nil
+}
+
+// Create reserva o valor do saldo; o saque fica pendente até ser enviado ao provedor
+func (s *PayoutService) Create(apiKey string, input dto.CreatePayoutInput, actor domain.Actor) (*dto.PayoutOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := s.payoutRepository.FindDestinationByID(input.DestinationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Destinos cadastrados antes da troca de provedor ficariam pendentes para sempre
+	if !s.config.SupportsDestination(destination.Type) {
+		return nil, domain.ErrPayoutDestinationNotSupported
+	}
+
+	payout, err := domain.NewPayout(account.ID, destination, input.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionPayoutRequested, auditEntityPayout, payout.ID, nil, payoutAuditState(payout))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.payoutRepository.Create(payout, audit); err != nil {
+		return nil, err
+	}
+
+	slog.Info("saque solicitado",
+		"payout_id", payout.ID,
+		"account_id", payout.AccountID,
+		"amount", payout.Amount)
+
+	return dto.FromPayout(payout), nil
+}
+
+func (s *PayoutService) GetByID(id, apiKey string) (*dto.PayoutOutput, error) {
+	payout, err := s.payoutRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if payout.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	return dto.FromPayout(payout), nil
+}
+
+func (s *PayoutService) ListByAccountAPIKey(apiKey string) ([]*dto.PayoutOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	payouts, err := s.payoutRepository.FindByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.PayoutOutput, len(payouts))
+	for i, payout := range payouts {
+		output[i] = dto.FromPayout(payout)
+	}
+	return output, nil
+}
+
+func payoutAuditState(payout *domain.Payout) map[string]any {
+	return map[string]any{
+		"account_id":     payout.AccountID,
+		"destination_id": payout.DestinationID,
+		"amount":         payout.Amount,
+		"status":         payout.Status,
+		"failure_reason": payout.FailureReason,
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/google/uuid"
+)
+
+// PixProvider é a integração com o PSP que recebe os Pix. A confirmação do pagamento chega
+// de forma assíncrona pelo callback /webhooks/pix.
+type PixProvider interface {
+	// Register cria a cobrança no PSP e retorna a location do payload dinâmico (sem o
+	// esquema); cobranças estáticas retornam location vazia
+	Register(ctx context.Context, charge *domain.PixCharge) (string, error)
+}
+
+// PixCallbackSignatureHeader carrega o HMAC-SHA256 (hex) do corpo do callback
+const PixCallbackSignatureHeader = "X-Pix-Signature"
+
+// SignCallback assina o corpo dos callbacks dos provedores de pagamento com o segredo compartilhado
+func SignCallback(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type SimulatedPixProviderConfig struct {
+	LocationBaseURL string        // prefixo das locations das cobranças dinâmicas
+	PaymentDelay    time.Duration // tempo até o pagamento simulado; zero desativa o pagamento automático
+	CallbackURL     string
+	WebhookSecret   string
+}
+
+func NewSimulatedPixProviderConfig() *SimulatedPixProviderConfig {
+	config := &SimulatedPixProviderConfig{
+		LocationBaseURL: "pix.localhost/qr/v2/",
+		PaymentDelay:    15 * time.Second,
+		CallbackURL:     "http://localhost:8080/webhooks/pix",
+		WebhookSecret:   os.Getenv("PIX_WEBHOOK_SECRET"),
+	}
+
+	if value := os.Getenv("PIX_SIMULATOR_LOCATION_BASE_URL"); value != "" {
+		config.LocationBaseURL = value
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("PIX_SIMULATOR_PAYMENT_DELAY")); err == nil && value >= 0 {
+		config.PaymentDelay = value
+	}
+
+	if value := os.Getenv("PIX_SIMULATOR_CALLBACK_URL"); value != "" {
+		config.CallbackURL = value
+	}
+
+	return config
+}
+
+// SimulatedPixProvider simula um PSP local para desenvolvimento: registra as cobranças e,
+// depois de PaymentDelay, "paga" cada uma chamando o callback assinado do gateway
+type SimulatedPixProvider struct {
+	config *SimulatedPixProviderConfig
+	client *http.Client
+}
+
+func NewSimulatedPixProvider(config *SimulatedPixProviderConfig) *SimulatedPixProvider {
+	return &SimulatedPixProvider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *SimulatedPixProvider) Register(ctx context.Context, charge *domain.PixCharge) (string, error) {
+	if p.config.PaymentDelay > 0 {
+		payment := dto.PixCallbackInput{
+			TxID:       charge.TxID,
+			EndToEndID: "E" + strings.ToUpper(strings.ReplaceAll(uuid.New().String(), "-", ""))[:31],
+			Amount:     charge.Amount,
+		}
+		time.AfterFunc(p.config.PaymentDelay, func() { p.pay(payment) })
+	}
+
+	if charge.Type == domain.PixChargeTypeStatic {
+		return "", nil
+	}
+	return p.config.LocationBaseURL + strings.ReplaceAll(charge.ID, "-", ""), nil
+}
+
+func (p *SimulatedPixProvider) pay(payment dto.PixCallbackInput) {
+	payment.PaidAt = time.Now()
+	body, err := json.Marshal(payment)
+	if err != nil {
+		slog.Error("erro ao montar callback do simulador Pix", "error", err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.config.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("erro ao montar callback do simulador Pix", "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(PixCallbackSignatureHeader, SignCallback(p.config.WebhookSecret, body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		slog.Error("erro ao enviar callback do simulador Pix", "error", err, "txid", payment.TxID)
+		return
+	}
+	resp.Body.Close()
+
+	slog.Info("pagamento Pix simulado", "txid", payment.TxID, "status_code", resp.StatusCode)
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/qrcode"
+)
+
+const pixExpirationJobLock = "pix_expiration"
+
+// pixQRCodeScale é o tamanho em pixels de cada módulo do QR Code
+const pixQRCodeScale = 8
+
+type PixConfig struct {
+	Receiver      domain.PixReceiver
+	WebhookSecret string // segredo do HMAC dos callbacks do PSP
+}
+
+func NewPixConfig() *PixConfig {
+	config := &PixConfig{
+		Receiver: domain.PixReceiver{
+			Key:  os.Getenv("PIX_KEY"),
+			Name: os.Getenv("PIX_MERCHANT_NAME"),
+			City: os.Getenv("PIX_MERCHANT_CITY"),
+		},
+		WebhookSecret: os.Getenv("PIX_WEBHOOK_SECRET"),
+	}
+
+	if config.Receiver.Name == "" {
+		config.Receiver.Name = "PAYMENT GATEWAY"
+	}
+
+	if config.Receiver.City == "" {
+		config.Receiver.City = "SAO PAULO"
+	}
+
+	if config.WebhookSecret == "" {
+		slog.Warn("PIX_WEBHOOK_SECRET não configurado, callbacks Pix serão recusados")
+	}
+
+	return config
+}
+
+// PixService gera as cobranças Pix das faturas e aprova as faturas quando o PSP confirma
+// o pagamento
+type PixService struct {
+	pixRepository     domain.PixRepository
+	invoiceRepository domain.InvoiceRepository
+	accountService    AccountService
+	fxService         *FXService
+	feeService        *FeeService
+	settlementService *SettlementService
+	provider          PixProvider
+	auditService      *AuditService
+	config            *PixConfig
+}
+
+func NewPixService(
+	pixRepository domain.PixRepository,
+	invoiceRepository domain.InvoiceRepository,
+	accountService AccountService,
+	fxService *FXService,
+	feeService *FeeService,
+	settlementService *SettlementService,
+	provider PixProvider,
+	auditService *AuditService,
+	config *PixConfig,
+) *PixService {
+	return &PixService{
+		pixRepository:     pixRepository,
+		invoiceRepository: invoiceRepository,
+		accountService:    accountService,
+		fxService:         fxService,
+		feeService:        feeService,
+		settlementService: settlementService,
+		provider:          provider,
+		auditService:      auditService,
+		config:            config,
+	}
+}
+
+// Create registra a cobrança no PSP e grava a fatura com a cobrança
+func (s *PixService) Create(invoice *domain.Invoice, chargeType domain.PixChargeType, expiresIn time.Duration, audit ...*domain.AuditEvent) (*domain.PixCharge, error) {
+	charge, err := domain.NewPixCharge(invoice, chargeType, expiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem chave não há como montar o payload estático; a validação evita registrar a cobrança no PSP
+	if charge.Type == domain.PixChargeTypeStatic && s.config.Receiver.Key == "" {
+		return nil, domain.ErrPixKeyNotConfigured
+	}
+
+	charge.Location, err = s.provider.Register(context.Background(), charge)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := charge.BuildPayload(s.config.Receiver); err != nil {
+		return nil, err
+	}
+
+	if err := s.pixRepository.Create(invoice, charge, audit...); err != nil {
+		return nil, err
+	}
+
+	return charge, nil
+}
+
+// ChargeFor retorna a cobrança Pix da fatura, ou nil se a fatura não for Pix
+func (s *PixService) ChargeFor(invoice *domain.Invoice) (*domain.PixCharge, error) {
+	if invoice.PaymentType != domain.PaymentTypePix {
+		return nil, nil
+	}
+
+	return s.pixRepository.FindByInvoiceID(invoice.ID)
+}
+
+// QRCode gera o PNG do BR Code da fatura
+func (s *PixService) QRCode(invoiceID, apiKey string) ([]byte, error) {
+	accountOutput, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := s.pixRepository.FindByInvoiceID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if charge.AccountID != accountOutput.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	code, err := qrcode.Encode(charge.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return code.PNG(pixQRCodeScale)
+}
+
+// HandleCallback confirma o pagamento informado pelo PSP. Reentregas do mesmo pagamento são
+// ignoradas.
+func (s *PixService) HandleCallback(body []byte, signature string) error {
+	if s.config.WebhookSecret == "" || !hmac.Equal([]byte(signature), []byte(SignCallback(s.config.WebhookSecret, body))) {
+		return domain.ErrInvalidPixSignature
+	}
+
+	var input dto.PixCallbackInput
+	if err := json.Unmarshal(body, &input); err != nil {
+		return err
+	}
+
+	charge, err := s.pixRepository.FindByTxID(input.TxID)
+	if err != nil {
+		return err
+	}
+
+	if charge.Status == domain.PixChargeStatusPaid && charge.EndToEndID == input.EndToEndID {
+		slog.Info("callback Pix duplicado ignorado", "txid", charge.TxID, "end_to_end_id", input.EndToEndID)
+		return nil
+	}
+
+	if input.PaidAt.IsZero() {
+		input.PaidAt = time.Now()
+	}
+
+	if err := charge.Pay(input.EndToEndID, input.Amount, input.PaidAt); err != nil {
+		return err
+	}
+
+	invoice, err := s.invoiceRepository.FindByID(charge.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	before := invoiceAuditState(invoice)
+	if err := invoice.ConfirmPayment(); err != nil {
+		return err
+	}
+
+	approval, err := approveInvoice(s.fxService, s.feeService, s.settlementService, invoice)
+	if err != nil {
+		return err
+	}
+
+	after := invoiceAuditState(invoice)
+	after["end_to_end_id"] = charge.EndToEndID
+	audit, err := s.auditService.Event(domain.NewSystemActor("pix-psp"), domain.AuditActionInvoiceStatusChanged,
+		auditEntityInvoice, invoice.ID, before, after)
+	if err != nil {
+		return err
+	}
+
+	if err := s.pixRepository.ConfirmPayment(charge, invoice, approval, audit); err != nil {
+		return err
+	}
+
+	slog.Info("pagamento Pix confirmado", "invoice_id", invoice.ID, "txid", charge.TxID)
+	return nil
+}
+
+type PixExpirationJobConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+func NewPixExpirationJobConfig() *PixExpirationJobConfig {
+	config := &PixExpirationJobConfig{
+		Interval:  time.Minute,
+		BatchSize: 100,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("PIX_EXPIRATION_INTERVAL")); err == nil && value > 0 {
+		config.Interval = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("PIX_EXPIRATION_BATCH_SIZE")); err == nil && value > 0 {
+		config.BatchSize = value
+	}
+
+	return config
+}
+
+// PixExpirationJob expira as cobranças Pix não pagas dentro do prazo e as suas faturas.
+// Apenas uma réplica executa cada rodada.
+type PixExpirationJob struct {
+	pixRepository     domain.PixRepository
+	invoiceRepository domain.InvoiceRepository
+	locker            domain.Locker
+	auditService      *AuditService
+	config            *PixExpirationJobConfig
+}
+
+func NewPixExpirationJob(
+	pixRepository domain.PixRepository,
+	invoiceRepository domain.InvoiceRepository,
+	locker domain.Locker,
+	auditService *AuditService,
+	config *PixExpirationJobConfig,
+) *PixExpirationJob {
+	return &PixExpirationJob{
+		pixRepository:     pixRepository,
+		invoiceRepository: invoiceRepository,
+		locker:            locker,
+		auditService:      auditService,
+		config:            config,
+	}
+}
+
+// Run executa a expiração periodicamente até o contexto ser cancelado
+func (j *PixExpirationJob) Run(ctx context.Context) error {
+	slog.Info("job de expiração de cobranças Pix iniciado", "interval", j.config.Interval)
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := j.ExpireDue(ctx, time.Now()); err != nil {
+				slog.Error("erro na expiração de cobranças Pix", "error", err)
+			}
+		}
+	}
+}
+
+func (j *PixExpirationJob) ExpireDue(ctx context.Context, now time.Time) error {
+	release, acquired, err := j.locker.TryLock(ctx, pixExpirationJobLock)
+	if err != nil {
+		return err
+	}
+
+	if !acquired {
+		slog.Debug("expiração de cobranças Pix em execução em outra réplica")
+		return nil
+	}
+	defer release()
+
+	charges, err := j.pixRepository.FindExpired(now, j.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, charge := range charges {
+		// Uma falha não impede o tratamento das demais cobranças
+		if err := j.expire(charge); err != nil {
+			slog.Error("erro ao expirar cobrança Pix", "error", err, "invoice_id", charge.InvoiceID)
+		}
+	}
+
+	return nil
+}
+
+func (j *PixExpirationJob) expire(charge *domain.PixCharge) error {
+	invoice, err := j.invoiceRepository.FindByID(charge.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	before := invoiceAuditState(invoice)
+	if err := charge.Expire(); err != nil {
+		return err
+	}
+
+	if err := invoice.ExpirePayment(); err != nil {
+		return err
+	}
+
+	audit, err := j.auditService.Event(domain.NewSystemActor(pixExpirationJobLock), domain.AuditActionInvoiceStatusChanged,
+		auditEntityInvoice, invoice.ID, before, invoiceAuditState(invoice))
+	if err != nil {
+		return err
+	}
+
+	if err := j.pixRepository.Expire(charge, invoice, audit); err != nil {
+		if err == domain.ErrInvalidStatus {
+			return nil // o pagamento foi confirmado antes da expiração
+		}
+		return err
+	}
+
+	slog.Info("cobrança Pix expirada", "invoice_id", invoice.ID, "txid", charge.TxID)
+	return nil
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/pdf"
+)
+
+// receiptDocument reúne os textos do recibo já formatados, usados tanto no HTML quanto no PDF
+type receiptDocument struct {
+	MerchantName  string
+	MerchantEmail string
+	InvoiceID     string
+	Fields        []receiptField
+	Lines         []receiptLine
+	Totals        []receiptTotal
+	Settings      *domain.ReceiptSettings
+	Logo          template.URL // data URI do logo, vazio sem logo
+}
+
+type receiptField struct {
+	Label string
+	Value string
+}
+
+type receiptLine struct {
+	Description string
+	SKU         string
+	Quantity    string
+	UnitPrice   string
+	Amount      string
+}
+
+type receiptTotal struct {
+	Label  string
+	Amount string
+	Strong bool
+}
+
+var receiptStatusLabels = map[domain.Status]string{
+	domain.StatusPending:         "Em análise",
+	domain.StatusApproved:        "Aprovada",
+	domain.StatusRejected:        "Recusada",
+	domain.StatusExpired:         "Expirada",
+	domain.StatusAwaitingPayment: "Aguardando pagamento",
+}
+
+var receiptDeclineReasonLabels = map[domain.DeclineReason]string{
+	domain.DeclineReasonCardDeclined:    "Cartão recusado",
+	domain.DeclineReasonCardExpired:     "Cartão vencido",
+	domain.DeclineReasonFraudSuspected:  "Recusada na análise de risco",
+	domain.DeclineReasonProcessingError: "Erro no processamento",
+}
+
+var receiptCardBrandLabels = map[domain.CardBrand]string{
+	domain.CardBrandVisa:       "Visa",
+	domain.CardBrandMastercard: "Mastercard",
+	domain.CardBrandAmex:       "American Express",
+	domain.CardBrandElo:        "Elo",
+	domain.CardBrandHipercard:  "Hipercard",
+}
+
+var receiptCurrencySymbols = map[domain.Currency]string{
+	domain.CurrencyBRL: "R$",
+	domain.CurrencyUSD: "US$",
+	domain.CurrencyEUR: "€",
+}
+
+const receiptTimeLayout = "02/01/2006 15:04 UTC"
+
+// newReceiptDocument monta o recibo com o cartão mascarado e o documento do pagador mascarado,
+// como em InvoiceOutput
+func newReceiptDocument(invoice *domain.Invoice, account *dto.AccountOutput, settings *domain.ReceiptSettings) *receiptDocument {
+	money := func(value float64) string {
+		return receiptCurrencySymbols[invoice.Currency] + " " + formatBRL(value)
+	}
+
+	document := &receiptDocument{
+		MerchantName:  account.Name,
+		MerchantEmail: account.Email,
+		InvoiceID:     invoice.ID,
+		Settings:      settings,
+	}
+
+	if len(settings.Logo) > 0 {
+		document.Logo = template.URL("data:" + settings.LogoContentType + ";base64," + base64.StdEncoding.EncodeToString(settings.Logo))
+	}
+
+	document.Fields = append(document.Fields, receiptField{"Status", receiptStatusLabels[invoice.Status]})
+	if invoice.DeclineReason != "" {
+		document.Fields = append(document.Fields, receiptField{"Motivo", receiptDeclineReasonLabels[invoice.DeclineReason]})
+	}
+
+	switch invoice.PaymentType {
+	case domain.PaymentTypePix:
+		document.Fields = append(document.Fields, receiptField{"Forma de pagamento", "Pix"})
+	case domain.PaymentTypeBoleto:
+		document.Fields = append(document.Fields, receiptField{"Forma de pagamento", "Boleto"})
+	default:
+		brand, ok := receiptCardBrandLabels[invoice.CardBrand]
+		if !ok {
+			brand = "Cartão"
+		}
+		document.Fields = append(document.Fields, receiptField{"Forma de pagamento", "Cartão de crédito " + brand + " **** **** **** " + invoice.CardLastDigits})
+	}
+
+	if invoice.Installments > 1 {
+		document.Fields = append(document.Fields, receiptField{"Parcelamento", strconv.Itoa(invoice.Installments) + "x de " + money(invoice.InstallmentAmount())})
+	}
+
+	if invoice.Payer != nil {
+		document.Fields = append(document.Fields, receiptField{"Pagador", invoice.Payer.Name + " - " + invoice.Payer.MaskedDocument()})
+	}
+
+	if invoice.Description != "" {
+		document.Fields = append(document.Fields, receiptField{"Descrição", invoice.Description})
+	}
+
+	document.Fields = append(document.Fields,
+		receiptField{"Emitida em", invoice.CreatedAt.UTC().Format(receiptTimeLayout)},
+		receiptField{"Atualizada em", invoice.UpdatedAt.UTC().Format(receiptTimeLayout)},
+	)
+
+	// Faturas sem itens aparecem como um único item com o valor da fatura
+	if breakdown := invoice.Breakdown; breakdown != nil {
+		for _, item := range breakdown.LineItems {
+			document.Lines = append(document.Lines, receiptLine{
+				Description: item.Description,
+				SKU:         item.SKU,
+				Quantity:    strconv.Itoa(item.Quantity),
+				UnitPrice:   money(item.UnitPrice),
+				Amount:      money(item.Amount),
+			})
+		}
+
+		document.Totals = append(document.Totals, receiptTotal{Label: "Subtotal", Amount: money(breakdown.Subtotal)})
+		if discount := breakdown.Discount; discount != nil {
+			label := "Desconto"
+			if discount.CouponCode != "" {
+				label += " (" + discount.CouponCode + ")"
+			}
+			document.Totals = append(document.Totals, receiptTotal{Label: label, Amount: "- " + money(discount.Amount)})
+		}
+		for _, tax := range breakdown.TaxLines {
+			label := tax.Name
+			if tax.Rate > 0 {
+				label += " (" + strings.TrimSuffix(strings.TrimRight(formatBRL(tax.Rate), "0"), ",") + "%)"
+			}
+			document.Totals = append(document.Totals, receiptTotal{Label: label, Amount: money(tax.Amount)})
+		}
+	} else {
+		description := invoice.Description
+		if description == "" {
+			description = "Pagamento"
+		}
+		document.Lines = append(document.Lines, receiptLine{
+			Description: description,
+			Quantity:    "1",
+			UnitPrice:   money(invoice.Amount),
+			Amount:      money(invoice.Amount),
+		})
+	}
+
+	// O valor cobrado inclui os juros pagos pelo comprador ou a multa e os juros do boleto
+	if extra := invoice.TotalAmount - invoice.Amount; extra >= 0.01 {
+		label := "Multa e juros de mora"
+		if invoice.InterestMode == domain.InterestModeBuyer && invoice.InterestAmount > 0 {
+			label = "Juros do parcelamento"
+		}
+		document.Totals = append(document.Totals, receiptTotal{Label: label, Amount: money(extra)})
+	}
+
+	document.Totals = append(document.Totals, receiptTotal{Label: "Total", Amount: money(invoice.TotalAmount), Strong: true})
+	return document
+}
+
+// receiptTemplate usa apenas CSS embutido; as cores já foram validadas como #RRGGBB
+var receiptTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>Recibo {{.InvoiceID}}</title>
+<style>
+  body { font-family: Arial, Helvetica, sans-serif; font-size: 13px; color: #111827; margin: 24px; }
+  .receipt { max-width: 680px; margin: 0 auto; }
+  header { background: {{.Settings.PrimaryColor}}; color: {{.HeaderTextColor}}; padding: 20px; display: flex; align-items: center; gap: 16px; }
+  header img { max-height: 50px; max-width: 150px; }
+  header .merchant { flex: 1; }
+  header .merchant strong { font-size: 18px; display: block; }
+  header .title { text-align: right; }
+  header .title strong { font-size: 20px; display: block; }
+  dl { display: grid; grid-template-columns: 160px 1fr; gap: 6px 12px; margin: 20px 0; }
+  dt { font-weight: bold; color: #4B5563; }
+  dd { margin: 0; }
+  table { border-collapse: collapse; width: 100%; }
+  th { background: {{.Settings.SecondaryColor}}; color: {{.TableHeaderTextColor}}; text-align: left; padding: 6px 8px; }
+  td { border-bottom: 1px solid #E5E7EB; padding: 6px 8px; vertical-align: top; }
+  .number { text-align: right; white-space: nowrap; }
+  .sku { display: block; font-size: 11px; color: #6B7280; }
+  .totals td { border: none; }
+  .totals .strong td { font-weight: bold; font-size: 15px; border-top: 2px solid #111827; }
+  footer { margin-top: 32px; font-size: 11px; color: #6B7280; white-space: pre-line; }
+  @media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<div class="receipt">
+<header>
+  {{if .Logo}}<img src="{{.Logo}}" alt="{{.MerchantName}}">{{end}}
+  <div class="merchant"><strong>{{.MerchantName}}</strong>{{.MerchantEmail}}</div>
+  <div class="title"><strong>Recibo</strong>Fatura {{.InvoiceID}}</div>
+</header>
+<dl>
+  {{range .Fields}}<dt>{{.Label}}</dt><dd>{{.Value}}</dd>
+  {{end}}
+</dl>
+<table>
+  <thead><tr><th>Item</th><th class="number">Qtd</th><th class="number">Unitário</th><th class="number">Total</th></tr></thead>
+  <tbody>
+  {{range .Lines}}<tr>
+    <td>{{.Description}}{{if .SKU}}<span class="sku">SKU {{.SKU}}</span>{{end}}</td>
+    <td class="number">{{.Quantity}}</td>
+    <td class="number">{{.UnitPrice}}</td>
+    <td class="number">{{.Amount}}</td>
+  </tr>
+  {{end}}
+  </tbody>
+  <tbody class="totals">
+  {{range .Totals}}<tr{{if .Strong}} class="strong"{{end}}>
+    <td colspan="3" class="number">{{.Label}}</td>
+    <td class="number">{{.Amount}}</td>
+  </tr>
+  {{end}}
+  </tbody>
+</table>
+{{if .Settings.Footer}}<footer>{{.Settings.Footer}}</footer>{{end}}
+</div>
+</body>
+</html>
+`))
+
+// receiptHTML acrescenta ao recibo as cores de texto que contrastam com as da conta
+type receiptHTML struct {
+	*receiptDocument
+	HeaderTextColor      string
+	TableHeaderTextColor string
+}
+
+func renderReceiptHTML(w io.Writer, document *receiptDocument) error {
+	return receiptTemplate.Execute(w, receiptHTML{
+		receiptDocument:      document,
+		HeaderTextColor:      hexString(contrastColor(hexColor(document.Settings.PrimaryColor))),
+		TableHeaderTextColor: hexString(contrastColor(hexColor(document.Settings.SecondaryColor))),
+	})
+}
+
+// Layout do PDF em pontos
+const (
+	receiptMargin       = 40
+	receiptContentRight = pdf.PageWidth - receiptMargin
+	receiptBottomLimit  = pdf.PageHeight - 60
+)
+
+var (
+	receiptTextColor  = pdf.Color{R: 0x11, G: 0x18, B: 0x27}
+	receiptMutedColor = pdf.Color{R: 0x6B, G: 0x72, B: 0x80}
+	receiptLineColor  = pdf.Color{R: 0xE5, G: 0xE7, B: 0xEB}
+)
+
+// renderReceiptPDF desenha o recibo em páginas A4, repetindo o cabeçalho da tabela de itens
+// quando ela continua na página seguinte
+func renderReceiptPDF(w io.Writer, document *receiptDocument) error {
+	doc := pdf.New()
+	page := doc.AddPage()
+
+	primary := hexColor(document.Settings.PrimaryColor)
+	secondary := hexColor(document.Settings.SecondaryColor)
+	headerText := contrastColor(primary)
+
+	page.Rect(0, 0, pdf.PageWidth, 90, primary)
+
+	textX := float64(receiptMargin)
+	if len(document.Settings.Logo) > 0 {
+		// Um logo que não decodifica não impede o recibo
+		if img, _, err := image.Decode(bytes.NewReader(document.Settings.Logo)); err == nil {
+			logo := doc.AddImage(img)
+			width, height := fitImage(logo, 150, 50)
+			page.Image(logo, receiptMargin, 45-height/2, width, height)
+			textX += width + 12
+		}
+	}
+
+	page.Text(textX, 42, 16, true, headerText, document.MerchantName)
+	page.Text(textX, 60, 9, false, headerText, document.MerchantEmail)
+	page.TextRight(receiptContentRight, 42, 18, true, headerText, "Recibo")
+	page.TextRight(receiptContentRight, 60, 9, false, headerText, "Fatura "+document.InvoiceID)
+
+	y := 125.0
+	for _, field := range document.Fields {
+		lines := pdf.Wrap(field.Value, 10, false, receiptContentRight-180)
+		page.Text(receiptMargin, y, 9, true, receiptMutedColor, field.Label)
+		for _, line := range lines {
+			page.Text(180, y, 10, false, receiptTextColor, line)
+			y += 14
+		}
+		y += 2
+	}
+
+	tableHeader := func() {
+		headerColor := contrastColor(secondary)
+		page.Rect(receiptMargin, y, receiptContentRight-receiptMargin, 20, secondary)
+		page.Text(receiptMargin+8, y+14, 9, true, headerColor, "Item")
+		page.TextRight(380, y+14, 9, true, headerColor, "Qtd")
+		page.TextRight(465, y+14, 9, true, headerColor, "Unitário")
+		page.TextRight(receiptContentRight-8, y+14, 9, true, headerColor, "Total")
+		y += 20
+	}
+
+	y += 12
+	tableHeader()
+	for _, line := range document.Lines {
+		description := pdf.Wrap(line.Description, 10, false, 280)
+		height := float64(len(description))*13 + 10
+		if line.SKU != "" {
+			height += 11
+		}
+
+		if y+height > receiptBottomLimit {
+			page = doc.AddPage()
+			y = receiptMargin
+			tableHeader()
+		}
+
+		rowY := y + 14
+		page.TextRight(380, rowY, 10, false, receiptTextColor, line.Quantity)
+		page.TextRight(465, rowY, 10, false, receiptTextColor, line.UnitPrice)
+		page.TextRight(receiptContentRight-8, rowY, 10, false, receiptTextColor, line.Amount)
+		for _, text := range description {
+			page.Text(receiptMargin+8, rowY, 10, false, receiptTextColor, text)
+			rowY += 13
+		}
+		if line.SKU != "" {
+			page.Text(receiptMargin+8, rowY-2, 8, false, receiptMutedColor, "SKU "+line.SKU)
+		}
+
+		y += height
+		page.Line(receiptMargin, y, receiptContentRight, y, 0.5, receiptLineColor)
+	}
+
+	y += 8
+	for _, total := range document.Totals {
+		if y+20 > receiptBottomLimit {
+			page = doc.AddPage()
+			y = receiptMargin
+		}
+
+		size := 10.0
+		if total.Strong {
+			size = 12
+			page.Line(300, y+2, receiptContentRight, y+2, 1, receiptTextColor)
+			y += 4
+		}
+		page.TextRight(465, y+14, size, total.Strong, receiptTextColor, total.Label)
+		page.TextRight(receiptContentRight-8, y+14, size, total.Strong, receiptTextColor, total.Amount)
+		y += size + 8
+	}
+
+	// O rodapé fica no fim da última página, ou numa nova se não couber
+	if footer := document.Settings.Footer; footer != "" {
+		lines := pdf.Wrap(footer, 8, false, receiptContentRight-receiptMargin)
+		footerY := pdf.PageHeight - receiptMargin - float64(len(lines)-1)*11
+		if footerY < y+24 {
+			page = doc.AddPage()
+			footerY = pdf.PageHeight - receiptMargin - float64(len(lines)-1)*11
+		}
+		for _, line := range lines {
+			page.Text(receiptMargin, footerY, 8, false, receiptMutedColor, line)
+			footerY += 11
+		}
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// fitImage reduz a imagem para caber em maxWidth x maxHeight, mantendo a proporção
+func fitImage(img *pdf.Image, maxWidth, maxHeight float64) (float64, float64) {
+	width, height := img.Size()
+	scale := min(maxWidth/float64(width), maxHeight/float64(height), 1)
+	return float64(width) * scale, float64(height) * scale
+}
+
+// hexColor converte uma cor #RRGGBB já validada
+func hexColor(value string) pdf.Color {
+	rgb, _ := strconv.ParseUint(strings.TrimPrefix(value, "#"), 16, 32)
+	return pdf.Color{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb)}
+}
+
+func hexString(color pdf.Color) string {
+	return "#" + strings.ToUpper(strconv.FormatUint(uint64(color.R)<<16|uint64(color.G)<<8|uint64(color.B)|1<<24, 16)[1:])
+}
+
+// contrastColor escolhe texto escuro sobre cores claras e branco sobre cores escuras
+func contrastColor(background pdf.Color) pdf.Color {
+	luminance := 0.299*float64(background.R) + 0.587*float64(background.G) + 0.114*float64(background.B)
+	if luminance > 160 {
+		return receiptTextColor
+	}
+	return pdf.Color{R: 0xFF, G: 0xFF, B: 0xFF}
+}
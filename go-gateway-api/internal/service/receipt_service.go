@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const auditEntityReceiptSettings = "receipt_settings"
+
+// ReceiptService gera os recibos das faturas em HTML ou PDF com a personalização da conta
+type ReceiptService struct {
+	invoiceRepository  domain.InvoiceRepository
+	settingsRepository domain.ReceiptSettingsRepository
+	accountService     *AccountService
+	auditService       *AuditService
+}
+
+func NewReceiptService(
+	invoiceRepository domain.InvoiceRepository,
+	settingsRepository domain.ReceiptSettingsRepository,
+	accountService *AccountService,
+	auditService *AuditService,
+) *ReceiptService {
+	return &ReceiptService{
+		invoiceRepository:  invoiceRepository,
+		settingsRepository: settingsRepository,
+		accountService:     accountService,
+		auditService:       auditService,
+	}
+}
+
+// Receipt monta o recibo de uma fatura da conta, em qualquer status
+func (s *ReceiptService) Receipt(invoiceID, apiKey string, format domain.ReceiptFormat) ([]byte, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepository.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	settings, err := s.settingsFor(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	document := newReceiptDocument(invoice, account, settings)
+
+	var receipt bytes.Buffer
+	if format == domain.ReceiptFormatPDF {
+		err = renderReceiptPDF(&receipt, document)
+	} else {
+		err = renderReceiptHTML(&receipt, document)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt.Bytes(), nil
+}
+
+func (s *ReceiptService) GetSettings(apiKey string) (*dto.ReceiptSettingsOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsFor(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromReceiptSettings(settings), nil
+}
+
+func (s *ReceiptService) UpdateSettings(apiKey string, input dto.UpdateReceiptSettingsInput, actor domain.Actor) (*dto.ReceiptSettingsOutput, error) {
+	account, err := s.accountService.Authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.settingsFor(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := dto.ToReceiptSettings(input, account.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromReceiptSettings(settings)
+	audit, err := s.auditService.Event(actor, domain.AuditActionReceiptSettingsUpdated, auditEntityReceiptSettings, account.ID, dto.FromReceiptSettings(current), output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.settingsRepository.Save(settings, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// settingsFor usa a personalização padrão quando a conta não tem uma
+func (s *ReceiptService) settingsFor(accountID string) (*domain.ReceiptSettings, error) {
+	settings, err := s.settingsRepository.FindByAccountID(accountID)
+	if err == domain.ErrReceiptSettingsNotFound {
+		return domain.DefaultReceiptSettings(accountID), nil
+	}
+	return settings, err
+}
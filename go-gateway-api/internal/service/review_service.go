@@ -0,0 +1,180 @@
+package service
+
+import (
+	"os"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const (
+	defaultReviewQueueLimit = 50
+	defaultReviewClaimTTL   = 30 * time.Minute
+)
+
+// ReviewService permite que analistas de risco decidam manualmente faturas pendentes
+type ReviewService struct {
+	reviewRepository  domain.ReviewRepository
+	invoiceRepository domain.InvoiceRepository
+	invoiceService    *InvoiceService
+	auditService      *AuditService
+	claimTTL          time.Duration
+}
+
+func NewReviewService(
+	reviewRepository domain.ReviewRepository,
+	invoiceRepository domain.InvoiceRepository,
+	invoiceService *InvoiceService,
+	auditService *AuditService,
+) *ReviewService {
+	claimTTL := defaultReviewClaimTTL
+	if value, err := time.ParseDuration(os.Getenv("REVIEW_CLAIM_TTL")); err == nil && value > 0 {
+		claimTTL = value
+	}
+
+	return &ReviewService{
+		reviewRepository:  reviewRepository,
+		invoiceRepository: invoiceRepository,
+		invoiceService:    invoiceService,
+		auditService:      auditService,
+		claimTTL:          claimTTL,
+	}
+}
+
+func (s *ReviewService) ListQueue(limit int) ([]*dto.ReviewQueueItemOutput, error) {
+	if limit <= 0 {
+		limit = defaultReviewQueueLimit
+	}
+
+	items, err := s.reviewRepository.FindPendingQueue(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.ReviewQueueItemOutput, len(items))
+	for i, item := range items {
+		output[i] = dto.FromReviewQueueItem(item)
+	}
+	return output, nil
+}
+
+// Claim reserva a fatura para o analista; renovar o próprio claim estende o prazo
+func (s *ReviewService) Claim(invoiceID string, actor domain.Actor) (*dto.ReviewClaimOutput, error) {
+	if _, err := s.findPendingInvoice(invoiceID); err != nil {
+		return nil, err
+	}
+
+	entry, err := domain.NewReviewActionEntry(invoiceID, actor.ID, domain.ReviewActionClaim, "")
+	if err != nil {
+		return nil, err
+	}
+
+	claim := domain.NewReviewClaim(invoiceID, actor.ID, s.claimTTL)
+	audit, err := s.auditService.Event(actor, domain.AuditActionReviewClaimed, auditEntityInvoice, invoiceID, nil,
+		map[string]any{"expires_at": claim.ExpiresAt})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reviewRepository.Claim(claim, entry, audit); err != nil {
+		return nil, err
+	}
+
+	return dto.FromReviewClaim(claim), nil
+}
+
+func (s *ReviewService) Release(invoiceID string, actor domain.Actor) error {
+	entry, err := domain.NewReviewActionEntry(invoiceID, actor.ID, domain.ReviewActionRelease, "")
+	if err != nil {
+		return err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionReviewReleased, auditEntityInvoice, invoiceID, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.reviewRepository.Release(invoiceID, entry, audit)
+}
+
+// Decide aprova ou rejeita a fatura pelo mesmo fluxo usado para os resultados da análise de fraude
+func (s *ReviewService) Decide(invoiceID string, actor domain.Actor, action domain.ReviewAction, input dto.ReviewDecisionInput) (*dto.InvoiceOutput, error) {
+	entry, err := domain.NewReviewActionEntry(invoiceID, actor.ID, action, input.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := entry.DecisionStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findPendingInvoice(invoiceID); err != nil {
+		return nil, err
+	}
+
+	claim, err := s.reviewRepository.FindClaim(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !claim.HeldBy(actor.ID) {
+		return nil, domain.ErrReviewClaimRequired
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionReviewDecided, auditEntityInvoice, invoiceID, nil,
+		map[string]any{"action": action, "reason": input.Reason})
+	if err != nil {
+		return nil, err
+	}
+
+	// O id da ação é o id do evento, deduplicando a decisão como qualquer outro resultado; a
+	// ação do revisor e a auditoria são gravadas na mesma transação da mudança de status
+	applied, err := s.invoiceService.applyTransactionResult(entry.ID, invoiceID, status, actor, entry, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Um resultado da análise de fraude pode ter chegado antes da decisão manual
+	if !applied {
+		return nil, domain.ErrInvalidStatus
+	}
+
+	invoice, err := s.invoiceRepository.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromInvoice(invoice), nil
+}
+
+func (s *ReviewService) ListActions(invoiceID string) ([]*dto.ReviewActionOutput, error) {
+	if _, err := s.invoiceRepository.FindByID(invoiceID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.reviewRepository.FindActionsByInvoiceID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.ReviewActionOutput, len(entries))
+	for i, entry := range entries {
+		output[i] = dto.FromReviewActionEntry(entry)
+	}
+	return output, nil
+}
+
+func (s *ReviewService) findPendingInvoice(invoiceID string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepository.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != domain.StatusPending {
+		return nil, domain.ErrInvalidStatus
+	}
+
+	return invoice, nil
+}
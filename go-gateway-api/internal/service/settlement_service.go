@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const settlementJobLock = "settlement_job"
+
+// SettlementService agenda os recebíveis das faturas aprovadas conforme o prazo de
+// liquidação da conta e informa o saldo pendente e disponível
+type SettlementService struct {
+	accountRepository    domain.AccountRepository
+	receivableRepository domain.ReceivableRepository
+}
+
+func NewSettlementService(
+	accountRepository domain.AccountRepository,
+	receivableRepository domain.ReceivableRepository,
+) *SettlementService {
+	return &SettlementService{
+		accountRepository:    accountRepository,
+		receivableRepository: receivableRepository,
+	}
+}
+
+// ScheduleFor calcula os recebíveis do valor líquido de uma fatura aprovada agora
+func (s *SettlementService) ScheduleFor(invoice *domain.Invoice) ([]*domain.Receivable, error) {
+	account, err := s.accountRepository.FindByID(invoice.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.SettlementSchedule.Receivables(invoice, invoice.Installments, time.Now()), nil
+}
+
+func (s *SettlementService) ReceivablesFor(invoiceID string) ([]*domain.Receivable, error) {
+	return s.receivableRepository.FindByInvoiceID(invoiceID)
+}
+
+func (s *SettlementService) Balance(apiKey string) (*dto.BalanceOutput, error) {
+	account, err := s.accountRepository.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming, err := s.receivableRepository.FindUpcomingByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromBalance(account, upcoming), nil
+}
+
+type SettlementJobConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+func NewSettlementJobConfig() *SettlementJobConfig {
+	config := &SettlementJobConfig{
+		Interval:  24 * time.Hour,
+		BatchSize: 500,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("SETTLEMENT_JOB_INTERVAL")); err == nil && value > 0 {
+		config.Interval = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("SETTLEMENT_BATCH_SIZE")); err == nil && value > 0 {
+		config.BatchSize = value
+	}
+
+	return config
+}
+
+// SettlementJob move para o saldo disponível os recebíveis com data de liquidação vencida.
+// É idempotente: recebíveis atrasados (ex.: o job ficou parado) são liquidados na execução
+// seguinte. Apenas uma réplica executa cada rodada.
+type SettlementJob struct {
+	receivableRepository domain.ReceivableRepository
+	locker               domain.Locker
+	config               *SettlementJobConfig
+}
+
+func NewSettlementJob(
+	receivableRepository domain.ReceivableRepository,
+	locker domain.Locker,
+	config *SettlementJobConfig,
+) *SettlementJob {
+	return &SettlementJob{
+		receivableRepository: receivableRepository,
+		locker:               locker,
+		config:               config,
+	}
+}
+
+// Run liquida imediatamente e depois a cada intervalo, até o contexto ser cancelado
+func (j *SettlementJob) Run(ctx context.Context) error {
+	slog.Info("job de liquidação iniciado", "interval", j.config.Interval)
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.Settle(ctx, time.Now()); err != nil {
+			slog.Error("erro na liquidação de recebíveis", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Settle liquida todos os recebíveis com vencimento até a data de now (UTC)
+func (j *SettlementJob) Settle(ctx context.Context, now time.Time) error {
+	release, acquired, err := j.locker.TryLock(ctx, settlementJobLock)
+	if err != nil {
+		return err
+	}
+
+	if !acquired {
+		slog.Debug("liquidação em execução em outra réplica")
+		return nil
+	}
+	defer release()
+
+	today := now.UTC().Format(time.DateOnly)
+	date, _ := time.Parse(time.DateOnly, today)
+
+	total := 0
+	for {
+		settled, err := j.receivableRepository.SettleDue(date, j.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		total += settled
+		if settled < j.config.BatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	slog.Info("recebíveis liquidados", "date", today, "count", total)
+	return nil
+}
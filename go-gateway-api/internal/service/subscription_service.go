@@ -0,0 +1,706 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain/events"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+const (
+	auditEntityPlan         = "plan"
+	auditEntitySubscription = "subscription"
+)
+
+type DunningConfig struct {
+	Policy domain.DunningPolicy
+}
+
+// NewDunningConfig lê a política de novas tentativas das cobranças recusadas; entradas
+// inválidas são ignoradas e mantêm o padrão (1, 3 e 7 dias, sem tentar de novo cartões
+// vencidos ou suspeitas de fraude, cancelando no fim)
+func NewDunningConfig() *DunningConfig {
+	policy := domain.DefaultDunningPolicy()
+
+	if value := os.Getenv("SUBSCRIPTION_DUNNING_RETRY_DAYS"); value != "" {
+		days := []int{}
+		for _, entry := range strings.Split(value, ",") {
+			day, err := strconv.Atoi(strings.TrimSpace(entry))
+			if err != nil || day < 1 || (len(days) > 0 && day <= days[len(days)-1]) {
+				slog.Warn("entrada inválida nos dias de nova tentativa ignorada", "entry", entry)
+				continue
+			}
+			days = append(days, day)
+		}
+		policy.RetryDays = days
+	}
+
+	if value, found := os.LookupEnv("SUBSCRIPTION_DUNNING_NO_RETRY_REASONS"); found {
+		reasons := map[domain.DeclineReason]bool{}
+		for _, entry := range strings.Split(value, ",") {
+			reason := domain.DeclineReason(strings.TrimSpace(entry))
+			if reason == "" {
+				continue
+			}
+			if !reason.Valid() {
+				slog.Warn("motivo de recusa inválido ignorado", "entry", entry)
+				continue
+			}
+			reasons[reason] = true
+		}
+		policy.NoRetryReasons = reasons
+	}
+
+	switch status := domain.SubscriptionStatus(os.Getenv("SUBSCRIPTION_DUNNING_FINAL_STATUS")); status {
+	case "":
+	case domain.SubscriptionStatusCanceled, domain.SubscriptionStatusUnpaid:
+		policy.FinalStatus = status
+	default:
+		slog.Warn("status final de inadimplência inválido ignorado", "status", status)
+	}
+
+	return &DunningConfig{Policy: policy}
+}
+
+// SubscriptionService cadastra os planos e as assinaturas e cobra cada período no cartão salvo
+// do cliente. As faturas das cobranças seguem o fluxo das faturas de cartão: decididas na hora
+// ou enviadas para a análise de fraude, quando o resultado é aplicado pelo
+// SubscriptionBillingJob. Cobranças recusadas são tentadas de novo conforme a DunningConfig.
+type SubscriptionService struct {
+	subscriptionRepository domain.SubscriptionRepository
+	customerRepository     domain.CustomerRepository
+	invoiceRepository      domain.InvoiceRepository
+	accountService         *AccountService
+	invoiceService         *InvoiceService
+	fxService              *FXService
+	auditService           *AuditService
+	eventPublisher         EventPublisher
+	dunningConfig          *DunningConfig
+}
+
+func NewSubscriptionService(
+	subscriptionRepository domain.SubscriptionRepository,
+	customerRepository domain.CustomerRepository,
+	invoiceRepository domain.InvoiceRepository,
+	accountService *AccountService,
+	invoiceService *InvoiceService,
+	fxService *FXService,
+	auditService *AuditService,
+	eventPublisher EventPublisher,
+	dunningConfig *DunningConfig,
+) *SubscriptionService {
+	return &SubscriptionService{
+		subscriptionRepository: subscriptionRepository,
+		customerRepository:     customerRepository,
+		invoiceRepository:      invoiceRepository,
+		accountService:         accountService,
+		invoiceService:         invoiceService,
+		fxService:              fxService,
+		auditService:           auditService,
+		eventPublisher:         eventPublisher,
+		dunningConfig:          dunningConfig,
+	}
+}
+
+func (s *SubscriptionService) CreatePlan(apiKey string, input dto.CreatePlanInput, actor domain.Actor) (*dto.PlanOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := dto.ToPlan(input, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := dto.FromPlan(plan)
+	audit, err := s.auditService.Event(actor, domain.AuditActionPlanCreated, auditEntityPlan, plan.ID, nil, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepository.SavePlan(plan, audit); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (s *SubscriptionService) ListPlans(apiKey string) ([]*dto.PlanOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := s.subscriptionRepository.FindPlansByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.PlanOutput, len(plans))
+	for i, plan := range plans {
+		output[i] = dto.FromPlan(plan)
+	}
+	return output, nil
+}
+
+func (s *SubscriptionService) GetPlan(id, apiKey string) (*dto.PlanOutput, error) {
+	plan, err := s.subscriptionRepository.FindPlanByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	return dto.FromPlan(plan), nil
+}
+
+// Create inicia a assinatura. Planos sem teste são cobrados na hora; se a cobrança for
+// recusada, a assinatura já nasce em atraso.
+func (s *SubscriptionService) Create(apiKey string, input dto.CreateSubscriptionInput, actor domain.Actor) (*dto.SubscriptionOutput, error) {
+	// Contas suspensas ou encerradas não podem iniciar cobranças
+	account, err := s.accountService.Authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepository.FindByID(input.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer.AccountID != account.ID {
+		return nil, domain.ErrCustomerNotFound
+	}
+
+	plan, err := s.subscriptionRepository.FindPlanByID(input.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := s.paymentMethodFor(customer, input.PaymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if method.Expired(now) {
+		return nil, domain.ErrCardExpired
+	}
+
+	// Recusa na criação os planos em moedas sem cotação para a moeda da conta
+	if _, err := s.fxService.Rate(context.Background(), plan.Currency, domain.Currency(account.Currency)); err != nil {
+		return nil, err
+	}
+
+	subscription, err := domain.NewSubscription(customer, plan, method, now)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionSubscriptionCreated, auditEntitySubscription, subscription.ID, nil, subscriptionAuditState(subscription))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepository.Create(subscription, audit); err != nil {
+		return nil, err
+	}
+
+	if subscription.Due(now) {
+		// Uma falha aqui não desfaz a assinatura: a cobrança continua vencida e o
+		// SubscriptionBillingJob tenta de novo
+		if err := s.charge(subscription, actor); err != nil {
+			slog.Error("erro na primeira cobrança da assinatura", "error", err, "subscription_id", subscription.ID)
+		}
+	}
+
+	return dto.FromSubscription(subscription), nil
+}
+
+func (s *SubscriptionService) List(apiKey string) ([]*dto.SubscriptionOutput, error) {
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.subscriptionRepository.FindByAccountID(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]*dto.SubscriptionOutput, len(subscriptions))
+	for i, subscription := range subscriptions {
+		output[i] = dto.FromSubscription(subscription)
+	}
+	return output, nil
+}
+
+func (s *SubscriptionService) GetByID(id, apiKey string) (*dto.SubscriptionOutput, error) {
+	subscription, err := s.findOwned(id, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.FromSubscription(subscription), nil
+}
+
+// ChangePlan troca o plano da assinatura; a diferença proporcional ao restante do período
+// entra na próxima cobrança
+func (s *SubscriptionService) ChangePlan(id, apiKey string, input dto.ChangeSubscriptionPlanInput, actor domain.Actor) (*dto.SubscriptionOutput, error) {
+	subscription, err := s.findOwned(id, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.subscriptionRepository.FindPlanByID(subscription.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := s.subscriptionRepository.FindPlanByID(input.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if next.AccountID != subscription.AccountID {
+		return nil, domain.ErrPlanNotFound
+	}
+
+	before := subscriptionAuditState(subscription)
+	proration, err := subscription.ChangePlan(current, next, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	after := subscriptionAuditState(subscription)
+	after["proration"] = proration
+	audit, err := s.auditService.Event(actor, domain.AuditActionSubscriptionUpdated, auditEntitySubscription, subscription.ID, before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepository.Update(subscription, audit); err != nil {
+		return nil, err
+	}
+
+	return dto.FromSubscription(subscription), nil
+}
+
+// ChangePaymentMethod troca o cartão das próximas cobranças
+func (s *SubscriptionService) ChangePaymentMethod(id, apiKey string, input dto.ChangeSubscriptionPaymentMethodInput, actor domain.Actor) (*dto.SubscriptionOutput, error) {
+	subscription, err := s.findOwned(id, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepository.FindByID(subscription.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := s.paymentMethodFor(customer, input.PaymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if method.Expired(now) {
+		return nil, domain.ErrCardExpired
+	}
+
+	before := subscriptionAuditState(subscription)
+	if err := subscription.ChangePaymentMethod(method, now); err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionSubscriptionUpdated, auditEntitySubscription, subscription.ID, before, subscriptionAuditState(subscription))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepository.Update(subscription, audit); err != nil {
+		return nil, err
+	}
+
+	return dto.FromSubscription(subscription), nil
+}
+
+// Cancel encerra a assinatura na hora ou agenda o encerramento para o fim do período
+func (s *SubscriptionService) Cancel(id, apiKey string, input dto.CancelSubscriptionInput, actor domain.Actor) (*dto.SubscriptionOutput, error) {
+	subscription, err := s.findOwned(id, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	before := subscriptionAuditState(subscription)
+	if err := subscription.Cancel(input.AtPeriodEnd, time.Now()); err != nil {
+		return nil, err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionSubscriptionCanceled, auditEntitySubscription, subscription.ID, before, subscriptionAuditState(subscription))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepository.Update(subscription, audit); err != nil {
+		return nil, err
+	}
+
+	return dto.FromSubscription(subscription), nil
+}
+
+func (s *SubscriptionService) findOwned(id, apiKey string) (*domain.Subscription, error) {
+	subscription, err := s.subscriptionRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.AccountID != account.ID {
+		return nil, domain.ErrUnauthorizedAccess
+	}
+
+	return subscription, nil
+}
+
+// paymentMethodFor busca um cartão salvo do cliente; cartões de outros clientes não são
+// encontrados
+func (s *SubscriptionService) paymentMethodFor(customer *domain.Customer, id string) (*domain.PaymentMethod, error) {
+	method, err := s.customerRepository.FindPaymentMethodByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if method.CustomerID != customer.ID {
+		return nil, domain.ErrPaymentMethodNotFound
+	}
+
+	return method, nil
+}
+
+// charge cobra o próximo período da assinatura. Quando o crédito das trocas de plano cobre o
+// valor, o período é renovado sem fatura.
+func (s *SubscriptionService) charge(subscription *domain.Subscription, actor domain.Actor) error {
+	plan, err := s.subscriptionRepository.FindPlanByID(subscription.PlanID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	before := subscriptionAuditState(subscription)
+
+	if subscription.ChargeAmount(plan) <= 0 {
+		subscription.CoverWithCredit(plan, now)
+		audit, err := s.auditService.Event(actor, domain.AuditActionSubscriptionStatusChanged, auditEntitySubscription, subscription.ID, before, subscriptionAuditState(subscription))
+		if err != nil {
+			return err
+		}
+
+		if err := s.subscriptionRepository.Update(subscription, audit); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	account, err := s.accountService.FindByID(subscription.AccountID)
+	if err != nil {
+		return err
+	}
+
+	customer, err := s.customerRepository.FindByID(subscription.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	method, err := s.customerRepository.FindPaymentMethodByID(subscription.PaymentMethodID)
+	if err != nil {
+		return err
+	}
+
+	invoice, err := domain.NewSubscriptionInvoice(subscription, plan, customer, method, now)
+	if err != nil {
+		return err
+	}
+
+	if err := invoice.SetCurrency(plan.Currency, domain.Currency(account.Currency)); err != nil {
+		return err
+	}
+
+	// Faturas de cartões vencidos já nascem recusadas e não passam pela decisão
+	var approval *domain.InvoiceApproval
+	if invoice.Status == domain.StatusPending {
+		rate, err := s.fxService.Rate(context.Background(), invoice.Currency, invoice.SettlementCurrency)
+		if err != nil {
+			return err
+		}
+
+		approval, err = s.invoiceService.decide(invoice, rate)
+		if err != nil {
+			return err
+		}
+	}
+
+	subscription.StartCharge(invoice, now)
+	if err := subscription.ApplyCharge(invoice, plan, s.dunningConfig.Policy, now); err != nil {
+		return err
+	}
+
+	created, err := s.auditService.Event(actor, domain.AuditActionInvoiceCreated, auditEntityInvoice, invoice.ID, nil, invoiceAuditState(invoice))
+	if err != nil {
+		return err
+	}
+
+	changed, err := s.auditService.Event(actor, domain.AuditActionSubscriptionStatusChanged, auditEntitySubscription, subscription.ID, before, subscriptionAuditState(subscription))
+	if err != nil {
+		return err
+	}
+
+	if err := s.subscriptionRepository.SaveCharge(subscription, invoice, approval, created, changed); err != nil {
+		return err
+	}
+
+	slog.Info("cobrança de assinatura criada",
+		"subscription_id", subscription.ID,
+		"invoice_id", invoice.ID,
+		"amount", invoice.Amount,
+		"status", invoice.Status)
+
+	s.publishPaymentFailed(subscription, invoice)
+	return nil
+}
+
+// applyDecidedCharge aplica à assinatura o resultado da análise de fraude da última cobrança
+func (s *SubscriptionService) applyDecidedCharge(subscription *domain.Subscription, actor domain.Actor) error {
+	invoice, err := s.invoiceRepository.FindByID(subscription.LatestInvoiceID)
+	if err != nil {
+		return err
+	}
+
+	plan, err := s.subscriptionRepository.FindPlanByID(subscription.PlanID)
+	if err != nil {
+		return err
+	}
+
+	before := subscriptionAuditState(subscription)
+	canceled := subscription.Status == domain.SubscriptionStatusCanceled
+	if err := subscription.ApplyCharge(invoice, plan, s.dunningConfig.Policy, time.Now()); err != nil {
+		return err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionSubscriptionStatusChanged, auditEntitySubscription, subscription.ID, before, subscriptionAuditState(subscription))
+	if err != nil {
+		return err
+	}
+
+	if err := s.subscriptionRepository.Update(subscription, audit); err != nil {
+		return err
+	}
+
+	// O resultado de uma assinatura já cancelada não conta como tentativa
+	if !canceled {
+		s.publishPaymentFailed(subscription, invoice)
+	}
+	return nil
+}
+
+// publishPaymentFailed avisa o lojista de cada cobrança recusada ou expirada. A assinatura já
+// foi gravada, então uma falha na publicação é apenas registrada.
+func (s *SubscriptionService) publishPaymentFailed(subscription *domain.Subscription, invoice *domain.Invoice) {
+	if invoice.Status != domain.StatusRejected && invoice.Status != domain.StatusExpired {
+		return
+	}
+
+	failed := events.NewSubscriptionPaymentFailed(
+		subscription.AccountID,
+		subscription.ID,
+		subscription.CustomerID,
+		invoice.ID,
+		invoice.TotalAmount,
+		string(invoice.Currency),
+		subscription.FailedAttempts,
+		string(invoice.DeclineReason),
+		string(subscription.Status),
+		subscription.NextRetryAt,
+		invoice.UpdatedAt,
+	)
+
+	if err := s.eventPublisher.Publish(context.Background(), subscription.AccountID, events.SubscriptionPaymentFailedEventType, events.SubscriptionPaymentFailedVersion, failed); err != nil {
+		slog.Error("erro ao publicar a falha de cobrança da assinatura",
+			"error", err,
+			"subscription_id", subscription.ID,
+			"invoice_id", invoice.ID)
+		return
+	}
+
+	slog.Warn("cobrança de assinatura recusada",
+		"subscription_id", subscription.ID,
+		"invoice_id", invoice.ID,
+		"attempt", subscription.FailedAttempts,
+		"decline_reason", invoice.DeclineReason,
+		"status", subscription.Status)
+}
+
+// endAtPeriodEnd encerra a assinatura cujo cancelamento foi agendado para o fim do período
+func (s *SubscriptionService) endAtPeriodEnd(subscription *domain.Subscription, actor domain.Actor) error {
+	before := subscriptionAuditState(subscription)
+	if err := subscription.Cancel(false, time.Now()); err != nil {
+		return err
+	}
+
+	audit, err := s.auditService.Event(actor, domain.AuditActionSubscriptionCanceled, auditEntitySubscription, subscription.ID, before, subscriptionAuditState(subscription))
+	if err != nil {
+		return err
+	}
+
+	if err := s.subscriptionRepository.Update(subscription, audit); err != nil {
+		return err
+	}
+	return nil
+}
+
+func subscriptionAuditState(subscription *domain.Subscription) map[string]any {
+	return map[string]any{
+		"account_id":           subscription.AccountID,
+		"customer_id":          subscription.CustomerID,
+		"plan_id":              subscription.PlanID,
+		"payment_method_id":    subscription.PaymentMethodID,
+		"status":               subscription.Status,
+		"current_period_end":   subscription.CurrentPeriodEnd,
+		"cancel_at_period_end": subscription.CancelAtPeriodEnd,
+		"proration_amount":     subscription.ProrationAmount,
+		"latest_invoice_id":    subscription.LatestInvoiceID,
+		"failed_attempts":      subscription.FailedAttempts,
+		"next_retry_at":        subscription.NextRetryAt,
+	}
+}
+
+const subscriptionBillingJobLock = "subscription_billing_job"
+
+type SubscriptionBillingJobConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+func NewSubscriptionBillingJobConfig() *SubscriptionBillingJobConfig {
+	config := &SubscriptionBillingJobConfig{
+		Interval:  time.Minute,
+		BatchSize: 100,
+	}
+
+	if value, err := time.ParseDuration(os.Getenv("SUBSCRIPTION_BILLING_INTERVAL")); err == nil && value > 0 {
+		config.Interval = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("SUBSCRIPTION_BILLING_BATCH_SIZE")); err == nil && value > 0 {
+		config.BatchSize = value
+	}
+
+	return config
+}
+
+// SubscriptionBillingJob cobra as assinaturas na data de cobrança, tenta de novo as cobranças
+// em atraso, encerra as canceladas no fim do período e aplica o resultado das cobranças que estavam na análise de fraude.
+// Apenas uma réplica executa cada rodada.
+type SubscriptionBillingJob struct {
+	subscriptionService    *SubscriptionService
+	subscriptionRepository domain.SubscriptionRepository
+	locker                 domain.Locker
+	config                 *SubscriptionBillingJobConfig
+}
+
+func NewSubscriptionBillingJob(
+	subscriptionService *SubscriptionService,
+	subscriptionRepository domain.SubscriptionRepository,
+	locker domain.Locker,
+	config *SubscriptionBillingJobConfig,
+) *SubscriptionBillingJob {
+	return &SubscriptionBillingJob{
+		subscriptionService:    subscriptionService,
+		subscriptionRepository: subscriptionRepository,
+		locker:                 locker,
+		config:                 config,
+	}
+}
+
+// Run executa a cobrança periodicamente até o contexto ser cancelado
+func (j *SubscriptionBillingJob) Run(ctx context.Context) error {
+	slog.Info("job de cobrança de assinaturas iniciado", "interval", j.config.Interval)
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := j.BillDue(ctx, time.Now()); err != nil {
+				slog.Error("erro na cobrança de assinaturas", "error", err)
+			}
+		}
+	}
+}
+
+func (j *SubscriptionBillingJob) BillDue(ctx context.Context, now time.Time) error {
+	release, acquired, err := j.locker.TryLock(ctx, subscriptionBillingJobLock)
+	if err != nil {
+		return err
+	}
+
+	if !acquired {
+		slog.Debug("cobrança de assinaturas em execução em outra réplica")
+		return nil
+	}
+	defer release()
+
+	actor := domain.NewSystemActor(subscriptionBillingJobLock)
+
+	// Os resultados pendentes são aplicados antes, liberando essas assinaturas para a
+	// próxima cobrança
+	decided, err := j.subscriptionRepository.FindDecidedCharges(j.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range decided {
+		// Uma falha não impede o tratamento das demais assinaturas
+		if err := j.subscriptionService.applyDecidedCharge(subscription, actor); err != nil {
+			slog.Error("erro ao aplicar o resultado da cobrança", "error", err, "subscription_id", subscription.ID)
+		}
+	}
+
+	due, err := j.subscriptionRepository.FindDue(now, j.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range due {
+		if subscription.CancelAtPeriodEnd {
+			err = j.subscriptionService.endAtPeriodEnd(subscription, actor)
+		} else {
+			err = j.subscriptionService.charge(subscription, actor)
+		}
+
+		if err != nil {
+			slog.Error("erro ao cobrar assinatura", "error", err, "subscription_id", subscription.ID)
+		}
+	}
+
+	return nil
+}
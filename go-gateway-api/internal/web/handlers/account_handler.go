@@ -4,51 +4,154 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
 )
 
 type AccountHandler struct {
-	accountService *service.AccountService // Este handler vai precisar acessar o service, logo o service vai ser uma dependencia dele
+	accountService    *service.AccountService // Este handler vai precisar acessar o service, logo o service vai ser uma dependencia dele
+	exportService     *service.DataExportService
+	settlementService *service.SettlementService
 }
 
-func NewAccountHandlers(accountService *service.AccountService) *AccountHandler {
-	return &AccountHandler{accountService: accountService}
+func NewAccountHandlers(
+	accountService *service.AccountService,
+	exportService *service.DataExportService,
+	settlementService *service.SettlementService,
+) *AccountHandler {
+	return &AccountHandler{
+		accountService:    accountService,
+		exportService:     exportService,
+		settlementService: settlementService,
+	}
 }
 
 // Endpoints que o handler vai expor (controller com requests e responses)
-func (h *AccountHandler) Create(w http.ResponseWriter, r *http.Request) {
-	var input dto.CreateAccountInput
-	err := json.NewDecoder(r.Body).Decode(&input)
+func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
+	apiKey := r.Header.Get("X-API-Key")
+	if apiKey == "" {
+		http.Error(w, "API Key is required", http.StatusUnauthorized)
+		return
+	}
+
+	output, err := h.accountService.FindByAPIKey(apiKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /accounts/balance
+// Method: GET
+func (h *AccountHandler) Balance(w http.ResponseWriter, r *http.Request) {
+	output, err := h.settlementService.Balance(r.Header.Get("X-API-KEY"))
 	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /accounts
+// Method: PATCH
+func (h *AccountHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input dto.UpdateAccountInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	output, err := h.accountService.CreateAccount(input)
+	output, err := h.accountService.UpdateProfile(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeAccountError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(output)
 }
 
-func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
-	apiKey := r.Header.Get("X-API-Key")
-	if apiKey == "" {
-		http.Error(w, "API Key is required", http.StatusUnauthorized)
+// Endpoint: /accounts/close
+// Method: POST
+func (h *AccountHandler) Close(w http.ResponseWriter, r *http.Request) {
+	account, err := h.accountService.FindByAPIKey(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeAccountError(w, err)
 		return
 	}
 
-	output, err := h.accountService.FindByAPIKey(apiKey)
+	output, err := h.accountService.Close(account.ID, middleware.ActorFromRequest(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeAccountError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(output)
-}
\ No newline at end of file
+}
+
+// Endpoint: /accounts/api-key/rotate
+// Method: POST
+func (h *AccountHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	output, err := h.accountService.RotateAPIKey(r.Header.Get("X-API-KEY"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /accounts/publishable-key/rotate
+// Method: POST
+func (h *AccountHandler) RotatePublishableKey(w http.ResponseWriter, r *http.Request) {
+	output, err := h.accountService.RotatePublishableKey(r.Header.Get("X-API-KEY"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /accounts/export
+// Method: GET
+func (h *AccountHandler) Export(w http.ResponseWriter, r *http.Request) {
+	account, err := h.accountService.FindByAPIKey(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+
+	output, err := h.exportService.ExportAccount(account.ID)
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="account-export.json"`)
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeAccountError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidName, domain.ErrInvalidEmail:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrDuplicatedEmail, domain.ErrDuplicatedAPIKey, domain.ErrDuplicatedPublishableKey, domain.ErrNonZeroBalance, domain.ErrInvalidAccountStatus:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminAccountHandler expõe a gestão de contas para operadores internos
+type AdminAccountHandler struct {
+	accountService *service.AccountService
+	invoiceService *service.InvoiceService
+	exportService  *service.DataExportService
+}
+
+func NewAdminAccountHandler(
+	accountService *service.AccountService,
+	invoiceService *service.InvoiceService,
+	exportService *service.DataExportService,
+) *AdminAccountHandler {
+	return &AdminAccountHandler{
+		accountService: accountService,
+		invoiceService: invoiceService,
+		exportService:  exportService,
+	}
+}
+
+// Endpoint: /admin/accounts
+// Method: POST
+func (h *AdminAccountHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateAccountInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.accountService.CreateAccount(input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/accounts/{id}
+// Method: GET
+func (h *AdminAccountHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	output, err := h.accountService.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+
+	// A API key é uma credencial do lojista e não é exibida para operadores
+	output.APIKey = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/accounts/{id}/suspend
+// Method: POST
+func (h *AdminAccountHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.accountService.Suspend)
+}
+
+// Endpoint: /admin/accounts/{id}/reactivate
+// Method: POST
+func (h *AdminAccountHandler) Reactivate(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.accountService.Reactivate)
+}
+
+func (h *AdminAccountHandler) changeStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	transition func(id string, input dto.AccountStatusInput, actor domain.Actor) (*dto.AccountOutput, error),
+) {
+	var input dto.AccountStatusInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	output, err := transition(chi.URLParam(r, "id"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+	output.APIKey = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/accounts/{id}/close
+// Method: POST
+func (h *AdminAccountHandler) Close(w http.ResponseWriter, r *http.Request) {
+	output, err := h.accountService.Close(chi.URLParam(r, "id"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+	output.APIKey = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/accounts/{id}/export
+// Method: GET
+func (h *AdminAccountHandler) Export(w http.ResponseWriter, r *http.Request) {
+	output, err := h.exportService.ExportAccount(chi.URLParam(r, "id"))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/accounts/{id}/invoices
+// Method: GET
+func (h *AdminAccountHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	account, err := h.accountService.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+
+	output, err := h.invoiceService.ListByAccount(account.ID, r.URL.Query().Get("payer_document"))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/invoices?payer_document=...
+// Method: GET
+// Faturas de um pagador em todas as contas
+func (h *AdminAccountHandler) ListInvoicesByPayer(w http.ResponseWriter, r *http.Request) {
+	output, err := h.invoiceService.ListByPayerDocument(r.URL.Query().Get("payer_document"))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/accounts/{id}/settlement-schedule
+// Method: PUT
+func (h *AdminAccountHandler) UpdateSettlementSchedule(w http.ResponseWriter, r *http.Request) {
+	var input dto.SettlementScheduleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.accountService.UpdateSettlementSchedule(chi.URLParam(r, "id"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+	output.APIKey = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/accounts/{id}/balance-adjustments
+// Method: POST
+func (h *AdminAccountHandler) AdjustBalance(w http.ResponseWriter, r *http.Request) {
+	var input dto.BalanceAdjustmentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.accountService.AdjustBalance(chi.URLParam(r, "id"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeAdminAccountError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeAdminAccountError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrReasonRequired, domain.ErrInvalidAdjustmentAmount, domain.ErrInvalidName, domain.ErrInvalidEmail, domain.ErrInvalidSettlementSchedule, domain.ErrInvalidTaxID, domain.ErrUnsupportedCurrency:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrInvalidAccountStatus, domain.ErrInsufficientBalance, domain.ErrNonZeroBalance, domain.ErrDuplicatedEmail:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type AnticipationHandler struct {
+	service *service.AnticipationService
+}
+
+func NewAnticipationHandler(service *service.AnticipationService) *AnticipationHandler {
+	return &AnticipationHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /receivables/anticipatable
+// Method: GET
+func (h *AnticipationHandler) ListAnticipatable(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListAnticipatable(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeAnticipationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /anticipations/simulate
+// Method: POST
+func (h *AnticipationHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	var input dto.AnticipationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.Simulate(r.Header.Get("X-API-KEY"), input)
+	if err != nil {
+		writeAnticipationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /anticipations
+// Method: POST
+func (h *AnticipationHandler) Request(w http.ResponseWriter, r *http.Request) {
+	var input dto.AnticipationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.Request(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeAnticipationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /anticipations/{id}
+// Method: GET
+func (h *AnticipationHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.GetByID(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeAnticipationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /anticipations
+// Method: GET
+func (h *AnticipationHandler) ListByAccount(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListByAccountAPIKey(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeAnticipationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeAnticipationError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrUnauthorizedAccess, domain.ErrAccountSuspended, domain.ErrAccountClosed:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrAnticipationNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrNoReceivablesToAnticipate, domain.ErrReceivableNotAnticipatable:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxBoletoCallbackSize limita o corpo dos avisos de liquidação do banco
+const maxBoletoCallbackSize = 64 << 10
+
+type BoletoHandler struct {
+	service *service.BoletoService
+}
+
+func NewBoletoHandler(service *service.BoletoService) *BoletoHandler {
+	return &BoletoHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /invoice/{id}/boleto
+// Method: GET
+// Boleto em HTML para impressão ou para salvar em PDF pelo navegador
+func (h *BoletoHandler) Document(w http.ResponseWriter, r *http.Request) {
+	document, err := h.service.Document(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"))
+	if err != nil {
+		switch err {
+		case domain.ErrBoletoNotFound, domain.ErrInvoiceNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case domain.ErrAccountNotFound:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case domain.ErrUnauthorizedAccess:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(document)
+}
+
+// Endpoint: /webhooks/boleto
+// Method: POST
+// Aviso de liquidação do banco, autenticado pela assinatura do corpo e não por API key
+func (h *BoletoHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBoletoCallbackSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.HandleCallback(body, r.Header.Get(service.BoletoCallbackSignatureHeader))
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidBoletoSignature:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case domain.ErrBoletoNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case domain.ErrBoletoExpired, domain.ErrBoletoAlreadyPaid, domain.ErrBoletoAmountMismatch, domain.ErrInvalidStatus:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+)
+
+type CardTokenHandler struct {
+	service *service.CardTokenService
+}
+
+func NewCardTokenHandler(service *service.CardTokenService) *CardTokenHandler {
+	return &CardTokenHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /tokens
+// Method: POST
+func (h *CardTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateCardTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	account := middleware.PublishableAccountFromContext(r.Context())
+	output, err := h.service.Create(account.ID, input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidCard:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case domain.ErrCardExpired:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type CheckoutHandler struct {
+	service *service.CheckoutService
+}
+
+func NewCheckoutHandler(service *service.CheckoutService) *CheckoutHandler {
+	return &CheckoutHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /checkout/sessions
+// Method: POST
+func (h *CheckoutHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateCheckoutSessionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.CreateSession(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /checkout/sessions/{id}
+// Method: GET
+func (h *CheckoutHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.GetSession(chi.URLParam(r, "id"))
+	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /checkout/sessions/{id}/confirm
+// Method: POST
+func (h *CheckoutHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	var input dto.ConfirmCheckoutSessionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	account := middleware.PublishableAccountFromContext(r.Context())
+	output, err := h.service.Confirm(chi.URLParam(r, "id"), account.ID, input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /payment-links
+// Method: POST
+func (h *CheckoutHandler) CreatePaymentLink(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreatePaymentLinkInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.CreatePaymentLink(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /payment-links
+// Method: GET
+func (h *CheckoutHandler) ListPaymentLinks(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListPaymentLinks(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /payment-links/{id}/deactivate
+// Method: POST
+func (h *CheckoutHandler) DeactivatePaymentLink(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.DeactivatePaymentLink(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /payment-links/{id}/sessions
+// Method: POST
+func (h *CheckoutHandler) CreateSessionFromLink(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.CreateSessionFromLink(chi.URLParam(r, "id"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCheckoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeCheckoutError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrUnauthorizedAccess, domain.ErrAccountSuspended, domain.ErrAccountClosed:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrCheckoutSessionNotFound, domain.ErrPaymentLinkNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidAmount, domain.ErrInvalidRedirectURL, domain.ErrUnsupportedCurrency,
+		domain.ErrInvalidInstallments, domain.ErrInvalidPayerName, domain.ErrInvalidTaxID,
+		domain.ErrInvalidPayerEmail, domain.ErrInvalidBillingAddress:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrCheckoutSessionNotOpen:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case domain.ErrInvalidCard:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrPaymentLinkSessionLimit:
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case domain.ErrCheckoutSessionExpired, domain.ErrCheckoutSessionFailed, domain.ErrPaymentLinkInactive, domain.ErrExchangeRateNotFound,
+		domain.ErrCardTokenNotFound, domain.ErrCardTokenUsed, domain.ErrCardTokenExpired:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxCNABReturnSize limita o tamanho dos arquivos de retorno enviados
+const maxCNABReturnSize = 10 << 20
+
+// CNABHandler expõe aos operadores a geração de remessas e o envio dos retornos dos bancos
+type CNABHandler struct {
+	service *service.CNABService
+}
+
+func NewCNABHandler(service *service.CNABService) *CNABHandler {
+	return &CNABHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /admin/cnab/remittances
+// Method: POST
+func (h *CNABHandler) GenerateRemittance(w http.ResponseWriter, r *http.Request) {
+	var input dto.CNABRemittanceInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.GenerateRemittance(input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCNABError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/cnab/returns?file_name=...
+// Method: POST
+// O corpo é o arquivo de retorno como recebido do banco
+func (h *CNABHandler) UploadReturn(w http.ResponseWriter, r *http.Request) {
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCNABReturnSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, duplicate, err := h.service.ProcessReturn(r.URL.Query().Get("file_name"), content, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCNABError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !duplicate {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/cnab/files
+// Method: GET
+func (h *CNABHandler) ListFiles(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListFiles()
+	if err != nil {
+		writeCNABError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/cnab/files/{id}
+// Method: GET
+func (h *CNABHandler) GetFile(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.GetFile(chi.URLParam(r, "id"))
+	if err != nil {
+		writeCNABError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/cnab/files/{id}/content
+// Method: GET
+func (h *CNABHandler) Content(w http.ResponseWriter, r *http.Request) {
+	file, err := h.service.Content(chi.URLParam(r, "id"))
+	if err != nil {
+		writeCNABError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=us-ascii")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	io.WriteString(w, file.Content)
+}
+
+func writeCNABError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrCNABFileNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidCNABKind, domain.ErrInvalidCNABLayout:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrInvalidCNABFile, domain.ErrNothingToRemit, domain.ErrCNABPayoutsDisabled:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case domain.ErrInvalidStatus, domain.ErrInvalidPayoutStatus:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
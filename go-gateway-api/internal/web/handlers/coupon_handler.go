@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type CouponHandler struct {
+	service *service.CouponService
+}
+
+func NewCouponHandler(service *service.CouponService) *CouponHandler {
+	return &CouponHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /coupons
+// Method: POST
+func (h *CouponHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateCouponInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.Create(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCouponError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /coupons
+// Method: GET
+func (h *CouponHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.List(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeCouponError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /coupons/{id}/deactivate
+// Method: POST
+func (h *CouponHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.Deactivate(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCouponError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeCouponError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrUnauthorizedAccess, domain.ErrAccountSuspended, domain.ErrAccountClosed:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrCouponNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidCoupon, domain.ErrUnsupportedCurrency:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrDuplicatedCouponCode:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case domain.ErrCouponInactive:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
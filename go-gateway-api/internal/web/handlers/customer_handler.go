@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type CustomerHandler struct {
+	service *service.CustomerService
+}
+
+func NewCustomerHandler(service *service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /customers
+// Method: POST
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateCustomerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.Create(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /customers
+// Method: GET
+func (h *CustomerHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.List(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /customers/{id}
+// Method: GET
+func (h *CustomerHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.GetByID(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /customers/{id}/payment-methods
+// Method: POST
+func (h *CustomerHandler) AddPaymentMethod(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreatePaymentMethodInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.AddPaymentMethod(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /customers/{id}/payment-methods
+// Method: GET
+func (h *CustomerHandler) ListPaymentMethods(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListPaymentMethods(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeCustomerError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrUnauthorizedAccess:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrCustomerNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidPayerName, domain.ErrInvalidPayerEmail, domain.ErrInvalidTaxID, domain.ErrInvalidCard:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrCardExpired:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
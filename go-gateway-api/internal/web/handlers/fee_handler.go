@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// FeeHandler expõe aos operadores as regras de MDR de cada conta
+type FeeHandler struct {
+	service *service.FeeService
+}
+
+func NewFeeHandler(service *service.FeeService) *FeeHandler {
+	return &FeeHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /admin/accounts/{id}/fee-rules
+// Method: GET
+func (h *FeeHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListRules(chi.URLParam(r, "id"))
+	if err != nil {
+		writeFeeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/accounts/{id}/fee-rules
+// Method: PUT
+func (h *FeeHandler) ReplaceRules(w http.ResponseWriter, r *http.Request) {
+	var input dto.FeeRulesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.ReplaceRules(chi.URLParam(r, "id"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeFeeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeFeeError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidFeeRule:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
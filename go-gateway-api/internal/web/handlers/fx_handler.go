@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// FXHandler expõe aos operadores as cotações usadas na conversão das faturas e as cotações
+// fixadas manualmente
+type FXHandler struct {
+	service *service.FXService
+}
+
+func NewFXHandler(service *service.FXService) *FXHandler {
+	return &FXHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /admin/fx/rates/{base}/{quote}
+// Method: GET
+func (h *FXHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.Quote(chi.URLParam(r, "base"), chi.URLParam(r, "quote"))
+	if err != nil {
+		writeFXError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/fx/overrides
+// Method: GET
+func (h *FXHandler) ListOverrides(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListOverrides()
+	if err != nil {
+		writeFXError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/fx/overrides/{base}/{quote}
+// Method: PUT
+func (h *FXHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	var input dto.ExchangeRateOverrideInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.SetOverride(chi.URLParam(r, "base"), chi.URLParam(r, "quote"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeFXError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/fx/overrides/{base}/{quote}
+// Method: DELETE
+func (h *FXHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	err := h.service.DeleteOverride(chi.URLParam(r, "base"), chi.URLParam(r, "quote"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeFXError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeFXError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrExchangeRateNotFound, domain.ErrExchangeRateOverrideNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrUnsupportedCurrency, domain.ErrInvalidExchangeRate, domain.ErrInvalidExchangeRateExpiration, domain.ErrReasonRequired:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
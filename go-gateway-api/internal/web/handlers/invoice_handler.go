@@ -7,6 +7,7 @@ import (
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -32,10 +33,27 @@ func (h *InvoiceHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	input.APIKey = r.Header.Get("X-API-KEY")
 
-	output, err := h.service.Create(input)
+	output, err := h.service.Create(input, middleware.ActorFromRequest(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		switch err {
+		case domain.ErrInvalidAmount, domain.ErrInvalidInstallments, domain.ErrInvalidInterestMode,
+			domain.ErrInvalidPixChargeType, domain.ErrInvalidPixExpiration,
+			domain.ErrInvalidPayerName, domain.ErrInvalidTaxID, domain.ErrInvalidPayerEmail, domain.ErrInvalidBillingAddress,
+			domain.ErrInvalidBoletoDueDate, domain.ErrInvalidBoletoCharges,
+			domain.ErrUnsupportedCurrency, domain.ErrCurrencyNotSupportedByPaymentType,
+			domain.ErrInvalidLineItem, domain.ErrInvalidDiscount, domain.ErrInvalidTaxLine,
+			domain.ErrLineItemsRequired, domain.ErrInvoiceAmountMismatch:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case domain.ErrPixKeyNotConfigured, domain.ErrExchangeRateNotFound,
+			domain.ErrCouponNotFound, domain.ErrCouponInactive, domain.ErrCouponExpired,
+			domain.ErrCouponRedemptionLimitReached, domain.ErrCouponCurrencyMismatch:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -89,12 +107,16 @@ func (h *InvoiceHandler) ListByAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	output, err := h.service.ListByAccountAPIKey(apiKey)
+	// payer_document filtra pelas faturas de um pagador (CPF ou CNPJ)
+	output, err := h.service.ListByAccountAPIKey(apiKey, r.URL.Query().Get("payer_document"))
 	if err != nil {
 		switch err {
 		case domain.ErrAccountNotFound:
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
+		case domain.ErrInvalidTaxID:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
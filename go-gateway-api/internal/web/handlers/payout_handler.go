@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type PayoutHandler struct {
+	service *service.PayoutService
+}
+
+func NewPayoutHandler(service *service.PayoutService) *PayoutHandler {
+	return &PayoutHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /payout-destinations
+// Method: POST
+func (h *PayoutHandler) CreateDestination(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreatePayoutDestinationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.CreateDestination(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writePayoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /payout-destinations
+// Method: GET
+func (h *PayoutHandler) ListDestinations(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListDestinations(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writePayoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /payouts
+// Method: POST
+func (h *PayoutHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreatePayoutInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.Create(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writePayoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /payouts/{id}
+// Method: GET
+func (h *PayoutHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.GetByID(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writePayoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /payouts
+// Method: GET
+func (h *PayoutHandler) ListByAccount(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListByAccountAPIKey(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writePayoutError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writePayoutError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrUnauthorizedAccess:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrPayoutNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidAmount, domain.ErrInvalidPayoutDestination:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrPayoutDestinationNotFound, domain.ErrInsufficientBalance, domain.ErrPayoutDestinationNotSupported:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxPixCallbackSize limita o corpo dos callbacks do PSP
+const maxPixCallbackSize = 64 << 10
+
+type PixHandler struct {
+	service *service.PixService
+}
+
+func NewPixHandler(service *service.PixService) *PixHandler {
+	return &PixHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /invoice/{id}/pix/qrcode
+// Method: GET
+func (h *PixHandler) QRCode(w http.ResponseWriter, r *http.Request) {
+	image, err := h.service.QRCode(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"))
+	if err != nil {
+		switch err {
+		case domain.ErrPixChargeNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case domain.ErrAccountNotFound:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case domain.ErrUnauthorizedAccess:
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(image)
+}
+
+// Endpoint: /webhooks/pix
+// Method: POST
+// Callback do PSP, autenticado pela assinatura HMAC do corpo e não por API key
+func (h *PixHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPixCallbackSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.HandleCallback(body, r.Header.Get(service.PixCallbackSignatureHeader))
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidPixSignature:
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case domain.ErrPixChargeNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case domain.ErrPixChargeExpired, domain.ErrPixChargeAlreadyPaid, domain.ErrPixAmountMismatch, domain.ErrInvalidStatus:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
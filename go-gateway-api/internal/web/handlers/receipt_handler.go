@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type ReceiptHandler struct {
+	service *service.ReceiptService
+}
+
+func NewReceiptHandler(service *service.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /invoice/{id}/receipt
+// Method: GET
+// Recibo em HTML (padrão) ou PDF, escolhido por ?format=pdf ou pelo header Accept
+func (h *ReceiptHandler) Receipt(w http.ResponseWriter, r *http.Request) {
+	value := r.URL.Query().Get("format")
+	if value == "" && strings.Contains(r.Header.Get("Accept"), "application/pdf") {
+		value = string(domain.ReceiptFormatPDF)
+	}
+
+	format, err := domain.ParseReceiptFormat(value)
+	if err != nil {
+		writeReceiptError(w, err)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	receipt, err := h.service.Receipt(id, r.Header.Get("X-API-KEY"), format)
+	if err != nil {
+		writeReceiptError(w, err)
+		return
+	}
+
+	if format == domain.ReceiptFormatPDF {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `inline; filename="recibo-`+id+`.pdf"`)
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	w.Write(receipt)
+}
+
+// Endpoint: /accounts/receipt-settings
+// Method: GET
+func (h *ReceiptHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.GetSettings(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeReceiptError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /accounts/receipt-settings
+// Method: PUT
+func (h *ReceiptHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var input dto.UpdateReceiptSettingsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.UpdateSettings(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeReceiptError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeReceiptError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrUnauthorizedAccess, domain.ErrAccountSuspended, domain.ErrAccountClosed:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrInvoiceNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrUnsupportedReceiptFormat, domain.ErrInvalidReceiptSettings, domain.ErrInvalidReceiptLogo:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
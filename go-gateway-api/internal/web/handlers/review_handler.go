@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewHandler struct {
+	service *service.ReviewService
+}
+
+func NewReviewHandler(service *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /admin/reviews
+// Method: GET
+func (h *ReviewHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	output, err := h.service.ListQueue(limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/reviews/{id}/claim
+// Method: POST
+func (h *ReviewHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.Claim(chi.URLParam(r, "id"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/reviews/{id}/claim
+// Method: DELETE
+func (h *ReviewHandler) Release(w http.ResponseWriter, r *http.Request) {
+	err := h.service.Release(chi.URLParam(r, "id"), middleware.ActorFromRequest(r))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Endpoint: /admin/reviews/{id}/approve
+// Method: POST
+func (h *ReviewHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, domain.ReviewActionApprove)
+}
+
+// Endpoint: /admin/reviews/{id}/reject
+// Method: POST
+func (h *ReviewHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, domain.ReviewActionReject)
+}
+
+func (h *ReviewHandler) decide(w http.ResponseWriter, r *http.Request, action domain.ReviewAction) {
+	var input dto.ReviewDecisionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.Decide(chi.URLParam(r, "id"), middleware.ActorFromRequest(r), action, input)
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /admin/reviews/{id}/actions
+// Method: GET
+func (h *ReviewHandler) ListActions(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListActions(chi.URLParam(r, "id"))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeReviewError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvoiceNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrReasonRequired:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrInvalidStatus, domain.ErrInvoiceAlreadyClaimed, domain.ErrReviewClaimRequired:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
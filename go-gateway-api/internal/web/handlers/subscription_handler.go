@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/web/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type SubscriptionHandler struct {
+	service *service.SubscriptionService
+}
+
+func NewSubscriptionHandler(service *service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		service: service,
+	}
+}
+
+// Endpoint: /plans
+// Method: POST
+func (h *SubscriptionHandler) CreatePlan(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreatePlanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.CreatePlan(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /plans
+// Method: GET
+func (h *SubscriptionHandler) ListPlans(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.ListPlans(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /plans/{id}
+// Method: GET
+func (h *SubscriptionHandler) GetPlan(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.GetPlan(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /subscriptions
+// Method: POST
+func (h *SubscriptionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateSubscriptionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.Create(r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /subscriptions
+// Method: GET
+func (h *SubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.List(r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /subscriptions/{id}
+// Method: GET
+func (h *SubscriptionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	output, err := h.service.GetByID(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /subscriptions/{id}/plan
+// Method: POST
+func (h *SubscriptionHandler) ChangePlan(w http.ResponseWriter, r *http.Request) {
+	var input dto.ChangeSubscriptionPlanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.ChangePlan(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /subscriptions/{id}/payment-method
+// Method: PUT
+func (h *SubscriptionHandler) ChangePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	var input dto.ChangeSubscriptionPaymentMethodInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	output, err := h.service.ChangePaymentMethod(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Endpoint: /subscriptions/{id}/cancel
+// Method: POST
+func (h *SubscriptionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	var input dto.CancelSubscriptionInput
+	// O corpo é opcional: sem ele a assinatura é cancelada na hora
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	output, err := h.service.Cancel(chi.URLParam(r, "id"), r.Header.Get("X-API-KEY"), input, middleware.ActorFromRequest(r))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeSubscriptionError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAccountNotFound:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case domain.ErrUnauthorizedAccess, domain.ErrAccountSuspended, domain.ErrAccountClosed:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case domain.ErrPlanNotFound, domain.ErrSubscriptionNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrInvalidPlan, domain.ErrInvalidAmount, domain.ErrUnsupportedCurrency:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrCustomerNotFound, domain.ErrPaymentMethodNotFound, domain.ErrCardExpired,
+		domain.ErrInvalidSubscriptionStatus, domain.ErrIncompatiblePlan, domain.ErrExchangeRateNotFound:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case domain.ErrSubscriptionConflict:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
+	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/dto"
+)
+
+type accountContextKey struct{}
+
+// AccountFromContext retorna a conta autenticada pelo AuthMiddleware
+func AccountFromContext(ctx context.Context) *dto.AccountOutput {
+	account, _ := ctx.Value(accountContextKey{}).(*dto.AccountOutput)
+	return account
+}
+
+type publishableAccountContextKey struct{}
+
+// PublishableAccountFromContext retorna a conta da chave pública autenticada por
+// AuthenticatePublishable. Fica separada de AccountFromContext porque quem chama essas rotas
+// é o comprador, não o lojista.
+func PublishableAccountFromContext(ctx context.Context) *dto.AccountOutput {
+	account, _ := ctx.Value(publishableAccountContextKey{}).(*dto.AccountOutput)
+	return account
+}
+
+// ActorFromRequest identifica quem está executando a requisição para a auditoria:
+// operador interno nas rotas /admin ou lojista nas rotas autenticadas por API key
+func ActorFromRequest(r *http.Request) domain.Actor {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	if operator := OperatorFromContext(r.Context()); operator.Name != "" {
+		return domain.Actor{Type: domain.ActorTypeOperator, ID: operator.Name, IP: ip}
+	}
+
+	if account := AccountFromContext(r.Context()); account != nil {
+		return domain.Actor{Type: domain.ActorTypeMerchant, ID: account.ID, IP: ip}
+	}
+
+	return domain.Actor{Type: domain.ActorTypeMerchant, IP: ip}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/domain"
@@ -27,18 +28,62 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
+		// A chave pública fica exposta no navegador e não pode acessar as rotas do servidor
+		if domain.IsPublishableKey(apiKey) {
+			http.Error(w, domain.ErrPublishableKeyNotAllowed.Error(), http.StatusForbidden)
+			return
+		}
+
 		// Todos os handlers que utilizarem esse middleware devem ter o X-API-KEY
-		_, err := m.accountService.FindByAPIKey(apiKey)
+		account, err := m.accountService.Authenticate(apiKey)
 		if err != nil {
 			if err == domain.ErrAccountNotFound {
 				http.Error(w, err.Error(), http.StatusUnauthorized)
 				return
 			}
 
+			if err == domain.ErrAccountSuspended || err == domain.ErrAccountClosed {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), accountContextKey{}, account)
+		next.ServeHTTP(w, r.WithContext(ctx)) // Chama o próximo handler na cadeia de middleware passando req, res
+	})
+}
+
+// AuthenticatePublishable protege as rotas chamadas pelo navegador (tokenização e pagamento do
+// checkout): aceita apenas a chave pública, enviada no mesmo X-API-KEY, para que a chave
+// secreta nunca precise sair do servidor do lojista
+func (m *AuthMiddleware) AuthenticatePublishable(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-KEY")
+		if !domain.IsPublishableKey(key) {
+			http.Error(w, domain.ErrInvalidPublishableKey.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		account, err := m.accountService.AuthenticatePublishable(key)
+		if err != nil {
+			if err == domain.ErrAccountNotFound {
+				http.Error(w, domain.ErrInvalidPublishableKey.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			if err == domain.ErrAccountSuspended || err == domain.ErrAccountClosed {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		next.ServeHTTP(w, r) // Chama o próximo handler na cadeia de middleware passando req, res
+		ctx := context.WithValue(r.Context(), publishableAccountContextKey{}, account)
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
-}
\ No newline at end of file
+}
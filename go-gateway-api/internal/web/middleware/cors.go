@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSMiddleware libera as rotas do navegador para as origens configuradas pelos lojistas
+// (ex.: o frontend do checkout). Origens fora da lista não recebem os cabeçalhos e o navegador
+// bloqueia a resposta.
+type CORSMiddleware struct {
+	origins map[string]bool
+}
+
+func NewCORSMiddleware(origins []string) *CORSMiddleware {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return &CORSMiddleware{
+		origins: allowed,
+	}
+}
+
+// ParseAllowedOrigins lê a lista no formato "https://loja.com,https://checkout.loja.com"
+func ParseAllowedOrigins(raw string) []string {
+	origins := []string{}
+	for _, entry := range strings.Split(raw, ",") {
+		origin := strings.TrimRight(strings.TrimSpace(entry), "/")
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+func (m *CORSMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if m.origins[origin] {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-KEY")
+			w.Header().Set("Access-Control-Max-Age", "600")
+		}
+
+		// O preflight é respondido aqui, antes da autenticação: o navegador não envia a chave
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Preflight é registrado como handler de OPTIONS nas rotas do navegador para que o roteador
+// encaminhe o preflight ao Handler; a resposta é sempre dada pelo middleware
+func (m *CORSMiddleware) Preflight(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
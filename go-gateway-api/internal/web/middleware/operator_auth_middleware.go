@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Papéis dos operadores internos
+const (
+	RoleAdmin       = "admin"        // acesso total às rotas /admin
+	RoleRiskAnalyst = "risk_analyst" // revisão manual de faturas
+	RoleSupport     = "support"      // apenas consulta
+)
+
+type Operator struct {
+	Name string
+	Role string
+}
+
+type operatorContextKey struct{}
+
+// OperatorAuthMiddleware autentica operadores internos (analistas, administradores) por
+// bearer token, separado das API keys dos lojistas
+type OperatorAuthMiddleware struct {
+	tokens map[string]Operator
+}
+
+func NewOperatorAuthMiddleware(tokens map[string]Operator) *OperatorAuthMiddleware {
+	return &OperatorAuthMiddleware{
+		tokens: tokens,
+	}
+}
+
+// validRoles são os papéis aceitos em OPERATOR_TOKENS
+var validRoles = map[string]bool{
+	RoleAdmin:       true,
+	RoleRiskAnalyst: true,
+	RoleSupport:     true,
+}
+
+// ParseOperatorTokens lê a lista no formato "nome:papel:token,nome:papel:token". Uma entrada
+// malformada, com papel desconhecido ou token repetido é um erro, para que um erro de
+// digitação não deixe um operador sem acesso ou com um papel que nenhuma rota reconhece. Os
+// erros nunca incluem o token.
+func ParseOperatorTokens(raw string) (map[string]Operator, error) {
+	tokens := make(map[string]Operator)
+	for i, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("operator entry %d must be name:role:token", i+1)
+		}
+
+		name, role, token := parts[0], parts[1], parts[2]
+		if !validRoles[role] {
+			return nil, fmt.Errorf("operator %q has unknown role %q", name, role)
+		}
+
+		if _, exists := tokens[token]; exists {
+			return nil, fmt.Errorf("operator %q reuses the token of another operator", name)
+		}
+
+		tokens[token] = Operator{Name: name, Role: role}
+	}
+	return tokens, nil
+}
+
+func (m *OperatorAuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "Bearer token is required", http.StatusUnauthorized)
+			return
+		}
+
+		operator, found := m.lookup(token)
+		if !found {
+			http.Error(w, "invalid operator token", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), operatorContextKey{}, operator)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireRole libera a rota apenas para os papéis informados; admin sempre tem acesso
+func (m *OperatorAuthMiddleware) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			operator := OperatorFromContext(r.Context())
+			if operator.Role == RoleAdmin {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, role := range roles {
+				if operator.Role == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, "operator role not allowed", http.StatusForbidden)
+		})
+	}
+}
+
+// lookup compara em tempo constante para não vazar informação sobre os tokens
+func (m *OperatorAuthMiddleware) lookup(token string) (Operator, bool) {
+	for candidate, operator := range m.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return operator, true
+		}
+	}
+	return Operator{}, false
+}
+
+// OperatorFromContext retorna o operador autenticado pelo OperatorAuthMiddleware
+func OperatorFromContext(ctx context.Context) Operator {
+	operator, _ := ctx.Value(operatorContextKey{}).(Operator)
+	return operator
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseRateLimit lê o limite no formato "requisições/janela", ex.: "60/1m"; valores inválidos
+// usam 60 requisições por minuto
+func ParseRateLimit(raw string) RateLimit {
+	limit := RateLimit{Requests: 60, Window: time.Minute}
+
+	requests, window, found := strings.Cut(strings.TrimSpace(raw), "/")
+	if !found {
+		return limit
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 1 {
+		return limit
+	}
+
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		return limit
+	}
+
+	return RateLimit{Requests: n, Window: duration}
+}
+
+type rateWindow struct {
+	start    time.Time
+	requests int
+}
+
+// RateLimiter limita as requisições por IP ou por conta em janelas fixas. As rotas do navegador
+// não têm chave secreta, então esses limites é que impedem testes de cartões em massa. A
+// contagem fica em memória e vale por réplica.
+type RateLimiter struct {
+	limit     RateLimit
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// Limit depende do RealIPMiddleware para usar o IP do cliente atrás de um proxy confiável
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		if retryAfter, allowed := l.allow(ip, time.Now()); !allowed {
+			writeTooManyRequests(w, retryAfter)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LimitByAccount conta as requisições pela conta da chave pública, que o comprador não
+// consegue trocar como troca de IP. Deve vir depois do AuthenticatePublishable: apenas chaves
+// válidas chegam aqui, então chaves inventadas não ocupam janelas.
+func (l *RateLimiter) LimitByAccount(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account := PublishableAccountFromContext(r.Context())
+		if account == nil {
+			http.Error(w, "publishable account not found in context", http.StatusInternalServerError)
+			return
+		}
+
+		if retryAfter, allowed := l.allow(account.ID, time.Now()); !allowed {
+			writeTooManyRequests(w, retryAfter)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+	http.Error(w, "too many requests", http.StatusTooManyRequests)
+}
+
+// allow conta a requisição na janela da chave (IP ou conta); quando o limite foi atingido,
+// retorna quanto falta para a janela acabar
+func (l *RateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// As janelas encerradas são descartadas uma vez por janela
+	if now.Sub(l.lastSweep) >= l.limit.Window {
+		for expired, window := range l.windows {
+			if now.Sub(window.start) >= l.limit.Window {
+				delete(l.windows, expired)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	window, found := l.windows[key]
+	if !found || now.Sub(window.start) >= l.limit.Window {
+		window = &rateWindow{start: now}
+		l.windows[key] = window
+	}
+
+	if window.requests >= l.limit.Requests {
+		return window.start.Add(l.limit.Window).Sub(now), false
+	}
+
+	window.requests++
+	return 0, true
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIPMiddleware troca o RemoteAddr pelo IP do cliente. Os cabeçalhos X-Forwarded-For e
+// X-Real-IP só são aceitos quando a conexão vem de um proxy confiável; sem proxies
+// configurados vale o endereço da conexão TCP, já que qualquer cliente pode enviar os
+// cabeçalhos. A auditoria e o limite por IP usam o endereço resultante.
+type RealIPMiddleware struct {
+	trustedProxies []*net.IPNet
+}
+
+func NewRealIPMiddleware(trustedProxies []*net.IPNet) *RealIPMiddleware {
+	return &RealIPMiddleware{
+		trustedProxies: trustedProxies,
+	}
+}
+
+// ParseTrustedProxies lê a lista no formato "10.0.0.0/8,192.168.1.10"; IPs sem máscara valem
+// apenas para o próprio endereço
+func ParseTrustedProxies(raw string) []*net.IPNet {
+	proxies := []*net.IPNet{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			slog.Warn("proxy confiável inválido ignorado", "value", entry)
+			continue
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies
+}
+
+func (m *RealIPMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.RemoteAddr = m.clientIP(r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP percorre o X-Forwarded-For da direita para a esquerda: cada proxy confiável
+// acrescenta o endereço de quem o chamou, então o primeiro endereço não confiável é o cliente
+func (m *RealIPMiddleware) clientIP(r *http.Request) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+
+	if !m.trusted(peer) {
+		return peer
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			peer = hop
+			if !m.trusted(hop) {
+				return hop
+			}
+		}
+		return peer
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return peer
+}
+
+func (m *RealIPMiddleware) trusted(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range m.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
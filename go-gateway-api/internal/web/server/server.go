@@ -1,6 +1,7 @@
 package server
 
 import (
+	"net"
 	"net/http"
 
 	"github.com/Victormrf/payment-gateway/go-gateway-api/internal/service"
@@ -14,34 +15,245 @@ type Server struct {
 	server *http.Server
 	accountService *service.AccountService
 	invoiceService *service.InvoiceService
+	reviewService *service.ReviewService
+	exportService *service.DataExportService
+	auditService *service.AuditService
+	payoutService *service.PayoutService
+	settlementService *service.SettlementService
+	feeService *service.FeeService
+	anticipationService *service.AnticipationService
+	pixService *service.PixService
+	boletoService *service.BoletoService
+	cnabService *service.CNABService
+	fxService *service.FXService
+	customerService *service.CustomerService
+	subscriptionService *service.SubscriptionService
+	checkoutService *service.CheckoutService
+	cardTokenService *service.CardTokenService
+	couponService *service.CouponService
+	receiptService *service.ReceiptService
+	operatorTokens map[string]middleware.Operator // token -> operador com acesso às rotas /admin
+	allowedOrigins []string // origens do navegador liberadas no CORS das rotas do checkout
+	browserRateLimit middleware.RateLimit // limite por IP das rotas do navegador
+	publishableKeyRateLimit middleware.RateLimit // limite por conta das rotas autenticadas pela chave pública
+	trustedProxies []*net.IPNet // proxies dos quais os cabeçalhos X-Forwarded-For são aceitos
 	port string
 }
 
-func NewServer(accountService *service.AccountService, invoiceService *service.InvoiceService, port string) *Server {
+func NewServer(
+	accountService *service.AccountService,
+	invoiceService *service.InvoiceService,
+	reviewService *service.ReviewService,
+	exportService *service.DataExportService,
+	auditService *service.AuditService,
+	payoutService *service.PayoutService,
+	settlementService *service.SettlementService,
+	feeService *service.FeeService,
+	anticipationService *service.AnticipationService,
+	pixService *service.PixService,
+	boletoService *service.BoletoService,
+	cnabService *service.CNABService,
+	fxService *service.FXService,
+	customerService *service.CustomerService,
+	subscriptionService *service.SubscriptionService,
+	checkoutService *service.CheckoutService,
+	cardTokenService *service.CardTokenService,
+	couponService *service.CouponService,
+	receiptService *service.ReceiptService,
+	operatorTokens map[string]middleware.Operator,
+	allowedOrigins []string,
+	browserRateLimit middleware.RateLimit,
+	publishableKeyRateLimit middleware.RateLimit,
+	trustedProxies []*net.IPNet,
+	port string,
+) *Server {
 	return &Server{
 		router: chi.NewRouter(),
 		accountService: accountService,
 		invoiceService: invoiceService,
+		reviewService: reviewService,
+		exportService: exportService,
+		auditService: auditService,
+		payoutService: payoutService,
+		settlementService: settlementService,
+		feeService: feeService,
+		anticipationService: anticipationService,
+		pixService: pixService,
+		boletoService: boletoService,
+		cnabService: cnabService,
+		fxService: fxService,
+		customerService: customerService,
+		subscriptionService: subscriptionService,
+		checkoutService: checkoutService,
+		cardTokenService: cardTokenService,
+		couponService: couponService,
+		receiptService: receiptService,
+		operatorTokens: operatorTokens,
+		allowedOrigins: allowedOrigins,
+		browserRateLimit: browserRateLimit,
+		publishableKeyRateLimit: publishableKeyRateLimit,
+		trustedProxies: trustedProxies,
 		port: port,
 	}
 }
 
 func (s *Server) ConfigureRoutes() {
-	accountHandler := handlers.NewAccountHandlers(s.accountService)
+	accountHandler := handlers.NewAccountHandlers(s.accountService, s.exportService, s.settlementService)
 	invoiceHandler := handlers.NewInvoiceHandler(s.invoiceService)
+	payoutHandler := handlers.NewPayoutHandler(s.payoutService)
+	anticipationHandler := handlers.NewAnticipationHandler(s.anticipationService)
+	pixHandler := handlers.NewPixHandler(s.pixService)
+	boletoHandler := handlers.NewBoletoHandler(s.boletoService)
+	customerHandler := handlers.NewCustomerHandler(s.customerService)
+	subscriptionHandler := handlers.NewSubscriptionHandler(s.subscriptionService)
+	checkoutHandler := handlers.NewCheckoutHandler(s.checkoutService)
+	cardTokenHandler := handlers.NewCardTokenHandler(s.cardTokenService)
+	couponHandler := handlers.NewCouponHandler(s.couponService)
+	receiptHandler := handlers.NewReceiptHandler(s.receiptService)
 	authMiddleware := middleware.NewAuthMiddleware(s.accountService)
 
-	s.router.Post("/accounts", accountHandler.Create)
-	s.router.Get("/accounts", accountHandler.Get)
+	// O IP registrado na auditoria vem do X-Forwarded-For apenas quando a conexão chega por
+	// um dos proxies confiáveis
+	s.router.Use(middleware.NewRealIPMiddleware(s.trustedProxies).Handler)
 
+	// As rotas precisam ser registradas em r para que o middleware seja aplicado
 	s.router.Group(func(r chi.Router) {
 		r.Use(authMiddleware.Authenticate)
-		s.router.Post("/invoice", invoiceHandler.Create)
-		s.router.Get("/invoice/{id}", invoiceHandler.GetByID)
-		s.router.Get("/invoice", invoiceHandler.ListByAccount)
+		r.Get("/accounts", accountHandler.Get)
+		r.Patch("/accounts", accountHandler.Update)
+		r.Get("/accounts/balance", accountHandler.Balance)
+		r.Post("/accounts/close", accountHandler.Close)
+		r.Post("/accounts/api-key/rotate", accountHandler.RotateAPIKey)
+		r.Post("/accounts/publishable-key/rotate", accountHandler.RotatePublishableKey)
+		r.Get("/accounts/receipt-settings", receiptHandler.GetSettings)
+		r.Put("/accounts/receipt-settings", receiptHandler.UpdateSettings)
+		r.Get("/accounts/export", accountHandler.Export)
+		r.Post("/invoice", invoiceHandler.Create)
+		r.Get("/invoice/{id}", invoiceHandler.GetByID)
+		r.Get("/invoice/{id}/pix/qrcode", pixHandler.QRCode)
+		r.Get("/invoice/{id}/boleto", boletoHandler.Document)
+		r.Get("/invoice/{id}/receipt", receiptHandler.Receipt)
+		r.Get("/invoice", invoiceHandler.ListByAccount)
+		r.Post("/payout-destinations", payoutHandler.CreateDestination)
+		r.Get("/payout-destinations", payoutHandler.ListDestinations)
+		r.Post("/payouts", payoutHandler.Create)
+		r.Get("/payouts/{id}", payoutHandler.GetByID)
+		r.Get("/payouts", payoutHandler.ListByAccount)
+		r.Get("/receivables/anticipatable", anticipationHandler.ListAnticipatable)
+		r.Post("/anticipations/simulate", anticipationHandler.Simulate)
+		r.Post("/anticipations", anticipationHandler.Request)
+		r.Get("/anticipations/{id}", anticipationHandler.GetByID)
+		r.Get("/anticipations", anticipationHandler.ListByAccount)
+		r.Post("/customers", customerHandler.Create)
+		r.Get("/customers", customerHandler.List)
+		r.Get("/customers/{id}", customerHandler.GetByID)
+		r.Post("/customers/{id}/payment-methods", customerHandler.AddPaymentMethod)
+		r.Get("/customers/{id}/payment-methods", customerHandler.ListPaymentMethods)
+		r.Post("/plans", subscriptionHandler.CreatePlan)
+		r.Get("/plans", subscriptionHandler.ListPlans)
+		r.Get("/plans/{id}", subscriptionHandler.GetPlan)
+		r.Post("/subscriptions", subscriptionHandler.Create)
+		r.Get("/subscriptions", subscriptionHandler.List)
+		r.Get("/subscriptions/{id}", subscriptionHandler.GetByID)
+		r.Post("/subscriptions/{id}/plan", subscriptionHandler.ChangePlan)
+		r.Put("/subscriptions/{id}/payment-method", subscriptionHandler.ChangePaymentMethod)
+		r.Post("/subscriptions/{id}/cancel", subscriptionHandler.Cancel)
+		r.Post("/checkout/sessions", checkoutHandler.CreateSession)
+		r.Post("/payment-links", checkoutHandler.CreatePaymentLink)
+		r.Get("/payment-links", checkoutHandler.ListPaymentLinks)
+		r.Post("/payment-links/{id}/deactivate", checkoutHandler.DeactivatePaymentLink)
+		r.Post("/coupons", couponHandler.Create)
+		r.Get("/coupons", couponHandler.List)
+		r.Post("/coupons/{id}/deactivate", couponHandler.Deactivate)
 	})
 
-} 
+	// Rotas chamadas pelo navegador do comprador, com CORS para as origens configuradas e
+	// limite por IP. A consulta da sessão e a abertura pelo link usam o ID aleatório como
+	// credencial; a tokenização e o pagamento exigem a chave pública da conta e têm também um
+	// limite por conta, que não é contornado trocando de IP.
+	corsMiddleware := middleware.NewCORSMiddleware(s.allowedOrigins)
+	browserRateLimiter := middleware.NewRateLimiter(s.browserRateLimit)
+	publishableKeyRateLimiter := middleware.NewRateLimiter(s.publishableKeyRateLimit)
+
+	s.router.Group(func(r chi.Router) {
+		r.Use(corsMiddleware.Handler)
+		r.Use(browserRateLimiter.Limit)
+		r.Get("/checkout/sessions/{id}", checkoutHandler.GetSession)
+		r.Post("/payment-links/{id}/sessions", checkoutHandler.CreateSessionFromLink)
+
+		// O preflight não traz a chave, por isso é registrado fora do grupo autenticado
+		r.Options("/checkout/sessions/{id}", corsMiddleware.Preflight)
+		r.Options("/checkout/sessions/{id}/confirm", corsMiddleware.Preflight)
+		r.Options("/payment-links/{id}/sessions", corsMiddleware.Preflight)
+		r.Options("/tokens", corsMiddleware.Preflight)
+
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.AuthenticatePublishable)
+			r.Use(publishableKeyRateLimiter.LimitByAccount)
+			r.Post("/tokens", cardTokenHandler.Create)
+			r.Post("/checkout/sessions/{id}/confirm", checkoutHandler.Confirm)
+		})
+	})
+
+	// Callbacks do PSP e do banco, autenticados pela assinatura do corpo
+	s.router.Post("/webhooks/pix", pixHandler.Callback)
+	s.router.Post("/webhooks/boleto", boletoHandler.Callback)
+
+	// Rotas internas, autenticadas por token de operador e não por API key.
+	// Contas de lojistas só podem ser criadas por aqui.
+	reviewHandler := handlers.NewReviewHandler(s.reviewService)
+	adminAccountHandler := handlers.NewAdminAccountHandler(s.accountService, s.invoiceService, s.exportService)
+	auditHandler := handlers.NewAuditHandler(s.auditService)
+	feeHandler := handlers.NewFeeHandler(s.feeService)
+	cnabHandler := handlers.NewCNABHandler(s.cnabService)
+	fxHandler := handlers.NewFXHandler(s.fxService)
+	operatorAuthMiddleware := middleware.NewOperatorAuthMiddleware(s.operatorTokens)
+
+	s.router.Route("/admin", func(r chi.Router) {
+		r.Use(operatorAuthMiddleware.Authenticate)
+
+		r.Group(func(r chi.Router) {
+			r.Use(operatorAuthMiddleware.RequireRole(middleware.RoleRiskAnalyst, middleware.RoleSupport))
+			r.Get("/accounts/{id}", adminAccountHandler.GetByID)
+			r.Get("/accounts/{id}/invoices", adminAccountHandler.ListInvoices)
+			r.Get("/invoices", adminAccountHandler.ListInvoicesByPayer)
+			r.Get("/accounts/{id}/export", adminAccountHandler.Export)
+			r.Get("/accounts/{id}/fee-rules", feeHandler.ListRules)
+			r.Get("/fx/rates/{base}/{quote}", fxHandler.Quote)
+			r.Get("/fx/overrides", fxHandler.ListOverrides)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(operatorAuthMiddleware.RequireRole(middleware.RoleRiskAnalyst))
+			r.Get("/reviews", reviewHandler.ListQueue)
+			r.Post("/reviews/{id}/claim", reviewHandler.Claim)
+			r.Delete("/reviews/{id}/claim", reviewHandler.Release)
+			r.Post("/reviews/{id}/approve", reviewHandler.Approve)
+			r.Post("/reviews/{id}/reject", reviewHandler.Reject)
+			r.Get("/reviews/{id}/actions", reviewHandler.ListActions)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(operatorAuthMiddleware.RequireRole(middleware.RoleAdmin))
+			r.Post("/accounts", adminAccountHandler.Create)
+			r.Post("/accounts/{id}/suspend", adminAccountHandler.Suspend)
+			r.Post("/accounts/{id}/reactivate", adminAccountHandler.Reactivate)
+			r.Post("/accounts/{id}/close", adminAccountHandler.Close)
+			r.Post("/accounts/{id}/balance-adjustments", adminAccountHandler.AdjustBalance)
+			r.Put("/accounts/{id}/settlement-schedule", adminAccountHandler.UpdateSettlementSchedule)
+			r.Put("/accounts/{id}/fee-rules", feeHandler.ReplaceRules)
+			r.Get("/audit-events", auditHandler.List)
+			r.Get("/audit-events/verify", auditHandler.Verify)
+			r.Post("/cnab/remittances", cnabHandler.GenerateRemittance)
+			r.Post("/cnab/returns", cnabHandler.UploadReturn)
+			r.Get("/cnab/files", cnabHandler.ListFiles)
+			r.Get("/cnab/files/{id}", cnabHandler.GetFile)
+			r.Get("/cnab/files/{id}/content", cnabHandler.Content)
+			r.Put("/fx/overrides/{base}/{quote}", fxHandler.SetOverride)
+			r.Delete("/fx/overrides/{base}/{quote}", fxHandler.DeleteOverride)
+		})
+	})
+}
 
 func (s *Server) Start() error {
 	s.server = &http.Server{
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS processed_events;
+
+ALTER TABLE accounts ALTER COLUMN balance DROP DEFAULT;
+
+ALTER TABLE accounts ALTER COLUMN balance TYPE VARCHAR(255) USING balance::VARCHAR(255);
+
+ALTER TABLE accounts ALTER COLUMN balance SET DEFAULT 0;
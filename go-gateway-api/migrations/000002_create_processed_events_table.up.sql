@@ -0,0 +1,14 @@
+-- O saldo precisa ser numérico para ser creditado de forma atômica (balance = balance + valor)
+ALTER TABLE accounts ALTER COLUMN balance DROP DEFAULT;
+
+ALTER TABLE accounts ALTER COLUMN balance TYPE DECIMAL(15,2) USING balance::DECIMAL(15,2);
+
+ALTER TABLE accounts ALTER COLUMN balance SET DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id VARCHAR(255) PRIMARY KEY,
+    event_type VARCHAR(100) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_processed_events_processed_at ON processed_events(processed_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_invoices_pending_last_published_at;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS last_published_at;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS publish_attempts;
@@ -0,0 +1,8 @@
+ALTER TABLE invoices ADD COLUMN publish_attempts INT NOT NULL DEFAULT 0;
+
+ALTER TABLE invoices ADD COLUMN last_published_at TIMESTAMP;
+
+-- Faturas pendentes existentes já foram publicadas uma vez na criação
+UPDATE invoices SET publish_attempts = 1, last_published_at = created_at WHERE status = 'pending';
+
+CREATE INDEX idx_invoices_pending_last_published_at ON invoices(last_published_at) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS invoice_review_actions;
+
+DROP TABLE IF EXISTS invoice_review_claims;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS invoice_review_claims (
+    invoice_id UUID PRIMARY KEY REFERENCES invoices(id) ON DELETE CASCADE,
+    reviewer VARCHAR(255) NOT NULL,
+    claimed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoice_review_actions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    reviewer VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_invoice_review_actions_invoice_id ON invoice_review_actions(invoice_id);
+
+CREATE INDEX idx_invoice_review_actions_reviewer ON invoice_review_actions(reviewer);
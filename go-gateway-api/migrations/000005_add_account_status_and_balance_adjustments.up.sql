@@ -0,0 +1,16 @@
+ALTER TABLE accounts ADD COLUMN status VARCHAR(50) NOT NULL DEFAULT 'active';
+
+CREATE INDEX idx_accounts_status ON accounts(status);
+
+CREATE TABLE IF NOT EXISTS balance_adjustments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(15,2) NOT NULL,
+    reason TEXT NOT NULL,
+    operator VARCHAR(255) NOT NULL,
+    balance_before DECIMAL(15,2) NOT NULL,
+    balance_after DECIMAL(15,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_balance_adjustments_account_id ON balance_adjustments(account_id);
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+
+DROP TRIGGER IF EXISTS audit_events_no_update_delete ON audit_events;
+
+DROP FUNCTION IF EXISTS prevent_audit_events_mutation();
+
+DROP TABLE IF EXISTS audit_events;
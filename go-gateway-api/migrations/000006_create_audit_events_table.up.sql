@@ -0,0 +1,39 @@
+-- before/after usam JSON (e não JSONB) para preservar o texto exato usado no hash
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY,
+    sequence BIGINT NOT NULL UNIQUE,
+    actor_type VARCHAR(50) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    actor_ip VARCHAR(64) NOT NULL DEFAULT '',
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    before_state JSON,
+    after_state JSON,
+    occurred_at TIMESTAMP NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL,
+    hash VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id);
+
+CREATE INDEX idx_audit_events_action ON audit_events(action);
+
+CREATE INDEX idx_audit_events_occurred_at ON audit_events(occurred_at);
+
+-- A tabela é append-only: qualquer UPDATE, DELETE ou TRUNCATE é rejeitado
+CREATE OR REPLACE FUNCTION prevent_audit_events_mutation() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION prevent_audit_events_mutation();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION prevent_audit_events_mutation();
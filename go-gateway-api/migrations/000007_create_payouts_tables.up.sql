@@ -0,0 +1,33 @@
+-- Colunas de conta bancária ou de chave Pix são preenchidas conforme o tipo do destino
+CREATE TABLE IF NOT EXISTS payout_destinations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    bank_code VARCHAR(3),
+    branch VARCHAR(5),
+    account_number VARCHAR(20),
+    account_type VARCHAR(20),
+    holder_name VARCHAR(255),
+    holder_document VARCHAR(14),
+    pix_key_type VARCHAR(20),
+    pix_key VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payout_destinations_account_id ON payout_destinations(account_id);
+
+CREATE TABLE IF NOT EXISTS payouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    destination_id UUID NOT NULL REFERENCES payout_destinations(id),
+    amount DECIMAL(15,2) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    provider_reference VARCHAR(255) NOT NULL DEFAULT '',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payouts_account_id ON payouts(account_id);
+
+CREATE INDEX idx_payouts_status ON payouts(status);
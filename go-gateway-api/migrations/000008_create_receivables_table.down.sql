@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS receivables;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS settlement_per_installment;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS settlement_delay_days;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS pending_balance;
@@ -0,0 +1,24 @@
+-- balance passa a representar o saldo disponível; valores aprovados ficam em
+-- pending_balance até a data de liquidação de cada recebível
+ALTER TABLE accounts ADD COLUMN pending_balance DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE accounts ADD COLUMN settlement_delay_days INTEGER NOT NULL DEFAULT 30;
+
+ALTER TABLE accounts ADD COLUMN settlement_per_installment BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS receivables (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    installment_number INTEGER NOT NULL DEFAULT 1,
+    amount DECIMAL(15,2) NOT NULL,
+    due_date DATE NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'scheduled',
+    settled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (invoice_id, installment_number)
+);
+
+CREATE INDEX idx_receivables_account_id ON receivables(account_id);
+
+CREATE INDEX idx_receivables_status_due_date ON receivables(status, due_date);
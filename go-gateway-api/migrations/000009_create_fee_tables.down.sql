@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS invoice_fees;
+
+DROP TABLE IF EXISTS fee_rules;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS net_amount;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS fee_amount;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS card_brand;
@@ -0,0 +1,40 @@
+ALTER TABLE invoices ADD COLUMN card_brand VARCHAR(20) NOT NULL DEFAULT 'other';
+
+ALTER TABLE invoices ADD COLUMN fee_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE invoices ADD COLUMN net_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- Faturas aprovadas antes das taxas foram creditadas pelo valor bruto
+UPDATE invoices SET net_amount = amount WHERE status = 'approved';
+
+-- card_brand e payment_type vazios valem para qualquer bandeira/forma de pagamento
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    card_brand VARCHAR(20) NOT NULL DEFAULT '',
+    payment_type VARCHAR(50) NOT NULL DEFAULT '',
+    min_installments INTEGER NOT NULL DEFAULT 1,
+    max_installments INTEGER NOT NULL DEFAULT 12,
+    percentage DECIMAL(7,4) NOT NULL DEFAULT 0,
+    fixed_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_fee_rules_account_id ON fee_rules(account_id);
+
+CREATE TABLE IF NOT EXISTS invoice_fees (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    platform_account_id UUID REFERENCES accounts(id),
+    type VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    percentage DECIMAL(7,4) NOT NULL DEFAULT 0,
+    fixed_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(15,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_invoice_fees_invoice_id ON invoice_fees(invoice_id);
+
+CREATE INDEX idx_invoice_fees_platform_account_id ON invoice_fees(platform_account_id);
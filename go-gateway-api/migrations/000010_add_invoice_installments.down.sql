@@ -0,0 +1,9 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS total_amount;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS interest_amount;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS interest_rate;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS interest_mode;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS installments;
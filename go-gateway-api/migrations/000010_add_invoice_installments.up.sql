@@ -0,0 +1,12 @@
+ALTER TABLE invoices ADD COLUMN installments INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE invoices ADD COLUMN interest_mode VARCHAR(20) NOT NULL DEFAULT 'merchant';
+
+ALTER TABLE invoices ADD COLUMN interest_rate DECIMAL(7,4) NOT NULL DEFAULT 0;
+
+ALTER TABLE invoices ADD COLUMN interest_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE invoices ADD COLUMN total_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- Faturas anteriores foram cobradas à vista, sem juros
+UPDATE invoices SET total_amount = amount;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS ledger_entries;
+
+ALTER TABLE receivables DROP COLUMN IF EXISTS anticipation_id;
+
+DROP TABLE IF EXISTS anticipation_items;
+
+DROP TABLE IF EXISTS anticipations;
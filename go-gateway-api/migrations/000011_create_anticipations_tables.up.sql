@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS anticipations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    monthly_rate DECIMAL(7,4) NOT NULL,
+    gross_amount DECIMAL(15,2) NOT NULL,
+    discount_amount DECIMAL(15,2) NOT NULL,
+    net_amount DECIMAL(15,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_anticipations_account_id ON anticipations(account_id);
+
+CREATE TABLE IF NOT EXISTS anticipation_items (
+    anticipation_id UUID NOT NULL REFERENCES anticipations(id) ON DELETE CASCADE,
+    receivable_id UUID NOT NULL REFERENCES receivables(id) ON DELETE CASCADE,
+    days INTEGER NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    discount DECIMAL(15,2) NOT NULL,
+    net_amount DECIMAL(15,2) NOT NULL,
+    PRIMARY KEY (anticipation_id, receivable_id)
+);
+
+ALTER TABLE receivables ADD COLUMN anticipation_id UUID REFERENCES anticipations(id);
+
+-- Lançamentos do saldo disponível que não vêm de faturas, como créditos e descontos de antecipação
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    reference_id UUID,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_entries_account_id ON ledger_entries(account_id);
+
+CREATE INDEX idx_ledger_entries_reference_id ON ledger_entries(reference_id);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS pix_charges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL UNIQUE REFERENCES invoices(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    txid VARCHAR(35) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL,
+    payload TEXT NOT NULL,
+    location TEXT NOT NULL DEFAULT '',
+    amount DECIMAL(15,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMP NOT NULL,
+    end_to_end_id VARCHAR(32) UNIQUE,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pix_charges_status_expires_at ON pix_charges(status, expires_at);
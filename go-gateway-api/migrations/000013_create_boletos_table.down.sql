@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS boletos;
+DROP SEQUENCE IF EXISTS boleto_our_number_seq;
@@ -0,0 +1,26 @@
+CREATE SEQUENCE IF NOT EXISTS boleto_our_number_seq;
+
+CREATE TABLE IF NOT EXISTS boletos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL UNIQUE REFERENCES invoices(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    bank_code VARCHAR(3) NOT NULL,
+    our_number VARCHAR(11) NOT NULL,
+    payer_name VARCHAR(255) NOT NULL,
+    payer_document VARCHAR(14) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    due_date DATE NOT NULL,
+    payment_limit_date DATE NOT NULL,
+    fine_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    interest_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    barcode VARCHAR(44) NOT NULL,
+    digitable_line VARCHAR(47) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'registered',
+    paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bank_code, our_number)
+);
+
+CREATE INDEX idx_boletos_status_payment_limit_date ON boletos(status, payment_limit_date);
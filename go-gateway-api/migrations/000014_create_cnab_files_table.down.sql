@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payouts_provider_reference;
+ALTER TABLE boletos DROP COLUMN IF EXISTS remittance_file_id;
+DROP TABLE IF EXISTS cnab_files;
+DROP SEQUENCE IF EXISTS cnab_remittance_seq;
@@ -0,0 +1,25 @@
+CREATE SEQUENCE IF NOT EXISTS cnab_remittance_seq;
+
+CREATE TABLE IF NOT EXISTS cnab_files (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type VARCHAR(20) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    layout VARCHAR(3) NOT NULL,
+    sequence BIGINT NOT NULL DEFAULT 0,
+    file_name VARCHAR(255) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    content TEXT NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    record_count INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (type, checksum)
+);
+
+CREATE INDEX idx_cnab_files_created_at ON cnab_files(created_at);
+
+-- Boletos incluídos em uma remessa não são enviados de novo
+ALTER TABLE boletos ADD COLUMN remittance_file_id UUID REFERENCES cnab_files(id);
+
+-- Saques enviados por remessa são localizados no retorno pelo "seu número"
+CREATE INDEX idx_payouts_provider_reference ON payouts(provider_reference);
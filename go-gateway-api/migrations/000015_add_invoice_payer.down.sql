@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_invoices_payer_document_hash;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS billing_address,
+    DROP COLUMN IF EXISTS payer_email,
+    DROP COLUMN IF EXISTS payer_document_hash,
+    DROP COLUMN IF EXISTS payer_document,
+    DROP COLUMN IF EXISTS payer_name;
@@ -0,0 +1,9 @@
+ALTER TABLE invoices
+    ADD COLUMN payer_name VARCHAR(255),
+    ADD COLUMN payer_document VARCHAR(14),
+    ADD COLUMN payer_document_hash VARCHAR(64),
+    ADD COLUMN payer_email VARCHAR(255),
+    ADD COLUMN billing_address JSONB;
+
+-- Buscas por pagador usam o HMAC do documento, nunca o documento em claro
+CREATE INDEX idx_invoices_payer_document_hash ON invoices(payer_document_hash) WHERE payer_document_hash IS NOT NULL;
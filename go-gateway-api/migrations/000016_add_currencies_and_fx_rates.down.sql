@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS fx_rate_overrides;
+
+DROP TABLE IF EXISTS fx_rates;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS settlement_amount,
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS settlement_currency,
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
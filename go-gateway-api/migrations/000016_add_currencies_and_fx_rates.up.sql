@@ -0,0 +1,32 @@
+ALTER TABLE accounts ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'BRL';
+
+ALTER TABLE invoices
+    ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'BRL',
+    ADD COLUMN settlement_currency VARCHAR(3) NOT NULL DEFAULT 'BRL',
+    ADD COLUMN exchange_rate DECIMAL(18,8) NOT NULL DEFAULT 0,
+    ADD COLUMN settlement_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- Faturas aprovadas antes da conversão foram liquidadas na própria moeda
+UPDATE invoices SET exchange_rate = 1, settlement_amount = total_amount WHERE status = 'approved';
+
+-- Cotações de mercado, atualizadas pela rotina de importação do provedor (1 base = rate quote)
+CREATE TABLE IF NOT EXISTS fx_rates (
+    base VARCHAR(3) NOT NULL,
+    quote VARCHAR(3) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base, quote)
+);
+
+-- Cotações fixadas pelos operadores; prevalecem sobre o provedor até expirar
+CREATE TABLE IF NOT EXISTS fx_rate_overrides (
+    base VARCHAR(3) NOT NULL,
+    quote VARCHAR(3) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+    reason TEXT NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base, quote)
+);
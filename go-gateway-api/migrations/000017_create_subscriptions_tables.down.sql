@@ -0,0 +1,9 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS subscription_id;
+
+DROP TABLE IF EXISTS subscriptions;
+
+DROP TABLE IF EXISTS plans;
+
+DROP TABLE IF EXISTS payment_methods;
+
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,69 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    document VARCHAR(14),
+    document_hash VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_customers_account_id ON customers(account_id);
+
+-- Cartões salvos: apenas os dados que identificam o cartão, nunca o número completo ou o CVV
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    card_brand VARCHAR(20) NOT NULL,
+    card_last_digits VARCHAR(4) NOT NULL,
+    expiration_month INTEGER NOT NULL,
+    expiration_year INTEGER NOT NULL,
+    cardholder_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_methods_customer_id ON payment_methods(customer_id);
+
+CREATE TABLE IF NOT EXISTS plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'BRL',
+    interval VARCHAR(10) NOT NULL,
+    interval_count INTEGER NOT NULL DEFAULT 1,
+    trial_days INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_plans_account_id ON plans(account_id);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    plan_id UUID NOT NULL REFERENCES plans(id),
+    payment_method_id UUID NOT NULL REFERENCES payment_methods(id),
+    status VARCHAR(20) NOT NULL,
+    current_period_start TIMESTAMP NOT NULL,
+    current_period_end TIMESTAMP NOT NULL,
+    billing_cycle_anchor TIMESTAMP NOT NULL,
+    trial_end TIMESTAMP,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    canceled_at TIMESTAMP,
+    proration_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    latest_invoice_id UUID REFERENCES invoices(id),
+    charge_pending BOOLEAN NOT NULL DEFAULT FALSE,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_subscriptions_account_id ON subscriptions(account_id);
+-- Busca do agendador pelas assinaturas com cobrança vencida
+CREATE INDEX idx_subscriptions_status_period_end ON subscriptions(status, current_period_end);
+
+ALTER TABLE invoices ADD COLUMN subscription_id UUID REFERENCES subscriptions(id);
+
+CREATE INDEX idx_invoices_subscription_id ON invoices(subscription_id) WHERE subscription_id IS NOT NULL;
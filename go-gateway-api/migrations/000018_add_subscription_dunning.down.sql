@@ -0,0 +1,7 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS decline_reason;
+
+DROP INDEX IF EXISTS idx_subscriptions_next_retry_at;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS next_retry_at,
+    DROP COLUMN IF EXISTS failed_attempts;
@@ -0,0 +1,9 @@
+-- Novas tentativas das cobranças de assinatura recusadas
+ALTER TABLE subscriptions
+    ADD COLUMN failed_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN next_retry_at TIMESTAMP;
+
+-- Busca do agendador pelas assinaturas em atraso com nova tentativa vencida
+CREATE INDEX idx_subscriptions_next_retry_at ON subscriptions(next_retry_at) WHERE next_retry_at IS NOT NULL;
+
+ALTER TABLE invoices ADD COLUMN decline_reason VARCHAR(30) NOT NULL DEFAULT '';
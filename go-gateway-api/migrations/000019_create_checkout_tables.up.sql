@@ -0,0 +1,34 @@
+-- Links de pagamento reutilizáveis; o ID é público e vai na URL compartilhada pelo lojista
+CREATE TABLE IF NOT EXISTS payment_links (
+    id VARCHAR(40) PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'BRL',
+    description TEXT NOT NULL DEFAULT '',
+    success_url TEXT NOT NULL,
+    cancel_url TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_links_account_id ON payment_links(account_id);
+
+-- Sessões de checkout pagas pelo comprador no navegador; o ID é público e aleatório
+CREATE TABLE IF NOT EXISTS checkout_sessions (
+    id VARCHAR(40) PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    payment_link_id VARCHAR(40) REFERENCES payment_links(id),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'BRL',
+    description TEXT NOT NULL DEFAULT '',
+    success_url TEXT NOT NULL,
+    cancel_url TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    invoice_id UUID REFERENCES invoices(id),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_checkout_sessions_account_id ON checkout_sessions(account_id);
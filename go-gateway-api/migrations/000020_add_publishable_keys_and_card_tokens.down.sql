@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS card_tokens;
+
+ALTER TABLE accounts
+    DROP CONSTRAINT IF EXISTS accounts_publishable_key_key,
+    DROP COLUMN IF EXISTS publishable_key;
@@ -0,0 +1,26 @@
+-- Chave pública de cada conta, usada pelo navegador na tokenização e no checkout
+ALTER TABLE accounts ADD COLUMN publishable_key VARCHAR(40);
+
+UPDATE accounts SET publishable_key = 'pk_' || md5(random()::text || id::text) WHERE publishable_key IS NULL;
+
+ALTER TABLE accounts
+    ALTER COLUMN publishable_key SET NOT NULL,
+    ADD CONSTRAINT accounts_publishable_key_key UNIQUE (publishable_key);
+
+-- Cartões tokenizados no navegador: uso único, válidos por poucos minutos e com o número e o
+-- CVV cifrados pela aplicação, apagados no uso
+CREATE TABLE IF NOT EXISTS card_tokens (
+    id VARCHAR(40) PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    card_brand VARCHAR(20) NOT NULL,
+    card_last_digits VARCHAR(4) NOT NULL,
+    expiration_month INTEGER NOT NULL,
+    expiration_year INTEGER NOT NULL,
+    cardholder_name VARCHAR(255) NOT NULL,
+    sealed_card BYTEA,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_card_tokens_expires_at ON card_tokens(expires_at);
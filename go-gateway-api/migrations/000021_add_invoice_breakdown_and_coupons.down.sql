@@ -0,0 +1,3 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS breakdown;
+
+DROP TABLE IF EXISTS coupons;
@@ -0,0 +1,19 @@
+-- Cupons de desconto do lojista; redemptions é incrementado ao emitir cada fatura com o cupom
+CREATE TABLE IF NOT EXISTS coupons (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    code VARCHAR(40) NOT NULL,
+    discount_type VARCHAR(10) NOT NULL,
+    value DECIMAL(15,2) NOT NULL CHECK (value > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    max_redemptions INTEGER NOT NULL DEFAULT 0 CHECK (max_redemptions >= 0),
+    redemptions INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT coupons_account_id_code_key UNIQUE (account_id, code)
+);
+
+-- Itens, desconto e impostos da fatura; nulo nas faturas sem itens
+ALTER TABLE invoices ADD COLUMN breakdown JSONB;
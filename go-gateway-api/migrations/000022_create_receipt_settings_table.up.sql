@@ -0,0 +1,11 @@
+-- Personalização dos recibos por conta; o logo é guardado no banco para montar os recibos sem
+-- acessar serviços externos
+CREATE TABLE IF NOT EXISTS receipt_settings (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    logo BYTEA,
+    logo_content_type VARCHAR(20) NOT NULL DEFAULT '',
+    primary_color VARCHAR(7) NOT NULL,
+    secondary_color VARCHAR(7) NOT NULL,
+    footer TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_checkout_sessions_payment_link_id;
+
+ALTER TABLE checkout_sessions DROP COLUMN IF EXISTS failed_attempts;
@@ -0,0 +1,5 @@
+-- Pagamentos recusados de cada sessão de checkout; ao atingir o limite a sessão fica failed
+ALTER TABLE checkout_sessions ADD COLUMN failed_attempts INTEGER NOT NULL DEFAULT 0;
+
+-- Conta as sessões ainda abertas de cada link de pagamento
+CREATE INDEX idx_checkout_sessions_payment_link_id ON checkout_sessions(payment_link_id, status, expires_at);
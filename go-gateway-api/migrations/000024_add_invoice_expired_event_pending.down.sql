@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_invoices_expired_event_pending;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS expired_event_pending;
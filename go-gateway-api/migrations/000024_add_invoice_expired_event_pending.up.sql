@@ -0,0 +1,5 @@
+-- Marcada na mesma transação que expira a fatura pendente; o sweeper publica invoice.expired
+-- e limpa a marca, tentando de novo nas próximas varreduras se a publicação falhar
+ALTER TABLE invoices ADD COLUMN expired_event_pending BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_invoices_expired_event_pending ON invoices(updated_at) WHERE expired_event_pending;